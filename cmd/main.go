@@ -8,9 +8,12 @@ import (
 	"log"
 	"net/http"
 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
+	exchange_repository "prediction-risk/internal/app/exchange/repository"
 	exchange_service "prediction-risk/internal/app/exchange/service"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
 	trigger_repository "prediction-risk/internal/app/risk/trigger/repository"
 	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	weather_domain "prediction-risk/internal/app/weather/domain"
 	"prediction-risk/internal/app/weather/infrastructure/nws"
 	weather_repository "prediction-risk/internal/app/weather/repository"
 	weather_service "prediction-risk/internal/app/weather/service"
@@ -63,18 +66,74 @@ func main() {
 	defer db.Close()
 
 	triggerRepo := trigger_repository.NewTriggerRepository(db)
-	exchangeService := exchange_service.NewExchangeService(kalshiClient)
+
+	// Dry runs trade against a paper ledger priced from live Kalshi market data
+	var exchangeService exchange_service.ExchangeService
+	var paperExchangeService *exchange_service.PaperExchangeService
+	if config.IsDryRun {
+		log.Printf("Dry run: orders are paper traded")
+		paperExchangeService = exchange_service.NewPaperExchangeService(
+			exchange_service.NewExchangeService(kalshiClient),
+			exchange_repository.NewPaperTradingRepository(db),
+		)
+		exchangeService = paperExchangeService
+	} else {
+		log.Printf("Live trading: orders are sent to Kalshi")
+		exchangeService = exchange_service.NewExchangeService(kalshiClient)
+	}
+
 	triggerService := trigger_service.NewTriggerService(triggerRepo)
-	triggerExecutor := trigger_service.NewTriggerExecutor(triggerService, exchangeService)
-	triggerMonitor := trigger_service.NewTriggerMonitor(triggerService, triggerExecutor, exchangeService, 5*time.Second, config.IsDryRun)
+	defaultGuard, err := parseDefaultGuard(config)
+	if err != nil {
+		log.Fatalf("error parsing default guards: %v", err)
+	}
+	// Everything that trades shares one set of ticker locks, so no two of them act on a ticker at once
+	tickerLocks := trigger_service.NewTickerLocks()
+	triggerExecutor := trigger_service.NewTriggerExecutor(triggerService, exchangeService, tickerLocks, defaultGuard)
+	// Evaluate up to 8 triggers at once, giving each 10 seconds before it is abandoned
+	triggerMonitor := trigger_service.NewTriggerMonitor(triggerService, triggerExecutor, exchangeService, tickerLocks, 5*time.Second, 8, 10*time.Second)
+
+	// Protection policies place stops on new positions in their series or event
+	protectionPolicyRepo := trigger_repository.NewProtectionPolicyRepository(db)
+	protectionPolicyService := trigger_service.NewProtectionPolicyService(protectionPolicyRepo, triggerService)
+	positionMonitor := trigger_service.NewPositionMonitor(protectionPolicyService, triggerService, exchangeService, tickerLocks, 30*time.Second)
+
+	// Follow trigger orders until they fill, replacing remainders per each trigger's fill policy
+	orderTracker := trigger_service.NewOrderTracker(triggerService, exchangeService, tickerLocks, 10*time.Second)
 
 	// Weather services
 	weatherObservationRepo := weather_repository.NewTemperatureObservationRepo(db)
 	weatherObservationService := weather_service.NewWeatherObservationService(weatherObservationRepo, nwsClient)
-	weather_monitor := weather_service.NewWeatherMonitor("KNYC", weatherObservationService, 5*time.Second)
+
+	// NWS climate days for KNYC run midnight to midnight local standard time, all year
+	weatherTriggerEvaluator := trigger_service.NewWeatherTriggerEvaluator(
+		triggerService,
+		triggerExecutor,
+		exchangeService,
+		tickerLocks,
+		weatherObservationService,
+		time.FixedZone("EST", -5*60*60),
+	)
+	weather_monitor := weather_service.NewWeatherMonitor("KNYC", weatherObservationService, 5*time.Second, weatherTriggerEvaluator)
+
+	// Hourly forecasts for Central Park, the gridpoint covering KNYC, are reissued about once an hour
+	forecastService := weather_service.NewForecastService(nwsClient)
+	forecastTriggerEvaluator := trigger_service.NewForecastTriggerEvaluator(
+		triggerService,
+		triggerExecutor,
+		exchangeService,
+		tickerLocks,
+		forecastService,
+		time.FixedZone("EST", -5*60*60),
+	)
+	centralPark, err := weather_domain.NewGridpoint("OKX", 33, 37)
+	if err != nil {
+		log.Fatalf("error creating gridpoint: %v", err)
+	}
+	forecastMonitor := weather_service.NewForecastMonitor(centralPark, forecastService, 5*time.Minute, forecastTriggerEvaluator)
 
 	// Run monitors
-	monitors := []Monitor{triggerMonitor, weather_monitor}
+	monitors := []Monitor{triggerMonitor, positionMonitor, orderTracker, weather_monitor, forecastMonitor}
 	for _, m := range monitors {
 		m.Start()
 	}
@@ -91,8 +150,38 @@ func main() {
 	router.Use(middleware.Recoverer)
 
 	// Mount routes
-	stopTriggerRoutes := api.NewStopTriggerRoutes(triggerService)
+	stopTriggerRoutes := api.NewStopTriggerRoutes(triggerService, exchangeService)
 	stopTriggerRoutes.Register(router)
+	takeProfitTriggerRoutes := api.NewTakeProfitTriggerRoutes(triggerService)
+	takeProfitTriggerRoutes.Register(router)
+	trailingStopTriggerRoutes := api.NewTrailingStopTriggerRoutes(triggerService)
+	trailingStopTriggerRoutes.Register(router)
+	bracketRoutes := api.NewBracketRoutes(triggerService)
+	bracketRoutes.Register(router)
+	stopLadderRoutes := api.NewStopLadderRoutes(triggerService)
+	stopLadderRoutes.Register(router)
+	conditionalTriggerRoutes := api.NewConditionalTriggerRoutes(triggerService)
+	conditionalTriggerRoutes.Register(router)
+	entryTriggerRoutes := api.NewEntryTriggerRoutes(triggerService)
+	entryTriggerRoutes.Register(router)
+	hedgeTriggerRoutes := api.NewHedgeTriggerRoutes(triggerService)
+	hedgeTriggerRoutes.Register(router)
+	eventExposureTriggerRoutes := api.NewEventExposureTriggerRoutes(triggerService)
+	eventExposureTriggerRoutes.Register(router)
+	confirmationRoutes := api.NewConfirmationRoutes(triggerService)
+	confirmationRoutes.Register(router)
+	guardRoutes := api.NewGuardRoutes(triggerService)
+	guardRoutes.Register(router)
+	fillPolicyRoutes := api.NewFillPolicyRoutes(triggerService)
+	fillPolicyRoutes.Register(router)
+	protectionPolicyRoutes := api.NewProtectionPolicyRoutes(protectionPolicyService)
+	protectionPolicyRoutes.Register(router)
+	orderRoutes := api.NewOrderRoutes(triggerService)
+	orderRoutes.Register(router)
+	if paperExchangeService != nil {
+		paperTradingRoutes := api.NewPaperTradingRoutes(paperExchangeService)
+		paperTradingRoutes.Register(router)
+	}
 
 	// Start server
 	srv := &http.Server{
@@ -106,6 +195,30 @@ func main() {
 	}
 }
 
+// parseDefaultGuard builds the guards applied to triggers without their own, or nil if none are configured
+func parseDefaultGuard(cfg *config.Config) (*trigger_domain.ExecutionGuard, error) {
+	optional := func(limit int) *int {
+		if limit == 0 {
+			return nil
+		}
+		return &limit
+	}
+	guard := trigger_domain.ExecutionGuard{
+		MaxSpread:    optional(cfg.Guards.MaxSpread),
+		MinLiquidity: optional(cfg.Guards.MinLiquidity),
+		MinVolume24H: optional(cfg.Guards.MinVolume24H),
+		MinDepth:     optional(cfg.Guards.MinDepth),
+		OnFailure:    trigger_domain.GuardFailureAction(cfg.Guards.OnFailure),
+	}
+	if guard.MaxSpread == nil && guard.MinLiquidity == nil && guard.MinVolume24H == nil && guard.MinDepth == nil {
+		return nil, nil
+	}
+	if err := guard.Validate(); err != nil {
+		return nil, err
+	}
+	return &guard, nil
+}
+
 func parsePrivateKey(pemEncodedKey string) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode([]byte(pemEncodedKey))
 	if block == nil || block.Type != "RSA PRIVATE KEY" {
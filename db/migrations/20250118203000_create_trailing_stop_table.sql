@@ -0,0 +1,24 @@
+-- migrate:up transaction:false
+ALTER TYPE event_contract.trigger_type ADD VALUE IF NOT EXISTS 'TRAILING_STOP';
+
+CREATE TYPE event_contract.trail_type AS ENUM ('CENTS', 'PERCENT');
+
+-- Trailing stop settings and the high water mark, which must survive restarts
+CREATE TABLE event_contract.trailing_stop (
+    trigger_id UUID PRIMARY KEY REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    trail_type event_contract.trail_type NOT NULL,
+    trail_amount INTEGER NOT NULL CHECK (
+        trail_amount > 0
+        AND trail_amount < 100
+    ),
+    high_water_mark event_contract.contract_price_cents NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS event_contract.trailing_stop;
+
+DROP TYPE IF EXISTS event_contract.trail_type;
+
+DELETE FROM event_contract.trigger WHERE trigger_type = 'TRAILING_STOP';
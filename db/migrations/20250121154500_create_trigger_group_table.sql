@@ -0,0 +1,25 @@
+-- migrate:up
+CREATE TYPE event_contract.trigger_group_type AS ENUM ('OCO');
+
+-- Groups link triggers that must be managed together, e.g. one-cancels-other brackets
+CREATE TABLE event_contract.trigger_group (
+    group_id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    group_type event_contract.trigger_group_type NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+ALTER TABLE event_contract.trigger
+ADD COLUMN group_id UUID REFERENCES event_contract.trigger_group (group_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_trigger_group ON event_contract.trigger (group_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS event_contract.idx_trigger_group;
+
+ALTER TABLE event_contract.trigger
+DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS event_contract.trigger_group;
+
+DROP TYPE IF EXISTS event_contract.trigger_group_type;
@@ -0,0 +1,52 @@
+-- migrate:up
+-- Conditions are boolean expression trees, so store them as a JSONB document on the trigger
+ALTER TABLE event_contract.trigger
+ADD COLUMN condition JSONB;
+
+UPDATE event_contract.trigger t
+SET
+    condition = jsonb_build_object(
+        'contract', jsonb_build_object('ticker', pc.contract_ticker, 'side', pc.contract_side),
+        'price', jsonb_build_object('threshold', pc.threshold_price, 'direction', pc.direction)
+    )
+FROM event_contract.price_trigger_condition pc
+WHERE pc.trigger_id = t.trigger_id;
+
+DELETE FROM event_contract.trigger WHERE condition IS NULL;
+
+ALTER TABLE event_contract.trigger
+ALTER COLUMN condition SET NOT NULL;
+
+DROP TABLE event_contract.price_trigger_condition;
+
+-- migrate:down
+CREATE TABLE event_contract.price_trigger_condition (
+    trigger_id UUID PRIMARY KEY REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    contract_ticker VARCHAR(255) NOT NULL,
+    contract_side event_contract.contract_side NOT NULL,
+    threshold_price event_contract.contract_price_cents NOT NULL,
+    direction event_contract.price_direction NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+-- Only a single price rule fits the old table. Composite, weather, forecast, time and
+-- event conditions have no representation there and are dropped
+DELETE FROM event_contract.trigger
+WHERE NOT (condition ? 'price' AND condition ? 'contract');
+
+INSERT INTO event_contract.price_trigger_condition (
+    trigger_id, contract_ticker, contract_side, threshold_price, direction, created_at, updated_at
+)
+SELECT
+    trigger_id,
+    condition -> 'contract' ->> 'ticker',
+    (condition -> 'contract' ->> 'side')::event_contract.contract_side,
+    (condition -> 'price' ->> 'threshold')::INTEGER,
+    (condition -> 'price' ->> 'direction')::event_contract.price_direction,
+    created_at,
+    updated_at
+FROM event_contract.trigger;
+
+ALTER TABLE event_contract.trigger
+DROP COLUMN condition;
@@ -0,0 +1,17 @@
+-- migrate:up
+CREATE TYPE event_contract.expiry_reason AS ENUM ('MARKET_CLOSED', 'MARKET_SETTLED');
+
+-- Why a trigger was expired, and how its market settled if it did
+CREATE TABLE event_contract.trigger_expiry (
+    trigger_id UUID PRIMARY KEY REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    reason event_contract.expiry_reason NOT NULL,
+    market_ticker VARCHAR(255) NOT NULL,
+    settlement_result VARCHAR(32),
+    expired_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS event_contract.trigger_expiry;
+
+DROP TYPE IF EXISTS event_contract.expiry_reason;
@@ -0,0 +1,13 @@
+-- migrate:up transaction:false
+ALTER TYPE event_contract.trigger_type ADD VALUE IF NOT EXISTS 'ENTRY';
+
+-- Caps the total spend of a buy action, in cents
+ALTER TABLE event_contract.trigger_action
+ADD COLUMN max_cost INTEGER CHECK (max_cost > 0);
+
+-- migrate:down
+ALTER TABLE event_contract.trigger_action
+DROP COLUMN IF EXISTS max_cost;
+
+-- Postgres cannot drop a value from an enum, so remove the triggers using it instead
+DELETE FROM event_contract.trigger WHERE trigger_type = 'ENTRY';
@@ -0,0 +1,15 @@
+-- migrate:up
+-- Confirmation policy for a trigger and the progress of its current satisfied streak
+CREATE TABLE event_contract.trigger_confirmation (
+    trigger_id UUID PRIMARY KEY REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    required_evaluations INTEGER NOT NULL DEFAULT 0 CHECK (required_evaluations >= 0),
+    required_duration_seconds INTEGER NOT NULL DEFAULT 0 CHECK (required_duration_seconds >= 0),
+    satisfied_count INTEGER NOT NULL DEFAULT 0 CHECK (satisfied_count >= 0),
+    satisfied_since TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    CHECK ((required_evaluations > 0) <> (required_duration_seconds > 0))
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS event_contract.trigger_confirmation;
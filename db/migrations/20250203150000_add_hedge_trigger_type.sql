@@ -0,0 +1,6 @@
+-- migrate:up transaction:false
+ALTER TYPE event_contract.trigger_type ADD VALUE IF NOT EXISTS 'HEDGE';
+
+-- migrate:down
+-- Postgres cannot drop a value from an enum, so remove the triggers using it instead
+DELETE FROM event_contract.trigger WHERE trigger_type = 'HEDGE';
@@ -0,0 +1,34 @@
+-- migrate:up transaction:false
+ALTER TYPE event_contract.trigger_status ADD VALUE IF NOT EXISTS 'FLAGGED';
+
+CREATE TYPE event_contract.guard_failure_action AS ENUM ('DEFER', 'FLAG');
+
+-- Pre-trade limits a trigger's markets must meet before its orders are sent
+CREATE TABLE event_contract.trigger_guard (
+    trigger_id UUID PRIMARY KEY REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    max_spread INTEGER CHECK (max_spread BETWEEN 1 AND 100),
+    min_liquidity INTEGER CHECK (min_liquidity >= 0),
+    min_volume_24h INTEGER CHECK (min_volume_24h >= 0),
+    min_depth INTEGER CHECK (min_depth >= 0),
+    on_failure event_contract.guard_failure_action NOT NULL DEFAULT 'DEFER',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+-- Most recent pre-trade check that held a trigger's orders back, kept for review
+CREATE TABLE event_contract.trigger_guard_failure (
+    trigger_id UUID PRIMARY KEY REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    action event_contract.guard_failure_action NOT NULL,
+    failed_at TIMESTAMP NOT NULL
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS event_contract.trigger_guard_failure;
+
+DROP TABLE IF EXISTS event_contract.trigger_guard;
+
+DROP TYPE IF EXISTS event_contract.guard_failure_action;
+
+-- Postgres cannot drop a value from an enum, so move flagged triggers back to active instead
+UPDATE event_contract.trigger SET status = 'ACTIVE' WHERE status = 'FLAGGED';
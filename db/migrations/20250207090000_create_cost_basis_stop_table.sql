@@ -0,0 +1,16 @@
+-- migrate:up
+-- Stop offsets relative to the position's average entry, and the entry the stop was last resolved from
+CREATE TABLE event_contract.cost_basis_stop (
+    trigger_id UUID PRIMARY KEY REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    offset_type event_contract.trail_type NOT NULL,
+    offset_amount INTEGER NOT NULL CHECK (
+        offset_amount > 0
+        AND offset_amount < 100
+    ),
+    average_entry event_contract.contract_price_cents NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS event_contract.cost_basis_stop;
@@ -0,0 +1,16 @@
+-- migrate:up transaction:false
+ALTER TYPE event_contract.trigger_group_type ADD VALUE IF NOT EXISTS 'LADDER';
+
+-- Sizes a sell against the live position, used by ladder tiers
+ALTER TABLE event_contract.trigger_action
+ADD COLUMN size_percent INTEGER CHECK (size_percent > 0 AND size_percent <= 100);
+
+-- migrate:down
+ALTER TABLE event_contract.trigger_action
+DROP COLUMN IF EXISTS size_percent;
+
+-- Postgres cannot drop a value from an enum, so remove the groups using it instead
+DELETE FROM event_contract.trigger WHERE group_id IN (
+    SELECT group_id FROM event_contract.trigger_group WHERE group_type = 'LADDER'
+);
+DELETE FROM event_contract.trigger_group WHERE group_type = 'LADDER';
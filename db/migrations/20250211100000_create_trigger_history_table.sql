@@ -0,0 +1,21 @@
+-- migrate:up
+CREATE TYPE event_contract.trigger_change_field AS ENUM ('STATUS', 'TRIGGER_PRICE', 'LIMIT_PRICE');
+
+-- Append-only audit trail of status and price changes; rows are never updated
+CREATE TABLE event_contract.trigger_history (
+    history_id BIGSERIAL PRIMARY KEY,
+    trigger_id UUID NOT NULL REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    field event_contract.trigger_change_field NOT NULL,
+    old_value VARCHAR(32),
+    new_value VARCHAR(32),
+    actor VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+CREATE INDEX idx_history_trigger ON event_contract.trigger_history (trigger_id, changed_at);
+
+-- migrate:down
+DROP TABLE IF EXISTS event_contract.trigger_history;
+
+DROP TYPE IF EXISTS event_contract.trigger_change_field;
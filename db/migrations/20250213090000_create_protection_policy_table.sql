@@ -0,0 +1,28 @@
+-- migrate:up
+-- Policies that place a cost basis stop on every new position in a series or event
+CREATE TABLE event_contract.protection_policy (
+    policy_id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    ticker VARCHAR(255) NOT NULL,
+    contract_side event_contract.contract_side, -- Nullable to cover both sides
+    offset_type event_contract.trail_type NOT NULL,
+    offset_amount INTEGER NOT NULL CHECK (
+        offset_amount > 0
+        AND offset_amount < 100
+    ),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+ALTER TABLE event_contract.trigger
+ADD COLUMN policy_id UUID REFERENCES event_contract.protection_policy (policy_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_trigger_policy ON event_contract.trigger (policy_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS event_contract.idx_trigger_policy;
+
+ALTER TABLE event_contract.trigger
+DROP COLUMN IF EXISTS policy_id;
+
+DROP TABLE IF EXISTS event_contract.protection_policy;
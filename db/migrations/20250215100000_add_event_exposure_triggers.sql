@@ -0,0 +1,31 @@
+-- migrate:up transaction:false
+ALTER TYPE event_contract.trigger_type ADD VALUE IF NOT EXISTS 'EVENT_EXPOSURE';
+
+-- Flatten actions sell every position in an event instead of a single contract
+ALTER TABLE event_contract.trigger_action
+ALTER COLUMN contract_ticker DROP NOT NULL,
+ALTER COLUMN contract_side DROP NOT NULL,
+ADD COLUMN event_ticker VARCHAR(255),
+ADD CONSTRAINT action_has_contract_or_event CHECK (
+    (
+        contract_ticker IS NOT NULL
+        AND contract_side IS NOT NULL
+        AND event_ticker IS NULL
+    )
+    OR (
+        event_ticker IS NOT NULL
+        AND contract_ticker IS NULL
+        AND contract_side IS NULL
+        AND order_side = 'SELL'
+    )
+);
+
+-- migrate:down
+-- Postgres cannot drop a value from an enum, so remove the triggers using it instead
+DELETE FROM event_contract.trigger WHERE trigger_type = 'EVENT_EXPOSURE';
+
+ALTER TABLE event_contract.trigger_action
+DROP CONSTRAINT IF EXISTS action_has_contract_or_event,
+DROP COLUMN IF EXISTS event_ticker,
+ALTER COLUMN contract_ticker SET NOT NULL,
+ALTER COLUMN contract_side SET NOT NULL;
@@ -0,0 +1,35 @@
+-- migrate:up
+-- Simulated orders placed while running in dry run mode
+CREATE TABLE event_contract.paper_order (
+    order_id UUID PRIMARY KEY,
+    reference VARCHAR(255) NOT NULL,
+    contract_ticker VARCHAR(255) NOT NULL,
+    contract_side event_contract.contract_side NOT NULL,
+    order_side event_contract.order_side NOT NULL,
+    order_type VARCHAR(16) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    limit_price event_contract.contract_price_cents, -- Nullable for market orders
+    filled_quantity INTEGER NOT NULL DEFAULT 0 CHECK (filled_quantity >= 0),
+    fill_price event_contract.contract_price_cents, -- Null until filled
+    created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+CREATE INDEX idx_paper_order_contract ON event_contract.paper_order (contract_ticker, contract_side);
+
+-- Simulated positions built up from paper order fills
+CREATE TABLE event_contract.paper_position (
+    contract_ticker VARCHAR(255) NOT NULL,
+    contract_side event_contract.contract_side NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    cost_basis INTEGER NOT NULL,
+    realized_pnl INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    PRIMARY KEY (contract_ticker, contract_side)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS event_contract.paper_position;
+
+DROP TABLE IF EXISTS event_contract.paper_order;
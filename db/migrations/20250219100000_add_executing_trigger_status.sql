@@ -0,0 +1,8 @@
+-- migrate:up transaction:false
+-- Triggers are marked executing before their orders are sent, so a crash between
+-- sending and recording the outcome can be reconciled instead of firing twice
+ALTER TYPE event_contract.trigger_status ADD VALUE IF NOT EXISTS 'EXECUTING';
+
+-- migrate:down
+-- Postgres cannot drop a value from an enum, so hold executing triggers for review instead
+UPDATE event_contract.trigger SET status = 'FLAGGED' WHERE status = 'EXECUTING';
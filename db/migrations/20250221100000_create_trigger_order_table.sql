@@ -0,0 +1,25 @@
+-- migrate:up
+-- Exchange orders sent by trigger executions, updated as the exchange reports their status
+CREATE TABLE event_contract.trigger_order (
+    order_id UUID PRIMARY KEY,
+    trigger_id UUID NOT NULL REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    action_index INTEGER NOT NULL CHECK (action_index >= 0),
+    exchange VARCHAR(16) NOT NULL,
+    exchange_order_id VARCHAR(255) NOT NULL,
+    reference VARCHAR(255) NOT NULL,
+    contract_ticker VARCHAR(255) NOT NULL,
+    contract_side event_contract.contract_side NOT NULL,
+    order_side event_contract.order_side NOT NULL,
+    order_type VARCHAR(16) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    limit_price event_contract.contract_price_cents, -- Nullable for market orders
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (exchange, exchange_order_id)
+);
+
+CREATE INDEX idx_trigger_order_trigger ON event_contract.trigger_order (trigger_id, action_index);
+
+-- migrate:down
+DROP TABLE IF EXISTS event_contract.trigger_order;
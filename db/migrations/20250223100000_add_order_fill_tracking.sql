@@ -0,0 +1,29 @@
+-- migrate:up
+CREATE TYPE event_contract.fill_action AS ENUM ('REPRICE', 'MARKET');
+
+-- How a trigger's orders are followed up when they rest unfilled past the timeout
+CREATE TABLE event_contract.trigger_fill_policy (
+    trigger_id UUID PRIMARY KEY REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE,
+    timeout_seconds INTEGER NOT NULL CHECK (timeout_seconds > 0),
+    action event_contract.fill_action NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW ()
+);
+
+-- Follow-ups replace the unfilled remainder of an order, so fills are tracked per order
+ALTER TABLE event_contract.trigger_order
+    ADD COLUMN attempt INTEGER NOT NULL DEFAULT 0 CHECK (attempt >= 0),
+    ADD COLUMN filled_quantity INTEGER NOT NULL DEFAULT 0 CHECK (filled_quantity >= 0);
+
+CREATE INDEX idx_trigger_order_status ON event_contract.trigger_order (status);
+
+-- migrate:down
+DROP INDEX IF EXISTS event_contract.idx_trigger_order_status;
+
+ALTER TABLE event_contract.trigger_order
+    DROP COLUMN IF EXISTS filled_quantity,
+    DROP COLUMN IF EXISTS attempt;
+
+DROP TABLE IF EXISTS event_contract.trigger_fill_policy;
+
+DROP TYPE IF EXISTS event_contract.fill_action;
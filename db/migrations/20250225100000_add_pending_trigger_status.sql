@@ -0,0 +1,18 @@
+-- migrate:up transaction:false
+-- A bracket's exits wait as pending until the entry that arms them has filled
+ALTER TYPE event_contract.trigger_status ADD VALUE IF NOT EXISTS 'PENDING';
+
+ALTER TABLE event_contract.trigger
+ADD COLUMN armed_by UUID REFERENCES event_contract.trigger (trigger_id) ON DELETE CASCADE;
+
+CREATE INDEX idx_trigger_armed_by ON event_contract.trigger (armed_by);
+
+-- migrate:down
+DROP INDEX IF EXISTS event_contract.idx_trigger_armed_by;
+
+ALTER TABLE event_contract.trigger
+DROP COLUMN IF EXISTS armed_by;
+
+-- Postgres cannot drop a value from an enum, and without their entry pending exits
+-- would never arm, so retire them
+UPDATE event_contract.trigger SET status = 'CANCELLED' WHERE status = 'PENDING';
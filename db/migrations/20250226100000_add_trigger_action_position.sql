@@ -0,0 +1,21 @@
+-- migrate:up
+-- A trigger's actions share its timestamps, so their order is stored explicitly
+ALTER TABLE event_contract.trigger_action
+ADD COLUMN position INTEGER CHECK (position >= 0);
+
+UPDATE event_contract.trigger_action action
+SET position = numbered.position
+FROM (
+    SELECT action_id, ROW_NUMBER() OVER (PARTITION BY trigger_id ORDER BY created_at, action_id) - 1 AS position
+    FROM event_contract.trigger_action
+) numbered
+WHERE action.action_id = numbered.action_id;
+
+ALTER TABLE event_contract.trigger_action
+ALTER COLUMN position SET NOT NULL,
+ADD CONSTRAINT trigger_action_position_unique UNIQUE (trigger_id, position);
+
+-- migrate:down
+ALTER TABLE event_contract.trigger_action
+DROP CONSTRAINT IF EXISTS trigger_action_position_unique,
+DROP COLUMN IF EXISTS position;
@@ -0,0 +1,8 @@
+-- migrate:up
+-- Interrupted executions that keep failing to reconcile are held for review after a few tries
+ALTER TABLE event_contract.trigger
+ADD COLUMN reconcile_attempts INTEGER NOT NULL DEFAULT 0 CHECK (reconcile_attempts >= 0);
+
+-- migrate:down
+ALTER TABLE event_contract.trigger
+DROP COLUMN IF EXISTS reconcile_attempts;
@@ -0,0 +1,23 @@
+-- migrate:up
+-- A follow-up is stored before its order is cancelled and cleared with the replacement, so
+-- a remainder cancelled but never replaced is picked up again on the next pass
+ALTER TABLE event_contract.trigger_order
+    ADD COLUMN follow_up_attempt INTEGER CHECK (follow_up_attempt > 0),
+    ADD COLUMN follow_up_remaining INTEGER CHECK (follow_up_remaining > 0),
+    ADD COLUMN follow_up_action event_contract.fill_action,
+    ADD CONSTRAINT trigger_order_follow_up_complete CHECK (
+        (follow_up_attempt IS NULL) = (follow_up_remaining IS NULL)
+        AND (follow_up_attempt IS NULL) = (follow_up_action IS NULL)
+    );
+
+CREATE INDEX idx_trigger_order_follow_up ON event_contract.trigger_order (created_at)
+    WHERE follow_up_attempt IS NOT NULL;
+
+-- migrate:down
+DROP INDEX IF EXISTS event_contract.idx_trigger_order_follow_up;
+
+ALTER TABLE event_contract.trigger_order
+    DROP CONSTRAINT IF EXISTS trigger_order_follow_up_complete,
+    DROP COLUMN IF EXISTS follow_up_action,
+    DROP COLUMN IF EXISTS follow_up_remaining,
+    DROP COLUMN IF EXISTS follow_up_attempt;
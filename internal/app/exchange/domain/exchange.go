@@ -4,4 +4,5 @@ type Exchange string
 
 const (
 	ExchangeKalshi Exchange = "KALSHI"
+	ExchangePaper  Exchange = "PAPER" // simulated fills against Kalshi prices
 )
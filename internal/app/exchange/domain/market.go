@@ -17,9 +17,13 @@ type Market struct {
 
 // MarketInfo holds the identifying and descriptive information about a market
 type MarketInfo struct {
-	Title    string     // Human-readable market title
-	Category string     // Market category (e.g., "Sports", "Politics")
-	Type     MarketType // Type of market (e.g., Binary, Numeric)
+	EventTicker  contract.Ticker // Event the market belongs to
+	Title        string          // Human-readable market title
+	Category     string          // Market category (e.g., "Sports", "Politics")
+	Type         MarketType      // Type of market (e.g., Binary, Numeric)
+	YesSubTitle  string          // Outcome the YES side pays on (e.g., "78° to 79°")
+	StrikePrice  *string         // Strike the outcome is measured against, if any
+	RulesPrimary string          // Settlement rules
 }
 
 // MarketStatus tracks the current state and timing of a market
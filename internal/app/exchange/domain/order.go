@@ -31,6 +31,16 @@ const (
 	OrderTypeMarket MarketOrderType = "MARKET"
 )
 
+// Order statuses, the same across exchanges. An order may fill while it is resting or
+// partially filled; executed and canceled orders are final, though a canceled order can
+// have filled in part before it was canceled.
+const (
+	OrderStatusResting         = "resting"
+	OrderStatusPartiallyFilled = "partially_filled"
+	OrderStatusExecuted        = "executed"
+	OrderStatusCanceled        = "canceled"
+)
+
 type Order struct {
 	OrderID         OrderID
 	ExchangeOrderID string
@@ -40,6 +50,9 @@ type Order struct {
 	Side            contract.Side
 	Action          OrderAction
 	OrderType       MarketOrderType
+	Quantity        uint
+	LimitPrice      *contract.ContractPrice // nil for market orders
+	FilledQuantity  uint
 	Status          string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
@@ -70,3 +83,16 @@ func NewOrder(
 		UpdatedAt:       currentTime,
 	}
 }
+
+// IsOpen reports whether the order can still fill
+func (o Order) IsOpen() bool {
+	return o.Status == OrderStatusResting || o.Status == OrderStatusPartiallyFilled
+}
+
+// Remaining returns the quantity that has not filled
+func (o Order) Remaining() uint {
+	if o.FilledQuantity >= o.Quantity {
+		return 0
+	}
+	return o.Quantity - o.FilledQuantity
+}
@@ -0,0 +1,28 @@
+package exchange_domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		order     Order
+		open      bool
+		remaining uint
+	}{
+		{"resting", Order{Status: OrderStatusResting, Quantity: 10}, true, 10},
+		{"partially filled", Order{Status: OrderStatusPartiallyFilled, Quantity: 10, FilledQuantity: 4}, true, 6},
+		{"executed", Order{Status: OrderStatusExecuted, Quantity: 10, FilledQuantity: 10}, false, 0},
+		{"canceled after a partial fill", Order{Status: OrderStatusCanceled, Quantity: 10, FilledQuantity: 4}, false, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.open, tt.order.IsOpen())
+			assert.Equal(t, tt.remaining, tt.order.Remaining())
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package exchange_domain
+
+import "prediction-risk/internal/app/contract"
+
+// Orderbook holds the resting bids on each side of a binary market. A NO bid at p
+// is an offer to sell YES at 100-p, so each side's bids are the other side's asks.
+type Orderbook struct {
+	Ticker  contract.Ticker
+	YesBids []OrderbookLevel
+	NoBids  []OrderbookLevel
+}
+
+// OrderbookLevel is the quantity resting at one price
+type OrderbookLevel struct {
+	Price    contract.ContractPrice
+	Quantity int
+}
+
+// Depth returns how many contracts are resting that an order to buy or sell
+// the given side could fill against
+func (o Orderbook) Depth(side contract.Side, action OrderAction) int {
+	// Sells hit their own side's bids, buys lift the other side's
+	yesBids := side == contract.SideYes
+	if action == OrderActionBuy {
+		yesBids = !yesBids
+	}
+	levels := o.NoBids
+	if yesBids {
+		levels = o.YesBids
+	}
+
+	depth := 0
+	for _, level := range levels {
+		depth += level.Quantity
+	}
+	return depth
+}
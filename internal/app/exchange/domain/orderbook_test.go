@@ -0,0 +1,34 @@
+package exchange_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderbook_Depth(t *testing.T) {
+	orderbook := Orderbook{
+		Ticker:  "FOO",
+		YesBids: []OrderbookLevel{{Price: 40, Quantity: 10}, {Price: 42, Quantity: 5}},
+		NoBids:  []OrderbookLevel{{Price: 55, Quantity: 3}},
+	}
+
+	tests := []struct {
+		name     string
+		side     contract.Side
+		action   OrderAction
+		expected int
+	}{
+		{"sell yes hits yes bids", contract.SideYes, OrderActionSell, 15},
+		{"buy yes lifts no bids", contract.SideYes, OrderActionBuy, 3},
+		{"sell no hits no bids", contract.SideNo, OrderActionSell, 3},
+		{"buy no lifts yes bids", contract.SideNo, OrderActionBuy, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, orderbook.Depth(tt.side, tt.action))
+		})
+	}
+}
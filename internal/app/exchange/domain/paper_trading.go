@@ -0,0 +1,81 @@
+package exchange_domain
+
+import (
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	"time"
+)
+
+// Statuses of paper orders, which fill in full or are canceled as soon as they are placed
+const (
+	PaperOrderExecuted = OrderStatusExecuted
+	PaperOrderCanceled = OrderStatusCanceled
+)
+
+// PaperFill is the simulated execution of a paper order
+type PaperFill struct {
+	Quantity uint
+	Price    contract.ContractPrice
+}
+
+// Cost returns the cents exchanged for the fill
+func (f PaperFill) Cost() int {
+	return int(f.Quantity) * f.Price.Value()
+}
+
+// PaperOrder is an order placed with the paper exchange, with its fill if it had one
+type PaperOrder struct {
+	Order
+	Fill *PaperFill // nil if the order was not marketable
+}
+
+// PaperPosition is a simulated position built up from paper fills
+type PaperPosition struct {
+	Position
+	RealizedPNL int // cents locked in by sells
+	UpdatedAt   time.Time
+}
+
+func NewPaperPosition(contractID contract.ContractIdentifier) *PaperPosition {
+	return &PaperPosition{
+		Position:  Position{ContractID: contractID},
+		UpdatedAt: time.Now(),
+	}
+}
+
+// ApplyFill adds bought contracts to the position, or removes sold ones at their
+// average cost and realizes the difference
+func (p *PaperPosition) ApplyFill(action OrderAction, fill PaperFill) error {
+	switch action {
+	case OrderActionBuy:
+		p.Quantity += fill.Quantity
+		p.CostBasis += fill.Cost()
+	case OrderActionSell:
+		if fill.Quantity > p.Quantity {
+			return fmt.Errorf("cannot sell %d contracts of a %d contract position", fill.Quantity, p.Quantity)
+		}
+		soldCost := p.CostBasis * int(fill.Quantity) / int(p.Quantity)
+		p.Quantity -= fill.Quantity
+		p.CostBasis -= soldCost
+		p.RealizedPNL += fill.Cost() - soldCost
+	default:
+		return fmt.Errorf("invalid order action: %s", action)
+	}
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// UnrealizedPNL marks the open contracts to the price they could be sold at
+func (p PaperPosition) UnrealizedPNL(bid contract.ContractPrice) int {
+	return int(p.Quantity)*bid.Value() - p.CostBasis
+}
+
+// PaperPNL is the simulated profit and loss across every paper position, in cents
+type PaperPNL struct {
+	Realized   int
+	Unrealized int
+}
+
+func (p PaperPNL) Total() int {
+	return p.Realized + p.Unrealized
+}
@@ -0,0 +1,44 @@
+package exchange_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaperPosition_ApplyFill(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	t.Run("buys accumulate cost basis", func(t *testing.T) {
+		position := NewPaperPosition(foo)
+		require.NoError(t, position.ApplyFill(OrderActionBuy, PaperFill{Quantity: 10, Price: 40}))
+		require.NoError(t, position.ApplyFill(OrderActionBuy, PaperFill{Quantity: 10, Price: 50}))
+
+		assert.Equal(t, uint(20), position.Quantity)
+		assert.Equal(t, 900, position.CostBasis)
+		assert.Equal(t, 0, position.RealizedPNL)
+		assert.Equal(t, 100, position.UnrealizedPNL(50))
+	})
+
+	t.Run("sells realize against average cost", func(t *testing.T) {
+		position := NewPaperPosition(foo)
+		require.NoError(t, position.ApplyFill(OrderActionBuy, PaperFill{Quantity: 20, Price: 45}))
+		require.NoError(t, position.ApplyFill(OrderActionSell, PaperFill{Quantity: 5, Price: 60}))
+
+		assert.Equal(t, uint(15), position.Quantity)
+		assert.Equal(t, 675, position.CostBasis)
+		assert.Equal(t, 75, position.RealizedPNL)
+	})
+
+	t.Run("cannot sell more than held", func(t *testing.T) {
+		position := NewPaperPosition(foo)
+		require.NoError(t, position.ApplyFill(OrderActionBuy, PaperFill{Quantity: 5, Price: 45}))
+
+		err := position.ApplyFill(OrderActionSell, PaperFill{Quantity: 6, Price: 60})
+
+		assert.ErrorContains(t, err, "cannot sell 6 contracts")
+		assert.Equal(t, uint(5), position.Quantity)
+	})
+}
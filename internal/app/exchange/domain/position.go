@@ -1,8 +1,38 @@
 package exchange_domain
 
-import "prediction-risk/internal/app/contract"
+import (
+	"prediction-risk/internal/app/contract"
+	"strings"
+)
 
 type Position struct {
 	ContractID contract.ContractIdentifier
 	Quantity   uint
+	CostBasis  int // total cents paid for the contracts traded in this market
+}
+
+// AverageEntry returns the average price paid per contract, rounded to the
+// nearest cent, or false if there is no position to average over
+func (p Position) AverageEntry() (contract.ContractPrice, bool) {
+	if p.Quantity == 0 || p.CostBasis <= 0 {
+		return 0, false
+	}
+	quantity := int(p.Quantity)
+	return contract.ContractPrice((p.CostBasis + quantity/2) / quantity), true
+}
+
+// EventPosition is the exposure held across every market in an event
+type EventPosition struct {
+	EventTicker contract.Ticker
+	Exposure    int         // cents paid for the contracts still held
+	RealizedPNL int         // cents locked in by closed trades
+	FeesPaid    int         // cents
+	TotalCost   int         // cents traded across the event
+	Positions   []*Position // open positions in the event's markets
+}
+
+// Contains reports whether the market belongs to the event. Kalshi market
+// tickers extend their event ticker, e.g. KXHIGHNY-25FEB13-T40 in KXHIGHNY-25FEB13.
+func (e EventPosition) Contains(ticker contract.Ticker) bool {
+	return strings.HasPrefix(string(ticker), string(e.EventTicker)+"-")
 }
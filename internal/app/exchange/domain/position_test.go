@@ -0,0 +1,30 @@
+package exchange_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPosition_AverageEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		position Position
+		expected contract.ContractPrice
+		ok       bool
+	}{
+		{"even average", Position{Quantity: 10, CostBasis: 420}, 42, true},
+		{"rounds to nearest cent", Position{Quantity: 3, CostBasis: 100}, 33, true},
+		{"no contracts", Position{Quantity: 0, CostBasis: 420}, 0, false},
+		{"no cost basis", Position{Quantity: 10}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := tt.position.AverageEntry()
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, entry)
+		})
+	}
+}
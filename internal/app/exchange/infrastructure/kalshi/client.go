@@ -1,6 +1,7 @@
 package kalshi
 
 import (
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
@@ -53,22 +54,40 @@ func newClient(host, keyID string, privateKey *rsa.PrivateKey) *client {
 	}
 }
 
-func (kc *client) rateLimit() {
-	kc.mutex.Lock()
-	defer kc.mutex.Unlock()
-
+// rateLimit waits for the next free request slot. The slot is reserved under the mutex
+// but waited for outside it, so a cancelled caller does not hold up the others.
+func (kc *client) rateLimit(ctx context.Context) error {
 	const thresholdInMilliseconds = 100
 	threshold := time.Duration(thresholdInMilliseconds) * time.Millisecond
 
-	now := time.Now()
-	if now.Sub(kc.lastAPICall) < threshold {
-		time.Sleep(threshold - now.Sub(kc.lastAPICall))
+	kc.mutex.Lock()
+	slot := time.Now()
+	if next := kc.lastAPICall.Add(threshold); next.After(slot) {
+		slot = next
+	}
+	kc.lastAPICall = slot
+	kc.mutex.Unlock()
+
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
-	kc.lastAPICall = time.Now()
 }
 
-func (kc *client) get(path string, params map[string]string) (*http.Response, error) {
-	kc.rateLimit()
+func (kc *client) get(ctx context.Context, path string, params map[string]string) (*http.Response, error) {
+	if err := kc.rateLimit(ctx); err != nil {
+		return nil, err
+	}
 
 	fullURL := kc.host + path
 	if params != nil && len(params) > 0 {
@@ -76,7 +95,7 @@ func (kc *client) get(path string, params map[string]string) (*http.Response, er
 		fullURL += query
 	}
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -93,8 +112,10 @@ func (kc *client) get(path string, params map[string]string) (*http.Response, er
 	return kc.httpClient.Do(req)
 }
 
-func (kc *client) post(path string, body interface{}) (*http.Response, error) {
-	kc.rateLimit()
+func (kc *client) post(ctx context.Context, path string, body interface{}) (*http.Response, error) {
+	if err := kc.rateLimit(ctx); err != nil {
+		return nil, err
+	}
 
 	fullURL := kc.host + path
 
@@ -103,7 +124,7 @@ func (kc *client) post(path string, body interface{}) (*http.Response, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fullURL, strings.NewReader(string(reqBody)))
+	req, err := http.NewRequestWithContext(ctx, "POST", fullURL, strings.NewReader(string(reqBody)))
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +141,28 @@ func (kc *client) post(path string, body interface{}) (*http.Response, error) {
 	return kc.httpClient.Do(req)
 }
 
+func (kc *client) delete(ctx context.Context, path string) (*http.Response, error) {
+	if err := kc.rateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", kc.host+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := kc.requestHeaders("DELETE", path)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	return kc.httpClient.Do(req)
+}
+
 func (kc *client) requestHeaders(method, path string) (map[string]string, error) {
 	currentTimeMilliseconds := time.Now().UnixNano() / int64(time.Millisecond)
 	timestampStr := fmt.Sprintf("%d", currentTimeMilliseconds)
@@ -0,0 +1,63 @@
+package kalshi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_RateLimit(t *testing.T) {
+	t.Run("spaces out consecutive requests", func(t *testing.T) {
+		client := newClient("http://localhost", "test-key", nil)
+		client.lastAPICall = time.Time{}
+
+		start := time.Now()
+		assert.NoError(t, client.rateLimit(context.Background()))
+		assert.NoError(t, client.rateLimit(context.Background()))
+
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("stops waiting when the context is cancelled", func(t *testing.T) {
+		client := newClient("http://localhost", "test-key", nil)
+		client.lastAPICall = time.Now().Add(time.Hour)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		start := time.Now()
+		err := client.rateLimit(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("does not hold up other callers while waiting", func(t *testing.T) {
+		client := newClient("http://localhost", "test-key", nil)
+		client.lastAPICall = time.Now().Add(time.Hour)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		waiting := make(chan error)
+		go func() { waiting <- client.rateLimit(ctx) }()
+
+		// A second caller can reserve its slot while the first is still waiting
+		reserved := make(chan struct{})
+		go func() {
+			cancelled, cancelSecond := context.WithCancel(context.Background())
+			cancelSecond()
+			client.rateLimit(cancelled)
+			close(reserved)
+		}()
+
+		select {
+		case <-reserved:
+		case <-time.After(time.Second):
+			t.Fatal("second caller blocked behind the first")
+		}
+
+		cancel()
+		assert.ErrorIs(t, <-waiting, context.Canceled)
+	})
+}
@@ -1,6 +1,7 @@
 package kalshi
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -14,27 +15,27 @@ func newEventClient(client *client) *eventClient {
 	return &eventClient{client}
 }
 
-func (c *eventClient) GetEvent(eventTicker string) (*EventResponse, error) {
-	resp, err := c.client.get(eventsPath+"/"+eventTicker, nil)
+func (c *eventClient) GetEvent(ctx context.Context, eventTicker string) (*EventResponse, error) {
+	resp, err := c.client.get(ctx, eventsPath+"/"+eventTicker, nil)
 	if err != nil {
 		return nil, err
 	}
 	return handleResponse[EventResponse](resp)
 }
 
-func (c *eventClient) GetEvents(params GetEventsOptions) (*EventsResult, error) {
+func (c *eventClient) GetEvents(ctx context.Context, params GetEventsOptions) (*EventsResult, error) {
 	result := &EventsResult{
 		Events: make([]Event, 0),
 	}
 
-	if err := c.collectAllEvents(params, result); err != nil {
+	if err := c.collectAllEvents(ctx, params, result); err != nil {
 		return nil, fmt.Errorf("collecting events: %w", err)
 	}
 
 	return result, nil
 }
 
-func (c *eventClient) collectAllEvents(params GetEventsOptions, result *EventsResult) error {
+func (c *eventClient) collectAllEvents(ctx context.Context, params GetEventsOptions, result *EventsResult) error {
 	var cursor *string
 	var remaining int
 	if params.Limit != nil {
@@ -50,7 +51,7 @@ func (c *eventClient) collectAllEvents(params GetEventsOptions, result *EventsRe
 			pageSize = 200
 		}
 
-		page, err := c.fetchPage(params, cursor, &pageSize)
+		page, err := c.fetchPage(ctx, params, cursor, &pageSize)
 		if err != nil {
 			return fmt.Errorf("fetching page: %w", err)
 		}
@@ -73,8 +74,8 @@ func (c *eventClient) collectAllEvents(params GetEventsOptions, result *EventsRe
 	return nil
 }
 
-func (c *eventClient) fetchPage(params GetEventsOptions, cursor *string, limit *int) (*EventsResponse, error) {
-	resp, err := c.client.get(eventsPath, eventParamsToMap(params, cursor, limit))
+func (c *eventClient) fetchPage(ctx context.Context, params GetEventsOptions, cursor *string, limit *int) (*EventsResponse, error) {
+	resp, err := c.client.get(ctx, eventsPath, eventParamsToMap(params, cursor, limit))
 	if err != nil {
 		return nil, fmt.Errorf("API request failed: %w", err)
 	}
@@ -1,6 +1,7 @@
 package kalshi
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
@@ -55,7 +56,7 @@ func TestEventClient(t *testing.T) {
 			require.NoError(t, err)
 
 			// Act
-			result, err := client.GetEvent("SHUTDOWNBY-24")
+			result, err := client.GetEvent(context.Background(), "SHUTDOWNBY-24")
 
 			// Assert
 			assert.NoError(t, err)
@@ -74,7 +75,7 @@ func TestEventClient(t *testing.T) {
 
 			client, err := setupTestClient(server.URL)
 			require.NoError(t, err)
-			result, err := client.GetEvent("INVALID-EVENT")
+			result, err := client.GetEvent(context.Background(), "INVALID-EVENT")
 
 			assert.Error(t, err)
 			assert.Nil(t, result)
@@ -131,7 +132,7 @@ func TestEventClient(t *testing.T) {
 				WithSeriesTicker("SERIES1").
 				WithStatuses([]string{"active"})
 
-			result, err := client.GetEvents(options)
+			result, err := client.GetEvents(context.Background(), options)
 
 			fmt.Printf("RESULT: %d", len(result.Events))
 
@@ -158,7 +159,7 @@ func TestEventClient(t *testing.T) {
 			require.NoError(t, err)
 
 			options := NewGetEventsOptions().WithLimit(2)
-			result, err := client.GetEvents(options)
+			result, err := client.GetEvents(context.Background(), options)
 
 			assert.NoError(t, err)
 			assert.Equal(t, 2, len(result.Events))
@@ -175,7 +176,7 @@ func TestEventClient(t *testing.T) {
 			require.NoError(t, err)
 
 			options := NewGetEventsOptions().WithLimit(10)
-			result, err := client.GetEvents(options)
+			result, err := client.GetEvents(context.Background(), options)
 
 			assert.Error(t, err)
 			assert.Nil(t, result)
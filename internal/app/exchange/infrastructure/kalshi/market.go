@@ -1,6 +1,7 @@
 package kalshi
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -15,27 +16,41 @@ func NewMarketClient(client *client) *marketClient {
 	return &marketClient{client}
 }
 
-func (c *marketClient) GetMarket(ticker string) (*MarketResponse, error) {
-	resp, err := c.client.get(marketsPath+"/"+ticker, nil)
+func (c *marketClient) GetMarket(ctx context.Context, ticker string) (*MarketResponse, error) {
+	resp, err := c.client.get(ctx, marketsPath+"/"+ticker, nil)
 	if err != nil {
 		return nil, err
 	}
 	return handleResponse[MarketResponse](resp)
 }
 
-func (c *marketClient) GetMarkets(params GetMarketsOptions) (*MarketsResult, error) {
+// GetMarketOrderbook returns the resting bids for a market, limited to depth
+// price levels per side if depth is set
+func (c *marketClient) GetMarketOrderbook(ctx context.Context, ticker string, depth *int) (*OrderbookResponse, error) {
+	params := make(map[string]string)
+	if depth != nil {
+		params["depth"] = strconv.Itoa(*depth)
+	}
+	resp, err := c.client.get(ctx, marketsPath+"/"+ticker+"/orderbook", params)
+	if err != nil {
+		return nil, err
+	}
+	return handleResponse[OrderbookResponse](resp)
+}
+
+func (c *marketClient) GetMarkets(ctx context.Context, params GetMarketsOptions) (*MarketsResult, error) {
 	result := &MarketsResult{
 		Markets: make([]Market, 0),
 	}
 
-	if err := c.collectAllMarkets(params, result); err != nil {
+	if err := c.collectAllMarkets(ctx, params, result); err != nil {
 		return nil, fmt.Errorf("collecting markets: %w", err)
 	}
 
 	return result, nil
 }
 
-func (c *marketClient) collectAllMarkets(params GetMarketsOptions, result *MarketsResult) error {
+func (c *marketClient) collectAllMarkets(ctx context.Context, params GetMarketsOptions, result *MarketsResult) error {
 	var cursor *string
 	var remaining int
 	if params.Limit != nil {
@@ -51,7 +66,7 @@ func (c *marketClient) collectAllMarkets(params GetMarketsOptions, result *Marke
 			pageSize = 1000
 		}
 
-		page, err := c.fetchPage(params, cursor, &pageSize)
+		page, err := c.fetchPage(ctx, params, cursor, &pageSize)
 		if err != nil {
 			return fmt.Errorf("fetching page: %w", err)
 		}
@@ -74,8 +89,8 @@ func (c *marketClient) collectAllMarkets(params GetMarketsOptions, result *Marke
 	return nil
 }
 
-func (c *marketClient) fetchPage(params GetMarketsOptions, cursor *string, limit *int) (*MarketsResponse, error) {
-	resp, err := c.client.get(marketsPath, marketParamsToMap(params, cursor, limit))
+func (c *marketClient) fetchPage(ctx context.Context, params GetMarketsOptions, cursor *string, limit *int) (*MarketsResponse, error) {
+	resp, err := c.client.get(ctx, marketsPath, marketParamsToMap(params, cursor, limit))
 	if err != nil {
 		return nil, fmt.Errorf("API request failed: %w", err)
 	}
@@ -92,7 +107,7 @@ func marketParamsToMap(params GetMarketsOptions, cursor *string, limit *int) map
 		result["limit"] = strconv.Itoa(*limit)
 	}
 	if params.Tickers != nil {
-		result["ticker"] = strings.Join(*params.Tickers, ",")
+		result["tickers"] = strings.Join(*params.Tickers, ",")
 	}
 	if params.EventTicker != nil {
 		result["event_ticker"] = *params.EventTicker
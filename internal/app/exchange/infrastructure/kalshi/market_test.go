@@ -1,6 +1,7 @@
 package kalshi
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
@@ -49,7 +50,7 @@ func TestMarketClient(t *testing.T) {
 			require.NoError(t, err)
 
 			// Act
-			result, err := client.GetMarket("SHUTDOWNBY-24")
+			result, err := client.GetMarket(context.Background(), "SHUTDOWNBY-24")
 
 			// Assert
 			assert.NoError(t, err)
@@ -69,12 +70,34 @@ func TestMarketClient(t *testing.T) {
 			client, err := setupTestMarketClient(server.URL)
 			require.NoError(t, err)
 
-			result, err := client.GetMarket("INVALID-MARKET")
+			result, err := client.GetMarket(context.Background(), "INVALID-MARKET")
 			assert.Error(t, err)
 			assert.Nil(t, result)
 		})
 	})
 
+	t.Run("GetMarketOrderbook", func(t *testing.T) {
+		t.Run("successfully gets orderbook", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/trade-api/v2/markets/SHUTDOWNBY-24/orderbook", r.URL.Path)
+				assert.Equal(t, "5", r.URL.Query().Get("depth"))
+
+				w.Write([]byte(`{"orderbook": {"yes": [[58, 10], [60, 25]], "no": null}}`))
+			}))
+			defer server.Close()
+
+			client, err := setupTestMarketClient(server.URL)
+			require.NoError(t, err)
+
+			depth := 5
+			result, err := client.GetMarketOrderbook(context.Background(), "SHUTDOWNBY-24", &depth)
+
+			assert.NoError(t, err)
+			assert.Equal(t, [][]int{{58, 10}, {60, 25}}, result.Orderbook.Yes)
+			assert.Empty(t, result.Orderbook.No)
+		})
+	})
+
 	t.Run("GetMarkets", func(t *testing.T) {
 		t.Run("successfully gets paginated markets with filters", func(t *testing.T) {
 			var callCount int
@@ -126,7 +149,7 @@ func TestMarketClient(t *testing.T) {
 				WithSeriesTicker("SERIES1").
 				WithMaxCloseTime(now.Add(24 * time.Hour))
 
-			result, err := client.GetMarkets(options)
+			result, err := client.GetMarkets(context.Background(), options)
 
 			assert.NoError(t, err)
 			assert.NotNil(t, result)
@@ -137,7 +160,7 @@ func TestMarketClient(t *testing.T) {
 
 		t.Run("handles market query parameters", func(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				assert.Equal(t, "MARKET1,MARKET2", r.URL.Query().Get("ticker"))
+				assert.Equal(t, "MARKET1,MARKET2", r.URL.Query().Get("tickers"))
 				assert.Equal(t, "EVENT1", r.URL.Query().Get("event_ticker"))
 				assert.Equal(t, "open,settled", r.URL.Query().Get("status"))
 
@@ -159,7 +182,7 @@ func TestMarketClient(t *testing.T) {
 				WithEventTicker("EVENT1").
 				WithStatus([]string{"open", "settled"})
 
-			result, err := client.GetMarkets(options)
+			result, err := client.GetMarkets(context.Background(), options)
 			assert.NoError(t, err)
 			assert.Equal(t, 2, len(result.Markets))
 		})
@@ -185,7 +208,7 @@ func TestMarketClient(t *testing.T) {
 			options := NewGetMarketsOptions()
 			options.Limit = &limit
 
-			result, err := client.GetMarkets(options)
+			result, err := client.GetMarkets(context.Background(), options)
 			assert.NoError(t, err)
 			assert.Equal(t, 2, len(result.Markets))
 		})
@@ -48,6 +48,11 @@ func (o GetMarketsOptions) WithStatus(status []string) GetMarketsOptions {
 	return o
 }
 
+func (o GetMarketsOptions) WithLimit(limit int) GetMarketsOptions {
+	o.Limit = &limit
+	return o
+}
+
 type Market struct {
 	// Market Information
 	Ticker             string     `json:"ticker"`
@@ -124,6 +129,17 @@ type MarketsResult struct {
 	Markets []Market
 }
 
+// Orderbook lists the resting bids on each side as [price, quantity] levels.
+// Kalshi omits a side entirely when it has no bids.
+type Orderbook struct {
+	Yes [][]int `json:"yes"`
+	No  [][]int `json:"no"`
+}
+
+type OrderbookResponse struct {
+	Orderbook Orderbook `json:"orderbook"`
+}
+
 type MarketType string
 
 const (
@@ -138,4 +154,10 @@ const (
 	MarketStatusOpen     MarketStatus = "open"
 	MarketStatusClosed   MarketStatus = "closed"
 	MarketStatusSettled  MarketStatus = "settled"
+
+	// Lifecycle values returned by the markets API in place of the above
+	MarketStatusInitialized MarketStatus = "initialized"
+	MarketStatusActive      MarketStatus = "active"
+	MarketStatusDetermined  MarketStatus = "determined"
+	MarketStatusFinalized   MarketStatus = "finalized"
 )
@@ -1,6 +1,7 @@
 package kalshi_mocks
 
 import (
+	"context"
 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
 
 	"github.com/stretchr/testify/mock"
@@ -10,16 +11,16 @@ type MockEventService struct {
 	mock.Mock
 }
 
-func (m *MockMarketService) GetEvent(eventTicker string) (*kalshi.EventResponse, error) {
-	args := m.Called(eventTicker)
+func (m *MockMarketService) GetEvent(ctx context.Context, eventTicker string) (*kalshi.EventResponse, error) {
+	args := m.Called(ctx, eventTicker)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*kalshi.EventResponse), args.Error(1)
 }
 
-func (m *MockMarketService) GetEvents(params kalshi.GetEventsOptions) (*kalshi.EventsResult, error) {
-	args := m.Called(params)
+func (m *MockMarketService) GetEvents(ctx context.Context, params kalshi.GetEventsOptions) (*kalshi.EventsResult, error) {
+	args := m.Called(ctx, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
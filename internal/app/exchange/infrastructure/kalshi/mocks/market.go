@@ -1,6 +1,7 @@
 package kalshi_mocks
 
 import (
+	"context"
 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
 
 	"github.com/stretchr/testify/mock"
@@ -10,18 +11,26 @@ type MockMarketService struct {
 	mock.Mock
 }
 
-func (m *MockMarketService) GetMarket(ticker string) (*kalshi.MarketResponse, error) {
-	args := m.Called(ticker)
+func (m *MockMarketService) GetMarket(ctx context.Context, ticker string) (*kalshi.MarketResponse, error) {
+	args := m.Called(ctx, ticker)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*kalshi.MarketResponse), args.Error(1)
 }
 
-func (m *MockMarketService) GetMarkets(params kalshi.GetMarketsOptions) (*kalshi.MarketsResult, error) {
-	args := m.Called(params)
+func (m *MockMarketService) GetMarkets(ctx context.Context, params kalshi.GetMarketsOptions) (*kalshi.MarketsResult, error) {
+	args := m.Called(ctx, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*kalshi.MarketsResult), args.Error(1)
 }
+
+func (m *MockMarketService) GetMarketOrderbook(ctx context.Context, ticker string, depth *int) (*kalshi.OrderbookResponse, error) {
+	args := m.Called(ctx, ticker, depth)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kalshi.OrderbookResponse), args.Error(1)
+}
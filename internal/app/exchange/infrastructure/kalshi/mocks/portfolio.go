@@ -1,6 +1,7 @@
 package kalshi_mocks
 
 import (
+	"context"
 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
 
 	"github.com/stretchr/testify/mock"
@@ -10,16 +11,16 @@ type MockPortfolioService struct {
 	mock.Mock
 }
 
-func (m *MockPortfolioService) CreateOrder(request kalshi.CreateOrderRequest) (*kalshi.CreateOrderResponse, error) {
-	args := m.Called(request)
+func (m *MockPortfolioService) CreateOrder(ctx context.Context, request kalshi.CreateOrderRequest) (*kalshi.CreateOrderResponse, error) {
+	args := m.Called(ctx, request)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*kalshi.CreateOrderResponse), args.Error(1)
 }
 
-func (m *MockPortfolioService) GetPositions(options kalshi.GetPositionsOptions) (*kalshi.PositionsResult, error) {
-	args := m.Called(options)
+func (m *MockPortfolioService) GetPositions(ctx context.Context, options kalshi.GetPositionsOptions) (*kalshi.PositionsResult, error) {
+	args := m.Called(ctx, options)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -1,6 +1,7 @@
 package kalshi
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
@@ -13,21 +14,59 @@ func NewPortfolioClient(client *client) *portfolioClient {
 	return &portfolioClient{client}
 }
 
-func (c *portfolioClient) CreateOrder(order CreateOrderRequest) (*CreateOrderResponse, error) {
-	resp, err := c.client.post(portfolioPath+"/order", order)
+func (c *portfolioClient) CreateOrder(ctx context.Context, order CreateOrderRequest) (*CreateOrderResponse, error) {
+	resp, err := c.client.post(ctx, portfolioPath+"/order", order)
 	if err != nil {
 		return nil, err
 	}
 	return handleResponse[CreateOrderResponse](resp)
 }
 
-func (c *portfolioClient) GetPositions(params GetPositionsOptions) (*PositionsResult, error) {
+// CancelOrder cancels whatever of the order is still resting. Kalshi returns the order
+// as it stands afterwards, with any fills it had already received.
+func (c *portfolioClient) CancelOrder(ctx context.Context, orderID string) (*CancelOrderResponse, error) {
+	resp, err := c.client.delete(ctx, portfolioPath+"/orders/"+orderID)
+	if err != nil {
+		return nil, err
+	}
+	return handleResponse[CancelOrderResponse](resp)
+}
+
+// GetOrders lists the orders placed on the account, following the cursor through every page
+func (c *portfolioClient) GetOrders(ctx context.Context, params GetOrdersOptions) (*OrdersResult, error) {
+	result := &OrdersResult{
+		Orders: make([]Order, 0),
+	}
+
+	var cursor *string
+	for {
+		resp, err := c.client.get(ctx, portfolioPath+"/orders", ordersParamsToMap(params, cursor))
+		if err != nil {
+			return nil, fmt.Errorf("API request failed: %w", err)
+		}
+		page, err := handleResponse[OrdersResponse](resp)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Orders = append(result.Orders, page.Orders...)
+
+		if page.Cursor == nil || *page.Cursor == "" || len(page.Orders) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	return result, nil
+}
+
+func (c *portfolioClient) GetPositions(ctx context.Context, params GetPositionsOptions) (*PositionsResult, error) {
 	result := &PositionsResult{
 		MarketPositions: make([]MarketPosition, 0),
 		EventPositions:  make([]EventPosition, 0),
 	}
 
-	if err := c.collectAllPositions(params, result); err != nil {
+	if err := c.collectAllPositions(ctx, params, result); err != nil {
 		return nil, fmt.Errorf("collecting positions: %w", err)
 	}
 
@@ -35,11 +74,11 @@ func (c *portfolioClient) GetPositions(params GetPositionsOptions) (*PositionsRe
 }
 
 // collectAllPositions is clearer than "recursive" in the name
-func (c *portfolioClient) collectAllPositions(params GetPositionsOptions, result *PositionsResult) error {
+func (c *portfolioClient) collectAllPositions(ctx context.Context, params GetPositionsOptions, result *PositionsResult) error {
 	var cursor *string
 
 	for {
-		page, err := c.fetchPage(params, cursor, nil)
+		page, err := c.fetchPage(ctx, params, cursor, nil)
 		if err != nil {
 			return fmt.Errorf("fetching page: %w", err)
 		}
@@ -56,8 +95,8 @@ func (c *portfolioClient) collectAllPositions(params GetPositionsOptions, result
 	return nil
 }
 
-func (c *portfolioClient) fetchPage(params GetPositionsOptions, cursor *string, limit *int) (*PositionsResponse, error) {
-	resp, err := c.client.get(portfolioPath+"/positions", portfolioParamsToMap(params, cursor, limit))
+func (c *portfolioClient) fetchPage(ctx context.Context, params GetPositionsOptions, cursor *string, limit *int) (*PositionsResponse, error) {
+	resp, err := c.client.get(ctx, portfolioPath+"/positions", portfolioParamsToMap(params, cursor, limit))
 	if err != nil {
 		return nil, fmt.Errorf("API request failed: %w", err)
 	}
@@ -84,3 +123,20 @@ func portfolioParamsToMap(params GetPositionsOptions, cursor *string, limit *int
 	}
 	return result
 }
+
+func ordersParamsToMap(params GetOrdersOptions, cursor *string) map[string]string {
+	result := make(map[string]string)
+	if cursor != nil {
+		result["cursor"] = *cursor
+	}
+	if params.Ticker != nil {
+		result["ticker"] = *params.Ticker
+	}
+	if params.MinTs != nil {
+		result["min_ts"] = strconv.FormatInt(params.MinTs.Unix(), 10)
+	}
+	if params.Status != nil {
+		result["status"] = *params.Status
+	}
+	return result
+}
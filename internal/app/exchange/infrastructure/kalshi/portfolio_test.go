@@ -1,6 +1,7 @@
 package kalshi
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
@@ -73,7 +74,7 @@ func TestPortfolioClient(t *testing.T) {
 				Type:          OrderTypeLimit,
 				YesPrice:      &yesPrice,
 			}
-			result, err := client.CreateOrder(request)
+			result, err := client.CreateOrder(context.Background(), request)
 
 			// Assert
 			assert.NoError(t, err)
@@ -100,12 +101,94 @@ func TestPortfolioClient(t *testing.T) {
 				Count:  -1, // Invalid count
 			}
 
-			result, err := client.CreateOrder(request)
+			result, err := client.CreateOrder(context.Background(), request)
 			assert.Error(t, err)
 			assert.Nil(t, result)
 		})
 	})
 
+	t.Run("CancelOrder", func(t *testing.T) {
+		t.Run("cancels the resting remainder", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/trade-api/v2/portfolio/orders/test-order-id", r.URL.Path)
+				assert.Equal(t, http.MethodDelete, r.Method)
+
+				json.NewEncoder(w).Encode(CancelOrderResponse{
+					Order: Order{
+						ID:             "test-order-id",
+						Status:         OrderStatusCanceled,
+						PlaceCount:     10,
+						TakerFillCount: 4,
+					},
+					ReducedBy: 6,
+				})
+			}))
+			defer server.Close()
+
+			client, err := setupTestPortfolioClient(server.URL)
+			require.NoError(t, err)
+
+			result, err := client.CancelOrder(context.Background(), "test-order-id")
+
+			require.NoError(t, err)
+			assert.Equal(t, OrderStatusCanceled, result.Order.Status)
+			assert.Equal(t, 4, result.Order.TakerFillCount)
+			assert.Equal(t, 6, result.ReducedBy)
+		})
+
+		t.Run("handles error response", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer server.Close()
+
+			client, err := setupTestPortfolioClient(server.URL)
+			require.NoError(t, err)
+
+			result, err := client.CancelOrder(context.Background(), "missing")
+			assert.Error(t, err)
+			assert.Nil(t, result)
+		})
+	})
+
+	t.Run("GetOrders", func(t *testing.T) {
+		t.Run("follows the cursor through every page", func(t *testing.T) {
+			minTs := time.Date(2025, 2, 19, 12, 0, 0, 0, time.UTC)
+			var callCount int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/trade-api/v2/portfolio/orders", r.URL.Path)
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "1739966400", r.URL.Query().Get("min_ts"))
+
+				callCount++
+				if callCount == 1 {
+					cursor := "next"
+					json.NewEncoder(w).Encode(OrdersResponse{
+						Cursor: &cursor,
+						Orders: []Order{{ID: "order-1", ClientOrderID: "client-1"}},
+					})
+					return
+				}
+				assert.Equal(t, "next", r.URL.Query().Get("cursor"))
+				json.NewEncoder(w).Encode(OrdersResponse{
+					Orders: []Order{{ID: "order-2", ClientOrderID: "client-2"}},
+				})
+			}))
+			defer server.Close()
+
+			client, err := setupTestPortfolioClient(server.URL)
+			require.NoError(t, err)
+
+			result, err := client.GetOrders(context.Background(), NewGetOrdersOptions().WithMinTs(minTs))
+
+			require.NoError(t, err)
+			assert.Equal(t, 2, callCount)
+			require.Len(t, result.Orders, 2)
+			assert.Equal(t, "client-1", result.Orders[0].ClientOrderID)
+			assert.Equal(t, "client-2", result.Orders[1].ClientOrderID)
+		})
+	})
+
 	t.Run("GetPositions", func(t *testing.T) {
 		t.Run("successfully gets positions with pagination", func(t *testing.T) {
 			var callCount int
@@ -160,7 +243,7 @@ func TestPortfolioClient(t *testing.T) {
 			options := NewGetPositionsOptions().
 				WithSettlementStatus(SettlementStatusOpen)
 
-			result, err := client.GetPositions(options)
+			result, err := client.GetPositions(context.Background(), options)
 
 			assert.NoError(t, err)
 			assert.NotNil(t, result)
@@ -27,6 +27,41 @@ func (o GetPositionsOptions) WithSettlementStatus(settlementStatus string) GetPo
 	return o
 }
 
+type GetOrdersOptions struct {
+	Ticker *string
+	MinTs  *time.Time
+	Status *string
+}
+
+func NewGetOrdersOptions() GetOrdersOptions {
+	return GetOrdersOptions{}
+}
+
+func (o GetOrdersOptions) WithTicker(ticker string) GetOrdersOptions {
+	o.Ticker = &ticker
+	return o
+}
+
+// WithMinTs restricts the orders to those created after minTs
+func (o GetOrdersOptions) WithMinTs(minTs time.Time) GetOrdersOptions {
+	o.MinTs = &minTs
+	return o
+}
+
+func (o GetOrdersOptions) WithStatus(status string) GetOrdersOptions {
+	o.Status = &status
+	return o
+}
+
+type OrdersResponse struct {
+	Cursor *string `json:"cursor"`
+	Orders []Order `json:"orders"`
+}
+
+type OrdersResult struct {
+	Orders []Order
+}
+
 // Primary response type
 type PositionsResponse struct {
 	Cursor          *string          `json:"cursor"`
@@ -92,6 +127,11 @@ type CreateOrderResponse struct {
 	Order Order `json:"order"`
 }
 
+type CancelOrderResponse struct {
+	Order     Order `json:"order"`
+	ReducedBy int   `json:"reduced_by"` // contracts that were still resting when cancelled
+}
+
 type Order struct {
 	Action         string    `json:"action"`
 	ClientOrderID  string    `json:"client_order_id"`
@@ -99,6 +139,10 @@ type Order struct {
 	ExpirationTime time.Time `json:"expiration_time"`
 	ID             string    `json:"order_id"`
 	NoPrice        int       `json:"no_price"` // In cents
+	PlaceCount     int       `json:"place_count"`
+	RemainingCount int       `json:"remaining_count"`
+	MakerFillCount int       `json:"maker_fill_count"`
+	TakerFillCount int       `json:"taker_fill_count"`
 	Side           OrderSide `json:"side"`
 	Status         string    `json:"status"`
 	Ticker         string    `json:"ticker"`
@@ -126,4 +170,8 @@ const (
 	OrderStatusExecuted  = "executed"
 	OrderStatusExpired   = "expired"
 	OrderStatusReduced   = "reduced"
+	// Statuses reported by the v2 API
+	OrderStatusResting  = "resting"
+	OrderStatusPending  = "pending"
+	OrderStatusCanceled = "canceled"
 )
@@ -0,0 +1,37 @@
+package exchange_mock
+
+import (
+	"context"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMarketDataGetter struct {
+	mock.Mock
+}
+
+func (m *MockMarketDataGetter) GetMarket(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Market, error) {
+	args := m.Called(ctx, ticker)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*exchange_domain.Market), args.Error(1)
+}
+
+func (m *MockMarketDataGetter) GetMarkets(ctx context.Context, tickers []contract.Ticker) ([]*exchange_domain.Market, error) {
+	args := m.Called(ctx, tickers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*exchange_domain.Market), args.Error(1)
+}
+
+func (m *MockMarketDataGetter) GetOrderbook(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Orderbook, error) {
+	args := m.Called(ctx, ticker)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*exchange_domain.Orderbook), args.Error(1)
+}
@@ -1,6 +1,7 @@
 package exchange_mock
 
 import (
+	"context"
 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
 
 	"github.com/stretchr/testify/mock"
@@ -10,10 +11,26 @@ type MockMarketGetter struct {
 	mock.Mock
 }
 
-func (m *MockMarketGetter) GetMarket(ticker string) (*kalshi.MarketResponse, error) {
-	args := m.Called(ticker)
+func (m *MockMarketGetter) GetMarket(ctx context.Context, ticker string) (*kalshi.MarketResponse, error) {
+	args := m.Called(ctx, ticker)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*kalshi.MarketResponse), args.Error(1)
 }
+
+func (m *MockMarketGetter) GetMarketOrderbook(ctx context.Context, ticker string, depth *int) (*kalshi.OrderbookResponse, error) {
+	args := m.Called(ctx, ticker, depth)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kalshi.OrderbookResponse), args.Error(1)
+}
+
+func (m *MockMarketGetter) GetMarkets(ctx context.Context, params kalshi.GetMarketsOptions) (*kalshi.MarketsResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kalshi.MarketsResult), args.Error(1)
+}
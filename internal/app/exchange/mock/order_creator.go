@@ -1,6 +1,7 @@
 package exchange_mock
 
 import (
+	"context"
 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
 
 	"github.com/stretchr/testify/mock"
@@ -10,10 +11,26 @@ type MockOrderCreator struct {
 	mock.Mock
 }
 
-func (m *MockOrderCreator) CreateOrder(request kalshi.CreateOrderRequest) (*kalshi.CreateOrderResponse, error) {
-	args := m.Called(request)
+func (m *MockOrderCreator) CreateOrder(ctx context.Context, request kalshi.CreateOrderRequest) (*kalshi.CreateOrderResponse, error) {
+	args := m.Called(ctx, request)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*kalshi.CreateOrderResponse), args.Error(1)
 }
+
+func (m *MockOrderCreator) GetOrders(ctx context.Context, params kalshi.GetOrdersOptions) (*kalshi.OrdersResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kalshi.OrdersResult), args.Error(1)
+}
+
+func (m *MockOrderCreator) CancelOrder(ctx context.Context, orderID string) (*kalshi.CancelOrderResponse, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kalshi.CancelOrderResponse), args.Error(1)
+}
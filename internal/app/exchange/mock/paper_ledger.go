@@ -0,0 +1,50 @@
+package exchange_mock
+
+import (
+	"context"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPaperLedger struct {
+	mock.Mock
+}
+
+func (m *MockPaperLedger) PersistOrder(
+	ctx context.Context,
+	order *exchange_domain.PaperOrder,
+	position *exchange_domain.PaperPosition,
+) error {
+	args := m.Called(ctx, order, position)
+	return args.Error(0)
+}
+
+func (m *MockPaperLedger) GetPosition(
+	ctx context.Context,
+	contractID contract.ContractIdentifier,
+) (*exchange_domain.PaperPosition, error) {
+	args := m.Called(ctx, contractID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*exchange_domain.PaperPosition), args.Error(1)
+}
+
+func (m *MockPaperLedger) GetPositions(ctx context.Context) ([]*exchange_domain.PaperPosition, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*exchange_domain.PaperPosition), args.Error(1)
+}
+
+func (m *MockPaperLedger) GetOrders(ctx context.Context, since time.Time) ([]*exchange_domain.PaperOrder, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*exchange_domain.PaperOrder), args.Error(1)
+}
@@ -1,6 +1,7 @@
 package exchange_mock
 
 import (
+	"context"
 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
 
 	"github.com/stretchr/testify/mock"
@@ -10,8 +11,8 @@ type MockPositionGetter struct {
 	mock.Mock
 }
 
-func (m *MockPositionGetter) GetPositions(params kalshi.GetPositionsOptions) (*kalshi.PositionsResult, error) {
-	args := m.Called(params)
+func (m *MockPositionGetter) GetPositions(ctx context.Context, params kalshi.GetPositionsOptions) (*kalshi.PositionsResult, error) {
+	args := m.Called(ctx, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -0,0 +1,249 @@
+package exchange_repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type PaperPositionDB struct {
+	ContractTicker string    `db:"contract_ticker"`
+	ContractSide   string    `db:"contract_side"`
+	Quantity       int       `db:"quantity"`
+	CostBasis      int       `db:"cost_basis"`
+	RealizedPNL    int       `db:"realized_pnl"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (p PaperPositionDB) toDomain() (*exchange_domain.PaperPosition, error) {
+	side, err := contract.NewSide(p.ContractSide)
+	if err != nil {
+		return nil, fmt.Errorf("create side: %w", err)
+	}
+
+	return &exchange_domain.PaperPosition{
+		Position: exchange_domain.Position{
+			ContractID: contract.ContractIdentifier{
+				Ticker: contract.Ticker(p.ContractTicker),
+				Side:   side,
+			},
+			Quantity:  uint(p.Quantity),
+			CostBasis: p.CostBasis,
+		},
+		RealizedPNL: p.RealizedPNL,
+		UpdatedAt:   p.UpdatedAt,
+	}, nil
+}
+
+type PaperOrderDB struct {
+	OrderID        uuid.UUID `db:"order_id"`
+	Reference      string    `db:"reference"`
+	ContractTicker string    `db:"contract_ticker"`
+	ContractSide   string    `db:"contract_side"`
+	OrderSide      string    `db:"order_side"`
+	OrderType      string    `db:"order_type"`
+	Status         string    `db:"status"`
+	Quantity       int       `db:"quantity"`
+	LimitPrice     *int      `db:"limit_price"`
+	FilledQuantity int       `db:"filled_quantity"`
+	FillPrice      *int      `db:"fill_price"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (o PaperOrderDB) toDomain() (*exchange_domain.PaperOrder, error) {
+	side, err := contract.NewSide(o.ContractSide)
+	if err != nil {
+		return nil, fmt.Errorf("create side: %w", err)
+	}
+
+	order := &exchange_domain.PaperOrder{
+		Order: exchange_domain.Order{
+			OrderID:         exchange_domain.OrderID(o.OrderID),
+			ExchangeOrderID: "paper-" + o.OrderID.String(),
+			Exchange:        exchange_domain.ExchangePaper,
+			Reference:       o.Reference,
+			Ticker:          o.ContractTicker,
+			Side:            side,
+			Action:          exchange_domain.OrderAction(o.OrderSide),
+			OrderType:       exchange_domain.MarketOrderType(o.OrderType),
+			Quantity:        uint(o.Quantity),
+			Status:          o.Status,
+			CreatedAt:       o.CreatedAt,
+			UpdatedAt:       o.UpdatedAt,
+		},
+	}
+	if o.LimitPrice != nil {
+		limitPrice := contract.ContractPrice(*o.LimitPrice)
+		order.LimitPrice = &limitPrice
+	}
+	if o.FillPrice != nil {
+		order.FilledQuantity = uint(o.FilledQuantity)
+		order.Fill = &exchange_domain.PaperFill{
+			Quantity: uint(o.FilledQuantity),
+			Price:    contract.ContractPrice(*o.FillPrice),
+		}
+	}
+	return order, nil
+}
+
+// PaperTradingRepository stores the simulated order and position ledger used in dry run mode
+type PaperTradingRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaperTradingRepository(db *sqlx.DB) *PaperTradingRepository {
+	return &PaperTradingRepository{db: db}
+}
+
+// PersistOrder stores a paper order and, if it filled, the position it changed, in a single transaction
+func (r *PaperTradingRepository) PersistOrder(
+	ctx context.Context,
+	order *exchange_domain.PaperOrder,
+	position *exchange_domain.PaperPosition,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var limitPrice *int
+	if order.LimitPrice != nil {
+		value := order.LimitPrice.Value()
+		limitPrice = &value
+	}
+	filledQuantity := 0
+	var fillPrice *int
+	if order.Fill != nil {
+		filledQuantity = int(order.Fill.Quantity)
+		value := order.Fill.Price.Value()
+		fillPrice = &value
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO event_contract.paper_order (
+			order_id, reference, contract_ticker, contract_side, order_side, order_type, status,
+			quantity, limit_price, filled_quantity, fill_price, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`,
+		uuid.UUID(order.OrderID),
+		order.Reference,
+		order.Ticker,
+		order.Side.String(),
+		order.Action,
+		order.OrderType,
+		order.Status,
+		order.Quantity,
+		limitPrice,
+		filledQuantity,
+		fillPrice,
+		order.CreatedAt,
+		order.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert paper order: %w", err)
+	}
+
+	if position != nil {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO event_contract.paper_position (
+				contract_ticker, contract_side, quantity, cost_basis, realized_pnl, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (contract_ticker, contract_side) DO UPDATE SET
+				quantity = EXCLUDED.quantity,
+				cost_basis = EXCLUDED.cost_basis,
+				realized_pnl = EXCLUDED.realized_pnl,
+				updated_at = EXCLUDED.updated_at
+		`,
+			position.ContractID.Ticker,
+			position.ContractID.Side.String(),
+			position.Quantity,
+			position.CostBasis,
+			position.RealizedPNL,
+			position.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert paper position: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPosition retrieves the paper position in a contract, or nil if it was never traded
+func (r *PaperTradingRepository) GetPosition(
+	ctx context.Context,
+	contractID contract.ContractIdentifier,
+) (*exchange_domain.PaperPosition, error) {
+	var positionDB PaperPositionDB
+	err := r.db.GetContext(ctx, &positionDB, `
+		SELECT contract_ticker, contract_side, quantity, cost_basis, realized_pnl, updated_at
+		FROM event_contract.paper_position
+		WHERE contract_ticker = $1 AND contract_side = $2
+	`, contractID.Ticker, contractID.Side.String())
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query paper position: %w", err)
+	}
+
+	return positionDB.toDomain()
+}
+
+// GetPositions retrieves every paper position, including closed ones that hold realized P&L
+func (r *PaperTradingRepository) GetPositions(ctx context.Context) ([]*exchange_domain.PaperPosition, error) {
+	var positionDBs []PaperPositionDB
+	err := r.db.SelectContext(ctx, &positionDBs, `
+		SELECT contract_ticker, contract_side, quantity, cost_basis, realized_pnl, updated_at
+		FROM event_contract.paper_position
+		ORDER BY contract_ticker, contract_side
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query paper positions: %w", err)
+	}
+
+	positions := make([]*exchange_domain.PaperPosition, 0, len(positionDBs))
+	for _, positionDB := range positionDBs {
+		position, err := positionDB.toDomain()
+		if err != nil {
+			return nil, fmt.Errorf("position %s: %w", positionDB.ContractTicker, err)
+		}
+		positions = append(positions, position)
+	}
+
+	return positions, nil
+}
+
+// GetOrders retrieves the paper orders placed since the given time, oldest first
+func (r *PaperTradingRepository) GetOrders(ctx context.Context, since time.Time) ([]*exchange_domain.PaperOrder, error) {
+	var orderDBs []PaperOrderDB
+	err := r.db.SelectContext(ctx, &orderDBs, `
+		SELECT order_id, reference, contract_ticker, contract_side, order_side, order_type, status,
+			quantity, limit_price, filled_quantity, fill_price, created_at, updated_at
+		FROM event_contract.paper_order
+		WHERE created_at >= $1
+		ORDER BY created_at
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("query paper orders: %w", err)
+	}
+
+	orders := make([]*exchange_domain.PaperOrder, 0, len(orderDBs))
+	for _, orderDB := range orderDBs {
+		order, err := orderDB.toDomain()
+		if err != nil {
+			return nil, fmt.Errorf("order %s: %w", orderDB.OrderID, err)
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,87 @@
+package exchange_repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"prediction-risk/internal/app/testutil"
+)
+
+func TestPaperTradingRepository(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Close(t)
+
+	repo := NewPaperTradingRepository(testDB.DB())
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	newPaperOrder := func(action exchange_domain.OrderAction, fill *exchange_domain.PaperFill) *exchange_domain.PaperOrder {
+		status := exchange_domain.PaperOrderCanceled
+		if fill != nil {
+			status = exchange_domain.PaperOrderExecuted
+		}
+		order := &exchange_domain.PaperOrder{
+			Order: *exchange_domain.NewOrder(
+				"paper-1",
+				exchange_domain.ExchangePaper,
+				"ref-1",
+				string(foo.Ticker),
+				foo.Side,
+				action,
+				exchange_domain.OrderTypeMarket,
+				status,
+			),
+			Fill: fill,
+		}
+		order.Quantity = 10
+		return order
+	}
+
+	t.Run("persists filled orders with their position", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		position := exchange_domain.NewPaperPosition(foo)
+		fill := exchange_domain.PaperFill{Quantity: 10, Price: 45}
+		require.NoError(t, position.ApplyFill(exchange_domain.OrderActionBuy, fill))
+		require.NoError(t, repo.PersistOrder(context.Background(), newPaperOrder(exchange_domain.OrderActionBuy, &fill), position))
+
+		sell := exchange_domain.PaperFill{Quantity: 4, Price: 60}
+		require.NoError(t, position.ApplyFill(exchange_domain.OrderActionSell, sell))
+		require.NoError(t, repo.PersistOrder(context.Background(), newPaperOrder(exchange_domain.OrderActionSell, &sell), position))
+
+		saved, err := repo.GetPosition(context.Background(), foo)
+		require.NoError(t, err)
+		require.NotNil(t, saved)
+		assert.Equal(t, foo, saved.ContractID)
+		assert.Equal(t, uint(6), saved.Quantity)
+		assert.Equal(t, 270, saved.CostBasis)
+		assert.Equal(t, 60, saved.RealizedPNL)
+
+		positions, err := repo.GetPositions(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, positions, 1)
+
+		orders, err := repo.GetOrders(context.Background(), time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		assert.Equal(t, "ref-1", orders[0].Reference)
+		assert.Equal(t, exchange_domain.OrderActionBuy, orders[0].Action)
+		assert.Equal(t, &fill, orders[0].Fill)
+		assert.Equal(t, exchange_domain.OrderActionSell, orders[1].Action)
+	})
+
+	t.Run("persists unfilled orders without a position", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		require.NoError(t, repo.PersistOrder(context.Background(), newPaperOrder(exchange_domain.OrderActionBuy, nil), nil))
+
+		saved, err := repo.GetPosition(context.Background(), foo)
+		require.NoError(t, err)
+		assert.Nil(t, saved)
+	})
+}
@@ -1,8 +1,10 @@
 package exchange_service
 
 import (
+	"context"
 	"prediction-risk/internal/app/contract"
 	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"time"
 )
 
 type OrderParams struct {
@@ -11,11 +13,18 @@ type OrderParams struct {
 	Action     exchange_domain.OrderAction
 	Reference  string
 	LimitPrice *contract.ContractPrice
+	MaxCost    *int // most a buy may spend in cents
 	// Future fields can be added without breaking the interface
 }
 
+// ExchangeService trades on an exchange. Every call gives up once its context is done.
 type ExchangeService interface {
-	GetMarket(ticker contract.Ticker) (*exchange_domain.Market, error)
-	GetPositions() ([]*exchange_domain.Position, error)
-	CreateOrder(orderParams OrderParams) (*exchange_domain.Order, error)
+	GetMarket(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Market, error)
+	GetMarkets(ctx context.Context, tickers []contract.Ticker) ([]*exchange_domain.Market, error)
+	GetOrderbook(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Orderbook, error)
+	GetPositions(ctx context.Context) ([]*exchange_domain.Position, error)
+	GetEventPositions(ctx context.Context) ([]*exchange_domain.EventPosition, error)
+	CreateOrder(ctx context.Context, orderParams OrderParams) (*exchange_domain.Order, error)
+	GetOrders(ctx context.Context, since time.Time) ([]*exchange_domain.Order, error)
+	CancelOrder(ctx context.Context, exchangeOrderID string) (*exchange_domain.Order, error)
 }
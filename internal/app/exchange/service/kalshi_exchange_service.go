@@ -1,24 +1,30 @@
 package exchange_service
 
 import (
+	"context"
 	"fmt"
 	"prediction-risk/internal/app/contract"
 	exchange_domain "prediction-risk/internal/app/exchange/domain"
 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
+	"time"
 
 	"github.com/samber/lo"
 )
 
 type marketGetter interface {
-	GetMarket(ticker string) (*kalshi.MarketResponse, error)
+	GetMarket(ctx context.Context, ticker string) (*kalshi.MarketResponse, error)
+	GetMarkets(ctx context.Context, params kalshi.GetMarketsOptions) (*kalshi.MarketsResult, error)
+	GetMarketOrderbook(ctx context.Context, ticker string, depth *int) (*kalshi.OrderbookResponse, error)
 }
 
 type positionGetter interface {
-	GetPositions(params kalshi.GetPositionsOptions) (*kalshi.PositionsResult, error)
+	GetPositions(ctx context.Context, params kalshi.GetPositionsOptions) (*kalshi.PositionsResult, error)
 }
 
 type orderCreator interface {
-	CreateOrder(request kalshi.CreateOrderRequest) (*kalshi.CreateOrderResponse, error)
+	CreateOrder(ctx context.Context, request kalshi.CreateOrderRequest) (*kalshi.CreateOrderResponse, error)
+	GetOrders(ctx context.Context, params kalshi.GetOrdersOptions) (*kalshi.OrdersResult, error)
+	CancelOrder(ctx context.Context, orderID string) (*kalshi.CancelOrderResponse, error)
 }
 
 type KalshiExchangeService struct {
@@ -35,43 +41,74 @@ func NewExchangeService(kalshiClient *kalshi.KalshiClient) *KalshiExchangeServic
 	}
 }
 
-func (es *KalshiExchangeService) GetMarket(ticker contract.Ticker) (*exchange_domain.Market, error) {
-	kalshiMarket, err := es.markets.GetMarket(string(ticker))
+func (es *KalshiExchangeService) GetMarket(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Market, error) {
+	resp, err := es.markets.GetMarket(ctx, string(ticker))
 	if err != nil {
 		return nil, fmt.Errorf("fetch market from kalshi: %w", err)
 	}
 
+	return mapMarket(resp.Market), nil
+}
+
+// maxTickersPerRequest keeps batched market requests to a reasonable URL length
+const maxTickersPerRequest = 100
+
+// GetMarkets fetches several markets in as few requests as possible. Tickers
+// Kalshi does not know are left out of the result rather than failing the batch.
+func (es *KalshiExchangeService) GetMarkets(ctx context.Context, tickers []contract.Ticker) ([]*exchange_domain.Market, error) {
+	markets := make([]*exchange_domain.Market, 0, len(tickers))
+	for _, chunk := range lo.Chunk(lo.Uniq(tickers), maxTickersPerRequest) {
+		params := kalshi.NewGetMarketsOptions().
+			WithTickers(lo.Map(chunk, func(t contract.Ticker, _ int) string { return string(t) })).
+			WithLimit(len(chunk))
+		resp, err := es.markets.GetMarkets(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("fetch markets from kalshi: %w", err)
+		}
+		for _, kalshiMarket := range resp.Markets {
+			markets = append(markets, mapMarket(kalshiMarket))
+		}
+	}
+	return markets, nil
+}
+
+// mapMarket converts a Kalshi market to our domain market model
+func mapMarket(kalshiMarket kalshi.Market) *exchange_domain.Market {
 	// Create the market info section, which contains the basic identifying information
 	marketInfo := exchange_domain.MarketInfo{
-		Title:    kalshiMarket.Market.Title,
-		Category: kalshiMarket.Market.Category,
-		Type:     exchange_domain.MarketTypeBinary, // Kalshi markets are always binary
+		EventTicker:  contract.Ticker(kalshiMarket.EventTicker),
+		Title:        kalshiMarket.Title,
+		Category:     kalshiMarket.Category,
+		Type:         exchange_domain.MarketTypeBinary, // Kalshi markets are always binary
+		YesSubTitle:  kalshiMarket.YesSubTitle,
+		StrikePrice:  kalshiMarket.StrikePrice,
+		RulesPrimary: kalshiMarket.RulesPrimary,
 	}
 
 	// Map the market status, handling the various time fields and current state
 	marketStatus := exchange_domain.MarketStatus{
-		// State:              mapMarketState(kalshiMarket.Status),
-		OpenTime:           kalshiMarket.Market.OpenTime,
-		CloseTime:          kalshiMarket.Market.CloseTime,
-		ExpirationTime:     kalshiMarket.Market.ExpirationTime,
-		SettlementTime:     kalshiMarket.Market.SettlementTime,
-		Result:             kalshiMarket.Market.Result,
-		AllowsEarlyClosing: kalshiMarket.Market.CanCloseEarly,
+		State:              mapMarketState(kalshiMarket.Status),
+		OpenTime:           kalshiMarket.OpenTime,
+		CloseTime:          kalshiMarket.CloseTime,
+		ExpirationTime:     kalshiMarket.ExpirationTime,
+		SettlementTime:     kalshiMarket.SettlementTime,
+		Result:             kalshiMarket.Result,
+		AllowsEarlyClosing: kalshiMarket.CanCloseEarly,
 	}
 
 	// Create the pricing information for both sides of the market
 	marketPricing := exchange_domain.MarketPricing{
 		YesSide: exchange_domain.PricingSide{
-			Bid:         contract.ContractPrice(kalshiMarket.Market.YesBid),
-			Ask:         contract.ContractPrice(kalshiMarket.Market.YesAsk),
-			LastPrice:   contract.ContractPrice(kalshiMarket.Market.LastPrice),
-			PreviousBid: contract.ContractPrice(kalshiMarket.Market.PreviousYesBid),
-			PreviousAsk: contract.ContractPrice(kalshiMarket.Market.PreviousYesAsk),
+			Bid:         contract.ContractPrice(kalshiMarket.YesBid),
+			Ask:         contract.ContractPrice(kalshiMarket.YesAsk),
+			LastPrice:   contract.ContractPrice(kalshiMarket.LastPrice),
+			PreviousBid: contract.ContractPrice(kalshiMarket.PreviousYesBid),
+			PreviousAsk: contract.ContractPrice(kalshiMarket.PreviousYesAsk),
 		},
 		NoSide: exchange_domain.PricingSide{
-			Bid: contract.ContractPrice(kalshiMarket.Market.NoBid),
-			Ask: contract.ContractPrice(kalshiMarket.Market.NoAsk),
-			// LastPrice:   kalshiMarket.LastPrice,
+			Bid:       contract.ContractPrice(kalshiMarket.NoBid),
+			Ask:       contract.ContractPrice(kalshiMarket.NoAsk),
+			LastPrice: noLastPrice(kalshiMarket.LastPrice),
 			// PreviousBid: kalshiMarket.PreviousYesBid,
 			// PreviousAsk: kalshiMarket.PreviousYesAsk,
 		},
@@ -79,22 +116,22 @@ func (es *KalshiExchangeService) GetMarket(ticker contract.Ticker) (*exchange_do
 
 	// Set up the trading constraints that define the rules for this market
 	tradingConstraints := exchange_domain.TradingConstraints{
-		NotionalValue: contract.ContractPrice(kalshiMarket.Market.NotionalValue),
-		TickSize:      contract.ContractPrice(kalshiMarket.Market.TickSize),
-		RiskLimit:     contract.ContractPrice(kalshiMarket.Market.RiskLimitCents),
+		NotionalValue: contract.ContractPrice(kalshiMarket.NotionalValue),
+		TickSize:      contract.ContractPrice(kalshiMarket.TickSize),
+		RiskLimit:     contract.ContractPrice(kalshiMarket.RiskLimitCents),
 	}
 
 	// Map the liquidity metrics that indicate market activity
 	liquidityMetrics := exchange_domain.LiquidityMetrics{
-		Volume:       kalshiMarket.Market.Volume,
-		Volume24H:    kalshiMarket.Market.Volume24H,
-		OpenInterest: kalshiMarket.Market.OpenInterest,
-		Liquidity:    kalshiMarket.Market.Liquidity,
+		Volume:       kalshiMarket.Volume,
+		Volume24H:    kalshiMarket.Volume24H,
+		OpenInterest: kalshiMarket.OpenInterest,
+		Liquidity:    kalshiMarket.Liquidity,
 	}
 
 	// Combine all components into our domain market model
 	market := exchange_domain.Market{
-		Ticker:      contract.Ticker(kalshiMarket.Market.Ticker),
+		Ticker:      contract.Ticker(kalshiMarket.Ticker),
 		Info:        marketInfo,
 		Status:      marketStatus,
 		Pricing:     marketPricing,
@@ -102,44 +139,118 @@ func (es *KalshiExchangeService) GetMarket(ticker contract.Ticker) (*exchange_do
 		Liquidity:   liquidityMetrics,
 	}
 
-	return &market, nil
+	return &market
+}
+
+func (es *KalshiExchangeService) GetOrderbook(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Orderbook, error) {
+	resp, err := es.markets.GetMarketOrderbook(ctx, string(ticker), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch orderbook from kalshi: %w", err)
+	}
+
+	return &exchange_domain.Orderbook{
+		Ticker:  ticker,
+		YesBids: mapOrderbookLevels(resp.Orderbook.Yes),
+		NoBids:  mapOrderbookLevels(resp.Orderbook.No),
+	}, nil
+}
+
+// mapOrderbookLevels converts Kalshi's [price, quantity] pairs, skipping malformed levels
+func mapOrderbookLevels(levels [][]int) []exchange_domain.OrderbookLevel {
+	result := make([]exchange_domain.OrderbookLevel, 0, len(levels))
+	for _, level := range levels {
+		if len(level) != 2 {
+			continue
+		}
+		result = append(result, exchange_domain.OrderbookLevel{
+			Price:    contract.ContractPrice(level[0]),
+			Quantity: level[1],
+		})
+	}
+	return result
 }
 
-func (es *KalshiExchangeService) GetPositions() ([]*exchange_domain.Position, error) {
+func (es *KalshiExchangeService) GetPositions(ctx context.Context) ([]*exchange_domain.Position, error) {
 	params := kalshi.GetPositionsOptions{}
-	resp, err := es.positions.GetPositions(params)
+	resp, err := es.positions.GetPositions(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("fetch market from kalshi: %w", err)
 	}
 
 	positions := lo.Map(resp.MarketPositions, func(p kalshi.MarketPosition, _ int) *exchange_domain.Position {
-		side := es.determinePositionSide(p.Position)
-		contractID := contract.ContractIdentifier{
-			Ticker: contract.Ticker(p.Ticker),
-			Side:   side,
-		}
+		return es.mapPosition(p)
+	})
+
+	return positions, nil
+}
+
+// GetEventPositions returns the exposure held in each event, with the open
+// market positions that make it up
+func (es *KalshiExchangeService) GetEventPositions(ctx context.Context) ([]*exchange_domain.EventPosition, error) {
+	resp, err := es.positions.GetPositions(ctx, kalshi.GetPositionsOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("fetch positions from kalshi: %w", err)
+	}
 
-		return &exchange_domain.Position{
-			ContractID: contractID,
-			Quantity:   uint(abs(p.Position)),
+	events := lo.Map(resp.EventPositions, func(p kalshi.EventPosition, _ int) *exchange_domain.EventPosition {
+		return &exchange_domain.EventPosition{
+			EventTicker: contract.Ticker(p.EventTicker),
+			Exposure:    p.EventExposure,
+			RealizedPNL: p.RealizedPNL,
+			FeesPaid:    p.FeesPaid,
+			TotalCost:   p.TotalCost,
+			Positions:   make([]*exchange_domain.Position, 0),
 		}
 	})
 
-	return positions, nil
+	for _, marketPosition := range resp.MarketPositions {
+		if marketPosition.Position == 0 {
+			continue
+		}
+		position := es.mapPosition(marketPosition)
+		for _, event := range events {
+			if event.Contains(position.ContractID.Ticker) {
+				event.Positions = append(event.Positions, position)
+				break
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// mapPosition converts a Kalshi market position, signed by side, to a domain position
+func (es *KalshiExchangeService) mapPosition(p kalshi.MarketPosition) *exchange_domain.Position {
+	side := es.determinePositionSide(p.Position)
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker(p.Ticker),
+		Side:   side,
+	}
+
+	return &exchange_domain.Position{
+		ContractID: contractID,
+		Quantity:   uint(abs(p.Position)),
+		CostBasis:  p.TotalTradedCost,
+	}
 }
 
 func (es *KalshiExchangeService) CreateOrder(
+	ctx context.Context,
 	orderParams OrderParams,
 ) (*exchange_domain.Order, error) {
 	switch orderParams.Action {
 	case exchange_domain.OrderActionBuy:
 		return es.createBuyOrder(
+			ctx,
 			orderParams.ContractID,
 			orderParams.Reference,
+			orderParams.Quantity,
 			orderParams.LimitPrice,
+			orderParams.MaxCost,
 		)
 	case exchange_domain.OrderActionSell:
 		return es.createSellOrder(
+			ctx,
 			orderParams.ContractID,
 			orderParams.Reference,
 			orderParams.Quantity,
@@ -150,21 +261,89 @@ func (es *KalshiExchangeService) CreateOrder(
 	}
 }
 
+// createBuyOrder buys the requested quantity. Kalshi only fills market buys up to
+// buy_max_cost, so a buy must have a limit price or a max cost.
 func (es *KalshiExchangeService) createBuyOrder(
+	ctx context.Context,
 	contractID contract.ContractIdentifier,
 	reference string,
+	quantity *uint,
 	limitPrice *contract.ContractPrice,
+	maxCost *int,
 ) (*exchange_domain.Order, error) {
-	return nil, fmt.Errorf("buy orders not yet supported")
+	if quantity == nil || *quantity == 0 {
+		return nil, fmt.Errorf("buy order must have a positive quantity")
+	}
+	if limitPrice == nil && maxCost == nil {
+		return nil, fmt.Errorf("buy order must have a limit price or max cost")
+	}
+
+	var orderSide kalshi.OrderSide
+	if contractID.Side == contract.SideYes {
+		orderSide = kalshi.OrderSideYes
+	} else {
+		orderSide = kalshi.OrderSideNo
+	}
+
+	var orderType string
+	var marketOrderType exchange_domain.MarketOrderType
+	var yesPrice *int
+	var noPrice *int
+	if limitPrice != nil {
+		orderType = "limit"
+		marketOrderType = exchange_domain.OrderTypeLimit
+		value := limitPrice.Value()
+		if contractID.Side == contract.SideYes {
+			yesPrice = &value
+		} else {
+			noPrice = &value
+		}
+	} else {
+		orderType = "market"
+		marketOrderType = exchange_domain.OrderTypeMarket
+	}
+
+	request := kalshi.CreateOrderRequest{
+		Ticker:        string(contractID.Ticker),
+		ClientOrderID: reference,
+		Side:          orderSide,
+		Action:        kalshi.OrderActionBuy,
+		Count:         int(*quantity),
+		Type:          orderType,
+		YesPrice:      yesPrice,
+		NoPrice:       noPrice,
+		BuyMaxCost:    maxCost,
+	}
+	resp, err := es.orders.CreateOrder(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	order := exchange_domain.NewOrder(
+		resp.Order.ID,
+		exchange_domain.ExchangeKalshi,
+		reference,
+		resp.Order.Ticker,
+		contractID.Side,
+		exchange_domain.OrderActionBuy,
+		marketOrderType,
+		mapOrderStatus(resp.Order),
+	)
+	order.FilledQuantity = filledCount(resp.Order)
+	order.Quantity = *quantity
+	order.LimitPrice = limitPrice
+
+	return order, nil
 }
 
 func (es *KalshiExchangeService) createSellOrder(
+	ctx context.Context,
 	contractID contract.ContractIdentifier,
 	reference string,
 	quantity *uint,
 	limitPrice *contract.ContractPrice,
 ) (*exchange_domain.Order, error) {
-	position, err := es.findPosition(contractID.Ticker)
+	position, err := es.findPosition(ctx, contractID.Ticker)
 	if err != nil {
 		return nil, fmt.Errorf("find position: %w", err)
 	}
@@ -194,7 +373,7 @@ func (es *KalshiExchangeService) createSellOrder(
 		marketOrderType = exchange_domain.OrderTypeMarket
 	}
 
-	sellQuantity, err := es.calculateSellQuantity(position.Position, quantity)
+	sellQuantity, err := es.calculateSellQuantity(position.Position, contractID.Side, quantity)
 	if err != nil {
 		return nil, fmt.Errorf("calculate sell quantity: %w", err)
 	}
@@ -209,7 +388,7 @@ func (es *KalshiExchangeService) createSellOrder(
 		YesPrice:      yesPrice,
 		NoPrice:       noPrice,
 	}
-	resp, err := es.orders.CreateOrder(request)
+	resp, err := es.orders.CreateOrder(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -222,12 +401,124 @@ func (es *KalshiExchangeService) createSellOrder(
 		contractID.Side,
 		exchange_domain.OrderActionSell,
 		marketOrderType,
-		resp.Order.Status,
+		mapOrderStatus(resp.Order),
 	)
+	order.FilledQuantity = filledCount(resp.Order)
+	order.Quantity = sellQuantity
+	order.LimitPrice = limitPrice
 
 	return order, nil
 }
 
+// GetOrders lists the orders placed since the given time. The reference on each
+// order is the client order ID it was placed with.
+func (es *KalshiExchangeService) GetOrders(ctx context.Context, since time.Time) ([]*exchange_domain.Order, error) {
+	resp, err := es.orders.GetOrders(ctx, kalshi.NewGetOrdersOptions().WithMinTs(since))
+	if err != nil {
+		return nil, fmt.Errorf("fetch orders from kalshi: %w", err)
+	}
+
+	return lo.Map(resp.Orders, func(o kalshi.Order, _ int) *exchange_domain.Order {
+		return mapOrder(o)
+	}), nil
+}
+
+// CancelOrder cancels the unfilled part of an order and returns the order as it then stands
+func (es *KalshiExchangeService) CancelOrder(ctx context.Context, exchangeOrderID string) (*exchange_domain.Order, error) {
+	resp, err := es.orders.CancelOrder(ctx, exchangeOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("cancel order on kalshi: %w", err)
+	}
+	return mapOrder(resp.Order), nil
+}
+
+// mapOrderStatus converts a Kalshi order status to a domain order status. Kalshi reports a
+// resting order with some fills as resting, so fills decide whether it is partially filled.
+func mapOrderStatus(kalshiOrder kalshi.Order) string {
+	switch kalshiOrder.Status {
+	case kalshi.OrderStatusResting, kalshi.OrderStatusPending, kalshi.OrderStatusOpen:
+		if filledCount(kalshiOrder) > 0 {
+			return exchange_domain.OrderStatusPartiallyFilled
+		}
+		return exchange_domain.OrderStatusResting
+	case kalshi.OrderStatusExecuted:
+		return exchange_domain.OrderStatusExecuted
+	case kalshi.OrderStatusCanceled, kalshi.OrderStatusCancelled, kalshi.OrderStatusExpired:
+		return exchange_domain.OrderStatusCanceled
+	default:
+		return kalshiOrder.Status
+	}
+}
+
+// filledCount returns the contracts of the order filled so far, as maker or taker
+func filledCount(kalshiOrder kalshi.Order) uint {
+	return uint(kalshiOrder.MakerFillCount + kalshiOrder.TakerFillCount)
+}
+
+// mapOrder converts an order listed by Kalshi to a domain order
+func mapOrder(kalshiOrder kalshi.Order) *exchange_domain.Order {
+	side := contract.SideYes
+	if kalshiOrder.Side == kalshi.OrderSideNo {
+		side = contract.SideNo
+	}
+	action := exchange_domain.OrderActionBuy
+	if kalshiOrder.Action == string(kalshi.OrderActionSell) {
+		action = exchange_domain.OrderActionSell
+	}
+	orderType := exchange_domain.OrderTypeMarket
+	if kalshiOrder.Type == kalshi.OrderTypeLimit {
+		orderType = exchange_domain.OrderTypeLimit
+	}
+
+	order := exchange_domain.NewOrder(
+		kalshiOrder.ID,
+		exchange_domain.ExchangeKalshi,
+		kalshiOrder.ClientOrderID,
+		kalshiOrder.Ticker,
+		side,
+		action,
+		orderType,
+		mapOrderStatus(kalshiOrder),
+	)
+	order.Quantity = uint(kalshiOrder.PlaceCount)
+	order.FilledQuantity = filledCount(kalshiOrder)
+	if orderType == exchange_domain.OrderTypeLimit {
+		// Kalshi quotes both sides; the limit is the price on the side being traded
+		price := contract.ContractPrice(kalshiOrder.YesPrice)
+		if side == contract.SideNo {
+			price = contract.ContractPrice(kalshiOrder.NoPrice)
+		}
+		order.LimitPrice = &price
+	}
+	order.CreatedAt = kalshiOrder.CreatedTime
+	return order
+}
+
+// noLastPrice derives the NO last price from Kalshi's last trade, which is quoted on the YES side
+func noLastPrice(yesLastPrice int) contract.ContractPrice {
+	if yesLastPrice <= 0 {
+		return 0
+	}
+	return contract.ContractPrice(100 - yesLastPrice)
+}
+
+// mapMarketState converts a Kalshi market status to a market state.
+// Unknown statuses map to an empty state rather than guessing.
+func mapMarketState(status kalshi.MarketStatus) exchange_domain.MarketState {
+	switch status {
+	case kalshi.MarketStatusUnopened, kalshi.MarketStatusInitialized:
+		return exchange_domain.MarketStateUnopened
+	case kalshi.MarketStatusOpen, kalshi.MarketStatusActive:
+		return exchange_domain.MarketStateOpen
+	case kalshi.MarketStatusClosed:
+		return exchange_domain.MarketStateClosed
+	case kalshi.MarketStatusSettled, kalshi.MarketStatusDetermined, kalshi.MarketStatusFinalized:
+		return exchange_domain.MarketStateSettled
+	default:
+		return ""
+	}
+}
+
 // determinePositionSide converts a signed position quantity to a contract side
 func (es *KalshiExchangeService) determinePositionSide(position int) contract.Side {
 	if position >= 0 {
@@ -236,9 +527,9 @@ func (es *KalshiExchangeService) determinePositionSide(position int) contract.Si
 	return contract.SideNo
 }
 
-func (es *KalshiExchangeService) findPosition(ticker contract.Ticker) (*kalshi.MarketPosition, error) {
+func (es *KalshiExchangeService) findPosition(ctx context.Context, ticker contract.Ticker) (*kalshi.MarketPosition, error) {
 	tickerStr := string(ticker)
-	positions, err := es.positions.GetPositions(kalshi.GetPositionsOptions{Ticker: &tickerStr})
+	positions, err := es.positions.GetPositions(ctx, kalshi.GetPositionsOptions{Ticker: &tickerStr})
 	if err != nil {
 		return nil, fmt.Errorf("get positions: %w", err)
 	}
@@ -260,13 +551,18 @@ func (es *KalshiExchangeService) findPosition(ticker contract.Ticker) (*kalshi.M
 }
 
 // If size is specified, it will be set to the minimum of the position and the size
-// Otherwise it will be set to the full position
+// Otherwise it will be set to the full position. Kalshi reports NO positions as negative,
+// so the position must be held on the side being sold.
 func (es *KalshiExchangeService) calculateSellQuantity(
 	position int,
+	side contract.Side,
 	requestedQuantity *uint,
 ) (uint, error) {
-	if position <= 0 {
-		return 0, fmt.Errorf("position must be positive, got %d", position)
+	if position == 0 {
+		return 0, fmt.Errorf("no position held to sell")
+	}
+	if held := es.determinePositionSide(position); held != side {
+		return 0, fmt.Errorf("position of %d is held on %s, cannot sell %s", position, held, side)
 	}
 
 	availableQuantity := uint(abs(position))
@@ -1,7 +1,9 @@
 package exchange_service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -39,11 +41,12 @@ func TestKalshiExchangeService_GetPositions(t *testing.T) {
 		service, _, positions, _ := newTestService()
 
 		// Mock the positions response
-		positions.On("GetPositions", kalshi.GetPositionsOptions{}).Return(&kalshi.PositionsResult{
+		positions.On("GetPositions", mock.Anything, kalshi.GetPositionsOptions{}).Return(&kalshi.PositionsResult{
 			MarketPositions: []kalshi.MarketPosition{
 				{
-					Ticker:   "MARKET-1",
-					Position: 100, // YES position
+					Ticker:          "MARKET-1",
+					Position:        100, // YES position
+					TotalTradedCost: 4200,
 				},
 				{
 					Ticker:   "MARKET-2",
@@ -53,7 +56,7 @@ func TestKalshiExchangeService_GetPositions(t *testing.T) {
 		}, nil)
 
 		// Execute test
-		result, err := service.GetPositions()
+		result, err := service.GetPositions(context.Background())
 
 		// Verify results
 		require.NoError(t, err)
@@ -63,6 +66,7 @@ func TestKalshiExchangeService_GetPositions(t *testing.T) {
 		assert.Equal(t, contract.Ticker("MARKET-1"), result[0].ContractID.Ticker)
 		assert.Equal(t, contract.SideYes, result[0].ContractID.Side)
 		assert.Equal(t, uint(100), result[0].Quantity)
+		assert.Equal(t, 4200, result[0].CostBasis)
 
 		// Verify second position (NO side)
 		assert.Equal(t, contract.Ticker("MARKET-2"), result[1].ContractID.Ticker)
@@ -75,11 +79,11 @@ func TestKalshiExchangeService_GetPositions(t *testing.T) {
 	t.Run("handles empty positions list", func(t *testing.T) {
 		service, _, positions, _ := newTestService()
 
-		positions.On("GetPositions", kalshi.GetPositionsOptions{}).Return(&kalshi.PositionsResult{
+		positions.On("GetPositions", mock.Anything, kalshi.GetPositionsOptions{}).Return(&kalshi.PositionsResult{
 			MarketPositions: []kalshi.MarketPosition{},
 		}, nil)
 
-		result, err := service.GetPositions()
+		result, err := service.GetPositions(context.Background())
 
 		require.NoError(t, err)
 		assert.Empty(t, result)
@@ -89,9 +93,9 @@ func TestKalshiExchangeService_GetPositions(t *testing.T) {
 	t.Run("handles API error", func(t *testing.T) {
 		service, _, positions, _ := newTestService()
 
-		positions.On("GetPositions", kalshi.GetPositionsOptions{}).Return(nil, errors.New("API error"))
+		positions.On("GetPositions", mock.Anything, kalshi.GetPositionsOptions{}).Return(nil, errors.New("API error"))
 
-		result, err := service.GetPositions()
+		result, err := service.GetPositions(context.Background())
 
 		require.Error(t, err)
 		assert.Nil(t, result)
@@ -100,16 +104,67 @@ func TestKalshiExchangeService_GetPositions(t *testing.T) {
 	})
 }
 
+func TestKalshiExchangeService_GetEventPositions(t *testing.T) {
+	t.Run("groups open market positions by event", func(t *testing.T) {
+		service, _, positions, _ := newTestService()
+
+		positions.On("GetPositions", mock.Anything, kalshi.GetPositionsOptions{}).Return(&kalshi.PositionsResult{
+			EventPositions: []kalshi.EventPosition{
+				{EventTicker: "KXHIGHNY-25FEB13", EventExposure: 6000, RealizedPNL: -1500, FeesPaid: 120, TotalCost: 9000},
+				{EventTicker: "KXHIGHCHI-25FEB13", EventExposure: 0, RealizedPNL: 300},
+			},
+			MarketPositions: []kalshi.MarketPosition{
+				{Ticker: "KXHIGHNY-25FEB13-T40", Position: 100, TotalTradedCost: 4000},
+				{Ticker: "KXHIGHNY-25FEB13-B41.5", Position: -40, TotalTradedCost: 2000},
+				{Ticker: "KXHIGHCHI-25FEB13-T30", Position: 0, TotalTradedCost: 500},
+			},
+		}, nil)
+
+		result, err := service.GetEventPositions(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		ny := result[0]
+		assert.Equal(t, contract.Ticker("KXHIGHNY-25FEB13"), ny.EventTicker)
+		assert.Equal(t, 6000, ny.Exposure)
+		assert.Equal(t, -1500, ny.RealizedPNL)
+		assert.Equal(t, 120, ny.FeesPaid)
+		require.Len(t, ny.Positions, 2)
+		assert.Equal(t, contract.SideYes, ny.Positions[0].ContractID.Side)
+		assert.Equal(t, contract.SideNo, ny.Positions[1].ContractID.Side)
+		assert.Equal(t, uint(40), ny.Positions[1].Quantity)
+
+		// Closed positions are not part of the event's exposure
+		assert.Empty(t, result[1].Positions)
+		positions.AssertExpectations(t)
+	})
+
+	t.Run("handles API error", func(t *testing.T) {
+		service, _, positions, _ := newTestService()
+
+		positions.On("GetPositions", mock.Anything, kalshi.GetPositionsOptions{}).Return(nil, errors.New("API error"))
+
+		result, err := service.GetEventPositions(context.Background())
+
+		assert.ErrorContains(t, err, "fetch positions from kalshi")
+		assert.Nil(t, result)
+	})
+}
+
 func TestKalshiExchangeService_GetMarket(t *testing.T) {
 	t.Run("successfully retrieves market details", func(t *testing.T) {
 		service, markets, _, _ := newTestService()
 
 		// Mock the market response
-		markets.On("GetMarket", "TEST-MARKET").Return(&kalshi.MarketResponse{
+		markets.On("GetMarket", mock.Anything, "TEST-MARKET").Return(&kalshi.MarketResponse{
 			Market: kalshi.Market{
 				Ticker:         "TEST-MARKET",
+				EventTicker:    "TEST",
 				Title:          "Test Market",
 				Category:       "TEST",
+				YesSubTitle:    "Above 50",
+				RulesPrimary:   "If the test value is above 50, then the market resolves to Yes.",
 				Status:         "active",
 				OpenTime:       time.Now(),
 				CloseTime:      time.Now().Add(24 * time.Hour),
@@ -132,7 +187,7 @@ func TestKalshiExchangeService_GetMarket(t *testing.T) {
 		}, nil)
 
 		// Execute test
-		result, err := service.GetMarket("TEST-MARKET")
+		result, err := service.GetMarket(context.Background(), "TEST-MARKET")
 
 		// Verify results
 		require.NoError(t, err)
@@ -143,6 +198,10 @@ func TestKalshiExchangeService_GetMarket(t *testing.T) {
 		assert.Equal(t, "Test Market", result.Info.Title)
 		assert.Equal(t, "TEST", result.Info.Category)
 		assert.Equal(t, exchange_domain.MarketTypeBinary, result.Info.Type)
+		assert.Equal(t, contract.Ticker("TEST"), result.Info.EventTicker)
+		assert.Equal(t, "Above 50", result.Info.YesSubTitle)
+		assert.Contains(t, result.Info.RulesPrimary, "resolves to Yes")
+		assert.Equal(t, exchange_domain.MarketStateOpen, result.Status.State)
 
 		// Verify YES side pricing
 		assert.Equal(t, contract.ContractPrice(60), result.Pricing.YesSide.Bid)
@@ -151,9 +210,10 @@ func TestKalshiExchangeService_GetMarket(t *testing.T) {
 		assert.Equal(t, contract.ContractPrice(58), result.Pricing.YesSide.PreviousBid)
 		assert.Equal(t, contract.ContractPrice(63), result.Pricing.YesSide.PreviousAsk)
 
-		// Verify NO side pricing (only current bid/ask)
+		// Verify NO side pricing (current bid/ask, last derived from the YES last)
 		assert.Equal(t, contract.ContractPrice(35), result.Pricing.NoSide.Bid)
 		assert.Equal(t, contract.ContractPrice(40), result.Pricing.NoSide.Ask)
+		assert.Equal(t, contract.ContractPrice(38), result.Pricing.NoSide.LastPrice)
 
 		// Verify trading constraints
 		assert.Equal(t, contract.ContractPrice(100), result.Constraints.NotionalValue)
@@ -172,9 +232,9 @@ func TestKalshiExchangeService_GetMarket(t *testing.T) {
 	t.Run("handles API error", func(t *testing.T) {
 		service, markets, _, _ := newTestService()
 
-		markets.On("GetMarket", "TEST-MARKET").Return(nil, errors.New("API error"))
+		markets.On("GetMarket", mock.Anything, "TEST-MARKET").Return(nil, errors.New("API error"))
 
-		result, err := service.GetMarket("TEST-MARKET")
+		result, err := service.GetMarket(context.Background(), "TEST-MARKET")
 
 		require.Error(t, err)
 		assert.Nil(t, result)
@@ -183,6 +243,158 @@ func TestKalshiExchangeService_GetMarket(t *testing.T) {
 	})
 }
 
+func TestKalshiExchangeService_GetMarkets(t *testing.T) {
+	t.Run("fetches distinct tickers in one request", func(t *testing.T) {
+		service, markets, _, _ := newTestService()
+
+		markets.On("GetMarkets", mock.Anything, kalshi.NewGetMarketsOptions().
+			WithTickers([]string{"MARKET-1", "MARKET-2"}).
+			WithLimit(2),
+		).Return(&kalshi.MarketsResult{
+			Markets: []kalshi.Market{
+				{Ticker: "MARKET-1", Status: "active", YesBid: 40, YesAsk: 42},
+				{Ticker: "MARKET-2", Status: "settled"},
+			},
+		}, nil).Once()
+
+		result, err := service.GetMarkets(context.Background(), []contract.Ticker{"MARKET-1", "MARKET-2", "MARKET-1"})
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, contract.Ticker("MARKET-1"), result[0].Ticker)
+		assert.Equal(t, contract.ContractPrice(42), result[0].Pricing.YesSide.Ask)
+		assert.Equal(t, exchange_domain.MarketStateSettled, result[1].Status.State)
+		markets.AssertExpectations(t)
+	})
+
+	t.Run("splits large batches", func(t *testing.T) {
+		service, markets, _, _ := newTestService()
+
+		tickers := make([]contract.Ticker, 0, maxTickersPerRequest+1)
+		for i := 0; i <= maxTickersPerRequest; i++ {
+			tickers = append(tickers, contract.Ticker(fmt.Sprintf("MARKET-%d", i)))
+		}
+		markets.On("GetMarkets", mock.Anything, mock.Anything).Return(&kalshi.MarketsResult{}, nil).Twice()
+
+		result, err := service.GetMarkets(context.Background(), tickers)
+
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		markets.AssertExpectations(t)
+	})
+
+	t.Run("handles API error", func(t *testing.T) {
+		service, markets, _, _ := newTestService()
+
+		markets.On("GetMarkets", mock.Anything, mock.Anything).Return(nil, errors.New("API error"))
+
+		result, err := service.GetMarkets(context.Background(), []contract.Ticker{"MARKET-1"})
+
+		assert.ErrorContains(t, err, "fetch markets from kalshi")
+		assert.Nil(t, result)
+	})
+}
+
+func TestKalshiExchangeService_GetOrderbook(t *testing.T) {
+	service, markets, _, _ := newTestService()
+
+	markets.On("GetMarketOrderbook", mock.Anything, "TEST-MARKET", (*int)(nil)).Return(&kalshi.OrderbookResponse{
+		Orderbook: kalshi.Orderbook{
+			Yes: [][]int{{40, 10}, {42, 5}},
+		},
+	}, nil)
+
+	result, err := service.GetOrderbook(context.Background(), "TEST-MARKET")
+
+	require.NoError(t, err)
+	assert.Equal(t, contract.Ticker("TEST-MARKET"), result.Ticker)
+	assert.Equal(t, []exchange_domain.OrderbookLevel{{Price: 40, Quantity: 10}, {Price: 42, Quantity: 5}}, result.YesBids)
+	assert.Empty(t, result.NoBids)
+	markets.AssertExpectations(t)
+}
+
+func TestMapMarketState(t *testing.T) {
+	tests := []struct {
+		status   kalshi.MarketStatus
+		expected exchange_domain.MarketState
+	}{
+		{kalshi.MarketStatusInitialized, exchange_domain.MarketStateUnopened},
+		{kalshi.MarketStatusActive, exchange_domain.MarketStateOpen},
+		{kalshi.MarketStatusOpen, exchange_domain.MarketStateOpen},
+		{kalshi.MarketStatusClosed, exchange_domain.MarketStateClosed},
+		{kalshi.MarketStatusDetermined, exchange_domain.MarketStateSettled},
+		{kalshi.MarketStatusFinalized, exchange_domain.MarketStateSettled},
+		{kalshi.MarketStatusSettled, exchange_domain.MarketStateSettled},
+		{"unknown", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.expected, mapMarketState(tt.status))
+		})
+	}
+}
+
+func TestMapOrderStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    kalshi.Order
+		expected string
+	}{
+		{"resting", kalshi.Order{Status: kalshi.OrderStatusResting}, exchange_domain.OrderStatusResting},
+		{"resting with fills", kalshi.Order{Status: kalshi.OrderStatusResting, MakerFillCount: 2}, exchange_domain.OrderStatusPartiallyFilled},
+		{"pending", kalshi.Order{Status: kalshi.OrderStatusPending}, exchange_domain.OrderStatusResting},
+		{"executed", kalshi.Order{Status: kalshi.OrderStatusExecuted, TakerFillCount: 5}, exchange_domain.OrderStatusExecuted},
+		{"canceled after fills", kalshi.Order{Status: kalshi.OrderStatusCanceled, TakerFillCount: 2}, exchange_domain.OrderStatusCanceled},
+		{"expired", kalshi.Order{Status: kalshi.OrderStatusExpired}, exchange_domain.OrderStatusCanceled},
+		{"unknown", kalshi.Order{Status: "unknown"}, "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mapOrderStatus(tt.order))
+		})
+	}
+}
+
+func TestKalshiExchangeService_CancelOrder(t *testing.T) {
+	t.Run("returns the order with its fills", func(t *testing.T) {
+		service, _, _, orders := newTestService()
+		orders.On("CancelOrder", mock.Anything, "order-123").Return(&kalshi.CancelOrderResponse{
+			Order: kalshi.Order{
+				ID:             "order-123",
+				Ticker:         "TEST-1234",
+				Side:           kalshi.OrderSideYes,
+				Action:         string(kalshi.OrderActionSell),
+				Type:           kalshi.OrderTypeLimit,
+				Status:         kalshi.OrderStatusCanceled,
+				YesPrice:       40,
+				PlaceCount:     10,
+				TakerFillCount: 3,
+			},
+			ReducedBy: 7,
+		}, nil)
+
+		order, err := service.CancelOrder(context.Background(), "order-123")
+
+		require.NoError(t, err)
+		assert.Equal(t, exchange_domain.OrderStatusCanceled, order.Status)
+		assert.Equal(t, uint(3), order.FilledQuantity)
+		assert.Equal(t, uint(7), order.Remaining())
+		orders.AssertExpectations(t)
+	})
+
+	t.Run("handles API error", func(t *testing.T) {
+		service, _, _, orders := newTestService()
+		orders.On("CancelOrder", mock.Anything, "order-123").Return(nil, errors.New("API error"))
+
+		order, err := service.CancelOrder(context.Background(), "order-123")
+
+		assert.ErrorContains(t, err, "cancel order on kalshi")
+		assert.Nil(t, order)
+	})
+}
+
 func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 	t.Run("successful sell order creation", func(t *testing.T) {
 		// Set up mocks
@@ -197,7 +409,7 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 		limitPrice := contract.ContractPrice(50)
 
 		// Mock GetPositions response
-		positions.On("GetPositions", kalshi.GetPositionsOptions{
+		positions.On("GetPositions", mock.Anything, kalshi.GetPositionsOptions{
 			Ticker: stringPtr("TEST-1234"),
 		}).Return(&kalshi.PositionsResult{
 			MarketPositions: []kalshi.MarketPosition{
@@ -218,7 +430,7 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 			Type:          "limit",
 			YesPrice:      intPtr(50),
 		}
-		orders.On("CreateOrder", expectedRequest).Return(&kalshi.CreateOrderResponse{
+		orders.On("CreateOrder", mock.Anything, expectedRequest).Return(&kalshi.CreateOrderResponse{
 			Order: kalshi.Order{
 				ID:     "order-123",
 				Ticker: "TEST-1234",
@@ -234,7 +446,7 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 			LimitPrice: &limitPrice,
 			Reference:  reference,
 		}
-		order, err := service.CreateOrder(params)
+		order, err := service.CreateOrder(context.Background(), params)
 
 		// Verify results
 		require.NoError(t, err)
@@ -242,15 +454,93 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 		assert.Equal(t, "order-123", order.ExchangeOrderID)
 		assert.Equal(t, exchange_domain.OrderActionSell, order.Action)
 		assert.Equal(t, contractID.Side, order.Side)
+		assert.Equal(t, uint(10), order.Quantity)
+		assert.Equal(t, &limitPrice, order.LimitPrice)
 		positions.AssertExpectations(t)
 		orders.AssertExpectations(t)
 	})
 
+	t.Run("successful buy order creation", func(t *testing.T) {
+		service, _, positions, orders := newTestService()
+
+		quantity := uint(10)
+		limitPrice := contract.ContractPrice(20)
+		maxCost := 250
+
+		expectedRequest := kalshi.CreateOrderRequest{
+			Ticker:        "TEST-1234",
+			ClientOrderID: "test-ref",
+			Side:          kalshi.OrderSideNo,
+			Action:        kalshi.OrderActionBuy,
+			Count:         10,
+			Type:          "limit",
+			NoPrice:       intPtr(20),
+			BuyMaxCost:    intPtr(250),
+		}
+		orders.On("CreateOrder", mock.Anything, expectedRequest).Return(&kalshi.CreateOrderResponse{
+			Order: kalshi.Order{
+				ID:     "order-456",
+				Ticker: "TEST-1234",
+				Status: "resting",
+			},
+		}, nil)
+
+		params := OrderParams{
+			ContractID: contract.ContractIdentifier{
+				Ticker: "TEST-1234",
+				Side:   contract.SideNo,
+			},
+			Action:     exchange_domain.OrderActionBuy,
+			Quantity:   &quantity,
+			LimitPrice: &limitPrice,
+			MaxCost:    &maxCost,
+			Reference:  "test-ref",
+		}
+		order, err := service.CreateOrder(context.Background(), params)
+
+		require.NoError(t, err)
+		require.NotNil(t, order)
+		assert.Equal(t, "order-456", order.ExchangeOrderID)
+		assert.Equal(t, exchange_domain.OrderActionBuy, order.Action)
+		assert.Equal(t, exchange_domain.OrderTypeLimit, order.OrderType)
+		positions.AssertNotCalled(t, "GetPositions", mock.Anything, mock.Anything)
+		orders.AssertExpectations(t)
+	})
+
+	t.Run("buy order validation", func(t *testing.T) {
+		quantity := uint(10)
+		testCases := []struct {
+			name         string
+			quantity     *uint
+			errorMessage string
+		}{
+			{name: "missing quantity", quantity: nil, errorMessage: "positive quantity"},
+			{name: "no price bound", quantity: &quantity, errorMessage: "limit price or max cost"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				service, _, _, orders := newTestService()
+
+				order, err := service.CreateOrder(context.Background(), OrderParams{
+					ContractID: contract.ContractIdentifier{Ticker: "TEST-1234", Side: contract.SideYes},
+					Action:     exchange_domain.OrderActionBuy,
+					Quantity:   tc.quantity,
+					Reference:  "test-ref",
+				})
+
+				assert.ErrorContains(t, err, tc.errorMessage)
+				assert.Nil(t, order)
+				orders.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
 	t.Run("sell order with no position available", func(t *testing.T) {
 		service, _, positions, _ := newTestService()
 
 		// Mock empty positions response
-		positions.On("GetPositions", mock.Anything).Return(&kalshi.PositionsResult{
+		positions.On("GetPositions", mock.Anything, mock.Anything).Return(&kalshi.PositionsResult{
 			MarketPositions: []kalshi.MarketPosition{},
 		}, nil)
 
@@ -262,7 +552,7 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 			Action:    exchange_domain.OrderActionSell,
 			Reference: "test-ref",
 		}
-		order, err := service.CreateOrder(params)
+		order, err := service.CreateOrder(context.Background(), params)
 
 		require.Error(t, err)
 		assert.Nil(t, order)
@@ -273,7 +563,7 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 	t.Run("position service error", func(t *testing.T) {
 		service, _, positions, _ := newTestService()
 
-		positions.On("GetPositions", mock.Anything).Return(nil, errors.New("service error"))
+		positions.On("GetPositions", mock.Anything, mock.Anything).Return(nil, errors.New("service error"))
 
 		params := OrderParams{
 			ContractID: contract.ContractIdentifier{
@@ -283,7 +573,7 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 			Action:    exchange_domain.OrderActionSell,
 			Reference: "test-ref",
 		}
-		order, err := service.CreateOrder(params)
+		order, err := service.CreateOrder(context.Background(), params)
 
 		require.Error(t, err)
 		assert.Nil(t, order)
@@ -295,6 +585,7 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 		testCases := []struct {
 			name          string
 			position      int
+			side          contract.Side
 			requestSize   *uint
 			expectedCount int
 		}{
@@ -304,6 +595,20 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 				requestSize:   nil,
 				expectedCount: 15,
 			},
+			{
+				name:          "full NO position, reported as negative",
+				position:      -15,
+				side:          contract.SideNo,
+				requestSize:   nil,
+				expectedCount: 15,
+			},
+			{
+				name:          "NO position limited by requested size",
+				position:      -20,
+				side:          contract.SideNo,
+				requestSize:   uintPtr(10),
+				expectedCount: 10,
+			},
 			{
 				name:          "limited by requested size",
 				position:      20,
@@ -322,7 +627,7 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 			t.Run(tc.name, func(t *testing.T) {
 				service, _, positions, orders := newTestService()
 
-				positions.On("GetPositions", mock.Anything).Return(&kalshi.PositionsResult{
+				positions.On("GetPositions", mock.Anything, mock.Anything).Return(&kalshi.PositionsResult{
 					MarketPositions: []kalshi.MarketPosition{
 						{
 							Ticker:   "TEST-1234",
@@ -331,8 +636,17 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 					},
 				}, nil)
 
-				orders.On("CreateOrder", mock.MatchedBy(func(req kalshi.CreateOrderRequest) bool {
-					return req.Count == tc.expectedCount
+				side := tc.side
+				if side == nil {
+					side = contract.SideYes
+				}
+				orderSide := kalshi.OrderSideYes
+				if side == contract.SideNo {
+					orderSide = kalshi.OrderSideNo
+				}
+
+				orders.On("CreateOrder", mock.Anything, mock.MatchedBy(func(req kalshi.CreateOrderRequest) bool {
+					return req.Count == tc.expectedCount && req.Side == orderSide
 				})).Return(&kalshi.CreateOrderResponse{
 					Order: kalshi.Order{
 						ID:     "order-123",
@@ -344,21 +658,56 @@ func TestKalshiExchangeService_CreateOrder(t *testing.T) {
 				params := OrderParams{
 					ContractID: contract.ContractIdentifier{
 						Ticker: "TEST-1234",
-						Side:   contract.SideYes,
+						Side:   side,
 					},
 					Action:    exchange_domain.OrderActionSell,
 					Quantity:  tc.requestSize,
 					Reference: "test-ref",
 				}
-				order, err := service.CreateOrder(params)
+				order, err := service.CreateOrder(context.Background(), params)
 
 				require.NoError(t, err)
 				require.NotNil(t, order)
+				assert.Equal(t, uint(tc.expectedCount), order.Quantity)
+				assert.Equal(t, side, order.Side)
 				positions.AssertExpectations(t)
 				orders.AssertExpectations(t)
 			})
 		}
 	})
+
+	t.Run("sell on the side not held", func(t *testing.T) {
+		testCases := []struct {
+			name         string
+			position     int
+			side         contract.Side
+			errorMessage string
+		}{
+			{name: "YES sell against a NO position", position: -10, side: contract.SideYes, errorMessage: "held on NO"},
+			{name: "NO sell against a YES position", position: 10, side: contract.SideNo, errorMessage: "held on YES"},
+			{name: "flat position", position: 0, side: contract.SideNo, errorMessage: "no position held"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				service, _, positions, orders := newTestService()
+
+				positions.On("GetPositions", mock.Anything, mock.Anything).Return(&kalshi.PositionsResult{
+					MarketPositions: []kalshi.MarketPosition{{Ticker: "TEST-1234", Position: tc.position}},
+				}, nil)
+
+				order, err := service.CreateOrder(context.Background(), OrderParams{
+					ContractID: contract.ContractIdentifier{Ticker: "TEST-1234", Side: tc.side},
+					Action:     exchange_domain.OrderActionSell,
+					Reference:  "test-ref",
+				})
+
+				assert.ErrorContains(t, err, tc.errorMessage)
+				assert.Nil(t, order)
+				orders.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+			})
+		}
+	})
 }
 
 // Helper functions for creating pointers to values
@@ -373,3 +722,40 @@ func intPtr(i int) *int {
 func uintPtr(u uint) *uint {
 	return &u
 }
+
+func TestKalshiExchangeService_GetOrders(t *testing.T) {
+	service, _, _, orders := newTestService()
+	since := time.Date(2025, 2, 19, 12, 0, 0, 0, time.UTC)
+	created := since.Add(time.Minute)
+
+	orders.On("GetOrders", mock.Anything, kalshi.GetOrdersOptions{MinTs: &since}).Return(&kalshi.OrdersResult{
+		Orders: []kalshi.Order{{
+			ID:            "order-789",
+			ClientOrderID: "trigger-ref",
+			Ticker:        "TEST-1234",
+			Side:          kalshi.OrderSideNo,
+			Action:        string(kalshi.OrderActionSell),
+			Type:          kalshi.OrderTypeLimit,
+			Status:        "resting",
+			YesPrice:      70,
+			NoPrice:       30,
+			PlaceCount:    5,
+			CreatedTime:   created,
+		}},
+	}, nil)
+
+	result, err := service.GetOrders(context.Background(), since)
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	order := result[0]
+	assert.Equal(t, "order-789", order.ExchangeOrderID)
+	assert.Equal(t, "trigger-ref", order.Reference)
+	assert.Equal(t, contract.SideNo, order.Side)
+	assert.Equal(t, exchange_domain.OrderActionSell, order.Action)
+	assert.Equal(t, uint(5), order.Quantity)
+	require.NotNil(t, order.LimitPrice)
+	assert.Equal(t, contract.ContractPrice(30), *order.LimitPrice)
+	assert.Equal(t, created, order.CreatedAt)
+	orders.AssertExpectations(t)
+}
@@ -0,0 +1,263 @@
+package exchange_service
+
+import (
+	"context"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+type marketDataGetter interface {
+	GetMarket(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Market, error)
+	GetMarkets(ctx context.Context, tickers []contract.Ticker) ([]*exchange_domain.Market, error)
+	GetOrderbook(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Orderbook, error)
+}
+
+type paperLedger interface {
+	PersistOrder(ctx context.Context, order *exchange_domain.PaperOrder, position *exchange_domain.PaperPosition) error
+	GetPosition(ctx context.Context, contractID contract.ContractIdentifier) (*exchange_domain.PaperPosition, error)
+	GetPositions(ctx context.Context) ([]*exchange_domain.PaperPosition, error)
+	GetOrders(ctx context.Context, since time.Time) ([]*exchange_domain.PaperOrder, error)
+}
+
+// PaperExchangeService simulates trading for dry runs. Market data comes from
+// Kalshi, but orders fill against the current bid or ask and are recorded in a
+// simulated ledger instead of being sent to the exchange.
+type PaperExchangeService struct {
+	markets marketDataGetter
+	ledger  paperLedger
+	mu      sync.Mutex // serializes fills so positions are updated one order at a time
+}
+
+func NewPaperExchangeService(markets marketDataGetter, ledger paperLedger) *PaperExchangeService {
+	return &PaperExchangeService{
+		markets: markets,
+		ledger:  ledger,
+	}
+}
+
+func (ps *PaperExchangeService) GetMarket(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Market, error) {
+	return ps.markets.GetMarket(ctx, ticker)
+}
+
+func (ps *PaperExchangeService) GetMarkets(ctx context.Context, tickers []contract.Ticker) ([]*exchange_domain.Market, error) {
+	return ps.markets.GetMarkets(ctx, tickers)
+}
+
+func (ps *PaperExchangeService) GetOrderbook(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Orderbook, error) {
+	return ps.markets.GetOrderbook(ctx, ticker)
+}
+
+// GetPositions returns the open paper positions
+func (ps *PaperExchangeService) GetPositions(ctx context.Context) ([]*exchange_domain.Position, error) {
+	paperPositions, err := ps.ledger.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get paper positions: %w", err)
+	}
+
+	open := lo.Filter(paperPositions, func(p *exchange_domain.PaperPosition, _ int) bool {
+		return p.Quantity > 0
+	})
+	return lo.Map(open, func(p *exchange_domain.PaperPosition, _ int) *exchange_domain.Position {
+		position := p.Position
+		return &position
+	}), nil
+}
+
+// GetEventPositions groups paper positions by event. Paper trades pay no fees.
+func (ps *PaperExchangeService) GetEventPositions(ctx context.Context) ([]*exchange_domain.EventPosition, error) {
+	paperPositions, err := ps.ledger.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get paper positions: %w", err)
+	}
+
+	events := make([]*exchange_domain.EventPosition, 0)
+	for _, paperPosition := range paperPositions {
+		eventTicker := eventTickerOf(paperPosition.ContractID.Ticker)
+		event, ok := lo.Find(events, func(e *exchange_domain.EventPosition) bool {
+			return e.EventTicker == eventTicker
+		})
+		if !ok {
+			event = &exchange_domain.EventPosition{
+				EventTicker: eventTicker,
+				Positions:   make([]*exchange_domain.Position, 0),
+			}
+			events = append(events, event)
+		}
+
+		event.RealizedPNL += paperPosition.RealizedPNL
+		event.TotalCost += paperPosition.CostBasis
+		if paperPosition.Quantity > 0 {
+			position := paperPosition.Position
+			event.Exposure += position.CostBasis
+			event.Positions = append(event.Positions, &position)
+		}
+	}
+
+	return events, nil
+}
+
+// GetPNL marks every paper position to its current bid
+func (ps *PaperExchangeService) GetPNL(ctx context.Context) (*exchange_domain.PaperPNL, error) {
+	paperPositions, err := ps.ledger.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get paper positions: %w", err)
+	}
+
+	var pnl exchange_domain.PaperPNL
+	for _, position := range paperPositions {
+		pnl.Realized += position.RealizedPNL
+		if position.Quantity == 0 {
+			continue
+		}
+		market, err := ps.markets.GetMarket(ctx, position.ContractID.Ticker)
+		if err != nil {
+			return nil, fmt.Errorf("get market %s: %w", position.ContractID.Ticker, err)
+		}
+		pricing, err := sidePricing(market, position.ContractID.Side)
+		if err != nil {
+			return nil, err
+		}
+		pnl.Unrealized += position.UnrealizedPNL(pricing.Bid)
+	}
+
+	return &pnl, nil
+}
+
+// CreateOrder fills the order in full against the current ask for buys or bid for
+// sells. Orders that are not marketable, because of their limit price or an empty
+// book, are cancelled rather than left resting.
+func (ps *PaperExchangeService) CreateOrder(ctx context.Context, orderParams OrderParams) (*exchange_domain.Order, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	contractID := orderParams.ContractID
+
+	market, err := ps.markets.GetMarket(ctx, contractID.Ticker)
+	if err != nil {
+		return nil, fmt.Errorf("get market: %w", err)
+	}
+	pricing, err := sidePricing(market, contractID.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	position, err := ps.ledger.GetPosition(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("get paper position: %w", err)
+	}
+	if position == nil {
+		position = exchange_domain.NewPaperPosition(contractID)
+	}
+
+	var quantity uint
+	var price contract.ContractPrice
+	var marketable bool
+	switch orderParams.Action {
+	case exchange_domain.OrderActionBuy:
+		if orderParams.Quantity == nil || *orderParams.Quantity == 0 {
+			return nil, fmt.Errorf("buy order must have a positive quantity")
+		}
+		if orderParams.LimitPrice == nil && orderParams.MaxCost == nil {
+			return nil, fmt.Errorf("buy order must have a limit price or max cost")
+		}
+		quantity = *orderParams.Quantity
+		price = pricing.Ask
+		marketable = price > 0 && (orderParams.LimitPrice == nil || price <= *orderParams.LimitPrice)
+		if marketable && orderParams.MaxCost != nil {
+			quantity = min(quantity, uint(*orderParams.MaxCost/price.Value()))
+		}
+	case exchange_domain.OrderActionSell:
+		if position.Quantity == 0 {
+			return nil, fmt.Errorf("position not found for ticker: %s", contractID.Ticker)
+		}
+		quantity = position.Quantity
+		if orderParams.Quantity != nil {
+			quantity = min(quantity, *orderParams.Quantity)
+		}
+		price = pricing.Bid
+		marketable = price > 0 && (orderParams.LimitPrice == nil || price >= *orderParams.LimitPrice)
+	default:
+		return nil, fmt.Errorf("invalid order action: %s", orderParams.Action)
+	}
+
+	orderType := exchange_domain.OrderTypeMarket
+	if orderParams.LimitPrice != nil {
+		orderType = exchange_domain.OrderTypeLimit
+	}
+	order := &exchange_domain.PaperOrder{
+		Order: *exchange_domain.NewOrder(
+			"",
+			exchange_domain.ExchangePaper,
+			orderParams.Reference,
+			string(contractID.Ticker),
+			contractID.Side,
+			orderParams.Action,
+			orderType,
+			exchange_domain.PaperOrderCanceled,
+		),
+	}
+	order.ExchangeOrderID = "paper-" + order.OrderID.String()
+	order.Quantity = quantity
+	order.LimitPrice = orderParams.LimitPrice
+
+	var changedPosition *exchange_domain.PaperPosition
+	if marketable && quantity > 0 {
+		fill := exchange_domain.PaperFill{Quantity: quantity, Price: price}
+		if err := position.ApplyFill(orderParams.Action, fill); err != nil {
+			return nil, err
+		}
+		order.Fill = &fill
+		order.FilledQuantity = fill.Quantity
+		order.Status = exchange_domain.PaperOrderExecuted
+		changedPosition = position
+	}
+
+	if err := ps.ledger.PersistOrder(ctx, order, changedPosition); err != nil {
+		return nil, fmt.Errorf("persist paper order: %w", err)
+	}
+
+	return &order.Order, nil
+}
+
+// GetOrders lists the paper orders placed since the given time
+func (ps *PaperExchangeService) GetOrders(ctx context.Context, since time.Time) ([]*exchange_domain.Order, error) {
+	paperOrders, err := ps.ledger.GetOrders(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("get paper orders: %w", err)
+	}
+	return lo.Map(paperOrders, func(o *exchange_domain.PaperOrder, _ int) *exchange_domain.Order {
+		order := o.Order
+		return &order
+	}), nil
+}
+
+// CancelOrder always fails, as paper orders are never left resting
+func (ps *PaperExchangeService) CancelOrder(ctx context.Context, exchangeOrderID string) (*exchange_domain.Order, error) {
+	return nil, fmt.Errorf("paper order %s is not resting", exchangeOrderID)
+}
+
+func sidePricing(market *exchange_domain.Market, side contract.Side) (exchange_domain.PricingSide, error) {
+	switch side {
+	case contract.SideYes:
+		return market.Pricing.YesSide, nil
+	case contract.SideNo:
+		return market.Pricing.NoSide, nil
+	default:
+		return exchange_domain.PricingSide{}, fmt.Errorf("invalid contract side: %s", side)
+	}
+}
+
+// eventTickerOf strips the market suffix, e.g. KXHIGHNY-25FEB13-T40 is in KXHIGHNY-25FEB13
+func eventTickerOf(ticker contract.Ticker) contract.Ticker {
+	i := strings.LastIndex(string(ticker), "-")
+	if i < 0 {
+		return ticker
+	}
+	return ticker[:i]
+}
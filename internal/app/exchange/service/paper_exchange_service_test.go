@@ -0,0 +1,179 @@
+package exchange_service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	exchange_mock "prediction-risk/internal/app/exchange/mock"
+)
+
+func newPaperTestService() (
+	*PaperExchangeService,
+	*exchange_mock.MockMarketDataGetter,
+	*exchange_mock.MockPaperLedger,
+) {
+	markets := new(exchange_mock.MockMarketDataGetter)
+	ledger := new(exchange_mock.MockPaperLedger)
+	return NewPaperExchangeService(markets, ledger), markets, ledger
+}
+
+func newPricedMarket(ticker contract.Ticker, yesBid, yesAsk contract.ContractPrice) *exchange_domain.Market {
+	return &exchange_domain.Market{
+		Ticker: ticker,
+		Pricing: exchange_domain.MarketPricing{
+			YesSide: exchange_domain.PricingSide{Bid: yesBid, Ask: yesAsk},
+			NoSide:  exchange_domain.PricingSide{Bid: 100 - yesAsk, Ask: 100 - yesBid},
+		},
+	}
+}
+
+func TestPaperExchangeService_CreateOrder(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "KXHIGHNY-25JUL01-T80", Side: contract.SideYes}
+
+	t.Run("buy fills at the ask", func(t *testing.T) {
+		service, markets, ledger := newPaperTestService()
+		markets.On("GetMarket", mock.Anything, foo.Ticker).Return(newPricedMarket(foo.Ticker, 40, 45), nil)
+		ledger.On("GetPosition", mock.Anything, foo).Return(nil, nil)
+		ledger.On("PersistOrder", mock.Anything,
+			mock.MatchedBy(func(o *exchange_domain.PaperOrder) bool {
+				return o.Status == exchange_domain.PaperOrderExecuted &&
+					o.Fill.Quantity == 10 && o.Fill.Price == 45
+			}),
+			mock.MatchedBy(func(p *exchange_domain.PaperPosition) bool {
+				return p.Quantity == 10 && p.CostBasis == 450
+			}),
+		).Return(nil).Once()
+
+		order, err := service.CreateOrder(context.Background(), OrderParams{
+			ContractID: foo,
+			Quantity:   lo.ToPtr(uint(10)),
+			Action:     exchange_domain.OrderActionBuy,
+			Reference:  "ref",
+			MaxCost:    lo.ToPtr(1000),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, exchange_domain.ExchangePaper, order.Exchange)
+		assert.Equal(t, exchange_domain.OrderTypeMarket, order.OrderType)
+		assert.Equal(t, exchange_domain.PaperOrderExecuted, order.Status)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("buy is capped by max cost", func(t *testing.T) {
+		service, markets, ledger := newPaperTestService()
+		markets.On("GetMarket", mock.Anything, foo.Ticker).Return(newPricedMarket(foo.Ticker, 40, 45), nil)
+		ledger.On("GetPosition", mock.Anything, foo).Return(nil, nil)
+		ledger.On("PersistOrder", mock.Anything,
+			mock.MatchedBy(func(o *exchange_domain.PaperOrder) bool { return o.Fill.Quantity == 4 }),
+			mock.Anything,
+		).Return(nil).Once()
+
+		_, err := service.CreateOrder(context.Background(), OrderParams{
+			ContractID: foo,
+			Quantity:   lo.ToPtr(uint(10)),
+			Action:     exchange_domain.OrderActionBuy,
+			MaxCost:    lo.ToPtr(200),
+		})
+
+		require.NoError(t, err)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("unmarketable limit buy is cancelled", func(t *testing.T) {
+		service, markets, ledger := newPaperTestService()
+		markets.On("GetMarket", mock.Anything, foo.Ticker).Return(newPricedMarket(foo.Ticker, 40, 45), nil)
+		ledger.On("GetPosition", mock.Anything, foo).Return(nil, nil)
+		ledger.On("PersistOrder", mock.Anything,
+			mock.MatchedBy(func(o *exchange_domain.PaperOrder) bool {
+				return o.Status == exchange_domain.PaperOrderCanceled && o.Fill == nil
+			}),
+			(*exchange_domain.PaperPosition)(nil),
+		).Return(nil).Once()
+
+		order, err := service.CreateOrder(context.Background(), OrderParams{
+			ContractID: foo,
+			Quantity:   lo.ToPtr(uint(10)),
+			Action:     exchange_domain.OrderActionBuy,
+			LimitPrice: lo.ToPtr(contract.ContractPrice(42)),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, exchange_domain.OrderTypeLimit, order.OrderType)
+		assert.Equal(t, exchange_domain.PaperOrderCanceled, order.Status)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("sell closes the position at the bid", func(t *testing.T) {
+		service, markets, ledger := newPaperTestService()
+		held := exchange_domain.NewPaperPosition(foo)
+		require.NoError(t, held.ApplyFill(exchange_domain.OrderActionBuy, exchange_domain.PaperFill{Quantity: 10, Price: 45}))
+
+		markets.On("GetMarket", mock.Anything, foo.Ticker).Return(newPricedMarket(foo.Ticker, 30, 33), nil)
+		ledger.On("GetPosition", mock.Anything, foo).Return(held, nil)
+		ledger.On("PersistOrder", mock.Anything,
+			mock.MatchedBy(func(o *exchange_domain.PaperOrder) bool {
+				return o.Fill.Quantity == 10 && o.Fill.Price == 30
+			}),
+			mock.MatchedBy(func(p *exchange_domain.PaperPosition) bool {
+				return p.Quantity == 0 && p.RealizedPNL == -150
+			}),
+		).Return(nil).Once()
+
+		_, err := service.CreateOrder(context.Background(), OrderParams{ContractID: foo, Action: exchange_domain.OrderActionSell})
+
+		require.NoError(t, err)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("sell without a position fails", func(t *testing.T) {
+		service, markets, ledger := newPaperTestService()
+		markets.On("GetMarket", mock.Anything, foo.Ticker).Return(newPricedMarket(foo.Ticker, 30, 33), nil)
+		ledger.On("GetPosition", mock.Anything, foo).Return(nil, nil)
+
+		_, err := service.CreateOrder(context.Background(), OrderParams{ContractID: foo, Action: exchange_domain.OrderActionSell})
+
+		assert.ErrorContains(t, err, "position not found")
+		ledger.AssertNotCalled(t, "PersistOrder", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPaperExchangeService_Positions(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "KXHIGHNY-25JUL01-T80", Side: contract.SideYes}
+	bar := contract.ContractIdentifier{Ticker: "KXHIGHNY-25JUL01-B78.5", Side: contract.SideNo}
+
+	open := exchange_domain.NewPaperPosition(foo)
+	require.NoError(t, open.ApplyFill(exchange_domain.OrderActionBuy, exchange_domain.PaperFill{Quantity: 10, Price: 45}))
+	closed := exchange_domain.NewPaperPosition(bar)
+	require.NoError(t, closed.ApplyFill(exchange_domain.OrderActionBuy, exchange_domain.PaperFill{Quantity: 5, Price: 20}))
+	require.NoError(t, closed.ApplyFill(exchange_domain.OrderActionSell, exchange_domain.PaperFill{Quantity: 5, Price: 30}))
+
+	service, markets, ledger := newPaperTestService()
+	ledger.On("GetPositions", mock.Anything).Return([]*exchange_domain.PaperPosition{open, closed}, nil)
+	markets.On("GetMarket", mock.Anything, foo.Ticker).Return(newPricedMarket(foo.Ticker, 50, 52), nil)
+
+	positions, err := service.GetPositions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.Equal(t, foo, positions[0].ContractID)
+
+	events, err := service.GetEventPositions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, contract.Ticker("KXHIGHNY-25JUL01"), events[0].EventTicker)
+	assert.Equal(t, 450, events[0].Exposure)
+	assert.Equal(t, 50, events[0].RealizedPNL)
+	assert.Len(t, events[0].Positions, 1)
+
+	pnl, err := service.GetPNL(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 50, pnl.Realized)
+	assert.Equal(t, 50, pnl.Unrealized)
+	assert.Equal(t, 100, pnl.Total())
+}
@@ -0,0 +1,64 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NewConditionalTrigger creates a trigger that fires its actions when a
+// composite condition tree is satisfied. The condition may span several contracts.
+func NewConditionalTrigger(
+	condition TriggerCondition,
+	actions []TriggerAction,
+) (*Trigger, error) {
+	trigger := NewTrigger(TriggerTypeConditional, condition, actions)
+
+	if err := ValidateConditionalTrigger(trigger); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+func ValidateConditionalTrigger(t *Trigger) error {
+	// Basic trigger validation
+	if t == nil {
+		return errors.New("trigger cannot be nil")
+	}
+	if t.TriggerType != TriggerTypeConditional {
+		return fmt.Errorf("invalid trigger type: expected %s, got %s", TriggerTypeConditional, t.TriggerType)
+	}
+
+	// Condition validation
+	if err := t.Condition.Validate(); err != nil {
+		return fmt.Errorf("invalid condition: %w", err)
+	}
+
+	// Actions validation
+	if len(t.Actions) == 0 {
+		return errors.New("conditional trigger must have at least one action")
+	}
+	for _, action := range t.Actions {
+		if !action.Side.IsValid() {
+			return fmt.Errorf("invalid conditional trigger action side: %s", action.Side)
+		}
+		if action.Side == Buy {
+			if err := action.validateBuy(); err != nil {
+				return fmt.Errorf("invalid buy action: %w", err)
+			}
+		}
+		if action.Contract.Ticker == "" || action.Contract.Side == nil {
+			return errors.New("conditional trigger action must have a contract")
+		}
+		if action.LimitPrice != nil && !action.LimitPrice.IsValid() {
+			return fmt.Errorf("invalid limit price: %v", *action.LimitPrice)
+		}
+	}
+
+	// Status validation
+	if !t.Status.IsValid() {
+		return fmt.Errorf("invalid trigger status: %s", t.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConditionalTrigger(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bar := contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes}
+
+	fooAbove, err := NewPriceCondition(foo, 60, Above)
+	require.NoError(t, err)
+	barBelow, err := NewPriceCondition(bar, 30, Below)
+	require.NoError(t, err)
+	composite, err := NewCompositeCondition(OperatorAnd, *fooAbove, *barBelow)
+	require.NoError(t, err)
+
+	sellFoo, err := NewTriggerAction(foo, Sell, nil, nil)
+	require.NoError(t, err)
+	size := uint(5)
+	buyBar, err := NewTriggerAction(bar, Buy, &size, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		condition    TriggerCondition
+		actions      []TriggerAction
+		errorMessage string
+	}{
+		{
+			name:      "valid composite trigger",
+			condition: *composite,
+			actions:   []TriggerAction{*sellFoo},
+		},
+		{
+			name:         "no actions",
+			condition:    *composite,
+			errorMessage: "at least one action",
+		},
+		{
+			name:      "buy action",
+			condition: *composite,
+			actions:   []TriggerAction{*sellFoo, *buyBar},
+		},
+		{
+			name:         "buy action without size",
+			condition:    *composite,
+			actions:      []TriggerAction{{Contract: bar, Side: Buy}},
+			errorMessage: "buy size must be positive",
+		},
+		{
+			name:         "invalid condition",
+			condition:    TriggerCondition{Operator: OperatorAnd, Children: []TriggerCondition{*fooAbove}},
+			actions:      []TriggerAction{*sellFoo},
+			errorMessage: "invalid condition",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := NewConditionalTrigger(tt.condition, tt.actions)
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, trigger)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, TriggerTypeConditional, trigger.TriggerType)
+			assert.Equal(t, StatusActive, trigger.Status)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Confirmation requires a trigger's condition to hold across several consecutive
+// evaluations, or for a minimum duration, before the trigger fires. This keeps
+// one-tick spikes in thin books from firing stops.
+type Confirmation struct {
+	RequiredEvaluations int           // consecutive satisfied evaluations, 0 if confirming by duration
+	RequiredDuration    time.Duration // minimum time satisfied, 0 if confirming by evaluations
+	SatisfiedCount      int           // consecutive satisfied evaluations so far
+	SatisfiedSince      *time.Time    // when the current satisfied streak began, nil if not satisfied
+}
+
+// NewEvaluationConfirmation requires the condition to hold for n consecutive evaluations
+func NewEvaluationConfirmation(n int) (*Confirmation, error) {
+	confirmation := &Confirmation{RequiredEvaluations: n}
+	if err := confirmation.Validate(); err != nil {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+// NewDurationConfirmation requires the condition to hold for at least d
+func NewDurationConfirmation(d time.Duration) (*Confirmation, error) {
+	confirmation := &Confirmation{RequiredDuration: d}
+	if err := confirmation.Validate(); err != nil {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+func (c Confirmation) Validate() error {
+	switch {
+	case c.RequiredEvaluations < 0 || c.RequiredDuration < 0:
+		return errors.New("confirmation requirements cannot be negative")
+	case c.RequiredEvaluations > 0 && c.RequiredDuration > 0:
+		return errors.New("confirmation must require evaluations or a duration, not both")
+	case c.RequiredEvaluations == 0 && c.RequiredDuration == 0:
+		return errors.New("confirmation must require evaluations or a duration")
+	case c.SatisfiedCount < 0:
+		return fmt.Errorf("invalid satisfied count: %d", c.SatisfiedCount)
+	}
+	return nil
+}
+
+// IsPending reports whether the condition is satisfied but not yet confirmed
+func (c Confirmation) IsPending() bool {
+	return c.SatisfiedCount > 0
+}
+
+// Observe records one evaluation of the condition and reports whether it is now confirmed.
+// Any unsatisfied evaluation resets the streak. Changed reports whether the state was modified.
+func (c *Confirmation) Observe(satisfied bool, at time.Time) (confirmed bool, changed bool) {
+	if !satisfied {
+		if !c.IsPending() {
+			return false, false
+		}
+		c.SatisfiedCount = 0
+		c.SatisfiedSince = nil
+		return false, true
+	}
+
+	c.SatisfiedCount++
+	if c.SatisfiedSince == nil {
+		since := at
+		c.SatisfiedSince = &since
+	}
+
+	if c.RequiredEvaluations > 0 {
+		return c.SatisfiedCount >= c.RequiredEvaluations, true
+	}
+	return at.Sub(*c.SatisfiedSince) >= c.RequiredDuration, true
+}
+
+// Reset clears any pending confirmation
+func (c *Confirmation) Reset() {
+	c.SatisfiedCount = 0
+	c.SatisfiedSince = nil
+}
+
+// SetConfirmation replaces the trigger's confirmation policy, discarding any pending progress.
+// A nil policy makes the trigger fire as soon as its condition is satisfied.
+func (t *Trigger) SetConfirmation(confirmation *Confirmation) error {
+	if t.Status.IsTerminal() {
+		return fmt.Errorf("cannot change confirmation of trigger in terminal status %s", t.Status)
+	}
+	if confirmation != nil {
+		if err := confirmation.Validate(); err != nil {
+			return err
+		}
+		confirmation.Reset()
+	}
+
+	t.Confirmation = confirmation
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Confirm records an evaluation of the trigger's condition and reports whether the trigger
+// should fire. Triggers without a confirmation policy fire as soon as they are satisfied.
+func (t *Trigger) Confirm(satisfied bool, at time.Time) (confirmed bool, changed bool) {
+	if t.Confirmation == nil {
+		return satisfied, false
+	}
+	return t.Confirmation.Observe(satisfied, at)
+}
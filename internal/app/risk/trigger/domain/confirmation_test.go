@@ -0,0 +1,91 @@
+package trigger_domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmation_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		confirmation Confirmation
+		errorMessage string
+	}{
+		{name: "evaluations", confirmation: Confirmation{RequiredEvaluations: 3}},
+		{name: "duration", confirmation: Confirmation{RequiredDuration: time.Minute}},
+		{name: "neither", confirmation: Confirmation{}, errorMessage: "must require evaluations or a duration"},
+		{
+			name:         "both",
+			confirmation: Confirmation{RequiredEvaluations: 3, RequiredDuration: time.Minute},
+			errorMessage: "not both",
+		},
+		{name: "negative", confirmation: Confirmation{RequiredEvaluations: -1}, errorMessage: "cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.confirmation.Validate()
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConfirmation_Observe(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("consecutive evaluations", func(t *testing.T) {
+		confirmation, err := NewEvaluationConfirmation(3)
+		require.NoError(t, err)
+
+		confirmed, _ := confirmation.Observe(true, start)
+		assert.False(t, confirmed)
+		confirmed, _ = confirmation.Observe(true, start.Add(5*time.Second))
+		assert.False(t, confirmed)
+		confirmed, _ = confirmation.Observe(true, start.Add(10*time.Second))
+		assert.True(t, confirmed)
+	})
+
+	t.Run("unsatisfied evaluation resets the streak", func(t *testing.T) {
+		confirmation, err := NewEvaluationConfirmation(2)
+		require.NoError(t, err)
+
+		confirmation.Observe(true, start)
+		confirmed, changed := confirmation.Observe(false, start.Add(5*time.Second))
+		assert.False(t, confirmed)
+		assert.True(t, changed)
+		assert.False(t, confirmation.IsPending())
+		assert.Nil(t, confirmation.SatisfiedSince)
+
+		confirmed, _ = confirmation.Observe(true, start.Add(10*time.Second))
+		assert.False(t, confirmed)
+	})
+
+	t.Run("unsatisfied evaluation without a streak changes nothing", func(t *testing.T) {
+		confirmation, err := NewEvaluationConfirmation(2)
+		require.NoError(t, err)
+
+		confirmed, changed := confirmation.Observe(false, start)
+		assert.False(t, confirmed)
+		assert.False(t, changed)
+	})
+
+	t.Run("minimum duration", func(t *testing.T) {
+		confirmation, err := NewDurationConfirmation(30 * time.Second)
+		require.NoError(t, err)
+
+		confirmed, _ := confirmation.Observe(true, start)
+		assert.False(t, confirmed)
+		confirmed, _ = confirmation.Observe(true, start.Add(20*time.Second))
+		assert.False(t, confirmed)
+		confirmed, _ = confirmation.Observe(true, start.Add(30*time.Second))
+		assert.True(t, confirmed)
+		assert.Equal(t, start, *confirmation.SatisfiedSince)
+	})
+}
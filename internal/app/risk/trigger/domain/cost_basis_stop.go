@@ -0,0 +1,128 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"time"
+)
+
+// CostBasisStop places a stop a fixed distance below the position's average entry
+// price, e.g. 15% below entry or 10c below cost basis. The stop level is resolved
+// to an absolute threshold and re-resolved whenever the position changes.
+type CostBasisStop struct {
+	OffsetType   TrailType // CENTS or PERCENT below the average entry
+	Offset       int       // cents for CENTS, whole percent for PERCENT
+	AverageEntry contract.ContractPrice
+}
+
+func newCostBasisStop(
+	offsetType TrailType,
+	offset int,
+	position exchange_domain.Position,
+) (*CostBasisStop, error) {
+	averageEntry, ok := position.AverageEntry()
+	if !ok {
+		return nil, fmt.Errorf("no cost basis for position in %s", position.ContractID.Ticker)
+	}
+
+	costBasis := &CostBasisStop{
+		OffsetType:   offsetType,
+		Offset:       offset,
+		AverageEntry: averageEntry,
+	}
+	if err := costBasis.validate(); err != nil {
+		return nil, err
+	}
+	return costBasis, nil
+}
+
+func (c CostBasisStop) validate() error {
+	if !c.OffsetType.IsValid() {
+		return fmt.Errorf("invalid offset type: %s", c.OffsetType)
+	}
+	if c.Offset <= 0 || c.Offset >= 100 {
+		return fmt.Errorf("offset must be between 1 and 99, got %d", c.Offset)
+	}
+	if !c.AverageEntry.IsValid() {
+		return fmt.Errorf("invalid average entry: %d", c.AverageEntry)
+	}
+	if c.StopPrice() <= 0 {
+		return fmt.Errorf("offset %d %s puts the stop at or below 0 for entry %d", c.Offset, c.OffsetType, c.AverageEntry)
+	}
+	return nil
+}
+
+// StopPrice is the stop level implied by the average entry
+func (c CostBasisStop) StopPrice() contract.ContractPrice {
+	entry := c.AverageEntry.Value()
+
+	var stop int
+	switch c.OffsetType {
+	case TrailTypePercent:
+		stop = entry * (100 - c.Offset) / 100
+	default:
+		stop = entry - c.Offset
+	}
+
+	if stop < 0 {
+		stop = 0
+	}
+	return contract.ContractPrice(stop)
+}
+
+// NewCostBasisStopTrigger creates a stop that sells the position once the price
+// falls the given offset below its average entry
+func NewCostBasisStopTrigger(
+	position exchange_domain.Position,
+	offsetType TrailType,
+	offset int,
+) (*Trigger, error) {
+	costBasis, err := newCostBasisStop(offsetType, offset, position)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stop level moves with the position, so exit at market like a trailing stop
+	trigger, err := NewStopTrigger(position.ContractID, costBasis.StopPrice(), nil)
+	if err != nil {
+		return nil, err
+	}
+	trigger.CostBasis = costBasis
+
+	if err := ValidateStopTrigger(trigger); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// UpdateCostBasis re-resolves the stop level from the position's current average entry.
+// Returns true if the trigger was modified.
+func (t *Trigger) UpdateCostBasis(position exchange_domain.Position) (bool, error) {
+	if t.CostBasis == nil || t.Condition.Price == nil {
+		return false, errors.New("trigger is not relative to cost basis")
+	}
+	if position.ContractID != t.Condition.Contract {
+		return false, fmt.Errorf("position contract (%v) does not match trigger contract (%v)",
+			position.ContractID, t.Condition.Contract)
+	}
+
+	averageEntry, ok := position.AverageEntry()
+	if !ok || averageEntry == t.CostBasis.AverageEntry {
+		return false, nil
+	}
+
+	updated := *t.CostBasis
+	updated.AverageEntry = averageEntry
+	if err := updated.validate(); err != nil {
+		return false, err
+	}
+
+	t.CostBasis = &updated
+	t.Condition.Price.Threshold = updated.StopPrice()
+	t.UpdatedAt = time.Now()
+
+	return true, nil
+}
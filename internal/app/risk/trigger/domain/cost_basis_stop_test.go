@@ -0,0 +1,103 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCostBasisStopTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	tests := []struct {
+		name          string
+		position      exchange_domain.Position
+		offsetType    TrailType
+		offset        int
+		expectedEntry contract.ContractPrice
+		expectedStop  contract.ContractPrice
+		errorMessage  string
+	}{
+		{
+			name:          "percent below entry",
+			position:      exchange_domain.Position{ContractID: contractID, Quantity: 10, CostBasis: 600},
+			offsetType:    TrailTypePercent,
+			offset:        15,
+			expectedEntry: 60,
+			expectedStop:  51,
+		},
+		{
+			name:          "cents below entry",
+			position:      exchange_domain.Position{ContractID: contractID, Quantity: 10, CostBasis: 600},
+			offsetType:    TrailTypeCents,
+			offset:        10,
+			expectedEntry: 60,
+			expectedStop:  50,
+		},
+		{
+			name:         "no position",
+			position:     exchange_domain.Position{ContractID: contractID},
+			offsetType:   TrailTypeCents,
+			offset:       10,
+			errorMessage: "no cost basis",
+		},
+		{
+			name:         "offset below zero",
+			position:     exchange_domain.Position{ContractID: contractID, Quantity: 10, CostBasis: 50},
+			offsetType:   TrailTypeCents,
+			offset:       10,
+			errorMessage: "at or below 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := NewCostBasisStopTrigger(tt.position, tt.offsetType, tt.offset)
+
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, trigger)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, TriggerTypeStop, trigger.TriggerType)
+			assert.Equal(t, tt.expectedEntry, trigger.CostBasis.AverageEntry)
+			assert.Equal(t, tt.expectedStop, trigger.Condition.Price.Threshold)
+			assert.Nil(t, trigger.Actions[0].LimitPrice)
+		})
+	}
+}
+
+func TestTrigger_UpdateCostBasis(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	position := exchange_domain.Position{ContractID: contractID, Quantity: 10, CostBasis: 600}
+
+	trigger, err := NewCostBasisStopTrigger(position, TrailTypePercent, 10)
+	require.NoError(t, err)
+	require.Equal(t, contract.ContractPrice(54), trigger.Condition.Price.Threshold)
+
+	// Unchanged position leaves the stop alone
+	changed, err := trigger.UpdateCostBasis(position)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Averaging down moves the stop down with the entry
+	changed, err = trigger.UpdateCostBasis(exchange_domain.Position{ContractID: contractID, Quantity: 20, CostBasis: 1000})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, contract.ContractPrice(50), trigger.CostBasis.AverageEntry)
+	assert.Equal(t, contract.ContractPrice(45), trigger.Condition.Price.Threshold)
+	assert.NoError(t, ValidateStopTrigger(trigger))
+
+	// Positions in other contracts are rejected
+	_, err = trigger.UpdateCostBasis(exchange_domain.Position{
+		ContractID: contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes},
+		Quantity:   10,
+		CostBasis:  300,
+	})
+	assert.ErrorContains(t, err, "does not match")
+}
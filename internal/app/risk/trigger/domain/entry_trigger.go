@@ -0,0 +1,93 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+)
+
+// NewEntryTrigger creates a trigger that buys into a position once the price
+// crosses the entry level, e.g. buy YES when the ask dips below 20
+func NewEntryTrigger(
+	contract contract.ContractIdentifier,
+	direction Direction,
+	triggerPrice contract.ContractPrice,
+	size uint,
+	limitPrice *contract.ContractPrice,
+	maxCost *int,
+) (*Trigger, error) {
+	condition, err := NewPriceCondition(contract, triggerPrice, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := NewBuyAction(contract, size, limitPrice, maxCost)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger := NewTrigger(TriggerTypeEntry, *condition, []TriggerAction{*action})
+
+	if err := ValidateEntryTrigger(trigger); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+func ValidateEntryTrigger(t *Trigger) error {
+	// Basic trigger validation
+	if t == nil {
+		return errors.New("trigger cannot be nil")
+	}
+	if t.TriggerType != TriggerTypeEntry {
+		return fmt.Errorf("invalid trigger type: expected %s, got %s", TriggerTypeEntry, t.TriggerType)
+	}
+
+	// Condition validation
+	if t.Condition.Price == nil {
+		return errors.New("entry trigger must have a price condition")
+	}
+	// Price validation
+	if !t.Condition.Price.Threshold.IsValid() {
+		return fmt.Errorf("invalid entry price: %v", t.Condition.Price.Threshold)
+	}
+	if !t.Condition.Price.Direction.IsValid() {
+		return fmt.Errorf("invalid entry price direction: %s", t.Condition.Price.Direction)
+	}
+
+	// Actions validation
+	if len(t.Actions) != 1 {
+		return fmt.Errorf("entry trigger must have exactly one action, got %d", len(t.Actions))
+	}
+
+	action := t.Actions[0]
+	if action.Side != Buy {
+		return fmt.Errorf("entry trigger action must be Buy, got %s", action.Side)
+	}
+	if err := action.validateBuy(); err != nil {
+		return err
+	}
+
+	// Contract consistency validation
+	if t.Condition.Contract != action.Contract {
+		return fmt.Errorf("condition contract (%v) must match action contract (%v)",
+			t.Condition.Contract, action.Contract)
+	}
+
+	// The limit is the highest price we pay once the entry level is hit,
+	// so bound the slippage the same way as the exit triggers
+	if action.LimitPrice != nil {
+		if float64(*action.LimitPrice) > float64(t.Condition.Price.Threshold)*1.1 { // 10% max slippage
+			return fmt.Errorf("limit price (%v) too high compared to entry price (%v)",
+				*action.LimitPrice, t.Condition.Price.Threshold)
+		}
+	}
+
+	// Status validation
+	if !t.Status.IsValid() {
+		return fmt.Errorf("invalid trigger status: %s", t.Status)
+	}
+
+	return nil
+}
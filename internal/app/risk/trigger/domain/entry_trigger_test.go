@@ -0,0 +1,94 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEntryTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker("FOO"),
+		Side:   contract.SideYes,
+	}
+	limitPrice := contract.ContractPrice(21)
+	badLimitPrice := contract.ContractPrice(30)
+	maxCost := 500
+	badMaxCost := 0
+
+	tests := []struct {
+		name        string
+		direction   Direction
+		entryPrice  contract.ContractPrice
+		size        uint
+		limitPrice  *contract.ContractPrice
+		maxCost     *int
+		expectError bool
+	}{
+		{
+			name:       "buy the dip with limit",
+			direction:  Below,
+			entryPrice: 20,
+			size:       10,
+			limitPrice: &limitPrice,
+			maxCost:    &maxCost,
+		},
+		{
+			name:       "buy the breakout at market",
+			direction:  Above,
+			entryPrice: 20,
+			size:       10,
+		},
+		{
+			name:        "zero size",
+			direction:   Below,
+			entryPrice:  20,
+			size:        0,
+			expectError: true,
+		},
+		{
+			name:        "limit price too high",
+			direction:   Below,
+			entryPrice:  20,
+			size:        10,
+			limitPrice:  &badLimitPrice, // More than 10% above entry price
+			expectError: true,
+		},
+		{
+			name:        "non-positive max cost",
+			direction:   Below,
+			entryPrice:  20,
+			size:        10,
+			maxCost:     &badMaxCost,
+			expectError: true,
+		},
+		{
+			name:        "invalid entry price",
+			direction:   Below,
+			entryPrice:  110,
+			size:        10,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := NewEntryTrigger(contractID, tt.direction, tt.entryPrice, tt.size, tt.limitPrice, tt.maxCost)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, trigger)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, TriggerTypeEntry, trigger.TriggerType)
+				assert.Equal(t, tt.direction, trigger.Condition.Price.Direction)
+				assert.Equal(t, tt.entryPrice, trigger.Condition.Price.Threshold)
+				assert.Equal(t, Buy, trigger.Actions[0].Side)
+				assert.Equal(t, tt.size, *trigger.Actions[0].Size)
+				assert.Equal(t, tt.limitPrice, trigger.Actions[0].LimitPrice)
+				assert.Equal(t, tt.maxCost, trigger.Actions[0].MaxCost)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+)
+
+// NewEventExposureTrigger creates a trigger that watches the combined exposure across
+// an event's markets and flattens every position in the event when the rule is met,
+// e.g. sell everything in KXHIGHNY-25FEB13 once its P&L falls to -$200
+func NewEventExposureTrigger(
+	eventTicker contract.Ticker,
+	metric EventMetric,
+	threshold int,
+	direction Direction,
+) (*Trigger, error) {
+	condition, err := NewEventCondition(eventTicker, metric, threshold, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := NewFlattenEventAction(eventTicker)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger := NewTrigger(TriggerTypeEventExposure, *condition, []TriggerAction{*action})
+
+	if err := ValidateEventExposureTrigger(trigger); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+func ValidateEventExposureTrigger(t *Trigger) error {
+	// Basic trigger validation
+	if t == nil {
+		return errors.New("trigger cannot be nil")
+	}
+	if t.TriggerType != TriggerTypeEventExposure {
+		return fmt.Errorf("invalid trigger type: expected %s, got %s", TriggerTypeEventExposure, t.TriggerType)
+	}
+
+	// Condition validation
+	if t.Condition.Event == nil {
+		return errors.New("event exposure trigger must have an event condition")
+	}
+	if err := t.Condition.Validate(); err != nil {
+		return fmt.Errorf("invalid condition: %w", err)
+	}
+
+	// Actions validation
+	if len(t.Actions) != 1 {
+		return fmt.Errorf("event exposure trigger must have exactly one action, got %d", len(t.Actions))
+	}
+	action := t.Actions[0]
+	if action.Side != Sell || !action.FlattensEvent() {
+		return errors.New("event exposure trigger action must flatten the event")
+	}
+	if action.Contract.Ticker != "" || action.Size != nil || action.SizePercent != nil || action.LimitPrice != nil {
+		return errors.New("flatten action sells every position at market")
+	}
+
+	// Event consistency validation
+	if action.Event != t.Condition.Event.EventTicker {
+		return fmt.Errorf("action event (%s) must match condition event (%s)", action.Event, t.Condition.Event.EventTicker)
+	}
+
+	// Status validation
+	if !t.Status.IsValid() {
+		return fmt.Errorf("invalid trigger status: %s", t.Status)
+	}
+
+	return nil
+}
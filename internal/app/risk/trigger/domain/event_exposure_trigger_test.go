@@ -0,0 +1,161 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEventExposureTrigger(t *testing.T) {
+	tests := []struct {
+		name         string
+		eventTicker  contract.Ticker
+		metric       EventMetric
+		threshold    int
+		direction    Direction
+		errorMessage string
+	}{
+		{name: "loss limit", eventTicker: "KXHIGHNY-25FEB13", metric: EventMetricPNL, threshold: -20000, direction: Below},
+		{name: "exposure cap", eventTicker: "KXHIGHNY-25FEB13", metric: EventMetricExposure, threshold: 50000, direction: Above},
+		{name: "missing event", metric: EventMetricPNL, threshold: -20000, direction: Below, errorMessage: "must have an event"},
+		{name: "invalid metric", eventTicker: "KXHIGHNY-25FEB13", metric: "INVALID", direction: Below, errorMessage: "invalid event metric"},
+		{name: "negative exposure", eventTicker: "KXHIGHNY-25FEB13", metric: EventMetricExposure, threshold: -1, direction: Below, errorMessage: "cannot be negative"},
+		{name: "invalid direction", eventTicker: "KXHIGHNY-25FEB13", metric: EventMetricPNL, direction: "SIDEWAYS", errorMessage: "invalid direction"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := NewEventExposureTrigger(tt.eventTicker, tt.metric, tt.threshold, tt.direction)
+
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, trigger)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, TriggerTypeEventExposure, trigger.TriggerType)
+			assert.Equal(t, []contract.Ticker{tt.eventTicker}, trigger.Condition.Events())
+			require.Len(t, trigger.Actions, 1)
+			assert.True(t, trigger.Actions[0].FlattensEvent())
+			assert.Empty(t, trigger.Tickers())
+		})
+	}
+}
+
+func TestEventRule_IsSatisfied(t *testing.T) {
+	yes := contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB13-T40", Side: contract.SideYes}
+	no := contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB13-B41.5", Side: contract.SideNo}
+
+	// $100 paid for 200 YES at 30c and 100 NO at 40c, $15 lost earlier, $1.20 in fees
+	position := exchange_domain.EventPosition{
+		EventTicker: "KXHIGHNY-25FEB13",
+		Exposure:    10000,
+		RealizedPNL: -1500,
+		FeesPaid:    120,
+		Positions: []*exchange_domain.Position{
+			{ContractID: yes, Quantity: 200, CostBasis: 6000},
+			{ContractID: no, Quantity: 100, CostBasis: 4000},
+		},
+	}
+	markets := map[contract.Ticker]*exchange_domain.Market{
+		yes.Ticker: {Ticker: yes.Ticker, Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Bid: 10, Ask: 12}}},
+		no.Ticker:  {Ticker: no.Ticker, Pricing: exchange_domain.MarketPricing{NoSide: exchange_domain.PricingSide{Bid: 35, Ask: 37}}},
+	}
+
+	exposure, err := NewEventExposure(position, markets)
+	require.NoError(t, err)
+	// Marked at the bid: 200*10 + 100*35 = 5500
+	assert.Equal(t, 5500, exposure.MarketValue)
+	assert.Equal(t, -1500+5500-10000-120, exposure.PNL())
+
+	snapshot := NewMarketSnapshot(nil, time.Now())
+	snapshot.Events[position.EventTicker] = exposure
+
+	tests := []struct {
+		name      string
+		metric    EventMetric
+		threshold int
+		direction Direction
+		expected  bool
+	}{
+		{"loss past limit", EventMetricPNL, -5000, Below, true},
+		{"loss within limit", EventMetricPNL, -20000, Below, false},
+		{"exposure over cap", EventMetricExposure, 8000, Above, true},
+		{"exposure under cap", EventMetricExposure, 12000, Above, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewEventCondition(position.EventTicker, tt.metric, tt.threshold, tt.direction)
+			require.NoError(t, err)
+
+			satisfied, err := condition.IsSatisfied(snapshot)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, satisfied)
+		})
+	}
+
+	t.Run("missing market data", func(t *testing.T) {
+		_, err := NewEventExposure(position, map[contract.Ticker]*exchange_domain.Market{})
+		assert.ErrorContains(t, err, "no market data")
+	})
+
+	t.Run("market with no bid is worth nothing", func(t *testing.T) {
+		noBid := map[contract.Ticker]*exchange_domain.Market{
+			yes.Ticker: {Ticker: yes.Ticker, Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 12}}},
+			no.Ticker:  markets[no.Ticker],
+		}
+
+		exposure, err := NewEventExposure(position, noBid)
+		require.NoError(t, err)
+		assert.Equal(t, 100*35, exposure.MarketValue)
+	})
+
+	t.Run("missing event data", func(t *testing.T) {
+		condition, err := NewEventCondition("KXHIGHCHI-25FEB13", EventMetricPNL, -5000, Below)
+		require.NoError(t, err)
+
+		_, err = condition.IsSatisfied(snapshot)
+		assert.ErrorContains(t, err, "no position data for event")
+	})
+}
+
+func TestResolveEventActions(t *testing.T) {
+	yes := contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB13-T40", Side: contract.SideYes}
+	no := contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB13-B41.5", Side: contract.SideNo}
+	other := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	flatten, err := NewFlattenEventAction("KXHIGHNY-25FEB13")
+	require.NoError(t, err)
+	buy, err := NewBuyAction(other, 5, nil, nil)
+	require.NoError(t, err)
+
+	events := map[contract.Ticker]exchange_domain.EventPosition{
+		"KXHIGHNY-25FEB13": {
+			EventTicker: "KXHIGHNY-25FEB13",
+			Positions: []*exchange_domain.Position{
+				{ContractID: yes, Quantity: 200},
+				{ContractID: no, Quantity: 100},
+			},
+		},
+	}
+
+	t.Run("sells every position in the event", func(t *testing.T) {
+		actions := ResolveEventActions([]TriggerAction{*flatten, *buy}, events)
+
+		require.Len(t, actions, 3)
+		assert.Equal(t, TriggerAction{Contract: yes, Side: Sell}, actions[0])
+		assert.Equal(t, TriggerAction{Contract: no, Side: Sell}, actions[1])
+		assert.Equal(t, *buy, actions[2])
+	})
+
+	t.Run("nothing held in the event", func(t *testing.T) {
+		actions := ResolveEventActions([]TriggerAction{*flatten}, map[contract.Ticker]exchange_domain.EventPosition{})
+		assert.Empty(t, actions)
+	})
+}
@@ -0,0 +1,169 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"strings"
+)
+
+// EventMetric represents which event-level quantity an event rule looks at
+type EventMetric string
+
+// Exposure is the cost of the contracts still held across the event's markets.
+// PNL is realized plus unrealized profit, net of fees, marking open positions at the bid.
+const (
+	EventMetricExposure EventMetric = "EXPOSURE"
+	EventMetricPNL      EventMetric = "PNL"
+)
+
+func (m EventMetric) String() string {
+	return string(m)
+}
+
+func (m EventMetric) IsValid() bool {
+	switch m {
+	case EventMetricExposure, EventMetricPNL:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewEventMetric(s string) (EventMetric, error) {
+	switch s {
+	case "EXPOSURE":
+		return EventMetricExposure, nil
+	case "PNL":
+		return EventMetricPNL, nil
+	default:
+		return "", fmt.Errorf("invalid EventMetric: %s", s)
+	}
+}
+
+// EventExposure is the state of everything held in an event at one point in time
+type EventExposure struct {
+	Position    exchange_domain.EventPosition
+	MarketValue int // cents the open positions would fetch at the bid
+}
+
+// NewEventExposure marks the event's open positions to the current bid of their markets
+func NewEventExposure(
+	position exchange_domain.EventPosition,
+	markets map[contract.Ticker]*exchange_domain.Market,
+) (EventExposure, error) {
+	snapshot := MarketSnapshot{Markets: markets}
+
+	marketValue := 0
+	for _, p := range position.Positions {
+		bid, err := snapshot.Price(p.ContractID, PriceSourceBid)
+		// With no bid there is nothing to sell into, so the position is worth nothing
+		if errors.Is(err, ErrNoPrice) {
+			continue
+		}
+		if err != nil {
+			return EventExposure{}, fmt.Errorf("value position in %s: %w", p.ContractID.Ticker, err)
+		}
+		marketValue += bid.Value() * int(p.Quantity)
+	}
+
+	return EventExposure{
+		Position:    position,
+		MarketValue: marketValue,
+	}, nil
+}
+
+// PNL is the event's realized and unrealized profit in cents, net of fees
+func (e EventExposure) PNL() int {
+	return e.Position.RealizedPNL + e.MarketValue - e.Position.Exposure - e.Position.FeesPaid
+}
+
+// EventRule represents a rule on the combined exposure across an event's markets,
+// e.g. "P&L on KXHIGHNY-25FEB13 at or below -$200"
+type EventRule struct {
+	EventTicker contract.Ticker
+	Metric      EventMetric
+	Threshold   int // cents, negative for losses
+	Direction   Direction
+}
+
+func newEventRule(
+	eventTicker contract.Ticker,
+	metric EventMetric,
+	threshold int,
+	direction Direction,
+) (*EventRule, error) {
+	rule := &EventRule{
+		EventTicker: eventTicker,
+		Metric:      metric,
+		Threshold:   threshold,
+		Direction:   direction,
+	}
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (e EventRule) validate() error {
+	if e.EventTicker == "" {
+		return errors.New("event rule must have an event")
+	}
+	if strings.HasSuffix(string(e.EventTicker), "-") {
+		return fmt.Errorf("invalid event ticker: %s", e.EventTicker)
+	}
+	if !e.Metric.IsValid() {
+		return fmt.Errorf("invalid event metric: %s", e.Metric)
+	}
+	if e.Metric == EventMetricExposure && e.Threshold < 0 {
+		return fmt.Errorf("exposure threshold cannot be negative, got %d", e.Threshold)
+	}
+	if !e.Direction.IsValid() {
+		return fmt.Errorf("invalid direction: %s", e.Direction)
+	}
+	return nil
+}
+
+// Checks whether the event's exposure satisfies the rule
+func (e EventRule) isSatisfied(exposure EventExposure) (bool, error) {
+	var value int
+	switch e.Metric {
+	case EventMetricExposure:
+		value = exposure.Position.Exposure
+	case EventMetricPNL:
+		value = exposure.PNL()
+	default:
+		return false, fmt.Errorf("invalid event metric: %s", e.Metric)
+	}
+
+	switch e.Direction {
+	case Above:
+		return value >= e.Threshold, nil
+	case Below:
+		return value <= e.Threshold, nil
+	default:
+		return false, fmt.Errorf("invalid direction: %s", e.Direction)
+	}
+}
+
+func (e EventRule) String() string {
+	return fmt.Sprintf("event rule %s %s %s %d", e.EventTicker, e.Metric, e.Direction, e.Threshold)
+}
+
+// NewEventCondition creates an event exposure condition with validation
+func NewEventCondition(
+	eventTicker contract.Ticker,
+	metric EventMetric,
+	threshold int,
+	direction Direction,
+) (*TriggerCondition, error) {
+	eventRule, err := newEventRule(eventTicker, metric, threshold, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TriggerCondition{
+		Event: eventRule,
+	}, nil
+}
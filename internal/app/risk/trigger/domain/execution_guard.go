@@ -0,0 +1,175 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"time"
+)
+
+// GuardFailureAction decides what happens to a trigger whose pre-trade guards fail
+type GuardFailureAction string
+
+// Defer leaves the trigger active so it is retried on the next evaluation.
+// Flag parks the trigger as FLAGGED until it is reviewed and resumed.
+const (
+	GuardFailureDefer GuardFailureAction = "DEFER"
+	GuardFailureFlag  GuardFailureAction = "FLAG"
+)
+
+func (a GuardFailureAction) String() string {
+	return string(a)
+}
+
+func (a GuardFailureAction) IsValid() bool {
+	switch a {
+	case GuardFailureDefer, GuardFailureFlag:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewGuardFailureAction(s string) (GuardFailureAction, error) {
+	switch s {
+	case "DEFER":
+		return GuardFailureDefer, nil
+	case "FLAG":
+		return GuardFailureFlag, nil
+	default:
+		return "", fmt.Errorf("invalid GuardFailureAction: %s", s)
+	}
+}
+
+// ExecutionGuard lists the checks each action's market must pass before a trigger
+// sends its orders, so a stop does not dump into an empty book. Nil limits are not checked.
+type ExecutionGuard struct {
+	MaxSpread    *int // widest bid/ask spread in cents on the side being traded
+	MinLiquidity *int // minimum LiquidityMetrics.Liquidity
+	MinVolume24H *int // minimum LiquidityMetrics.Volume24H
+	MinDepth     *int // minimum contracts resting that the order can fill against
+	OnFailure    GuardFailureAction
+}
+
+func (g ExecutionGuard) Validate() error {
+	if g.MaxSpread == nil && g.MinLiquidity == nil && g.MinVolume24H == nil && g.MinDepth == nil {
+		return errors.New("guard must set at least one limit")
+	}
+	if g.MaxSpread != nil && (*g.MaxSpread <= 0 || *g.MaxSpread > 100) {
+		return fmt.Errorf("max spread must be between 1 and 100, got %d", *g.MaxSpread)
+	}
+	if g.MinLiquidity != nil && *g.MinLiquidity < 0 {
+		return fmt.Errorf("min liquidity cannot be negative, got %d", *g.MinLiquidity)
+	}
+	if g.MinVolume24H != nil && *g.MinVolume24H < 0 {
+		return fmt.Errorf("min 24h volume cannot be negative, got %d", *g.MinVolume24H)
+	}
+	if g.MinDepth != nil && *g.MinDepth < 0 {
+		return fmt.Errorf("min depth cannot be negative, got %d", *g.MinDepth)
+	}
+	if !g.OnFailure.IsValid() {
+		return fmt.Errorf("invalid guard failure action: %s", g.OnFailure)
+	}
+	return nil
+}
+
+// RequiresOrderbook reports whether checking the guard needs the market's orderbook
+func (g ExecutionGuard) RequiresOrderbook() bool {
+	return g.MinDepth != nil
+}
+
+// Check returns a reason for every limit the action's market fails, or nil if it may trade.
+// The orderbook may be nil unless the guard requires it.
+func (g ExecutionGuard) Check(
+	action TriggerAction,
+	market *exchange_domain.Market,
+	orderbook *exchange_domain.Orderbook,
+) []string {
+	var reasons []string
+	ticker := action.Contract.Ticker
+
+	if g.MaxSpread != nil {
+		pricing := market.Pricing.YesSide
+		if action.Contract.Side != contract.SideYes {
+			pricing = market.Pricing.NoSide
+		}
+		spread := int(pricing.Ask) - int(pricing.Bid)
+		if pricing.Bid == 0 || pricing.Ask == 0 || spread > *g.MaxSpread {
+			reasons = append(reasons, fmt.Sprintf("%s spread %d-%d exceeds %d", ticker, pricing.Bid, pricing.Ask, *g.MaxSpread))
+		}
+	}
+	if g.MinLiquidity != nil && market.Liquidity.Liquidity < *g.MinLiquidity {
+		reasons = append(reasons, fmt.Sprintf("%s liquidity %d below %d", ticker, market.Liquidity.Liquidity, *g.MinLiquidity))
+	}
+	if g.MinVolume24H != nil && market.Liquidity.Volume24H < *g.MinVolume24H {
+		reasons = append(reasons, fmt.Sprintf("%s 24h volume %d below %d", ticker, market.Liquidity.Volume24H, *g.MinVolume24H))
+	}
+	if g.MinDepth != nil {
+		orderAction := exchange_domain.OrderActionSell
+		if action.Side == Buy {
+			orderAction = exchange_domain.OrderActionBuy
+		}
+		depth := 0
+		if orderbook != nil {
+			depth = orderbook.Depth(action.Contract.Side, orderAction)
+		}
+		if depth < *g.MinDepth {
+			reasons = append(reasons, fmt.Sprintf("%s resting depth %d below %d", ticker, depth, *g.MinDepth))
+		}
+	}
+
+	return reasons
+}
+
+// GuardFailure records why a trigger's orders were held back
+type GuardFailure struct {
+	Reason   string
+	Action   GuardFailureAction
+	FailedAt time.Time
+}
+
+// FailGuard records a failed pre-trade check, flagging the trigger for review if the guard asks to
+func (t *Trigger) FailGuard(failure GuardFailure) error {
+	if t.Status != StatusActive {
+		return fmt.Errorf("cannot hold back trigger in status %s", t.Status)
+	}
+	if !failure.Action.IsValid() {
+		return fmt.Errorf("invalid guard failure action: %s", failure.Action)
+	}
+
+	t.GuardFailure = &failure
+	if failure.Action == GuardFailureFlag {
+		t.Status = StatusFlagged
+	}
+	t.UpdatedAt = failure.FailedAt
+
+	return nil
+}
+
+// SetGuard replaces the trigger's own pre-trade guard. A nil guard falls back to the default guards.
+func (t *Trigger) SetGuard(guard *ExecutionGuard) error {
+	if t.Status.IsTerminal() {
+		return fmt.Errorf("cannot change guard of trigger in terminal status %s", t.Status)
+	}
+	if guard != nil {
+		if err := guard.Validate(); err != nil {
+			return err
+		}
+	}
+
+	t.Guard = guard
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Resume returns a flagged trigger to ACTIVE after review
+func (t *Trigger) Resume() error {
+	if t.Status != StatusFlagged {
+		return fmt.Errorf("only flagged triggers can be resumed, status is %s", t.Status)
+	}
+
+	t.Status = StatusActive
+	t.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,120 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestExecutionGuard_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		guard        ExecutionGuard
+		errorMessage string
+	}{
+		{name: "spread guard", guard: ExecutionGuard{MaxSpread: intPtr(5), OnFailure: GuardFailureDefer}},
+		{name: "no limits", guard: ExecutionGuard{OnFailure: GuardFailureDefer}, errorMessage: "at least one limit"},
+		{name: "zero spread", guard: ExecutionGuard{MaxSpread: intPtr(0), OnFailure: GuardFailureDefer}, errorMessage: "max spread"},
+		{name: "negative depth", guard: ExecutionGuard{MinDepth: intPtr(-1), OnFailure: GuardFailureFlag}, errorMessage: "min depth"},
+		{name: "missing failure action", guard: ExecutionGuard{MinLiquidity: intPtr(100)}, errorMessage: "invalid guard failure action"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.guard.Validate()
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestExecutionGuard_Check(t *testing.T) {
+	sellYes := TriggerAction{Contract: contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, Side: Sell}
+	market := &exchange_domain.Market{
+		Ticker:    "FOO",
+		Pricing:   exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Bid: 40, Ask: 43}},
+		Liquidity: exchange_domain.LiquidityMetrics{Liquidity: 5000, Volume24H: 200},
+	}
+	orderbook := &exchange_domain.Orderbook{
+		Ticker:  "FOO",
+		YesBids: []exchange_domain.OrderbookLevel{{Price: 40, Quantity: 8}},
+	}
+
+	tests := []struct {
+		name    string
+		guard   ExecutionGuard
+		market  *exchange_domain.Market
+		reasons []string
+	}{
+		{
+			name:   "passes all limits",
+			guard:  ExecutionGuard{MaxSpread: intPtr(3), MinLiquidity: intPtr(1000), MinVolume24H: intPtr(100), MinDepth: intPtr(5)},
+			market: market,
+		},
+		{
+			name:    "spread too wide",
+			guard:   ExecutionGuard{MaxSpread: intPtr(2)},
+			market:  market,
+			reasons: []string{"FOO spread 40-43 exceeds 2"},
+		},
+		{
+			name:  "empty bid side",
+			guard: ExecutionGuard{MaxSpread: intPtr(10)},
+			market: &exchange_domain.Market{
+				Ticker:  "FOO",
+				Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 5}},
+			},
+			reasons: []string{"FOO spread 0-5 exceeds 10"},
+		},
+		{
+			name:    "thin market",
+			guard:   ExecutionGuard{MinLiquidity: intPtr(10000), MinVolume24H: intPtr(500), MinDepth: intPtr(10)},
+			market:  market,
+			reasons: []string{"FOO liquidity 5000 below 10000", "FOO 24h volume 200 below 500", "FOO resting depth 8 below 10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.reasons, tt.guard.Check(sellYes, tt.market, orderbook))
+		})
+	}
+}
+
+func TestTrigger_FailGuard(t *testing.T) {
+	now := time.Now()
+
+	t.Run("defer keeps trigger active", func(t *testing.T) {
+		trigger, err := NewStopTrigger(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, 40, nil)
+		require.NoError(t, err)
+
+		failure := GuardFailure{Reason: "FOO spread 1-40 exceeds 5", Action: GuardFailureDefer, FailedAt: now}
+		require.NoError(t, trigger.FailGuard(failure))
+		assert.Equal(t, StatusActive, trigger.Status)
+		assert.Equal(t, &failure, trigger.GuardFailure)
+	})
+
+	t.Run("flag parks trigger until resumed", func(t *testing.T) {
+		trigger, err := NewStopTrigger(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, 40, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, trigger.FailGuard(GuardFailure{Reason: "thin", Action: GuardFailureFlag, FailedAt: now}))
+		assert.Equal(t, StatusFlagged, trigger.Status)
+		assert.False(t, trigger.Status.IsTerminal())
+
+		require.NoError(t, trigger.Resume())
+		assert.Equal(t, StatusActive, trigger.Status)
+		assert.ErrorContains(t, trigger.Resume(), "only flagged triggers")
+	})
+}
@@ -0,0 +1,106 @@
+package trigger_domain
+
+import (
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"time"
+)
+
+// FillAction decides what replaces the unfilled part of a trigger's order once it has
+// rested past the fill timeout
+type FillAction string
+
+// Reprice places the remainder again as a limit order at the current bid for sells or ask
+// for buys. Market sends the remainder as a market order.
+const (
+	FillActionReprice FillAction = "REPRICE"
+	FillActionMarket  FillAction = "MARKET"
+)
+
+func (a FillAction) String() string {
+	return string(a)
+}
+
+func (a FillAction) IsValid() bool {
+	switch a {
+	case FillActionReprice, FillActionMarket:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewFillAction(s string) (FillAction, error) {
+	switch s {
+	case "REPRICE":
+		return FillActionReprice, nil
+	case "MARKET":
+		return FillActionMarket, nil
+	default:
+		return "", fmt.Errorf("invalid FillAction: %s", s)
+	}
+}
+
+// FillPolicy follows up the orders a trigger sent that have not filled within the timeout,
+// cancelling what is left and replacing it. Triggers without one leave their orders resting.
+type FillPolicy struct {
+	Timeout time.Duration
+	Action  FillAction
+}
+
+func (p FillPolicy) Validate() error {
+	if p.Timeout < time.Second {
+		return fmt.Errorf("fill timeout must be at least a second, got %v", p.Timeout)
+	}
+	if !p.Action.IsValid() {
+		return fmt.Errorf("invalid fill action: %s", p.Action)
+	}
+	return nil
+}
+
+// Due reports whether the order has rested with a remainder for as long as the timeout
+func (p FillPolicy) Due(order exchange_domain.Order, now time.Time) bool {
+	return order.IsOpen() && order.Remaining() > 0 && now.Sub(order.CreatedAt) >= p.Timeout
+}
+
+// FollowUpLimit returns the limit price of the order replacing the remainder of order, or
+// nil for a market order. Repricing fails if that side of the market has no price.
+func (a FillAction) FollowUpLimit(
+	order exchange_domain.Order,
+	market *exchange_domain.Market,
+) (*contract.ContractPrice, error) {
+	if a == FillActionMarket {
+		return nil, nil
+	}
+
+	pricing := market.Pricing.YesSide
+	if order.Side != contract.SideYes {
+		pricing = market.Pricing.NoSide
+	}
+	price := pricing.Bid
+	if order.Action == exchange_domain.OrderActionBuy {
+		price = pricing.Ask
+	}
+	if price == 0 {
+		return nil, fmt.Errorf("no %s price in %s to reprice against", order.Side, order.Ticker)
+	}
+	return &price, nil
+}
+
+// SetFillPolicy replaces how the trigger's unfilled orders are followed up. It can still be
+// changed once the trigger has fired, while its orders may be resting.
+func (t *Trigger) SetFillPolicy(policy *FillPolicy) error {
+	if t.Status == StatusCancelled || t.Status == StatusExpired {
+		return fmt.Errorf("cannot change fill policy of trigger in status %s", t.Status)
+	}
+	if policy != nil {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	t.FillPolicy = policy
+	t.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,97 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFillPolicy_Validate(t *testing.T) {
+	assert.NoError(t, FillPolicy{Timeout: time.Minute, Action: FillActionReprice}.Validate())
+	assert.ErrorContains(t, FillPolicy{Timeout: time.Millisecond, Action: FillActionMarket}.Validate(), "fill timeout")
+	assert.ErrorContains(t, FillPolicy{Timeout: time.Minute}.Validate(), "invalid fill action")
+}
+
+func TestFillPolicy_Due(t *testing.T) {
+	policy := FillPolicy{Timeout: time.Minute, Action: FillActionMarket}
+	placed := time.Date(2025, 2, 21, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		order    exchange_domain.Order
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "resting past the timeout",
+			order:    exchange_domain.Order{Status: exchange_domain.OrderStatusResting, Quantity: 10, CreatedAt: placed},
+			now:      placed.Add(time.Minute),
+			expected: true,
+		},
+		{
+			name:     "resting within the timeout",
+			order:    exchange_domain.Order{Status: exchange_domain.OrderStatusResting, Quantity: 10, CreatedAt: placed},
+			now:      placed.Add(30 * time.Second),
+			expected: false,
+		},
+		{
+			name:     "partially filled past the timeout",
+			order:    exchange_domain.Order{Status: exchange_domain.OrderStatusPartiallyFilled, Quantity: 10, FilledQuantity: 6, CreatedAt: placed},
+			now:      placed.Add(time.Hour),
+			expected: true,
+		},
+		{
+			name:     "executed",
+			order:    exchange_domain.Order{Status: exchange_domain.OrderStatusExecuted, Quantity: 10, FilledQuantity: 10, CreatedAt: placed},
+			now:      placed.Add(time.Hour),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policy.Due(tt.order, tt.now))
+		})
+	}
+}
+
+func TestFillAction_FollowUpLimit(t *testing.T) {
+	market := &exchange_domain.Market{
+		Ticker: "FOO",
+		Pricing: exchange_domain.MarketPricing{
+			YesSide: exchange_domain.PricingSide{Bid: 38, Ask: 41},
+			NoSide:  exchange_domain.PricingSide{Bid: 0, Ask: 62},
+		},
+	}
+	sellYes := exchange_domain.Order{Ticker: "FOO", Side: contract.SideYes, Action: exchange_domain.OrderActionSell}
+	buyYes := exchange_domain.Order{Ticker: "FOO", Side: contract.SideYes, Action: exchange_domain.OrderActionBuy}
+	sellNo := exchange_domain.Order{Ticker: "FOO", Side: contract.SideNo, Action: exchange_domain.OrderActionSell}
+	reprice := FillActionReprice
+
+	t.Run("reprices sells at the bid", func(t *testing.T) {
+		limit, err := reprice.FollowUpLimit(sellYes, market)
+		require.NoError(t, err)
+		assert.Equal(t, contract.ContractPrice(38), *limit)
+	})
+
+	t.Run("reprices buys at the ask", func(t *testing.T) {
+		limit, err := reprice.FollowUpLimit(buyYes, market)
+		require.NoError(t, err)
+		assert.Equal(t, contract.ContractPrice(41), *limit)
+	})
+
+	t.Run("cannot reprice against an empty side", func(t *testing.T) {
+		_, err := reprice.FollowUpLimit(sellNo, market)
+		assert.ErrorContains(t, err, "to reprice against")
+	})
+
+	t.Run("market follow-ups have no limit", func(t *testing.T) {
+		limit, err := FillActionMarket.FollowUpLimit(sellNo, market)
+		require.NoError(t, err)
+		assert.Nil(t, limit)
+	})
+}
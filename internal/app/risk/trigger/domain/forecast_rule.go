@@ -0,0 +1,151 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"time"
+)
+
+// NWS hourly forecasts cover roughly the next week
+const maxForecastDayOffset = 6
+
+// ForecastMetric represents which forecast quantity a forecast rule looks at
+type ForecastMetric string
+
+// DailyHigh is the highest hourly temperature forecast for the rule's day.
+// DailyLow is the lowest.
+const (
+	ForecastDailyHigh ForecastMetric = "DAILY_HIGH"
+	ForecastDailyLow  ForecastMetric = "DAILY_LOW"
+)
+
+func (m ForecastMetric) String() string {
+	return string(m)
+}
+
+func (m ForecastMetric) IsValid() bool {
+	switch m {
+	case ForecastDailyHigh, ForecastDailyLow:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewForecastMetric(s string) (ForecastMetric, error) {
+	switch s {
+	case "DAILY_HIGH":
+		return ForecastDailyHigh, nil
+	case "DAILY_LOW":
+		return ForecastDailyLow, nil
+	default:
+		return "", fmt.Errorf("invalid ForecastMetric: %s", s)
+	}
+}
+
+// ForecastRule represents a rule on the latest NWS hourly forecast for a gridpoint,
+// e.g. "today's forecast high at OKX/33,37 at or below 78°F"
+type ForecastRule struct {
+	Gridpoint weather_domain.Gridpoint
+	Metric    ForecastMetric
+	DayOffset int // days after the current day, 0 for today
+	Threshold weather_domain.Temperature
+	Direction Direction
+}
+
+func newForecastRule(
+	gridpoint weather_domain.Gridpoint,
+	metric ForecastMetric,
+	dayOffset int,
+	threshold weather_domain.Temperature,
+	direction Direction,
+) (*ForecastRule, error) {
+	rule := &ForecastRule{
+		Gridpoint: gridpoint,
+		Metric:    metric,
+		DayOffset: dayOffset,
+		Threshold: threshold,
+		Direction: direction,
+	}
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (f ForecastRule) validate() error {
+	if f.Gridpoint.OfficeID == "" {
+		return errors.New("forecast rule must have a gridpoint")
+	}
+	if !f.Metric.IsValid() {
+		return fmt.Errorf("invalid forecast metric: %s", f.Metric)
+	}
+	if f.DayOffset < 0 || f.DayOffset > maxForecastDayOffset {
+		return fmt.Errorf("forecast day offset must be between 0 and %d, got %d", maxForecastDayOffset, f.DayOffset)
+	}
+	if !f.Threshold.TemperatureUnit.IsValid() {
+		return fmt.Errorf("invalid temperature unit: %s", f.Threshold.TemperatureUnit)
+	}
+	if !f.Direction.IsValid() {
+		return fmt.Errorf("invalid direction: %s", f.Direction)
+	}
+	return nil
+}
+
+// Checks whether the forecast for the rule's day satisfies the rule. Days run
+// midnight to midnight in the forecast's location, counted from now.
+func (f ForecastRule) isSatisfied(forecast GridpointForecast, now time.Time) (bool, error) {
+	local := now.In(forecast.Location)
+	start := time.Date(local.Year(), local.Month(), local.Day()+f.DayOffset, 0, 0, 0, 0, forecast.Location)
+	end := start.AddDate(0, 0, 1)
+
+	var forecasted weather_domain.Temperature
+	var ok bool
+	switch f.Metric {
+	case ForecastDailyHigh:
+		forecasted, ok = forecast.MaxTemperature(start, end)
+	case ForecastDailyLow:
+		forecasted, ok = forecast.MinTemperature(start, end)
+	default:
+		return false, fmt.Errorf("invalid forecast metric: %s", f.Metric)
+	}
+	if !ok {
+		return false, fmt.Errorf("forecast for %s has no periods on %s", f.Gridpoint, start.Format(time.DateOnly))
+	}
+
+	// Compare in the rule's unit so thresholds like 78°F are exact
+	value := forecasted.In(f.Threshold.TemperatureUnit).Value
+
+	switch f.Direction {
+	case Above:
+		return value >= f.Threshold.Value, nil
+	case Below:
+		return value <= f.Threshold.Value, nil
+	default:
+		return false, fmt.Errorf("invalid direction: %s", f.Direction)
+	}
+}
+
+func (f ForecastRule) String() string {
+	return fmt.Sprintf("forecast rule %s %s+%d %s %.1f %s",
+		f.Gridpoint, f.Metric, f.DayOffset, f.Direction, f.Threshold.Value, f.Threshold.TemperatureUnit)
+}
+
+// NewForecastCondition creates a forecast-based condition with validation
+func NewForecastCondition(
+	gridpoint weather_domain.Gridpoint,
+	metric ForecastMetric,
+	dayOffset int,
+	threshold weather_domain.Temperature,
+	direction Direction,
+) (*TriggerCondition, error) {
+	forecastRule, err := newForecastRule(gridpoint, metric, dayOffset, threshold, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TriggerCondition{
+		Forecast: forecastRule,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package trigger_domain
+
+import (
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var centralPark = weather_domain.Gridpoint{OfficeID: "OKX", X: 33, Y: 37}
+
+func TestNewForecastCondition(t *testing.T) {
+	tests := []struct {
+		name         string
+		gridpoint    weather_domain.Gridpoint
+		metric       ForecastMetric
+		dayOffset    int
+		threshold    weather_domain.Temperature
+		direction    Direction
+		errorMessage string
+	}{
+		{name: "valid", gridpoint: centralPark, metric: ForecastDailyHigh, threshold: fahrenheit(78), direction: Below},
+		{name: "missing gridpoint", metric: ForecastDailyHigh, threshold: fahrenheit(78), direction: Below, errorMessage: "must have a gridpoint"},
+		{name: "invalid metric", gridpoint: centralPark, metric: "HUMIDITY", threshold: fahrenheit(78), direction: Below, errorMessage: "invalid forecast metric"},
+		{name: "day beyond forecast", gridpoint: centralPark, metric: ForecastDailyHigh, dayOffset: 7, threshold: fahrenheit(78), direction: Below, errorMessage: "day offset"},
+		{name: "invalid unit", gridpoint: centralPark, metric: ForecastDailyHigh, threshold: weather_domain.Temperature{Value: 78}, direction: Below, errorMessage: "invalid temperature unit"},
+		{name: "invalid direction", gridpoint: centralPark, metric: ForecastDailyHigh, threshold: fahrenheit(78), direction: "DOWN", errorMessage: "invalid direction"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewForecastCondition(tt.gridpoint, tt.metric, tt.dayOffset, tt.threshold, tt.direction)
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, condition)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, condition.Validate())
+			assert.Equal(t, []weather_domain.Gridpoint{tt.gridpoint}, condition.Gridpoints())
+			assert.Empty(t, condition.Stations())
+			assert.Empty(t, condition.Tickers())
+		})
+	}
+}
+
+func TestForecastCondition_IsSatisfied(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	now := time.Date(2025, 7, 1, 9, 0, 0, 0, est)
+	hourly := func(start time.Time, temperatures ...float64) []weather_domain.ForecastPeriod {
+		periods := make([]weather_domain.ForecastPeriod, 0, len(temperatures))
+		for i, temperature := range temperatures {
+			periodStart := start.Add(time.Duration(i) * time.Hour)
+			periods = append(periods, weather_domain.ForecastPeriod{
+				StartTime:   periodStart,
+				EndTime:     periodStart.Add(time.Hour),
+				Temperature: fahrenheit(temperature),
+			})
+		}
+		return periods
+	}
+
+	// Today peaks at 77°F in the afternoon, tomorrow at 84°F
+	periods := append(hourly(now, 72, 75, 77, 76), hourly(now.Add(24*time.Hour), 80, 84, 81)...)
+	snapshot := MarketSnapshot{
+		Forecasts: map[weather_domain.Gridpoint]GridpointForecast{
+			centralPark: {
+				TemperatureForecast: weather_domain.TemperatureForecast{Gridpoint: centralPark, Periods: periods},
+				Location:            est,
+			},
+		},
+		Time: now,
+	}
+
+	tests := []struct {
+		name      string
+		gridpoint weather_domain.Gridpoint
+		metric    ForecastMetric
+		dayOffset int
+		threshold weather_domain.Temperature
+		direction Direction
+		want      bool
+		wantError bool
+	}{
+		{name: "today's high below threshold", gridpoint: centralPark, metric: ForecastDailyHigh, threshold: fahrenheit(78), direction: Below, want: true},
+		{name: "tomorrow's high above threshold", gridpoint: centralPark, metric: ForecastDailyHigh, dayOffset: 1, threshold: fahrenheit(78), direction: Below, want: false},
+		{name: "today's low in celsius", gridpoint: centralPark, metric: ForecastDailyLow, threshold: celsius(23), direction: Below, want: true},
+		{name: "day without periods", gridpoint: centralPark, metric: ForecastDailyHigh, dayOffset: 3, threshold: fahrenheit(78), direction: Below, wantError: true},
+		{name: "unknown gridpoint", gridpoint: weather_domain.Gridpoint{OfficeID: "OKX", X: 1, Y: 1}, metric: ForecastDailyHigh, threshold: fahrenheit(78), direction: Below, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewForecastCondition(tt.gridpoint, tt.metric, tt.dayOffset, tt.threshold, tt.direction)
+			require.NoError(t, err)
+
+			got, err := condition.IsSatisfied(snapshot)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestForecastCondition_CannotCombineWithObservations(t *testing.T) {
+	cool, err := NewForecastCondition(centralPark, ForecastDailyHigh, 0, fahrenheit(78), Below)
+	require.NoError(t, err)
+	hot, err := NewWeatherCondition("KNYC", MetricDailyMaxTemperature, fahrenheit(80), Above)
+	require.NoError(t, err)
+
+	_, err = NewCompositeCondition(OperatorOr, *cool, *hot)
+
+	assert.ErrorContains(t, err, "cannot combine weather observation and forecast rules")
+}
@@ -0,0 +1,88 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+)
+
+// NewHedgeTrigger creates a trigger that watches the price of one contract and trades
+// others when it crosses the trigger price, e.g. buy the adjacent temperature bracket
+// when our bracket's bid falls below 30
+func NewHedgeTrigger(
+	contract contract.ContractIdentifier,
+	direction Direction,
+	triggerPrice contract.ContractPrice,
+	actions []TriggerAction,
+) (*Trigger, error) {
+	condition, err := NewPriceCondition(contract, triggerPrice, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger := NewTrigger(TriggerTypeHedge, *condition, actions)
+
+	if err := ValidateHedgeTrigger(trigger); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+func ValidateHedgeTrigger(t *Trigger) error {
+	// Basic trigger validation
+	if t == nil {
+		return errors.New("trigger cannot be nil")
+	}
+	if t.TriggerType != TriggerTypeHedge {
+		return fmt.Errorf("invalid trigger type: expected %s, got %s", TriggerTypeHedge, t.TriggerType)
+	}
+
+	// Condition validation
+	if t.Condition.Price == nil {
+		return errors.New("hedge trigger must have a price condition")
+	}
+	if err := t.Condition.Validate(); err != nil {
+		return fmt.Errorf("invalid condition: %w", err)
+	}
+
+	// Actions validation
+	if len(t.Actions) == 0 {
+		return errors.New("hedge trigger must have at least one action")
+	}
+	crossContract := false
+	for _, action := range t.Actions {
+		if !action.Side.IsValid() {
+			return fmt.Errorf("invalid hedge trigger action side: %s", action.Side)
+		}
+		if action.Contract.Ticker == "" || action.Contract.Side == nil {
+			return errors.New("hedge trigger action must have a contract")
+		}
+		if action.Side == Buy {
+			if err := action.validateBuy(); err != nil {
+				return fmt.Errorf("invalid buy action: %w", err)
+			}
+		} else if action.MaxCost != nil {
+			return errors.New("max cost only applies to buy actions")
+		}
+		if action.LimitPrice != nil && !action.LimitPrice.IsValid() {
+			return fmt.Errorf("invalid limit price: %v", *action.LimitPrice)
+		}
+		if action.Contract != t.Condition.Contract {
+			crossContract = true
+		}
+	}
+
+	// Contract consistency validation: a hedge that only trades the watched
+	// contract is a stop, take profit or entry trigger
+	if !crossContract {
+		return fmt.Errorf("hedge trigger must trade a contract other than %v", t.Condition.Contract)
+	}
+
+	// Status validation
+	if !t.Status.IsValid() {
+		return fmt.Errorf("invalid trigger status: %s", t.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHedgeTrigger(t *testing.T) {
+	watched := contract.ContractIdentifier{Ticker: "HIGHNY-25JAN27-B40.5", Side: contract.SideYes}
+	adjacent := contract.ContractIdentifier{Ticker: "HIGHNY-25JAN27-B42.5", Side: contract.SideYes}
+
+	buyAdjacent, err := NewBuyAction(adjacent, 10, nil, nil)
+	require.NoError(t, err)
+	sellWatched, err := NewTriggerAction(watched, Sell, nil, nil)
+	require.NoError(t, err)
+	maxCost := 200
+	sellWithMaxCost := TriggerAction{Contract: adjacent, Side: Sell, MaxCost: &maxCost}
+
+	tests := []struct {
+		name         string
+		actions      []TriggerAction
+		errorMessage string
+	}{
+		{
+			name:    "buy adjacent bracket",
+			actions: []TriggerAction{*buyAdjacent},
+		},
+		{
+			name:    "exit watched and buy adjacent",
+			actions: []TriggerAction{*sellWatched, *buyAdjacent},
+		},
+		{
+			name:         "no actions",
+			errorMessage: "at least one action",
+		},
+		{
+			name:         "only trades watched contract",
+			actions:      []TriggerAction{*sellWatched},
+			errorMessage: "must trade a contract other than",
+		},
+		{
+			name:         "buy without size",
+			actions:      []TriggerAction{{Contract: adjacent, Side: Buy}},
+			errorMessage: "invalid buy action",
+		},
+		{
+			name:         "sell with max cost",
+			actions:      []TriggerAction{sellWithMaxCost},
+			errorMessage: "max cost only applies to buy actions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := NewHedgeTrigger(watched, Above, 60, tt.actions)
+
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, trigger)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, TriggerTypeHedge, trigger.TriggerType)
+			assert.Equal(t, watched, trigger.Condition.Contract)
+			assert.Equal(t, tt.actions, trigger.Actions)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"time"
+)
+
+// StationWeather is the latest known weather at an NWS station
+type StationWeather struct {
+	Temperature         weather_domain.Temperature
+	DailyMaxTemperature weather_domain.Temperature
+	ObservedAt          time.Time
+}
+
+// GridpointForecast is the latest forecast for a gridpoint, with the location
+// whose midnight-to-midnight days forecast rules look at
+type GridpointForecast struct {
+	weather_domain.TemperatureForecast
+	Location *time.Location
+}
+
+// MarketSnapshot is the market and weather state a condition is evaluated against
+type MarketSnapshot struct {
+	Markets   map[contract.Ticker]*exchange_domain.Market
+	Weather   map[string]StationWeather // keyed by station ID
+	Forecasts map[weather_domain.Gridpoint]GridpointForecast
+	Events    map[contract.Ticker]EventExposure
+	Time      time.Time
+}
+
+func NewMarketSnapshot(markets []*exchange_domain.Market, observedAt time.Time) MarketSnapshot {
+	snapshot := MarketSnapshot{
+		Markets:   make(map[contract.Ticker]*exchange_domain.Market, len(markets)),
+		Weather:   make(map[string]StationWeather),
+		Forecasts: make(map[weather_domain.Gridpoint]GridpointForecast),
+		Events:    make(map[contract.Ticker]EventExposure),
+		Time:      observedAt,
+	}
+	for _, market := range markets {
+		snapshot.Markets[market.Ticker] = market
+	}
+	return snapshot
+}
+
+// ErrNoPrice is returned when the book has nothing on the side a price is read from
+var ErrNoPrice = errors.New("no price")
+
+// Price returns the current price of the contract from the given source.
+// An unset source uses the ask, matching triggers created before sources existed.
+func (s MarketSnapshot) Price(contractID contract.ContractIdentifier, source PriceSource) (contract.ContractPrice, error) {
+	market, ok := s.Markets[contractID.Ticker]
+	if !ok || market == nil {
+		return 0, fmt.Errorf("no market data for %s", contractID.Ticker)
+	}
+
+	var pricing exchange_domain.PricingSide
+	switch contractID.Side {
+	case contract.SideYes:
+		pricing = market.Pricing.YesSide
+	case contract.SideNo:
+		pricing = market.Pricing.NoSide
+	default:
+		return 0, fmt.Errorf("invalid contract side: %s", contractID.Side)
+	}
+
+	if source == "" {
+		source = PriceSourceAsk
+	}
+
+	var price contract.ContractPrice
+	switch source {
+	case PriceSourceAsk:
+		price = pricing.Ask
+	case PriceSourceBid:
+		price = pricing.Bid
+	case PriceSourceLast:
+		price = pricing.LastPrice
+	case PriceSourceMid:
+		// Half of a one-sided book is not a price anyone can trade at
+		if pricing.Bid == 0 || pricing.Ask == 0 {
+			return 0, fmt.Errorf("%w: %s %s book is one-sided", ErrNoPrice, contractID.Ticker, contractID.Side)
+		}
+		price = (pricing.Bid + pricing.Ask) / 2
+	default:
+		return 0, fmt.Errorf("invalid price source: %s", source)
+	}
+
+	// An empty side of the book reads as 0, which would satisfy any stop below the market
+	if price == 0 {
+		return 0, fmt.Errorf("%w: %s %s has no %s", ErrNoPrice, contractID.Ticker, contractID.Side, source)
+	}
+	return price, nil
+}
+
+// StationWeather returns the latest weather known for the station
+func (s MarketSnapshot) StationWeather(stationID string) (StationWeather, error) {
+	weather, ok := s.Weather[stationID]
+	if !ok {
+		return StationWeather{}, fmt.Errorf("no weather data for station %s", stationID)
+	}
+	return weather, nil
+}
+
+// GridpointForecast returns the latest forecast known for the gridpoint
+func (s MarketSnapshot) GridpointForecast(gridpoint weather_domain.Gridpoint) (GridpointForecast, error) {
+	forecast, ok := s.Forecasts[gridpoint]
+	if !ok {
+		return GridpointForecast{}, fmt.Errorf("no forecast data for gridpoint %s", gridpoint)
+	}
+	return forecast, nil
+}
+
+// EventExposure returns what is held across the event's markets
+func (s MarketSnapshot) EventExposure(eventTicker contract.Ticker) (EventExposure, error) {
+	exposure, ok := s.Events[eventTicker]
+	if !ok {
+		return EventExposure{}, fmt.Errorf("no position data for event %s", eventTicker)
+	}
+	return exposure, nil
+}
@@ -0,0 +1,83 @@
+package trigger_domain
+
+import (
+	"fmt"
+	"prediction-risk/internal/app/contract"
+)
+
+// PriceSource represents which market price a price rule is compared against
+type PriceSource string
+
+// Bid is what a sell would execute at, Ask what a buy would execute at,
+// Last the most recent trade, and Mid halfway between bid and ask
+const (
+	PriceSourceBid  PriceSource = "BID"
+	PriceSourceAsk  PriceSource = "ASK"
+	PriceSourceLast PriceSource = "LAST"
+	PriceSourceMid  PriceSource = "MID"
+)
+
+func (s PriceSource) String() string {
+	return string(s)
+}
+
+func (s PriceSource) IsValid() bool {
+	switch s {
+	case PriceSourceBid, PriceSourceAsk, PriceSourceLast, PriceSourceMid:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewPriceSource(s string) (PriceSource, error) {
+	switch s {
+	case "BID":
+		return PriceSourceBid, nil
+	case "ASK":
+		return PriceSourceAsk, nil
+	case "LAST":
+		return PriceSourceLast, nil
+	case "MID":
+		return PriceSourceMid, nil
+	default:
+		return "", fmt.Errorf("invalid PriceSource: %s", s)
+	}
+}
+
+// SetPriceSource sets the price source of every price rule in the condition tree
+func (c *TriggerCondition) SetPriceSource(source PriceSource) error {
+	if !source.IsValid() {
+		return fmt.Errorf("invalid price source: %s", source)
+	}
+
+	// Copy the rule, since conditions built from the same leaf share it
+	if c.Price != nil {
+		rule := *c.Price
+		rule.Source = source
+		c.Price = &rule
+	}
+	for i := range c.Children {
+		if err := c.Children[i].SetPriceSource(source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewPriceConditionWithSource creates a price condition evaluated against the given price source
+func NewPriceConditionWithSource(
+	contractID contract.ContractIdentifier,
+	threshold contract.ContractPrice,
+	direction Direction,
+	source PriceSource,
+) (*TriggerCondition, error) {
+	condition, err := NewPriceCondition(contractID, threshold, direction)
+	if err != nil {
+		return nil, err
+	}
+	if err := condition.SetPriceSource(source); err != nil {
+		return nil, err
+	}
+	return condition, nil
+}
@@ -0,0 +1,142 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketSnapshot_Price(t *testing.T) {
+	snapshot := NewMarketSnapshot([]*exchange_domain.Market{
+		{
+			Ticker: "FOO",
+			Pricing: exchange_domain.MarketPricing{
+				YesSide: exchange_domain.PricingSide{Bid: 40, Ask: 45, LastPrice: 42},
+				NoSide:  exchange_domain.PricingSide{Bid: 55, Ask: 60, LastPrice: 58},
+			},
+		},
+		{
+			// Nobody is bidding on yes, and no has never traded
+			Ticker: "THIN",
+			Pricing: exchange_domain.MarketPricing{
+				YesSide: exchange_domain.PricingSide{Ask: 10},
+				NoSide:  exchange_domain.PricingSide{Bid: 90},
+			},
+		},
+	}, time.Now())
+	yes := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	no := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideNo}
+	thinYes := contract.ContractIdentifier{Ticker: "THIN", Side: contract.SideYes}
+	thinNo := contract.ContractIdentifier{Ticker: "THIN", Side: contract.SideNo}
+
+	tests := []struct {
+		name       string
+		contractID contract.ContractIdentifier
+		source     PriceSource
+		expected   contract.ContractPrice
+		wantError  bool
+		noPrice    bool
+	}{
+		{name: "yes bid", contractID: yes, source: PriceSourceBid, expected: 40},
+		{name: "yes ask", contractID: yes, source: PriceSourceAsk, expected: 45},
+		{name: "yes last", contractID: yes, source: PriceSourceLast, expected: 42},
+		{name: "yes mid rounds down", contractID: yes, source: PriceSourceMid, expected: 42},
+		{name: "no bid", contractID: no, source: PriceSourceBid, expected: 55},
+		{name: "unset source uses ask", contractID: no, source: "", expected: 60},
+		{name: "invalid source", contractID: yes, source: "OPEN", wantError: true},
+		{name: "missing market", contractID: contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes}, source: PriceSourceBid, wantError: true},
+		{name: "one-sided ask", contractID: thinYes, source: PriceSourceAsk, expected: 10},
+		{name: "empty bid", contractID: thinYes, source: PriceSourceBid, wantError: true, noPrice: true},
+		{name: "empty ask", contractID: thinNo, source: "", wantError: true, noPrice: true},
+		{name: "no last trade", contractID: thinNo, source: PriceSourceLast, wantError: true, noPrice: true},
+		{name: "mid of bid only book", contractID: thinNo, source: PriceSourceMid, wantError: true, noPrice: true},
+		{name: "mid of ask only book", contractID: thinYes, source: PriceSourceMid, wantError: true, noPrice: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := snapshot.Price(tt.contractID, tt.source)
+			if tt.wantError {
+				assert.Error(t, err)
+				if tt.noPrice {
+					assert.ErrorIs(t, err, ErrNoPrice)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, price)
+		})
+	}
+}
+
+func TestTriggerCondition_SetPriceSource(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bar := contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes}
+
+	fooBelow, err := NewPriceCondition(foo, 40, Below)
+	require.NoError(t, err)
+	barBelow, err := NewPriceCondition(bar, 40, Below)
+	require.NoError(t, err)
+	composite, err := NewCompositeCondition(OperatorOr, *fooBelow, *barBelow)
+	require.NoError(t, err)
+
+	require.NoError(t, composite.SetPriceSource(PriceSourceBid))
+
+	for _, child := range composite.Children {
+		assert.Equal(t, PriceSourceBid, child.Price.Source)
+	}
+	// The leaves the composite was built from are left alone
+	assert.Equal(t, PriceSourceAsk, fooBelow.Price.Source)
+
+	assert.Error(t, composite.SetPriceSource("OPEN"))
+}
+
+func TestTriggerCondition_IsSatisfied_PriceSource(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	// Wide market: the ask is above the stop while the bid is below it
+	snapshot := NewMarketSnapshot([]*exchange_domain.Market{
+		{
+			Ticker: "FOO",
+			Pricing: exchange_domain.MarketPricing{
+				YesSide: exchange_domain.PricingSide{Bid: 35, Ask: 50},
+			},
+		},
+	}, time.Now())
+
+	askStop, err := NewPriceCondition(foo, 40, Below)
+	require.NoError(t, err)
+	bidStop, err := NewPriceConditionWithSource(foo, 40, Below, PriceSourceBid)
+	require.NoError(t, err)
+
+	satisfied, err := askStop.IsSatisfied(snapshot)
+	require.NoError(t, err)
+	assert.False(t, satisfied)
+
+	satisfied, err = bidStop.IsSatisfied(snapshot)
+	require.NoError(t, err)
+	assert.True(t, satisfied)
+}
+
+func TestTriggerCondition_IsSatisfied_EmptyBid(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	// The bids have been pulled, which must not read as a price of 0
+	snapshot := NewMarketSnapshot([]*exchange_domain.Market{
+		{
+			Ticker: "FOO",
+			Pricing: exchange_domain.MarketPricing{
+				YesSide: exchange_domain.PricingSide{Ask: 50},
+			},
+		},
+	}, time.Now())
+
+	bidStop, err := NewPriceConditionWithSource(foo, 40, Below, PriceSourceBid)
+	require.NoError(t, err)
+
+	satisfied, err := bidStop.IsSatisfied(snapshot)
+	assert.ErrorIs(t, err, ErrNoPrice)
+	assert.False(t, satisfied)
+}
@@ -0,0 +1,123 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProtectionPolicyID uuid.UUID
+
+func NewProtectionPolicyID() ProtectionPolicyID {
+	return ProtectionPolicyID(uuid.New())
+}
+
+func (p ProtectionPolicyID) String() string {
+	return uuid.UUID(p).String()
+}
+
+// ProtectionPolicy automatically protects every position in a series or event, e.g.
+// "every YES position in KXHIGHNY gets a stop 12c below entry". Kalshi market tickers
+// extend their event ticker, which extends the series ticker, so a policy covers any
+// market whose ticker starts with its own.
+type ProtectionPolicy struct {
+	PolicyID   ProtectionPolicyID
+	Ticker     contract.Ticker // series or event ticker
+	Side       contract.Side   // nil covers both sides
+	OffsetType TrailType       // CENTS or PERCENT below the average entry
+	Offset     int
+	Enabled    bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func NewProtectionPolicy(
+	ticker contract.Ticker,
+	side contract.Side,
+	offsetType TrailType,
+	offset int,
+) (*ProtectionPolicy, error) {
+	currentTime := time.Now()
+	policy := &ProtectionPolicy{
+		PolicyID:   NewProtectionPolicyID(),
+		Ticker:     ticker,
+		Side:       side,
+		OffsetType: offsetType,
+		Offset:     offset,
+		Enabled:    true,
+		CreatedAt:  currentTime,
+		UpdatedAt:  currentTime,
+	}
+
+	if err := ValidateProtectionPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func ValidateProtectionPolicy(p *ProtectionPolicy) error {
+	if p == nil {
+		return errors.New("policy cannot be nil")
+	}
+	if p.Ticker == "" {
+		return errors.New("policy must have a series or event ticker")
+	}
+	if strings.HasSuffix(string(p.Ticker), "-") {
+		return fmt.Errorf("invalid series or event ticker: %s", p.Ticker)
+	}
+	if !p.OffsetType.IsValid() {
+		return fmt.Errorf("invalid offset type: %s", p.OffsetType)
+	}
+	if p.Offset <= 0 || p.Offset >= 100 {
+		return fmt.Errorf("offset must be between 1 and 99, got %d", p.Offset)
+	}
+	return nil
+}
+
+// Covers reports whether the policy applies to an open position
+func (p ProtectionPolicy) Covers(position exchange_domain.Position) bool {
+	if !p.Enabled || position.Quantity == 0 {
+		return false
+	}
+	if p.Side != nil && position.ContractID.Side != p.Side {
+		return false
+	}
+	return strings.HasPrefix(string(position.ContractID.Ticker), string(p.Ticker)+"-")
+}
+
+// NewStop creates the cost basis stop the policy places on a position
+func (p ProtectionPolicy) NewStop(position exchange_domain.Position) (*Trigger, error) {
+	if !p.Covers(position) {
+		return nil, fmt.Errorf("policy %s does not cover position in %s", p.PolicyID, position.ContractID.Ticker)
+	}
+
+	trigger, err := NewCostBasisStopTrigger(position, p.OffsetType, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	policyID := p.PolicyID
+	trigger.PolicyID = &policyID
+
+	return trigger, nil
+}
+
+// MatchPolicy returns the policy to apply to a position, preferring the most specific
+// ticker so an event policy overrides its series policy, or nil if none covers it
+func MatchPolicy(policies []*ProtectionPolicy, position exchange_domain.Position) *ProtectionPolicy {
+	var match *ProtectionPolicy
+	for _, policy := range policies {
+		if !policy.Covers(position) {
+			continue
+		}
+		if match == nil || len(policy.Ticker) > len(match.Ticker) {
+			match = policy
+		}
+	}
+	return match
+}
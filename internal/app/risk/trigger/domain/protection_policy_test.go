@@ -0,0 +1,129 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProtectionPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		ticker       contract.Ticker
+		offsetType   TrailType
+		offset       int
+		errorMessage string
+	}{
+		{name: "series policy", ticker: "KXHIGHNY", offsetType: TrailTypeCents, offset: 12},
+		{name: "event policy", ticker: "KXHIGHNY-25FEB13", offsetType: TrailTypePercent, offset: 20},
+		{name: "missing ticker", offsetType: TrailTypeCents, offset: 12, errorMessage: "must have a series or event ticker"},
+		{name: "trailing dash", ticker: "KXHIGHNY-", offsetType: TrailTypeCents, offset: 12, errorMessage: "invalid series or event ticker"},
+		{name: "invalid offset type", ticker: "KXHIGHNY", offsetType: "INVALID", offset: 12, errorMessage: "invalid offset type"},
+		{name: "offset too large", ticker: "KXHIGHNY", offsetType: TrailTypeCents, offset: 100, errorMessage: "offset must be between 1 and 99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewProtectionPolicy(tt.ticker, contract.SideYes, tt.offsetType, tt.offset)
+
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, policy)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, policy.Enabled)
+		})
+	}
+}
+
+func TestProtectionPolicy_Covers(t *testing.T) {
+	policy, err := NewProtectionPolicy("KXHIGHNY", contract.SideYes, TrailTypeCents, 12)
+	require.NoError(t, err)
+
+	position := func(ticker contract.Ticker, side contract.Side, quantity uint) exchange_domain.Position {
+		return exchange_domain.Position{
+			ContractID: contract.ContractIdentifier{Ticker: ticker, Side: side},
+			Quantity:   quantity,
+			CostBasis:  int(quantity) * 50,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		position exchange_domain.Position
+		expected bool
+	}{
+		{"market in series", position("KXHIGHNY-25FEB13-T40", contract.SideYes, 10), true},
+		{"other side", position("KXHIGHNY-25FEB13-T40", contract.SideNo, 10), false},
+		{"closed position", position("KXHIGHNY-25FEB13-T40", contract.SideYes, 0), false},
+		{"series sharing a prefix", position("KXHIGHNYC-25FEB13-T40", contract.SideYes, 10), false},
+		{"other series", position("KXHIGHCHI-25FEB13-T40", contract.SideYes, 10), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policy.Covers(tt.position))
+		})
+	}
+
+	t.Run("disabled policy", func(t *testing.T) {
+		disabled := *policy
+		disabled.Enabled = false
+		assert.False(t, disabled.Covers(position("KXHIGHNY-25FEB13-T40", contract.SideYes, 10)))
+	})
+
+	t.Run("policy without side covers both", func(t *testing.T) {
+		bothSides := *policy
+		bothSides.Side = nil
+		assert.True(t, bothSides.Covers(position("KXHIGHNY-25FEB13-T40", contract.SideNo, 10)))
+	})
+}
+
+func TestProtectionPolicy_NewStop(t *testing.T) {
+	policy, err := NewProtectionPolicy("KXHIGHNY", contract.SideYes, TrailTypeCents, 12)
+	require.NoError(t, err)
+
+	contractID := contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB13-T40", Side: contract.SideYes}
+
+	t.Run("stop below entry", func(t *testing.T) {
+		stop, err := policy.NewStop(exchange_domain.Position{ContractID: contractID, Quantity: 10, CostBasis: 500})
+		require.NoError(t, err)
+
+		assert.Equal(t, TriggerTypeStop, stop.TriggerType)
+		assert.Equal(t, &policy.PolicyID, stop.PolicyID)
+		assert.Equal(t, contract.ContractPrice(38), stop.Condition.Price.Threshold)
+		require.NotNil(t, stop.CostBasis)
+	})
+
+	t.Run("uncovered position", func(t *testing.T) {
+		other := contract.ContractIdentifier{Ticker: "KXHIGHCHI-25FEB13-T30", Side: contract.SideYes}
+		stop, err := policy.NewStop(exchange_domain.Position{ContractID: other, Quantity: 10, CostBasis: 500})
+		assert.ErrorContains(t, err, "does not cover position")
+		assert.Nil(t, stop)
+	})
+}
+
+func TestMatchPolicy(t *testing.T) {
+	series, err := NewProtectionPolicy("KXHIGHNY", nil, TrailTypeCents, 12)
+	require.NoError(t, err)
+	event, err := NewProtectionPolicy("KXHIGHNY-25FEB13", nil, TrailTypeCents, 5)
+	require.NoError(t, err)
+	policies := []*ProtectionPolicy{series, event}
+
+	position := func(ticker contract.Ticker) exchange_domain.Position {
+		return exchange_domain.Position{
+			ContractID: contract.ContractIdentifier{Ticker: ticker, Side: contract.SideYes},
+			Quantity:   10,
+			CostBasis:  500,
+		}
+	}
+
+	assert.Equal(t, event, MatchPolicy(policies, position("KXHIGHNY-25FEB13-T40")))
+	assert.Equal(t, series, MatchPolicy(policies, position("KXHIGHNY-25FEB14-T40")))
+	assert.Nil(t, MatchPolicy(policies, position("KXHIGHCHI-25FEB13-T30")))
+}
@@ -0,0 +1,180 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	"slices"
+	"time"
+)
+
+// LadderTier is one step of a scaled exit
+type LadderTier struct {
+	TriggerPrice contract.ContractPrice
+	LimitPrice   *contract.ContractPrice
+	Share        int // percent of the laddered position sold at this tier
+}
+
+// StopLadder scales out of a position through several stops at descending prices,
+// e.g. 1/3 at 45c, 1/3 at 40c and the rest at 35c. Each tier sells its share as a
+// percentage of the position left once the tiers above it have fired, so the tiers
+// size against the live position at execution time and the lowest tier sells the rest.
+type StopLadder struct {
+	Group *TriggerGroup
+	Tiers []*Trigger // highest stop first
+}
+
+func NewStopLadder(contract contract.ContractIdentifier, tiers []LadderTier) (*StopLadder, error) {
+	if err := validateLadderTiers(tiers); err != nil {
+		return nil, err
+	}
+
+	group := NewTriggerGroup(GroupTypeLadder)
+	ladder := &StopLadder{Group: group}
+	for i, tier := range tiers {
+		stop, err := NewStopTrigger(contract, tier.TriggerPrice, tier.LimitPrice)
+		if err != nil {
+			return nil, fmt.Errorf("create tier %d: %w", i+1, err)
+		}
+		stop.Actions[0].SizePercent = tierSizePercent(tiers, i)
+		group.AddTrigger(stop)
+		ladder.Tiers = append(ladder.Tiers, stop)
+	}
+
+	if err := ValidateStopLadder(ladder); err != nil {
+		return nil, err
+	}
+
+	return ladder, nil
+}
+
+// Triggers returns the tiers of the ladder
+func (l *StopLadder) Triggers() []*Trigger {
+	return l.Tiers
+}
+
+// ActiveTiers returns the tiers that have not fired or been cancelled, highest stop first
+func (l *StopLadder) ActiveTiers() []*Trigger {
+	var active []*Trigger
+	for _, tier := range l.Tiers {
+		if !tier.Status.IsTerminal() {
+			active = append(active, tier)
+		}
+	}
+	return active
+}
+
+// Update reprices the tiers that have not fired yet. The new tiers replace them one for
+// one, with shares of the position still held. Returns the modified triggers.
+func (l *StopLadder) Update(tiers []LadderTier) ([]*Trigger, error) {
+	active := l.ActiveTiers()
+	if len(active) == 0 {
+		return nil, errors.New("ladder has no active tiers")
+	}
+	if len(tiers) != len(active) {
+		return nil, fmt.Errorf("ladder has %d active tiers, got %d", len(active), len(tiers))
+	}
+	if err := validateLadderTiers(tiers); err != nil {
+		return nil, err
+	}
+
+	currentTime := time.Now()
+	for i, tier := range tiers {
+		trigger := active[i]
+		trigger.Condition.Price.Threshold = tier.TriggerPrice
+		trigger.Actions[0].LimitPrice = tier.LimitPrice
+		trigger.Actions[0].SizePercent = tierSizePercent(tiers, i)
+		trigger.UpdatedAt = currentTime
+		if err := ValidateStopTrigger(trigger); err != nil {
+			return nil, fmt.Errorf("invalid tier %d: %w", i+1, err)
+		}
+	}
+	l.Group.UpdatedAt = currentTime
+
+	if err := ValidateStopLadder(l); err != nil {
+		return nil, err
+	}
+
+	return active, nil
+}
+
+func ValidateStopLadder(l *StopLadder) error {
+	if l == nil || l.Group == nil {
+		return errors.New("ladder cannot be nil")
+	}
+	if l.Group.GroupType != GroupTypeLadder {
+		return fmt.Errorf("ladder group must be %s, got %s", GroupTypeLadder, l.Group.GroupType)
+	}
+	if len(l.Tiers) < 2 {
+		return fmt.Errorf("ladder must have at least 2 tiers, got %d", len(l.Tiers))
+	}
+
+	for i, tier := range l.Tiers {
+		if err := ValidateStopTrigger(tier); err != nil {
+			return fmt.Errorf("invalid tier %d: %w", i+1, err)
+		}
+		if tier.GroupID == nil || *tier.GroupID != l.Group.GroupID {
+			return fmt.Errorf("trigger %s is not in ladder group %s", tier.TriggerID, l.Group.GroupID)
+		}
+		if tier.Actions[0].SizePercent == nil {
+			return fmt.Errorf("tier %d must size as a percent of the position", i+1)
+		}
+		if tier.Condition.Contract != l.Tiers[0].Condition.Contract {
+			return fmt.Errorf("tier %d contract (%v) must match ladder contract (%v)",
+				i+1, tier.Condition.Contract, l.Tiers[0].Condition.Contract)
+		}
+	}
+
+	// Tiers that already fired no longer constrain the prices of the rest
+	active := l.ActiveTiers()
+	for i := 1; i < len(active); i++ {
+		if active[i].Condition.Price.Threshold >= active[i-1].Condition.Price.Threshold {
+			return fmt.Errorf("stop price (%v) must be below the tier above it (%v)",
+				active[i].Condition.Price.Threshold, active[i-1].Condition.Price.Threshold)
+		}
+	}
+
+	// The lowest tier still waiting to fire always closes out what is left
+	if len(active) > 0 && *active[len(active)-1].Actions[0].SizePercent != 100 {
+		return errors.New("lowest active tier must sell the rest of the position")
+	}
+
+	return nil
+}
+
+// SortLadderTiers orders a ladder's triggers highest stop first
+func SortLadderTiers(tiers []*Trigger) {
+	slices.SortFunc(tiers, func(a, b *Trigger) int {
+		return int(b.Condition.Price.Threshold) - int(a.Condition.Price.Threshold)
+	})
+}
+
+func validateLadderTiers(tiers []LadderTier) error {
+	if len(tiers) < 2 {
+		return fmt.Errorf("ladder must have at least 2 tiers, got %d", len(tiers))
+	}
+
+	total := 0
+	for i, tier := range tiers {
+		if tier.Share <= 0 {
+			return fmt.Errorf("tier %d share must be positive, got %d", i+1, tier.Share)
+		}
+		total += tier.Share
+	}
+	if total != 100 {
+		return fmt.Errorf("tier shares must add up to 100, got %d", total)
+	}
+
+	return nil
+}
+
+// tierSizePercent converts the tier's share of the whole ladder into a percentage of
+// the position left once the tiers above it have fired
+func tierSizePercent(tiers []LadderTier, i int) *int {
+	remaining := 0
+	for _, tier := range tiers[i:] {
+		remaining += tier.Share
+	}
+	percent := (tiers[i].Share*100 + remaining/2) / remaining
+	return &percent
+}
@@ -0,0 +1,138 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStopLadder(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	tests := []struct {
+		name            string
+		tiers           []LadderTier
+		expectedPercent []int
+		errorMessage    string
+	}{
+		{
+			name: "thirds",
+			tiers: []LadderTier{
+				{TriggerPrice: 45, Share: 33},
+				{TriggerPrice: 40, Share: 33},
+				{TriggerPrice: 35, Share: 34},
+			},
+			expectedPercent: []int{33, 49, 100},
+		},
+		{
+			name: "halves",
+			tiers: []LadderTier{
+				{TriggerPrice: 45, Share: 50},
+				{TriggerPrice: 35, Share: 50},
+			},
+			expectedPercent: []int{50, 100},
+		},
+		{
+			name:         "single tier",
+			tiers:        []LadderTier{{TriggerPrice: 45, Share: 100}},
+			errorMessage: "at least 2 tiers",
+		},
+		{
+			name: "shares do not add up",
+			tiers: []LadderTier{
+				{TriggerPrice: 45, Share: 50},
+				{TriggerPrice: 35, Share: 40},
+			},
+			errorMessage: "must add up to 100",
+		},
+		{
+			name: "prices out of order",
+			tiers: []LadderTier{
+				{TriggerPrice: 35, Share: 50},
+				{TriggerPrice: 45, Share: 50},
+			},
+			errorMessage: "must be below the tier above it",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ladder, err := NewStopLadder(contractID, tt.tiers)
+
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, ladder)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, GroupTypeLadder, ladder.Group.GroupType)
+			require.Len(t, ladder.Tiers, len(tt.expectedPercent))
+			for i, tier := range ladder.Tiers {
+				assert.Equal(t, tt.tiers[i].TriggerPrice, tier.Condition.Price.Threshold)
+				assert.Equal(t, tt.expectedPercent[i], *tier.Actions[0].SizePercent)
+				assert.Equal(t, ladder.Group.GroupID, *tier.GroupID)
+			}
+		})
+	}
+}
+
+func TestStopLadder_Update(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	tiers := []LadderTier{
+		{TriggerPrice: 45, Share: 33},
+		{TriggerPrice: 40, Share: 33},
+		{TriggerPrice: 35, Share: 34},
+	}
+
+	t.Run("reprices remaining tiers", func(t *testing.T) {
+		ladder, err := NewStopLadder(contractID, tiers)
+		require.NoError(t, err)
+		ladder.Tiers[0].Status = StatusTriggered
+
+		updated, err := ladder.Update([]LadderTier{
+			{TriggerPrice: 50, Share: 25},
+			{TriggerPrice: 30, Share: 75},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, updated, 2)
+		assert.Equal(t, contract.ContractPrice(50), updated[0].Condition.Price.Threshold)
+		assert.Equal(t, 25, *updated[0].Actions[0].SizePercent)
+		assert.Equal(t, 100, *updated[1].Actions[0].SizePercent)
+	})
+
+	t.Run("rejects tier count mismatch", func(t *testing.T) {
+		ladder, err := NewStopLadder(contractID, tiers)
+		require.NoError(t, err)
+
+		_, err = ladder.Update(tiers[:2])
+		assert.ErrorContains(t, err, "has 3 active tiers")
+	})
+}
+
+func TestTriggerAction_QuantityFor(t *testing.T) {
+	percent := func(p int) *int { return &p }
+
+	tests := []struct {
+		name        string
+		sizePercent *int
+		position    uint
+		expected    uint
+	}{
+		{name: "full position", position: 30, expected: 30},
+		{name: "third", sizePercent: percent(33), position: 30, expected: 10},
+		{name: "half of remainder", sizePercent: percent(49), position: 20, expected: 10},
+		{name: "rest", sizePercent: percent(100), position: 7, expected: 7},
+		{name: "small position still steps down", sizePercent: percent(33), position: 1, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := TriggerAction{Side: Sell, SizePercent: tt.sizePercent}
+			assert.Equal(t, tt.expected, action.QuantityFor(tt.position))
+		})
+	}
+}
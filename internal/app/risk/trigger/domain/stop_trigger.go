@@ -62,6 +62,10 @@ func ValidateStopTrigger(t *Trigger) error {
 		return fmt.Errorf("stop trigger action must be Sell, got %s", action.Side)
 	}
 
+	if err := action.validateSizePercent(); err != nil {
+		return err
+	}
+
 	// Contract consistency validation
 	if t.Condition.Contract != action.Contract {
 		return fmt.Errorf("condition contract (%v) must match action contract (%v)",
@@ -82,6 +86,20 @@ func ValidateStopTrigger(t *Trigger) error {
 		}
 	}
 
+	// Cost basis validation: the stop level is derived from the entry, not set directly
+	if t.CostBasis != nil {
+		if err := t.CostBasis.validate(); err != nil {
+			return err
+		}
+		if action.LimitPrice != nil {
+			return errors.New("cost basis stop cannot have a limit price")
+		}
+		if t.Condition.Price.Threshold != t.CostBasis.StopPrice() {
+			return fmt.Errorf("stop price (%v) must match the cost basis stop price (%v)",
+				t.Condition.Price.Threshold, t.CostBasis.StopPrice())
+		}
+	}
+
 	// Status validation
 	if !t.Status.IsValid() {
 		return fmt.Errorf("invalid trigger status: %s", t.Status)
@@ -0,0 +1,90 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+)
+
+// Ensure condition and action are for the same contract
+func NewTakeProfitTrigger(
+	contract contract.ContractIdentifier,
+	triggerPrice contract.ContractPrice,
+	limitPrice *contract.ContractPrice,
+) (*Trigger, error) {
+	condition, err := NewPriceCondition(contract, triggerPrice, Above)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := NewTriggerAction(contract, Sell, nil, limitPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger := NewTrigger(TriggerTypeTakeProfit, *condition, []TriggerAction{*action})
+
+	if err := ValidateTakeProfitTrigger(trigger); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+func ValidateTakeProfitTrigger(t *Trigger) error {
+	// Basic trigger validation
+	if t == nil {
+		return errors.New("trigger cannot be nil")
+	}
+	if t.TriggerType != TriggerTypeTakeProfit {
+		return fmt.Errorf("invalid trigger type: expected %s, got %s", TriggerTypeTakeProfit, t.TriggerType)
+	}
+
+	// Condition validation
+	if t.Condition.Price == nil {
+		return errors.New("take profit trigger must have a price condition")
+	}
+	// Price validation
+	if !t.Condition.Price.Threshold.IsValid() {
+		return fmt.Errorf("invalid take profit price: %v", t.Condition.Price.Threshold)
+	}
+	if t.Condition.Price.Direction != Above {
+		return fmt.Errorf("take profit trigger price direction must be Above, got %s", t.Condition.Price.Direction)
+	}
+
+	// Actions validation
+	if len(t.Actions) != 1 {
+		return fmt.Errorf("take profit trigger must have exactly one action, got %d", len(t.Actions))
+	}
+
+	action := t.Actions[0]
+	if action.Side != Sell {
+		return fmt.Errorf("take profit trigger action must be Sell, got %s", action.Side)
+	}
+
+	// Contract consistency validation
+	if t.Condition.Contract != action.Contract {
+		return fmt.Errorf("condition contract (%v) must match action contract (%v)",
+			t.Condition.Contract, action.Contract)
+	}
+
+	// If there's a limit price, it must be valid
+	if action.LimitPrice != nil {
+		if !action.LimitPrice.IsValid() {
+			return fmt.Errorf("invalid limit price: %v", *action.LimitPrice)
+		}
+		// The limit is the lowest price we accept once the target is hit,
+		// so apply the same slippage bound as stop triggers
+		if float64(*action.LimitPrice) < float64(t.Condition.Price.Threshold)*0.9 { // 10% max slippage
+			return fmt.Errorf("limit price (%v) too low compared to take profit price (%v)",
+				*action.LimitPrice, t.Condition.Price.Threshold)
+		}
+	}
+
+	// Status validation
+	if !t.Status.IsValid() {
+		return fmt.Errorf("invalid trigger status: %s", t.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTakeProfitTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker("FOO"),
+		Side:   contract.SideYes,
+	}
+	limitPrice := contract.ContractPrice(79)
+	badLimitPrice := contract.ContractPrice(70)
+	tests := []struct {
+		name        string
+		contract    contract.ContractIdentifier
+		targetPrice contract.ContractPrice
+		limitPrice  *contract.ContractPrice
+		expectError bool
+	}{
+		{
+			name:        "valid take profit trigger",
+			contract:    contractID,
+			targetPrice: contract.ContractPrice(80),
+			limitPrice:  &limitPrice,
+			expectError: false,
+		},
+		{
+			name:        "valid take profit market trigger",
+			contract:    contractID,
+			targetPrice: contract.ContractPrice(80),
+			limitPrice:  nil,
+			expectError: false,
+		},
+		{
+			name:        "invalid target price",
+			contract:    contractID,
+			targetPrice: contract.ContractPrice(110),
+			limitPrice:  nil,
+			expectError: true,
+		},
+		{
+			name:        "limit price too low",
+			contract:    contractID,
+			targetPrice: contract.ContractPrice(80),
+			limitPrice:  &badLimitPrice, // More than 10% below target price
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := NewTakeProfitTrigger(tt.contract, tt.targetPrice, tt.limitPrice)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, trigger)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, trigger)
+				assert.Equal(t, TriggerTypeTakeProfit, trigger.TriggerType)
+				assert.Equal(t, tt.contract, trigger.Condition.Contract)
+				assert.Equal(t, Above, trigger.Condition.Price.Direction)
+				assert.Equal(t, tt.targetPrice, trigger.Condition.Price.Threshold)
+				assert.Equal(t, Sell, trigger.Actions[0].Side)
+				assert.Equal(t, tt.limitPrice, trigger.Actions[0].LimitPrice)
+			}
+		})
+	}
+}
+
+func TestValidateTakeProfitTrigger(t *testing.T) {
+	validContract := contract.ContractIdentifier{
+		Ticker: contract.Ticker("FOO"),
+		Side:   contract.SideYes,
+	}
+	validTargetPrice := contract.ContractPrice(80)
+	validLimitPrice := contract.ContractPrice(79)
+
+	tests := []struct {
+		name         string
+		setupTrigger func() *Trigger
+		expectError  bool
+		errorMessage string
+	}{
+		{
+			name: "valid trigger",
+			setupTrigger: func() *Trigger {
+				trigger, _ := NewTakeProfitTrigger(validContract, validTargetPrice, &validLimitPrice)
+				return trigger
+			},
+			expectError: false,
+		},
+		{
+			name: "nil trigger",
+			setupTrigger: func() *Trigger {
+				return nil
+			},
+			expectError:  true,
+			errorMessage: "trigger cannot be nil",
+		},
+		{
+			name: "stop trigger type",
+			setupTrigger: func() *Trigger {
+				trigger, _ := NewTakeProfitTrigger(validContract, validTargetPrice, &validLimitPrice)
+				trigger.TriggerType = TriggerTypeStop
+				return trigger
+			},
+			expectError:  true,
+			errorMessage: "invalid trigger type",
+		},
+		{
+			name: "below direction",
+			setupTrigger: func() *Trigger {
+				trigger, _ := NewTakeProfitTrigger(validContract, validTargetPrice, &validLimitPrice)
+				trigger.Condition.Price.Direction = Below
+				return trigger
+			},
+			expectError:  true,
+			errorMessage: "direction must be Above",
+		},
+		{
+			name: "buy action",
+			setupTrigger: func() *Trigger {
+				trigger, _ := NewTakeProfitTrigger(validContract, validTargetPrice, &validLimitPrice)
+				trigger.Actions[0].Side = Buy
+				return trigger
+			},
+			expectError:  true,
+			errorMessage: "action must be Sell",
+		},
+		{
+			name: "mismatched contracts",
+			setupTrigger: func() *Trigger {
+				trigger, _ := NewTakeProfitTrigger(validContract, validTargetPrice, &validLimitPrice)
+				trigger.Actions[0].Contract =
+					contract.ContractIdentifier{
+						Ticker: contract.Ticker("BAR"),
+						Side:   contract.SideYes,
+					}
+				return trigger
+			},
+			expectError:  true,
+			errorMessage: "condition contract",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger := tt.setupTrigger()
+			err := ValidateTakeProfitTrigger(trigger)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.errorMessage != "" {
+					assert.Contains(t, err.Error(), tt.errorMessage)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	"time"
+)
+
+// TimeReference represents what a time rule's offset is measured from
+type TimeReference string
+
+// Absolute fires at a fixed wall-clock time.
+// MarketClose and MarketExpiration fire relative to the market's close or expiration time.
+const (
+	TimeReferenceAbsolute         TimeReference = "ABSOLUTE"
+	TimeReferenceMarketClose      TimeReference = "MARKET_CLOSE"
+	TimeReferenceMarketExpiration TimeReference = "MARKET_EXPIRATION"
+)
+
+func (r TimeReference) String() string {
+	return string(r)
+}
+
+func (r TimeReference) IsValid() bool {
+	switch r {
+	case TimeReferenceAbsolute, TimeReferenceMarketClose, TimeReferenceMarketExpiration:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewTimeReference(s string) (TimeReference, error) {
+	switch s {
+	case "ABSOLUTE":
+		return TimeReferenceAbsolute, nil
+	case "MARKET_CLOSE":
+		return TimeReferenceMarketClose, nil
+	case "MARKET_EXPIRATION":
+		return TimeReferenceMarketExpiration, nil
+	default:
+		return "", fmt.Errorf("invalid TimeReference: %s", s)
+	}
+}
+
+// TimeRule is satisfied once the snapshot time reaches the rule's target time,
+// e.g. "at 15:30 ET" or "30 minutes before market close"
+type TimeRule struct {
+	Reference TimeReference
+	At        *time.Time      // target time for ABSOLUTE rules
+	Ticker    contract.Ticker // market whose close or expiration is the reference
+	Offset    time.Duration   // added to the market time; negative means before
+}
+
+func (r TimeRule) validate() error {
+	switch r.Reference {
+	case TimeReferenceAbsolute:
+		if r.At == nil || r.At.IsZero() {
+			return errors.New("absolute time rule must have a time")
+		}
+		if r.Ticker != "" || r.Offset != 0 {
+			return errors.New("absolute time rule cannot have a market or offset")
+		}
+	case TimeReferenceMarketClose, TimeReferenceMarketExpiration:
+		if r.Ticker == "" {
+			return fmt.Errorf("%s time rule must have a market", r.Reference)
+		}
+		if r.At != nil {
+			return fmt.Errorf("%s time rule cannot have an absolute time", r.Reference)
+		}
+	default:
+		return fmt.Errorf("invalid time reference: %s", r.Reference)
+	}
+	return nil
+}
+
+// TargetTime resolves the time at which the rule becomes satisfied
+func (r TimeRule) TargetTime(snapshot MarketSnapshot) (time.Time, error) {
+	if r.Reference == TimeReferenceAbsolute {
+		if r.At == nil {
+			return time.Time{}, errors.New("absolute time rule must have a time")
+		}
+		return *r.At, nil
+	}
+
+	market, ok := snapshot.Markets[r.Ticker]
+	if !ok || market == nil {
+		return time.Time{}, fmt.Errorf("no market data for %s", r.Ticker)
+	}
+
+	var reference time.Time
+	switch r.Reference {
+	case TimeReferenceMarketClose:
+		reference = market.Status.CloseTime
+	case TimeReferenceMarketExpiration:
+		reference = market.Status.ExpirationTime
+	default:
+		return time.Time{}, fmt.Errorf("invalid time reference: %s", r.Reference)
+	}
+	if reference.IsZero() {
+		return time.Time{}, fmt.Errorf("market %s has no %s time", r.Ticker, r.Reference)
+	}
+
+	return reference.Add(r.Offset), nil
+}
+
+// Checks whether the snapshot time has reached the target time
+func (r TimeRule) isSatisfied(snapshot MarketSnapshot) (bool, error) {
+	target, err := r.TargetTime(snapshot)
+	if err != nil {
+		return false, err
+	}
+	return !snapshot.Time.Before(target), nil
+}
+
+func (r TimeRule) String() string {
+	if r.Reference == TimeReferenceAbsolute && r.At != nil {
+		return fmt.Sprintf("time rule at %s", r.At.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("time rule %s %s %+v", r.Ticker, r.Reference, r.Offset)
+}
+
+// NewAbsoluteTimeCondition creates a condition satisfied from the given time onward
+func NewAbsoluteTimeCondition(at time.Time) (*TriggerCondition, error) {
+	rule := &TimeRule{
+		Reference: TimeReferenceAbsolute,
+		At:        &at,
+	}
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return &TriggerCondition{Time: rule}, nil
+}
+
+// NewMarketTimeCondition creates a condition satisfied from an offset relative to
+// the market's close or expiration time onward
+func NewMarketTimeCondition(
+	ticker contract.Ticker,
+	reference TimeReference,
+	offset time.Duration,
+) (*TriggerCondition, error) {
+	rule := &TimeRule{
+		Reference: reference,
+		Ticker:    ticker,
+		Offset:    offset,
+	}
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return &TriggerCondition{Time: rule}, nil
+}
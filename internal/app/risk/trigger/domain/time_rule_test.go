@@ -0,0 +1,112 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMarketTimeCondition(t *testing.T) {
+	tests := []struct {
+		name         string
+		ticker       contract.Ticker
+		reference    TimeReference
+		errorMessage string
+	}{
+		{name: "before close", ticker: "FOO", reference: TimeReferenceMarketClose},
+		{name: "before expiration", ticker: "FOO", reference: TimeReferenceMarketExpiration},
+		{name: "missing market", reference: TimeReferenceMarketClose, errorMessage: "must have a market"},
+		{name: "absolute reference", ticker: "FOO", reference: TimeReferenceAbsolute, errorMessage: "must have a time"},
+		{name: "invalid reference", ticker: "FOO", reference: "OPEN", errorMessage: "invalid time reference"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewMarketTimeCondition(tt.ticker, tt.reference, -30*time.Minute)
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, condition)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []contract.Ticker{tt.ticker}, condition.Tickers())
+		})
+	}
+}
+
+func TestTimeCondition_IsSatisfied(t *testing.T) {
+	closeTime := time.Date(2025, 1, 20, 21, 0, 0, 0, time.UTC)
+	market := &exchange_domain.Market{
+		Ticker: "FOO",
+		Status: exchange_domain.MarketStatus{
+			CloseTime:      closeTime,
+			ExpirationTime: closeTime.Add(24 * time.Hour),
+		},
+	}
+
+	beforeClose, err := NewMarketTimeCondition("FOO", TimeReferenceMarketClose, -30*time.Minute)
+	require.NoError(t, err)
+	beforeExpiration, err := NewMarketTimeCondition("FOO", TimeReferenceMarketExpiration, -time.Hour)
+	require.NoError(t, err)
+	absolute, err := NewAbsoluteTimeCondition(closeTime.Add(-90 * time.Minute))
+	require.NoError(t, err)
+	otherMarket, err := NewMarketTimeCondition("BAR", TimeReferenceMarketClose, 0)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		condition *TriggerCondition
+		now       time.Time
+		want      bool
+		wantError bool
+	}{
+		{name: "before close offset", condition: beforeClose, now: closeTime.Add(-31 * time.Minute), want: false},
+		{name: "at close offset", condition: beforeClose, now: closeTime.Add(-30 * time.Minute), want: true},
+		{name: "before expiration offset", condition: beforeExpiration, now: closeTime, want: false},
+		{name: "after absolute time", condition: absolute, now: closeTime.Add(-time.Hour), want: true},
+		{name: "before absolute time", condition: absolute, now: closeTime.Add(-2 * time.Hour), want: false},
+		{name: "missing market", condition: otherMarket, now: closeTime, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := NewMarketSnapshot([]*exchange_domain.Market{market}, tt.now)
+			got, err := tt.condition.IsSatisfied(snapshot)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTimeCondition_CombinedWithPrice(t *testing.T) {
+	// "If at 15:30 ET the price is still below 40, sell"
+	et := time.FixedZone("EST", -5*60*60)
+	at := time.Date(2025, 1, 20, 15, 30, 0, 0, et)
+	afterCutoff, err := NewAbsoluteTimeCondition(at)
+	require.NoError(t, err)
+	stillLow, err := NewPriceCondition(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, 40, Below)
+	require.NoError(t, err)
+	condition, err := NewCompositeCondition(OperatorAnd, *afterCutoff, *stillLow)
+	require.NoError(t, err)
+
+	market := &exchange_domain.Market{
+		Ticker:  "FOO",
+		Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 35}},
+	}
+
+	early, err := condition.IsSatisfied(NewMarketSnapshot([]*exchange_domain.Market{market}, at.Add(-time.Minute)))
+	require.NoError(t, err)
+	assert.False(t, early)
+
+	onTime, err := condition.IsSatisfied(NewMarketSnapshot([]*exchange_domain.Market{market}, at))
+	require.NoError(t, err)
+	assert.True(t, onTime)
+}
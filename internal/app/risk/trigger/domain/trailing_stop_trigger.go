@@ -0,0 +1,206 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	"time"
+)
+
+// TrailType represents how the trailing distance is measured
+type TrailType string
+
+const (
+	TrailTypeCents   TrailType = "CENTS"
+	TrailTypePercent TrailType = "PERCENT"
+)
+
+func (t TrailType) String() string {
+	return string(t)
+}
+
+func (t TrailType) IsValid() bool {
+	switch t {
+	case TrailTypeCents, TrailTypePercent:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewTrailType(s string) (TrailType, error) {
+	switch s {
+	case "CENTS":
+		return TrailTypeCents, nil
+	case "PERCENT":
+		return TrailTypePercent, nil
+	default:
+		return "", fmt.Errorf("invalid TrailType: %s", s)
+	}
+}
+
+// TrailingStop tracks the best price seen so far and the distance the stop follows it by
+type TrailingStop struct {
+	TrailType     TrailType
+	TrailAmount   int // cents for CENTS, whole percent for PERCENT
+	HighWaterMark contract.ContractPrice
+}
+
+func newTrailingStop(
+	trailType TrailType,
+	trailAmount int,
+	highWaterMark contract.ContractPrice,
+) (*TrailingStop, error) {
+	trailingStop := &TrailingStop{
+		TrailType:     trailType,
+		TrailAmount:   trailAmount,
+		HighWaterMark: highWaterMark,
+	}
+	if err := trailingStop.validate(); err != nil {
+		return nil, err
+	}
+	return trailingStop, nil
+}
+
+func (t TrailingStop) validate() error {
+	if !t.TrailType.IsValid() {
+		return fmt.Errorf("invalid trail type: %s", t.TrailType)
+	}
+	if t.TrailAmount <= 0 || t.TrailAmount >= 100 {
+		return fmt.Errorf("trail amount must be between 1 and 99, got %d", t.TrailAmount)
+	}
+	if !t.HighWaterMark.IsValid() {
+		return fmt.Errorf("invalid high water mark: %d", t.HighWaterMark)
+	}
+	return nil
+}
+
+// StopPrice is the effective stop level implied by the high water mark
+func (t TrailingStop) StopPrice() contract.ContractPrice {
+	hwm := t.HighWaterMark.Value()
+
+	var stop int
+	switch t.TrailType {
+	case TrailTypePercent:
+		stop = hwm * (100 - t.TrailAmount) / 100
+	default:
+		stop = hwm - t.TrailAmount
+	}
+
+	if stop < 0 {
+		stop = 0
+	}
+	return contract.ContractPrice(stop)
+}
+
+// Ensure condition and action are for the same contract
+func NewTrailingStopTrigger(
+	contract contract.ContractIdentifier,
+	trailType TrailType,
+	trailAmount int,
+	referencePrice contract.ContractPrice,
+) (*Trigger, error) {
+	trailingStop, err := newTrailingStop(trailType, trailAmount, referencePrice)
+	if err != nil {
+		return nil, err
+	}
+
+	condition, err := NewPriceCondition(contract, trailingStop.StopPrice(), Below)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trailing stops always exit at market since the stop level moves
+	action, err := NewTriggerAction(contract, Sell, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger := NewTrigger(TriggerTypeTrailingStop, *condition, []TriggerAction{*action})
+	trigger.TrailingStop = trailingStop
+
+	if err := ValidateTrailingStopTrigger(trigger); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// UpdateTrailingStop raises the high water mark and stop level if the price made a new high.
+// Returns true if the trigger was modified.
+func (t *Trigger) UpdateTrailingStop(price contract.ContractPrice) (bool, error) {
+	if t.TrailingStop == nil || t.Condition.Price == nil {
+		return false, errors.New("trigger is not a trailing stop")
+	}
+	if !price.IsValid() {
+		return false, fmt.Errorf("invalid price: %d", price.Value())
+	}
+
+	if price <= t.TrailingStop.HighWaterMark {
+		return false, nil
+	}
+
+	t.TrailingStop.HighWaterMark = price
+	// The stop only ever ratchets up
+	if stopPrice := t.TrailingStop.StopPrice(); stopPrice > t.Condition.Price.Threshold {
+		t.Condition.Price.Threshold = stopPrice
+	}
+	t.UpdatedAt = time.Now()
+
+	return true, nil
+}
+
+func ValidateTrailingStopTrigger(t *Trigger) error {
+	// Basic trigger validation
+	if t == nil {
+		return errors.New("trigger cannot be nil")
+	}
+	if t.TriggerType != TriggerTypeTrailingStop {
+		return fmt.Errorf("invalid trigger type: expected %s, got %s", TriggerTypeTrailingStop, t.TriggerType)
+	}
+
+	// Trailing state validation
+	if t.TrailingStop == nil {
+		return errors.New("trailing stop trigger must have trailing stop settings")
+	}
+	if err := t.TrailingStop.validate(); err != nil {
+		return err
+	}
+
+	// Condition validation
+	if t.Condition.Price == nil {
+		return errors.New("trailing stop trigger must have a price condition")
+	}
+	if !t.Condition.Price.Threshold.IsValid() {
+		return fmt.Errorf("invalid stop price: %v", t.Condition.Price.Threshold)
+	}
+	if t.Condition.Price.Direction != Below {
+		return fmt.Errorf("trailing stop trigger price direction must be Below, got %s", t.Condition.Price.Direction)
+	}
+
+	// Actions validation
+	if len(t.Actions) != 1 {
+		return fmt.Errorf("trailing stop trigger must have exactly one action, got %d", len(t.Actions))
+	}
+
+	action := t.Actions[0]
+	if action.Side != Sell {
+		return fmt.Errorf("trailing stop trigger action must be Sell, got %s", action.Side)
+	}
+	if action.LimitPrice != nil {
+		return errors.New("trailing stop trigger cannot have a limit price")
+	}
+
+	// Contract consistency validation
+	if t.Condition.Contract != action.Contract {
+		return fmt.Errorf("condition contract (%v) must match action contract (%v)",
+			t.Condition.Contract, action.Contract)
+	}
+
+	// Status validation
+	if !t.Status.IsValid() {
+		return fmt.Errorf("invalid trigger status: %s", t.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrailingStop_StopPrice(t *testing.T) {
+	tests := []struct {
+		name          string
+		trailingStop  TrailingStop
+		expectedPrice contract.ContractPrice
+	}{
+		{
+			name:          "cents offset",
+			trailingStop:  TrailingStop{TrailType: TrailTypeCents, TrailAmount: 10, HighWaterMark: 90},
+			expectedPrice: 80,
+		},
+		{
+			name:          "percent offset rounds down",
+			trailingStop:  TrailingStop{TrailType: TrailTypePercent, TrailAmount: 15, HighWaterMark: 91},
+			expectedPrice: 77,
+		},
+		{
+			name:          "cents offset floors at zero",
+			trailingStop:  TrailingStop{TrailType: TrailTypeCents, TrailAmount: 20, HighWaterMark: 5},
+			expectedPrice: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedPrice, tt.trailingStop.StopPrice())
+		})
+	}
+}
+
+func TestNewTrailingStopTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker("FOO"),
+		Side:   contract.SideYes,
+	}
+	tests := []struct {
+		name           string
+		trailType      TrailType
+		trailAmount    int
+		referencePrice contract.ContractPrice
+		expectedStop   contract.ContractPrice
+		expectError    bool
+	}{
+		{
+			name:           "valid cents trailing stop",
+			trailType:      TrailTypeCents,
+			trailAmount:    10,
+			referencePrice: 60,
+			expectedStop:   50,
+		},
+		{
+			name:           "valid percent trailing stop",
+			trailType:      TrailTypePercent,
+			trailAmount:    10,
+			referencePrice: 60,
+			expectedStop:   54,
+		},
+		{
+			name:           "invalid trail type",
+			trailType:      "INVALID",
+			trailAmount:    10,
+			referencePrice: 60,
+			expectError:    true,
+		},
+		{
+			name:           "zero trail amount",
+			trailType:      TrailTypeCents,
+			trailAmount:    0,
+			referencePrice: 60,
+			expectError:    true,
+		},
+		{
+			name:           "invalid reference price",
+			trailType:      TrailTypeCents,
+			trailAmount:    10,
+			referencePrice: 120,
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := NewTrailingStopTrigger(contractID, tt.trailType, tt.trailAmount, tt.referencePrice)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, trigger)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, TriggerTypeTrailingStop, trigger.TriggerType)
+				assert.Equal(t, Below, trigger.Condition.Price.Direction)
+				assert.Equal(t, tt.expectedStop, trigger.Condition.Price.Threshold)
+				assert.Equal(t, tt.referencePrice, trigger.TrailingStop.HighWaterMark)
+				assert.Nil(t, trigger.Actions[0].LimitPrice)
+			}
+		})
+	}
+}
+
+func TestTrigger_UpdateTrailingStop(t *testing.T) {
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker("FOO"),
+		Side:   contract.SideYes,
+	}
+
+	t.Run("ratchets up on new high", func(t *testing.T) {
+		trigger, err := NewTrailingStopTrigger(contractID, TrailTypeCents, 10, 60)
+		require.NoError(t, err)
+
+		changed, err := trigger.UpdateTrailingStop(75)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, contract.ContractPrice(75), trigger.TrailingStop.HighWaterMark)
+		assert.Equal(t, contract.ContractPrice(65), trigger.Condition.Price.Threshold)
+	})
+
+	t.Run("does not move down when price falls", func(t *testing.T) {
+		trigger, err := NewTrailingStopTrigger(contractID, TrailTypeCents, 10, 60)
+		require.NoError(t, err)
+
+		changed, err := trigger.UpdateTrailingStop(55)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, contract.ContractPrice(60), trigger.TrailingStop.HighWaterMark)
+		assert.Equal(t, contract.ContractPrice(50), trigger.Condition.Price.Threshold)
+	})
+
+	t.Run("rejects non trailing trigger", func(t *testing.T) {
+		trigger, err := NewStopTrigger(contractID, 50, nil)
+		require.NoError(t, err)
+
+		_, err = trigger.UpdateTrailingStop(75)
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateTrailingStopTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker("FOO"),
+		Side:   contract.SideYes,
+	}
+
+	tests := []struct {
+		name         string
+		setupTrigger func() *Trigger
+		errorMessage string
+	}{
+		{
+			name: "valid trigger",
+			setupTrigger: func() *Trigger {
+				trigger, _ := NewTrailingStopTrigger(contractID, TrailTypeCents, 10, 60)
+				return trigger
+			},
+		},
+		{
+			name: "missing trailing settings",
+			setupTrigger: func() *Trigger {
+				trigger, _ := NewTrailingStopTrigger(contractID, TrailTypeCents, 10, 60)
+				trigger.TrailingStop = nil
+				return trigger
+			},
+			errorMessage: "must have trailing stop settings",
+		},
+		{
+			name: "limit price set",
+			setupTrigger: func() *Trigger {
+				trigger, _ := NewTrailingStopTrigger(contractID, TrailTypeCents, 10, 60)
+				limitPrice := contract.ContractPrice(49)
+				trigger.Actions[0].LimitPrice = &limitPrice
+				return trigger
+			},
+			errorMessage: "cannot have a limit price",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTrailingStopTrigger(tt.setupTrigger())
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package trigger_domain
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +20,46 @@ func (t TriggerID) String() string {
 	return uuid.UUID(t).String()
 }
 
+// OrderReference is the client order ID of the trigger's nth order, counting from
+// zero. The first carries the trigger ID itself and later ones add a suffix, so every
+// order the exchange holds can be traced back to the trigger that placed it.
+func (t TriggerID) OrderReference(n int) string {
+	if n == 0 {
+		return t.String()
+	}
+	return fmt.Sprintf("%s-%d", t, n)
+}
+
+// OrderIndex recovers n from a client order ID made by OrderReference(n). It reports
+// false if the order was not placed by this trigger.
+func (t TriggerID) OrderIndex(reference string) (int, bool) {
+	if reference == t.String() {
+		return 0, true
+	}
+	suffix, ok := strings.CutPrefix(reference, t.String()+"-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(suffix)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// FollowUpReference is the client order ID of an order replacing the unfilled part of the
+// trigger's nth order, on the given attempt. OrderIndex does not match it, so follow-ups
+// are never taken for orders sent when the trigger executed.
+func (t TriggerID) FollowUpReference(n, attempt int) string {
+	return fmt.Sprintf("%s-%d-r%d", t, n, attempt)
+}
+
+// PlacedOrder reports whether an order's client order ID was sent by this trigger
+func (t TriggerID) PlacedOrder(reference string) bool {
+	_, ok := t.OrderIndex(reference)
+	return ok
+}
+
 // OrderStatus represents the current state of an order
 type TriggerStatus string
 
@@ -27,7 +70,7 @@ func (s TriggerStatus) String() string {
 // IsValid checks if the OrderStatus is one of the defined constants
 func (s TriggerStatus) IsValid() bool {
 	switch s {
-	case StatusActive, StatusTriggered, StatusCancelled, StatusExpired:
+	case StatusPending, StatusActive, StatusExecuting, StatusTriggered, StatusCancelled, StatusExpired, StatusFlagged:
 		return true
 	default:
 		return false
@@ -36,14 +79,20 @@ func (s TriggerStatus) IsValid() bool {
 
 func NewTriggerStatus(s string) (TriggerStatus, error) {
 	switch s {
+	case "PENDING":
+		return StatusPending, nil
 	case "ACTIVE":
 		return StatusActive, nil
+	case "EXECUTING":
+		return StatusExecuting, nil
 	case "TRIGGERED":
 		return StatusTriggered, nil
 	case "CANCELLED":
 		return StatusCancelled, nil
 	case "EXPIRED":
 		return StatusExpired, nil
+	case "FLAGGED":
+		return StatusFlagged, nil
 	default:
 		return "", fmt.Errorf("invalid TriggerStatus: %s", s)
 	}
@@ -58,24 +107,57 @@ func (s TriggerStatus) IsTerminal() bool {
 	}
 }
 
+// Pending means the trigger waits for the orders of the trigger that arms it to fill
 // Active means the order is currently being monitored
+// Executing means the orders are being sent and the outcome is not yet recorded
 // Executed means the order has been triggered
 // Cancelled means the order has been cancelled
 // Expired means the event has passed and the order is no longer valid
+// Flagged means a pre-trade guard held the order back and it awaits review
 const (
+	StatusPending   TriggerStatus = "PENDING"
 	StatusActive    TriggerStatus = "ACTIVE"
+	StatusExecuting TriggerStatus = "EXECUTING"
 	StatusTriggered TriggerStatus = "TRIGGERED"
 	StatusCancelled TriggerStatus = "CANCELLED"
 	StatusExpired   TriggerStatus = "EXPIRED"
+	StatusFlagged   TriggerStatus = "FLAGGED"
 )
 
 // TriggerType represents the type of trigger
 type TriggerType string
 
 const (
-	TriggerTypeStop TriggerType = "STOP"
+	TriggerTypeStop          TriggerType = "STOP"
+	TriggerTypeTakeProfit    TriggerType = "TAKE_PROFIT"
+	TriggerTypeTrailingStop  TriggerType = "TRAILING_STOP"
+	TriggerTypeConditional   TriggerType = "CONDITIONAL"
+	TriggerTypeEntry         TriggerType = "ENTRY"
+	TriggerTypeHedge         TriggerType = "HEDGE"
+	TriggerTypeEventExposure TriggerType = "EVENT_EXPOSURE"
 )
 
+func NewTriggerType(s string) (TriggerType, error) {
+	switch s {
+	case "STOP":
+		return TriggerTypeStop, nil
+	case "TAKE_PROFIT":
+		return TriggerTypeTakeProfit, nil
+	case "TRAILING_STOP":
+		return TriggerTypeTrailingStop, nil
+	case "CONDITIONAL":
+		return TriggerTypeConditional, nil
+	case "ENTRY":
+		return TriggerTypeEntry, nil
+	case "HEDGE":
+		return TriggerTypeHedge, nil
+	case "EVENT_EXPOSURE":
+		return TriggerTypeEventExposure, nil
+	default:
+		return "", fmt.Errorf("invalid TriggerType: %s", s)
+	}
+}
+
 func (t TriggerType) String() string {
 	return string(t)
 }
@@ -83,21 +165,40 @@ func (t TriggerType) String() string {
 // IsValid checks if the OrderStatus is one of the defined constants
 func (t TriggerType) IsValid() bool {
 	switch t {
-	case TriggerTypeStop:
+	case TriggerTypeStop, TriggerTypeTakeProfit, TriggerTypeTrailingStop, TriggerTypeConditional, TriggerTypeEntry, TriggerTypeHedge,
+		TriggerTypeEventExposure:
 		return true
 	default:
 		return false
 	}
 }
 
+// ErrTriggerChanged means the trigger was changed elsewhere since it was read, e.g. cancelled
+// through the API while a monitor pass was evaluating it. The change is kept and the next
+// pass sees it.
+var ErrTriggerChanged = errors.New("trigger changed since it was read")
+
 type Trigger struct {
-	TriggerID   TriggerID
-	TriggerType TriggerType
-	Status      TriggerStatus
-	Condition   TriggerCondition
-	Actions     []TriggerAction
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	TriggerID    TriggerID
+	TriggerType  TriggerType
+	Status       TriggerStatus
+	Condition    TriggerCondition
+	Actions      []TriggerAction
+	TrailingStop *TrailingStop       // nil unless a trailing stop trigger
+	CostBasis    *CostBasisStop      // nil unless the stop level follows the position's cost basis
+	GroupID      *TriggerGroupID     // nil unless part of a trigger group
+	PolicyID     *ProtectionPolicyID // nil unless placed by a protection policy
+	ArmedBy      *TriggerID          // nil unless pending until another trigger's orders fill
+	Expiry       *TriggerExpiry      // nil unless expired
+	Confirmation *Confirmation       // nil fires as soon as the condition is satisfied
+	Guard        *ExecutionGuard     // nil uses the executor's default guards
+	GuardFailure *GuardFailure       // most recent failed pre-trade check, nil if none
+	FillPolicy   *FillPolicy         // nil leaves orders resting until they fill
+	Orders       []TriggerOrder      // sent when the trigger executed, in the order they were placed
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	ReconcileAttempts int // failed attempts to settle an interrupted execution
 }
 
 func NewTrigger(
@@ -116,3 +217,27 @@ func NewTrigger(
 		UpdatedAt:   currentTime,
 	}
 }
+
+// Arm activates a pending trigger once the orders of the trigger it waits on have filled
+// quantity contracts. Its sells are sized to that quantity, since that is the position
+// it was set up to protect.
+func (t *Trigger) Arm(quantity uint) error {
+	if t.Status != StatusPending {
+		return fmt.Errorf("trigger is not pending, status: %s", t.Status)
+	}
+	if quantity == 0 {
+		return fmt.Errorf("cannot arm trigger with nothing filled")
+	}
+
+	for i := range t.Actions {
+		if t.Actions[i].Side != Sell || t.Actions[i].FlattensEvent() {
+			continue
+		}
+		size := quantity
+		t.Actions[i].Size = &size
+		t.Actions[i].SizePercent = nil
+	}
+	t.Status = StatusActive
+	t.UpdatedAt = time.Now()
+	return nil
+}
@@ -3,6 +3,7 @@ package trigger_domain
 import (
 	"fmt"
 	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
 )
 
 // OrderSide represents whether we're buying or selling
@@ -39,10 +40,13 @@ func NewOrderSide(s string) (OrderSide, error) {
 
 // Action represents what to do when the condition is met
 type TriggerAction struct {
-	Contract   contract.ContractIdentifier
-	Side       OrderSide
-	Size       *uint                   // nil means "full position" for sells
-	LimitPrice *contract.ContractPrice // nil means "market order"
+	Contract    contract.ContractIdentifier
+	Side        OrderSide
+	Size        *uint                   // nil means "full position" for sells
+	SizePercent *int                    // percent of the live position to sell when Size is nil, e.g. for ladder tiers
+	LimitPrice  *contract.ContractPrice // nil means "market order"
+	MaxCost     *int                    // most a buy may spend in cents, nil means no cap
+	Event       contract.Ticker         // set to sell every position held in the event instead of one contract
 }
 
 func NewTriggerAction(
@@ -67,3 +71,121 @@ func NewTriggerAction(
 		LimitPrice: limitPrice,
 	}, nil
 }
+
+// NewBuyAction creates an action that buys size contracts, optionally
+// capped by a limit price and a maximum total cost in cents
+func NewBuyAction(
+	contract contract.ContractIdentifier,
+	size uint,
+	limitPrice *contract.ContractPrice,
+	maxCost *int,
+) (*TriggerAction, error) {
+	action, err := NewTriggerAction(contract, Buy, &size, limitPrice)
+	if err != nil {
+		return nil, err
+	}
+	action.MaxCost = maxCost
+
+	if err := action.validateBuy(); err != nil {
+		return nil, err
+	}
+
+	return action, nil
+}
+
+// validateBuy checks the sizing rules that only apply to buys
+func (a TriggerAction) validateBuy() error {
+	if a.Size == nil || *a.Size == 0 {
+		return fmt.Errorf("buy size must be positive")
+	}
+	if a.LimitPrice != nil && !a.LimitPrice.IsValid() {
+		return fmt.Errorf("invalid limit price: %v", *a.LimitPrice)
+	}
+	if a.MaxCost != nil && *a.MaxCost <= 0 {
+		return fmt.Errorf("max cost must be positive, got %d", *a.MaxCost)
+	}
+	return nil
+}
+
+// QuantityFor sizes a percentage sell against the position held at execution time.
+// Any held position sells at least one contract so small positions still step down.
+func (a TriggerAction) QuantityFor(position uint) uint {
+	if a.SizePercent == nil || *a.SizePercent >= 100 {
+		return position
+	}
+	quantity := (position*uint(*a.SizePercent) + 50) / 100
+	if quantity == 0 && position > 0 {
+		return 1
+	}
+	return quantity
+}
+
+// ClosesPosition reports whether the action sells everything held in its contract
+func (a TriggerAction) ClosesPosition() bool {
+	return a.Side == Sell && a.Size == nil && (a.SizePercent == nil || *a.SizePercent >= 100)
+}
+
+// validateSizePercent checks a percentage sell is well formed
+func (a TriggerAction) validateSizePercent() error {
+	if a.SizePercent == nil {
+		return nil
+	}
+	if a.Side != Sell {
+		return fmt.Errorf("size percent only applies to sells")
+	}
+	if a.Size != nil {
+		return fmt.Errorf("action cannot have both a size and a size percent")
+	}
+	if *a.SizePercent <= 0 || *a.SizePercent > 100 {
+		return fmt.Errorf("size percent must be between 1 and 100, got %d", *a.SizePercent)
+	}
+	return nil
+}
+
+// NewFlattenEventAction creates an action that sells every position held in the event's
+// markets at market. The positions are looked up when the action is executed.
+func NewFlattenEventAction(eventTicker contract.Ticker) (*TriggerAction, error) {
+	if eventTicker == "" {
+		return nil, fmt.Errorf("flatten action must have an event")
+	}
+	return &TriggerAction{
+		Side:  Sell,
+		Event: eventTicker,
+	}, nil
+}
+
+// FlattensEvent reports whether the action sells everything held in an event
+func (a TriggerAction) FlattensEvent() bool {
+	return a.Event != ""
+}
+
+// ResolveEventActions replaces each event flatten action with a full sell of every open
+// position in the event, leaving other actions as they are
+func ResolveEventActions(
+	actions []TriggerAction,
+	events map[contract.Ticker]exchange_domain.EventPosition,
+) []TriggerAction {
+	resolved := make([]TriggerAction, 0, len(actions))
+	for _, action := range actions {
+		if !action.FlattensEvent() {
+			resolved = append(resolved, action)
+			continue
+		}
+
+		event, ok := events[action.Event]
+		if !ok {
+			// Kalshi only reports events with a position, so there is nothing to sell
+			continue
+		}
+		for _, position := range event.Positions {
+			if position.Quantity == 0 {
+				continue
+			}
+			resolved = append(resolved, TriggerAction{
+				Contract: position.ContractID,
+				Side:     Sell,
+			})
+		}
+	}
+	return resolved
+}
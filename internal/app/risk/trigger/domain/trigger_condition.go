@@ -1,8 +1,13 @@
 package trigger_domain
 
 import (
+	"errors"
 	"fmt"
 	"prediction-risk/internal/app/contract"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"slices"
+
+	"github.com/samber/lo"
 )
 
 // Direction represents which way a price needs to move to trigger
@@ -30,6 +35,7 @@ func (d Direction) IsValid() bool {
 type PriceRule struct {
 	Threshold contract.ContractPrice
 	Direction Direction
+	Source    PriceSource // market price compared against the threshold, the ask if unset
 }
 
 func newPriceRule(threshold contract.ContractPrice, direction Direction) (*PriceRule, error) {
@@ -44,6 +50,7 @@ func newPriceRule(threshold contract.ContractPrice, direction Direction) (*Price
 	return &PriceRule{
 		Threshold: threshold,
 		Direction: direction,
+		Source:    PriceSourceAsk,
 	}, nil
 }
 
@@ -67,13 +74,60 @@ func (p PriceRule) isSatisfied(price contract.ContractPrice) (bool, error) {
 }
 
 func (p PriceRule) String() string {
+	if p.Source != "" {
+		return fmt.Sprintf("price rule %s %s %d", p.Source, p.Direction, p.Threshold.Value())
+	}
 	return fmt.Sprintf("price rule %s %d", p.Direction, p.Threshold.Value())
 }
 
-// Condition represents what we're watching for, with only one rule active at a time
+// LogicalOperator combines the child conditions of a composite condition
+type LogicalOperator string
+
+const (
+	OperatorAnd LogicalOperator = "AND"
+	OperatorOr  LogicalOperator = "OR"
+	OperatorNot LogicalOperator = "NOT"
+)
+
+func (o LogicalOperator) String() string {
+	return string(o)
+}
+
+func (o LogicalOperator) IsValid() bool {
+	switch o {
+	case OperatorAnd, OperatorOr, OperatorNot:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewLogicalOperator(s string) (LogicalOperator, error) {
+	switch s {
+	case "AND":
+		return OperatorAnd, nil
+	case "OR":
+		return OperatorOr, nil
+	case "NOT":
+		return OperatorNot, nil
+	default:
+		return "", fmt.Errorf("invalid LogicalOperator: %s", s)
+	}
+}
+
+// TriggerCondition is a node in a boolean expression tree.
+// Leaf nodes hold a single rule; composite nodes combine their children
+// with an operator and have no rule of their own.
 type TriggerCondition struct {
-	Contract contract.ContractIdentifier
-	Price    *PriceRule // nil if not a price condition
+	Contract contract.ContractIdentifier // set for price rules
+	Price    *PriceRule                  // nil if not a price condition
+	Weather  *WeatherRule                // nil if not a weather condition
+	Forecast *ForecastRule               // nil if not a forecast condition
+	Time     *TimeRule                   // nil if not a time condition
+	Event    *EventRule                  // nil if not an event exposure condition
+
+	Operator LogicalOperator    // empty for leaf conditions
+	Children []TriggerCondition // operands of the operator
 }
 
 // NewPriceCondition creates a price-based condition with validation
@@ -93,9 +147,234 @@ func NewPriceCondition(
 	}, nil
 }
 
-// IsSatisfied checks if any condition is satisfied
-func (c TriggerCondition) IsSatisfied(price contract.ContractPrice) (bool, error) {
+// NewCompositeCondition combines conditions with a logical operator
+func NewCompositeCondition(operator LogicalOperator, children ...TriggerCondition) (*TriggerCondition, error) {
+	condition := &TriggerCondition{
+		Operator: operator,
+		Children: children,
+	}
+	if err := condition.Validate(); err != nil {
+		return nil, err
+	}
+	return condition, nil
+}
+
+// IsComposite reports whether the condition combines other conditions
+func (c TriggerCondition) IsComposite() bool {
+	return c.Operator != ""
+}
+
+// Validate checks the whole condition tree
+func (c TriggerCondition) Validate() error {
+	if !c.IsComposite() {
+		rules := lo.Count([]bool{c.Price != nil, c.Weather != nil, c.Forecast != nil, c.Time != nil, c.Event != nil}, true)
+		switch {
+		case rules > 1:
+			return errors.New("leaf condition must have exactly one rule")
+		case c.Event != nil:
+			return c.Event.validate()
+		case c.Weather != nil:
+			return c.Weather.validate()
+		case c.Forecast != nil:
+			return c.Forecast.validate()
+		case c.Time != nil:
+			return c.Time.validate()
+		case c.Price == nil:
+			return errors.New("leaf condition must have a rule")
+		}
+		if c.Contract.Ticker == "" {
+			return errors.New("leaf condition must have a contract")
+		}
+		if c.Contract.Side == nil {
+			return errors.New("leaf condition must have a contract side")
+		}
+		if c.Price.Source != "" && !c.Price.Source.IsValid() {
+			return fmt.Errorf("invalid price source: %s", c.Price.Source)
+		}
+		if _, err := newPriceRule(c.Price.Threshold, c.Price.Direction); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	switch c.Operator {
+	case OperatorAnd, OperatorOr:
+		if len(c.Children) < 2 {
+			return fmt.Errorf("%s condition must have at least two children, got %d", c.Operator, len(c.Children))
+		}
+	case OperatorNot:
+		if len(c.Children) != 1 {
+			return fmt.Errorf("NOT condition must have exactly one child, got %d", len(c.Children))
+		}
+	default:
+		return fmt.Errorf("invalid operator: %s", c.Operator)
+	}
+	if c.Price != nil || c.Weather != nil || c.Forecast != nil || c.Time != nil || c.Event != nil {
+		return errors.New("composite condition cannot have its own rule")
+	}
+
+	for _, child := range c.Children {
+		if err := child.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Observations and forecasts arrive separately, so no evaluator has both
+	if len(c.Stations()) > 0 && len(c.Gridpoints()) > 0 {
+		return errors.New("condition cannot combine weather observation and forecast rules")
+	}
+	return nil
+}
+
+// Contracts returns every contract referenced by the price rules in the condition tree
+func (c TriggerCondition) Contracts() []contract.ContractIdentifier {
+	var contracts []contract.ContractIdentifier
+	c.walk(func(leaf TriggerCondition) {
+		if leaf.Price == nil {
+			return
+		}
+		for _, existing := range contracts {
+			if existing == leaf.Contract {
+				return
+			}
+		}
+		contracts = append(contracts, leaf.Contract)
+	})
+	return contracts
+}
+
+// Tickers returns every market referenced by the condition tree
+func (c TriggerCondition) Tickers() []contract.Ticker {
+	var tickers []contract.Ticker
+	c.walk(func(leaf TriggerCondition) {
+		var ticker contract.Ticker
+		switch {
+		case leaf.Price != nil:
+			ticker = leaf.Contract.Ticker
+		case leaf.Time != nil && leaf.Time.Ticker != "":
+			ticker = leaf.Time.Ticker
+		default:
+			return
+		}
+		if !slices.Contains(tickers, ticker) {
+			tickers = append(tickers, ticker)
+		}
+	})
+	return tickers
+}
+
+// Stations returns every weather station referenced by the condition tree
+func (c TriggerCondition) Stations() []string {
+	var stations []string
+	c.walk(func(leaf TriggerCondition) {
+		if leaf.Weather != nil && !slices.Contains(stations, leaf.Weather.StationID) {
+			stations = append(stations, leaf.Weather.StationID)
+		}
+	})
+	return stations
+}
+
+// Gridpoints returns every forecast gridpoint referenced by the condition tree
+func (c TriggerCondition) Gridpoints() []weather_domain.Gridpoint {
+	var gridpoints []weather_domain.Gridpoint
+	c.walk(func(leaf TriggerCondition) {
+		if leaf.Forecast != nil && !slices.Contains(gridpoints, leaf.Forecast.Gridpoint) {
+			gridpoints = append(gridpoints, leaf.Forecast.Gridpoint)
+		}
+	})
+	return gridpoints
+}
+
+// Events returns every event referenced by the condition tree
+func (c TriggerCondition) Events() []contract.Ticker {
+	var events []contract.Ticker
+	c.walk(func(leaf TriggerCondition) {
+		if leaf.Event != nil && !slices.Contains(events, leaf.Event.EventTicker) {
+			events = append(events, leaf.Event.EventTicker)
+		}
+	})
+	return events
+}
+
+func (c TriggerCondition) walk(visit func(leaf TriggerCondition)) {
+	if !c.IsComposite() {
+		visit(c)
+		return
+	}
+	for _, child := range c.Children {
+		child.walk(visit)
+	}
+}
+
+// IsSatisfied evaluates the condition tree against the market snapshot
+func (c TriggerCondition) IsSatisfied(snapshot MarketSnapshot) (bool, error) {
+	switch c.Operator {
+	case OperatorAnd:
+		for _, child := range c.Children {
+			satisfied, err := child.IsSatisfied(snapshot)
+			if err != nil || !satisfied {
+				return false, err
+			}
+		}
+		return len(c.Children) > 0, nil
+	case OperatorOr:
+		for _, child := range c.Children {
+			satisfied, err := child.IsSatisfied(snapshot)
+			if err != nil {
+				return false, err
+			}
+			if satisfied {
+				return true, nil
+			}
+		}
+		return false, nil
+	case OperatorNot:
+		if len(c.Children) != 1 {
+			return false, fmt.Errorf("NOT condition must have exactly one child, got %d", len(c.Children))
+		}
+		satisfied, err := c.Children[0].IsSatisfied(snapshot)
+		if err != nil {
+			return false, err
+		}
+		return !satisfied, nil
+	case "":
+	default:
+		return false, fmt.Errorf("invalid operator: %s", c.Operator)
+	}
+
+	if c.Time != nil {
+		return c.Time.isSatisfied(snapshot)
+	}
+
+	if c.Event != nil {
+		exposure, err := snapshot.EventExposure(c.Event.EventTicker)
+		if err != nil {
+			return false, err
+		}
+		return c.Event.isSatisfied(exposure)
+	}
+
+	if c.Weather != nil {
+		weather, err := snapshot.StationWeather(c.Weather.StationID)
+		if err != nil {
+			return false, err
+		}
+		return c.Weather.isSatisfied(weather)
+	}
+
+	if c.Forecast != nil {
+		forecast, err := snapshot.GridpointForecast(c.Forecast.Gridpoint)
+		if err != nil {
+			return false, err
+		}
+		return c.Forecast.isSatisfied(forecast, snapshot.Time)
+	}
+
 	if c.Price != nil {
+		price, err := snapshot.Price(c.Contract, c.Price.Source)
+		if err != nil {
+			return false, err
+		}
 		return c.Price.isSatisfied(price)
 	}
 
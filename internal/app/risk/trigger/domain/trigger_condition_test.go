@@ -2,9 +2,12 @@ package trigger_domain
 
 import (
 	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDirection(t *testing.T) {
@@ -153,6 +156,20 @@ func TestPriceRule_IsSatisfied(t *testing.T) {
 	}
 }
 
+// snapshotWithAsks builds a snapshot where each ticker's YES ask is the given price
+func snapshotWithAsks(asks map[contract.Ticker]contract.ContractPrice) MarketSnapshot {
+	var markets []*exchange_domain.Market
+	for ticker, ask := range asks {
+		markets = append(markets, &exchange_domain.Market{
+			Ticker: ticker,
+			Pricing: exchange_domain.MarketPricing{
+				YesSide: exchange_domain.PricingSide{Ask: ask},
+			},
+		})
+	}
+	return NewMarketSnapshot(markets, time.Now())
+}
+
 func TestTriggerCondition_IsSatisfied(t *testing.T) {
 	validPrice := contract.ContractPrice(100)
 	aboveRule := &PriceRule{Threshold: validPrice, Direction: Above}
@@ -160,7 +177,7 @@ func TestTriggerCondition_IsSatisfied(t *testing.T) {
 	tests := []struct {
 		name      string
 		condition TriggerCondition
-		price     contract.ContractPrice
+		snapshot  MarketSnapshot
 		want      bool
 		wantError bool
 	}{
@@ -173,7 +190,7 @@ func TestTriggerCondition_IsSatisfied(t *testing.T) {
 				},
 				Price: aboveRule,
 			},
-			price:     validPrice,
+			snapshot:  snapshotWithAsks(map[contract.Ticker]contract.ContractPrice{"FOO": validPrice}),
 			want:      true, // We're not testing the rule itself, just that it's called
 			wantError: false,
 		},
@@ -186,15 +203,28 @@ func TestTriggerCondition_IsSatisfied(t *testing.T) {
 				},
 				Price: nil,
 			},
-			price:     validPrice,
+			snapshot:  snapshotWithAsks(map[contract.Ticker]contract.ContractPrice{"FOO": validPrice}),
 			want:      false,
 			wantError: false,
 		},
+		{
+			name: "Missing market data",
+			condition: TriggerCondition{
+				Contract: contract.ContractIdentifier{
+					Ticker: contract.Ticker("FOO"),
+					Side:   contract.SideYes,
+				},
+				Price: aboveRule,
+			},
+			snapshot:  snapshotWithAsks(nil),
+			want:      false,
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.condition.IsSatisfied(tt.price)
+			got, err := tt.condition.IsSatisfied(tt.snapshot)
 			if (err != nil) != tt.wantError {
 				t.Errorf("TriggerCondition.IsSatisfied() error = %v, wantError %v", err, tt.wantError)
 				return
@@ -205,3 +235,86 @@ func TestTriggerCondition_IsSatisfied(t *testing.T) {
 		})
 	}
 }
+
+func TestCompositeCondition_IsSatisfied(t *testing.T) {
+	fooAbove, err := NewPriceCondition(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, 60, Above)
+	require.NoError(t, err)
+	barBelow, err := NewPriceCondition(contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes}, 30, Below)
+	require.NoError(t, err)
+
+	and, err := NewCompositeCondition(OperatorAnd, *fooAbove, *barBelow)
+	require.NoError(t, err)
+	or, err := NewCompositeCondition(OperatorOr, *fooAbove, *barBelow)
+	require.NoError(t, err)
+	not, err := NewCompositeCondition(OperatorNot, *and)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		condition *TriggerCondition
+		foo, bar  contract.ContractPrice
+		want      bool
+	}{
+		{"AND both satisfied", and, 70, 20, true},
+		{"AND one satisfied", and, 70, 40, false},
+		{"OR one satisfied", or, 50, 20, true},
+		{"OR none satisfied", or, 50, 40, false},
+		{"NOT of unsatisfied AND", not, 70, 40, true},
+		{"NOT of satisfied AND", not, 70, 20, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := snapshotWithAsks(map[contract.Ticker]contract.ContractPrice{"FOO": tt.foo, "BAR": tt.bar})
+			got, err := tt.condition.IsSatisfied(snapshot)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewCompositeCondition(t *testing.T) {
+	leaf, err := NewPriceCondition(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, 60, Above)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		operator     LogicalOperator
+		children     []TriggerCondition
+		errorMessage string
+	}{
+		{name: "valid AND", operator: OperatorAnd, children: []TriggerCondition{*leaf, *leaf}},
+		{name: "valid NOT", operator: OperatorNot, children: []TriggerCondition{*leaf}},
+		{name: "AND with one child", operator: OperatorAnd, children: []TriggerCondition{*leaf}, errorMessage: "at least two children"},
+		{name: "NOT with two children", operator: OperatorNot, children: []TriggerCondition{*leaf, *leaf}, errorMessage: "exactly one child"},
+		{name: "invalid operator", operator: "XOR", children: []TriggerCondition{*leaf, *leaf}, errorMessage: "invalid operator"},
+		{name: "invalid leaf", operator: OperatorOr, children: []TriggerCondition{*leaf, {}}, errorMessage: "must have a rule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewCompositeCondition(tt.operator, tt.children...)
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, condition)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, condition.IsComposite())
+		})
+	}
+}
+
+func TestTriggerCondition_Tickers(t *testing.T) {
+	fooYes, _ := NewPriceCondition(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, 60, Above)
+	fooNo, _ := NewPriceCondition(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideNo}, 60, Above)
+	bar, _ := NewPriceCondition(contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes}, 30, Below)
+
+	inner, err := NewCompositeCondition(OperatorOr, *fooNo, *bar)
+	require.NoError(t, err)
+	condition, err := NewCompositeCondition(OperatorAnd, *fooYes, *inner)
+	require.NoError(t, err)
+
+	assert.Equal(t, []contract.Ticker{"FOO", "BAR"}, condition.Tickers())
+	assert.Len(t, condition.Contracts(), 3)
+}
@@ -0,0 +1,141 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"slices"
+	"time"
+)
+
+// ExpiryReason represents why a trigger was expired instead of firing
+type ExpiryReason string
+
+// MarketClosed means trading ended before the trigger fired.
+// MarketSettled means the market resolved, so there is nothing left to protect.
+const (
+	ExpiryReasonMarketClosed  ExpiryReason = "MARKET_CLOSED"
+	ExpiryReasonMarketSettled ExpiryReason = "MARKET_SETTLED"
+)
+
+func (r ExpiryReason) String() string {
+	return string(r)
+}
+
+func (r ExpiryReason) IsValid() bool {
+	switch r {
+	case ExpiryReasonMarketClosed, ExpiryReasonMarketSettled:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewExpiryReason(s string) (ExpiryReason, error) {
+	switch s {
+	case "MARKET_CLOSED":
+		return ExpiryReasonMarketClosed, nil
+	case "MARKET_SETTLED":
+		return ExpiryReasonMarketSettled, nil
+	default:
+		return "", fmt.Errorf("invalid ExpiryReason: %s", s)
+	}
+}
+
+// TriggerExpiry records why and when a trigger was expired
+type TriggerExpiry struct {
+	Reason           ExpiryReason
+	Ticker           contract.Ticker // market that closed or settled
+	SettlementResult *string         // market outcome, nil unless settled
+	ExpiredAt        time.Time
+}
+
+// NewMarketExpiry returns the expiry for a trigger on the market,
+// or nil if the market can still be traded
+func NewMarketExpiry(market *exchange_domain.Market, at time.Time) *TriggerExpiry {
+	if market == nil {
+		return nil
+	}
+
+	switch market.Status.State {
+	case exchange_domain.MarketStateClosed:
+		return &TriggerExpiry{
+			Reason:    ExpiryReasonMarketClosed,
+			Ticker:    market.Ticker,
+			ExpiredAt: at,
+		}
+	case exchange_domain.MarketStateSettled:
+		var result *string
+		if market.Status.Result != nil && *market.Status.Result != "" {
+			value := *market.Status.Result
+			result = &value
+		}
+		return &TriggerExpiry{
+			Reason:           ExpiryReasonMarketSettled,
+			Ticker:           market.Ticker,
+			SettlementResult: result,
+			ExpiredAt:        at,
+		}
+	default:
+		return nil
+	}
+}
+
+// Expiry returns the expiry for a trigger watching the snapshot's markets, or nil
+// if all of them can still be traded. Settled markets take precedence over closed ones.
+func (s MarketSnapshot) Expiry() *TriggerExpiry {
+	tickers := make([]contract.Ticker, 0, len(s.Markets))
+	for ticker := range s.Markets {
+		tickers = append(tickers, ticker)
+	}
+	slices.Sort(tickers)
+
+	var expiry *TriggerExpiry
+	for _, ticker := range tickers {
+		marketExpiry := NewMarketExpiry(s.Markets[ticker], s.Time)
+		if marketExpiry == nil {
+			continue
+		}
+		if marketExpiry.Reason == ExpiryReasonMarketSettled {
+			return marketExpiry
+		}
+		if expiry == nil {
+			expiry = marketExpiry
+		}
+	}
+	return expiry
+}
+
+// Expire moves an active trigger to EXPIRED, recording why
+func (t *Trigger) Expire(expiry TriggerExpiry) error {
+	if t.Status.IsTerminal() {
+		return fmt.Errorf("cannot expire trigger in terminal status %s", t.Status)
+	}
+	if !expiry.Reason.IsValid() {
+		return fmt.Errorf("invalid expiry reason: %s", expiry.Reason)
+	}
+	if expiry.Ticker == "" {
+		return errors.New("expiry must reference a market")
+	}
+
+	t.Status = StatusExpired
+	t.Expiry = &expiry
+	t.UpdatedAt = expiry.ExpiredAt
+
+	return nil
+}
+
+// Tickers returns every market the trigger watches or trades
+func (t *Trigger) Tickers() []contract.Ticker {
+	tickers := t.Condition.Tickers()
+	for _, action := range t.Actions {
+		if action.FlattensEvent() {
+			continue
+		}
+		if !slices.Contains(tickers, action.Contract.Ticker) {
+			tickers = append(tickers, action.Contract.Ticker)
+		}
+	}
+	return tickers
+}
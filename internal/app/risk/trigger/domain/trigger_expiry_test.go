@@ -0,0 +1,94 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func marketInState(ticker contract.Ticker, state exchange_domain.MarketState, result *string) *exchange_domain.Market {
+	return &exchange_domain.Market{
+		Ticker: ticker,
+		Status: exchange_domain.MarketStatus{State: state, Result: result},
+	}
+}
+
+func TestMarketSnapshot_Expiry(t *testing.T) {
+	now := time.Date(2025, 1, 27, 12, 0, 0, 0, time.UTC)
+	yes := "yes"
+
+	tests := []struct {
+		name     string
+		markets  []*exchange_domain.Market
+		expected *TriggerExpiry
+	}{
+		{
+			name: "open market",
+			markets: []*exchange_domain.Market{
+				marketInState("FOO", exchange_domain.MarketStateOpen, nil),
+			},
+		},
+		{
+			name: "closed market",
+			markets: []*exchange_domain.Market{
+				marketInState("FOO", exchange_domain.MarketStateClosed, nil),
+			},
+			expected: &TriggerExpiry{Reason: ExpiryReasonMarketClosed, Ticker: "FOO", ExpiredAt: now},
+		},
+		{
+			name: "settled market records result",
+			markets: []*exchange_domain.Market{
+				marketInState("FOO", exchange_domain.MarketStateSettled, &yes),
+			},
+			expected: &TriggerExpiry{
+				Reason:           ExpiryReasonMarketSettled,
+				Ticker:           "FOO",
+				SettlementResult: &yes,
+				ExpiredAt:        now,
+			},
+		},
+		{
+			name: "settled takes precedence over closed",
+			markets: []*exchange_domain.Market{
+				marketInState("AAA", exchange_domain.MarketStateClosed, nil),
+				marketInState("BBB", exchange_domain.MarketStateSettled, nil),
+			},
+			expected: &TriggerExpiry{Reason: ExpiryReasonMarketSettled, Ticker: "BBB", ExpiredAt: now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := NewMarketSnapshot(tt.markets, now)
+			assert.Equal(t, tt.expected, snapshot.Expiry())
+		})
+	}
+}
+
+func TestTrigger_Expire(t *testing.T) {
+	now := time.Now()
+	expiry := TriggerExpiry{Reason: ExpiryReasonMarketClosed, Ticker: "FOO", ExpiredAt: now}
+
+	t.Run("expires active trigger", func(t *testing.T) {
+		trigger, err := NewStopTrigger(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, 40, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, trigger.Expire(expiry))
+		assert.Equal(t, StatusExpired, trigger.Status)
+		assert.Equal(t, &expiry, trigger.Expiry)
+		assert.Equal(t, now, trigger.UpdatedAt)
+	})
+
+	t.Run("rejects terminal trigger", func(t *testing.T) {
+		trigger, err := NewStopTrigger(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, 40, nil)
+		require.NoError(t, err)
+		trigger.Status = StatusTriggered
+
+		assert.ErrorContains(t, trigger.Expire(expiry), "terminal status")
+		assert.Nil(t, trigger.Expiry)
+	})
+}
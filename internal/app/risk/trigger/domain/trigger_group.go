@@ -0,0 +1,228 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TriggerGroupID uuid.UUID
+
+func NewTriggerGroupID() TriggerGroupID {
+	return TriggerGroupID(uuid.New())
+}
+
+func (g TriggerGroupID) String() string {
+	return uuid.UUID(g).String()
+}
+
+// TriggerGroupType represents how the triggers in a group relate to each other
+type TriggerGroupType string
+
+// OCO means one-cancels-other: once a member triggers, the rest are cancelled.
+// LADDER members each fire independently but are created, updated and cancelled together.
+const (
+	GroupTypeOCO    TriggerGroupType = "OCO"
+	GroupTypeLadder TriggerGroupType = "LADDER"
+)
+
+func (t TriggerGroupType) String() string {
+	return string(t)
+}
+
+func (t TriggerGroupType) IsValid() bool {
+	switch t {
+	case GroupTypeOCO, GroupTypeLadder:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewTriggerGroupType(s string) (TriggerGroupType, error) {
+	switch s {
+	case "OCO":
+		return GroupTypeOCO, nil
+	case "LADDER":
+		return GroupTypeLadder, nil
+	default:
+		return "", fmt.Errorf("invalid TriggerGroupType: %s", s)
+	}
+}
+
+// ErrGroupHeld means another member of an OCO group is executing or has triggered. It may
+// already have orders out, so none of its siblings may execute.
+var ErrGroupHeld = errors.New("another member of the group is executing or has triggered")
+
+type TriggerGroup struct {
+	GroupID   TriggerGroupID
+	GroupType TriggerGroupType
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewTriggerGroup(groupType TriggerGroupType) *TriggerGroup {
+	currentTime := time.Now()
+	return &TriggerGroup{
+		GroupID:   NewTriggerGroupID(),
+		GroupType: groupType,
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}
+}
+
+// AddTrigger links the trigger to the group
+func (g *TriggerGroup) AddTrigger(trigger *Trigger) {
+	groupID := g.GroupID
+	trigger.GroupID = &groupID
+}
+
+// CheckExecution reports whether a member may start executing, given the current status of
+// every member of the group. The member must still be active, and in an OCO group no
+// sibling may be executing or triggered, since either may already have sold the position.
+func (g *TriggerGroup) CheckExecution(triggerID TriggerID, members map[TriggerID]TriggerStatus) error {
+	status, ok := members[triggerID]
+	if !ok {
+		return fmt.Errorf("trigger %s is not in group %s", triggerID, g.GroupID)
+	}
+	if status != StatusActive {
+		return fmt.Errorf("trigger is not active, status: %s", status)
+	}
+	if g.GroupType != GroupTypeOCO {
+		return nil
+	}
+
+	for id, status := range members {
+		if id != triggerID && (status == StatusExecuting || status == StatusTriggered) {
+			return fmt.Errorf("%w: %s is %s", ErrGroupHeld, id, status)
+		}
+	}
+	return nil
+}
+
+// Bracket enters a position and protects it with a stop below and a take profit above.
+// The exits are linked in an OCO group so only one of them can ever fire, and stay
+// pending until the entry's orders have filled.
+type Bracket struct {
+	Group      *TriggerGroup
+	Entry      *Trigger
+	Stop       *Trigger
+	TakeProfit *Trigger
+}
+
+// NewBracket wraps an entry trigger with a stop and a take profit on the same contract,
+// armed by the entry and sized to what it fills
+func NewBracket(
+	entry *Trigger,
+	stopPrice contract.ContractPrice,
+	stopLimitPrice *contract.ContractPrice,
+	takeProfitPrice contract.ContractPrice,
+	takeProfitLimitPrice *contract.ContractPrice,
+) (*Bracket, error) {
+	if entry == nil {
+		return nil, errors.New("bracket must have an entry trigger")
+	}
+	contract := entry.Condition.Contract
+
+	stop, err := NewStopTrigger(contract, stopPrice, stopLimitPrice)
+	if err != nil {
+		return nil, fmt.Errorf("create stop trigger: %w", err)
+	}
+
+	takeProfit, err := NewTakeProfitTrigger(contract, takeProfitPrice, takeProfitLimitPrice)
+	if err != nil {
+		return nil, fmt.Errorf("create take profit trigger: %w", err)
+	}
+
+	group := NewTriggerGroup(GroupTypeOCO)
+	for _, exit := range []*Trigger{stop, takeProfit} {
+		group.AddTrigger(exit)
+		entryID := entry.TriggerID
+		exit.ArmedBy = &entryID
+		exit.Status = StatusPending
+	}
+
+	bracket := &Bracket{
+		Group:      group,
+		Entry:      entry,
+		Stop:       stop,
+		TakeProfit: takeProfit,
+	}
+
+	if err := ValidateBracket(bracket); err != nil {
+		return nil, err
+	}
+
+	return bracket, nil
+}
+
+// Triggers returns the legs of the bracket, the entry first since the exits refer to it
+func (b *Bracket) Triggers() []*Trigger {
+	return []*Trigger{b.Entry, b.Stop, b.TakeProfit}
+}
+
+// Exits returns the members of the bracket's OCO group
+func (b *Bracket) Exits() []*Trigger {
+	return []*Trigger{b.Stop, b.TakeProfit}
+}
+
+func ValidateBracket(b *Bracket) error {
+	if b == nil || b.Group == nil {
+		return errors.New("bracket cannot be nil")
+	}
+	if b.Group.GroupType != GroupTypeOCO {
+		return fmt.Errorf("bracket group must be %s, got %s", GroupTypeOCO, b.Group.GroupType)
+	}
+	if b.Entry == nil {
+		return errors.New("bracket must have an entry trigger")
+	}
+	if b.Stop == nil || b.TakeProfit == nil {
+		return errors.New("bracket must have a stop and a take profit trigger")
+	}
+
+	if err := ValidateEntryTrigger(b.Entry); err != nil {
+		return fmt.Errorf("invalid entry trigger: %w", err)
+	}
+	if err := ValidateStopTrigger(b.Stop); err != nil {
+		return fmt.Errorf("invalid stop trigger: %w", err)
+	}
+	if err := ValidateTakeProfitTrigger(b.TakeProfit); err != nil {
+		return fmt.Errorf("invalid take profit trigger: %w", err)
+	}
+
+	if b.Entry.GroupID != nil {
+		return fmt.Errorf("entry trigger %s cannot be in a trigger group", b.Entry.TriggerID)
+	}
+	for _, trigger := range b.Exits() {
+		if trigger.GroupID == nil || *trigger.GroupID != b.Group.GroupID {
+			return fmt.Errorf("trigger %s is not in bracket group %s", trigger.TriggerID, b.Group.GroupID)
+		}
+		if trigger.ArmedBy == nil || *trigger.ArmedBy != b.Entry.TriggerID {
+			return fmt.Errorf("trigger %s is not armed by bracket entry %s", trigger.TriggerID, b.Entry.TriggerID)
+		}
+	}
+
+	if b.Entry.Condition.Contract != b.Stop.Condition.Contract {
+		return fmt.Errorf("entry contract (%v) must match stop contract (%v)",
+			b.Entry.Condition.Contract, b.Stop.Condition.Contract)
+	}
+	if b.Stop.Condition.Contract != b.TakeProfit.Condition.Contract {
+		return fmt.Errorf("stop contract (%v) must match take profit contract (%v)",
+			b.Stop.Condition.Contract, b.TakeProfit.Condition.Contract)
+	}
+	if b.Stop.Condition.Price.Threshold >= b.TakeProfit.Condition.Price.Threshold {
+		return fmt.Errorf("stop price (%v) must be below take profit price (%v)",
+			b.Stop.Condition.Price.Threshold, b.TakeProfit.Condition.Price.Threshold)
+	}
+	// Entering outside the exits would fire one of them as soon as it is armed
+	entryPrice := b.Entry.Condition.Price.Threshold
+	if entryPrice <= b.Stop.Condition.Price.Threshold || entryPrice >= b.TakeProfit.Condition.Price.Threshold {
+		return fmt.Errorf("entry price (%v) must be between stop price (%v) and take profit price (%v)",
+			entryPrice, b.Stop.Condition.Price.Threshold, b.TakeProfit.Condition.Price.Threshold)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerGroupType(t *testing.T) {
+	tests := []struct {
+		name      string
+		groupType TriggerGroupType
+		isValid   bool
+	}{
+		{"oco group", GroupTypeOCO, true},
+		{"invalid group", "INVALID", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.isValid, tt.groupType.IsValid())
+		})
+	}
+}
+
+func TestTriggerGroup_CheckExecution(t *testing.T) {
+	stop, takeProfit := NewTriggerID(), NewTriggerID()
+
+	tests := []struct {
+		name         string
+		groupType    TriggerGroupType
+		stopStatus   TriggerStatus
+		siblingState TriggerStatus
+		expectedErr  error
+		errorMessage string
+	}{
+		{name: "active with active sibling", groupType: GroupTypeOCO, stopStatus: StatusActive, siblingState: StatusActive},
+		{name: "sibling executing", groupType: GroupTypeOCO, stopStatus: StatusActive, siblingState: StatusExecuting, expectedErr: ErrGroupHeld},
+		{name: "sibling triggered", groupType: GroupTypeOCO, stopStatus: StatusActive, siblingState: StatusTriggered, expectedErr: ErrGroupHeld},
+		{name: "sibling cancelled", groupType: GroupTypeOCO, stopStatus: StatusActive, siblingState: StatusCancelled},
+		{name: "member no longer active", groupType: GroupTypeOCO, stopStatus: StatusCancelled, siblingState: StatusActive, errorMessage: "not active"},
+		{name: "ladder tiers fire independently", groupType: GroupTypeLadder, stopStatus: StatusActive, siblingState: StatusExecuting},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := NewTriggerGroup(tt.groupType)
+			err := group.CheckExecution(stop, map[TriggerID]TriggerStatus{stop: tt.stopStatus, takeProfit: tt.siblingState})
+
+			switch {
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, err, tt.expectedErr)
+			case tt.errorMessage != "":
+				assert.ErrorContains(t, err, tt.errorMessage)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("member outside the group", func(t *testing.T) {
+		group := NewTriggerGroup(GroupTypeOCO)
+		assert.ErrorContains(t, group.CheckExecution(NewTriggerID(), map[TriggerID]TriggerStatus{stop: StatusActive}), "not in group")
+	})
+}
+
+func newBracketEntry(t *testing.T, contractID contract.ContractIdentifier) *Trigger {
+	t.Helper()
+	entry, err := NewEntryTrigger(contractID, Below, 60, 10, nil, nil)
+	require.NoError(t, err)
+	return entry
+}
+
+func TestNewBracket(t *testing.T) {
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker("FOO"),
+		Side:   contract.SideYes,
+	}
+
+	tests := []struct {
+		name            string
+		stopPrice       contract.ContractPrice
+		takeProfitPrice contract.ContractPrice
+		errorMessage    string
+	}{
+		{
+			name:            "valid bracket",
+			stopPrice:       40,
+			takeProfitPrice: 80,
+		},
+		{
+			name:            "stop above take profit",
+			stopPrice:       80,
+			takeProfitPrice: 40,
+			errorMessage:    "must be below take profit price",
+		},
+		{
+			name:            "invalid stop price",
+			stopPrice:       -1,
+			takeProfitPrice: 80,
+			errorMessage:    "create stop trigger",
+		},
+		{
+			name:            "entry outside the exits",
+			stopPrice:       65,
+			takeProfitPrice: 80,
+			errorMessage:    "must be between stop price",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := newBracketEntry(t, contractID)
+			bracket, err := NewBracket(entry, tt.stopPrice, nil, tt.takeProfitPrice, nil)
+
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, bracket)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, GroupTypeOCO, bracket.Group.GroupType)
+			assert.Same(t, entry, bracket.Entry)
+			assert.Nil(t, entry.GroupID)
+			assert.Equal(t, StatusActive, entry.Status)
+			assert.Equal(t, TriggerTypeStop, bracket.Stop.TriggerType)
+			assert.Equal(t, TriggerTypeTakeProfit, bracket.TakeProfit.TriggerType)
+			for _, trigger := range bracket.Exits() {
+				require.NotNil(t, trigger.GroupID)
+				assert.Equal(t, bracket.Group.GroupID, *trigger.GroupID)
+				require.NotNil(t, trigger.ArmedBy)
+				assert.Equal(t, entry.TriggerID, *trigger.ArmedBy)
+				assert.Equal(t, StatusPending, trigger.Status)
+			}
+		})
+	}
+
+	t.Run("missing entry", func(t *testing.T) {
+		_, err := NewBracket(nil, 40, nil, 80, nil)
+		assert.ErrorContains(t, err, "must have an entry trigger")
+	})
+}
+
+func TestValidateBracket(t *testing.T) {
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker("FOO"),
+		Side:   contract.SideYes,
+	}
+
+	t.Run("trigger outside group", func(t *testing.T) {
+		bracket, err := NewBracket(newBracketEntry(t, contractID), 40, nil, 80, nil)
+		require.NoError(t, err)
+
+		bracket.TakeProfit.GroupID = nil
+		assert.ErrorContains(t, ValidateBracket(bracket), "is not in bracket group")
+	})
+
+	t.Run("exit not armed by the entry", func(t *testing.T) {
+		bracket, err := NewBracket(newBracketEntry(t, contractID), 40, nil, 80, nil)
+		require.NoError(t, err)
+
+		other := NewTriggerID()
+		bracket.Stop.ArmedBy = &other
+		assert.ErrorContains(t, ValidateBracket(bracket), "is not armed by bracket entry")
+	})
+
+	t.Run("mismatched contracts", func(t *testing.T) {
+		bracket, err := NewBracket(newBracketEntry(t, contractID), 40, nil, 80, nil)
+		require.NoError(t, err)
+
+		other := contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes}
+		bracket.TakeProfit.Condition.Contract = other
+		bracket.TakeProfit.Actions[0].Contract = other
+		assert.ErrorContains(t, ValidateBracket(bracket), "must match take profit contract")
+	})
+}
@@ -0,0 +1,129 @@
+package trigger_domain
+
+import (
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	"strconv"
+	"time"
+)
+
+// Actor identifies who made a change to a trigger
+type Actor string
+
+// ActorSystem is used for changes made by the monitors, e.g. a trigger firing
+const ActorSystem Actor = "system"
+
+func (a Actor) String() string {
+	return string(a)
+}
+
+// ChangeField names the part of a trigger a history entry records
+type ChangeField string
+
+const (
+	ChangeFieldStatus       ChangeField = "STATUS"
+	ChangeFieldTriggerPrice ChangeField = "TRIGGER_PRICE"
+	ChangeFieldLimitPrice   ChangeField = "LIMIT_PRICE"
+)
+
+func (f ChangeField) String() string {
+	return string(f)
+}
+
+func (f ChangeField) IsValid() bool {
+	switch f {
+	case ChangeFieldStatus, ChangeFieldTriggerPrice, ChangeFieldLimitPrice:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewChangeField(s string) (ChangeField, error) {
+	switch s {
+	case "STATUS":
+		return ChangeFieldStatus, nil
+	case "TRIGGER_PRICE":
+		return ChangeFieldTriggerPrice, nil
+	case "LIMIT_PRICE":
+		return ChangeFieldLimitPrice, nil
+	default:
+		return "", fmt.Errorf("invalid ChangeField: %s", s)
+	}
+}
+
+// TriggerChange is one entry in a trigger's append-only history
+type TriggerChange struct {
+	TriggerID TriggerID
+	Field     ChangeField
+	OldValue  *string // nil when the field was unset, e.g. no limit price
+	NewValue  *string
+	Actor     Actor
+	ChangedAt time.Time
+}
+
+// TriggerSnapshot captures the audited fields of a trigger before it is modified
+type TriggerSnapshot struct {
+	Status       TriggerStatus
+	TriggerPrice *contract.ContractPrice
+	LimitPrice   *contract.ContractPrice
+}
+
+// Snapshot records the trigger's audited fields so changes can be diffed later
+func (t *Trigger) Snapshot() TriggerSnapshot {
+	snapshot := TriggerSnapshot{Status: t.Status}
+	if t.Condition.Price != nil {
+		threshold := t.Condition.Price.Threshold
+		snapshot.TriggerPrice = &threshold
+	}
+	if len(t.Actions) > 0 && t.Actions[0].LimitPrice != nil {
+		limitPrice := *t.Actions[0].LimitPrice
+		snapshot.LimitPrice = &limitPrice
+	}
+	return snapshot
+}
+
+// ChangesSince returns a history entry for every audited field that differs from the snapshot
+func (t *Trigger) ChangesSince(before TriggerSnapshot, actor Actor, at time.Time) []TriggerChange {
+	after := t.Snapshot()
+	var changes []TriggerChange
+
+	change := func(field ChangeField, oldValue, newValue *string) {
+		changes = append(changes, TriggerChange{
+			TriggerID: t.TriggerID,
+			Field:     field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			Actor:     actor,
+			ChangedAt: at,
+		})
+	}
+
+	if before.Status != after.Status {
+		oldStatus, newStatus := before.Status.String(), after.Status.String()
+		change(ChangeFieldStatus, &oldStatus, &newStatus)
+	}
+	if !samePrice(before.TriggerPrice, after.TriggerPrice) {
+		change(ChangeFieldTriggerPrice, formatPrice(before.TriggerPrice), formatPrice(after.TriggerPrice))
+	}
+	if !samePrice(before.LimitPrice, after.LimitPrice) {
+		change(ChangeFieldLimitPrice, formatPrice(before.LimitPrice), formatPrice(after.LimitPrice))
+	}
+
+	return changes
+}
+
+func samePrice(a, b *contract.ContractPrice) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatPrice(price *contract.ContractPrice) *string {
+	if price == nil {
+		return nil
+	}
+	value := strconv.Itoa(price.Value())
+	return &value
+}
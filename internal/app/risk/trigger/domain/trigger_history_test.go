@@ -0,0 +1,70 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrigger_ChangesSince(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	now := time.Date(2025, 2, 11, 10, 0, 0, 0, time.UTC)
+	limitPrice := contract.ContractPrice(38)
+
+	tests := []struct {
+		name     string
+		modify   func(*Trigger)
+		expected []ChangeField
+	}{
+		{name: "no change", modify: func(t *Trigger) {}},
+		{
+			name:     "status",
+			modify:   func(t *Trigger) { t.Status = StatusCancelled },
+			expected: []ChangeField{ChangeFieldStatus},
+		},
+		{
+			name: "threshold and limit price",
+			modify: func(t *Trigger) {
+				t.Condition.Price.Threshold = 42
+				t.Actions[0].LimitPrice = &limitPrice
+			},
+			expected: []ChangeField{ChangeFieldTriggerPrice, ChangeFieldLimitPrice},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := NewStopTrigger(contractID, 40, nil)
+			require.NoError(t, err)
+
+			before := trigger.Snapshot()
+			tt.modify(trigger)
+			changes := trigger.ChangesSince(before, "trader", now)
+
+			require.Len(t, changes, len(tt.expected))
+			for i, change := range changes {
+				assert.Equal(t, tt.expected[i], change.Field)
+				assert.Equal(t, trigger.TriggerID, change.TriggerID)
+				assert.Equal(t, Actor("trader"), change.Actor)
+				assert.Equal(t, now, change.ChangedAt)
+			}
+		})
+	}
+
+	t.Run("records before and after values", func(t *testing.T) {
+		trigger, err := NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+
+		before := trigger.Snapshot()
+		trigger.Actions[0].LimitPrice = &limitPrice
+		changes := trigger.ChangesSince(before, ActorSystem, now)
+
+		require.Len(t, changes, 1)
+		assert.Nil(t, changes[0].OldValue)
+		require.NotNil(t, changes[0].NewValue)
+		assert.Equal(t, "38", *changes[0].NewValue)
+	})
+}
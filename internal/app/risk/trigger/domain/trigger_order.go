@@ -0,0 +1,46 @@
+package trigger_domain
+
+import exchange_domain "prediction-risk/internal/app/exchange/domain"
+
+// TriggerOrder is an exchange order sent when a trigger executed
+type TriggerOrder struct {
+	TriggerID   TriggerID
+	ActionIndex int // position of the action among those resolved at execution, as numbered in the order's reference
+	Attempt     int // 0 for the order sent at execution, counting up for each follow-up of its remainder
+	Order       exchange_domain.Order
+	FollowUp    *PendingFollowUp // nil unless the order's remainder is being replaced
+}
+
+// PendingFollowUp is a replacement for the unfilled remainder of an order. It is stored
+// before the order is cancelled and cleared with the replacement, so a follow-up
+// interrupted in between is picked up again rather than dropping the remainder.
+type PendingFollowUp struct {
+	Attempt   int        // attempt the replacement is sent as
+	Remaining uint       // contracts to place, final once the order is cancelled
+	Action    FillAction // how the replacement is priced
+}
+
+// StartFollowUp marks the order's remainder for replacement as the action says
+func (o *TriggerOrder) StartFollowUp(action FillAction) {
+	o.FollowUp = &PendingFollowUp{Attempt: o.Attempt + 1, Remaining: o.Order.Remaining(), Action: action}
+}
+
+// Settled reports whether the order is done: it cannot fill any further and no replacement
+// of its remainder is pending
+func (o TriggerOrder) Settled() bool {
+	return !o.Order.IsOpen() && o.FollowUp == nil
+}
+
+// FilledQuantity reports how many contracts a trigger's orders have filled, and whether
+// that is final: the trigger is done and all of its orders are settled
+func (t *Trigger) FilledQuantity() (uint, bool) {
+	var filled uint
+	settled := t.Status.IsTerminal()
+	for _, order := range t.Orders {
+		filled += order.Order.FilledQuantity
+		if !order.Settled() {
+			settled = false
+		}
+	}
+	return filled, settled
+}
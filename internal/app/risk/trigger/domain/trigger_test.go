@@ -2,10 +2,12 @@ package trigger_domain
 
 import (
 	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTriggerID(t *testing.T) {
@@ -20,6 +22,23 @@ func TestTriggerID(t *testing.T) {
 		id := TriggerID(originalUUID)
 		assert.Equal(t, originalUUID.String(), id.String())
 	})
+
+	t.Run("order references round trip to their index", func(t *testing.T) {
+		id := NewTriggerID()
+		for _, n := range []int{0, 1, 12} {
+			index, ok := id.OrderIndex(id.OrderReference(n))
+			assert.True(t, ok)
+			assert.Equal(t, n, index)
+		}
+	})
+
+	t.Run("order references from elsewhere are not matched", func(t *testing.T) {
+		id := NewTriggerID()
+		for _, reference := range []string{"", NewTriggerID().String(), id.String() + "-x", id.String() + "-0"} {
+			_, ok := id.OrderIndex(reference)
+			assert.False(t, ok, reference)
+		}
+	})
 }
 
 func TestTriggerStatus(t *testing.T) {
@@ -29,6 +48,7 @@ func TestTriggerStatus(t *testing.T) {
 		isValid  bool
 		terminal bool
 	}{
+		{"pending status", StatusPending, true, false},
 		{"active status", StatusActive, true, false},
 		{"triggered status", StatusTriggered, true, true},
 		{"cancelled status", StatusCancelled, true, true},
@@ -75,3 +95,72 @@ func TestNewTrigger(t *testing.T) {
 		assert.False(t, trigger.UpdatedAt.IsZero())
 	})
 }
+
+func TestTrigger_Arm(t *testing.T) {
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker("FOO"),
+		Side:   contract.SideYes,
+	}
+
+	t.Run("sizes sells to the filled quantity", func(t *testing.T) {
+		stop, err := NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+		stop.Status = StatusPending
+
+		require.NoError(t, stop.Arm(7))
+
+		assert.Equal(t, StatusActive, stop.Status)
+		require.NotNil(t, stop.Actions[0].Size)
+		assert.Equal(t, uint(7), *stop.Actions[0].Size)
+		assert.Nil(t, stop.Actions[0].SizePercent)
+	})
+
+	t.Run("not pending", func(t *testing.T) {
+		stop, err := NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+
+		assert.ErrorContains(t, stop.Arm(7), "not pending")
+	})
+
+	t.Run("nothing filled", func(t *testing.T) {
+		stop, err := NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+		stop.Status = StatusPending
+
+		assert.ErrorContains(t, stop.Arm(0), "nothing filled")
+	})
+}
+
+func TestTrigger_FilledQuantity(t *testing.T) {
+	order := func(status string, filled uint) TriggerOrder {
+		return TriggerOrder{Order: exchange_domain.Order{Quantity: 10, FilledQuantity: filled, Status: status}}
+	}
+	pendingFollowUp := order(exchange_domain.OrderStatusCanceled, 4)
+	pendingFollowUp.StartFollowUp(FillActionMarket)
+
+	tests := []struct {
+		name    string
+		status  TriggerStatus
+		orders  []TriggerOrder
+		filled  uint
+		settled bool
+	}{
+		{"still active", StatusActive, nil, 0, false},
+		{"cancelled before executing", StatusCancelled, nil, 0, true},
+		{"order still resting", StatusTriggered, []TriggerOrder{order(exchange_domain.OrderStatusPartiallyFilled, 4)}, 4, false},
+		{"remainder replaced and filled", StatusTriggered, []TriggerOrder{
+			order(exchange_domain.OrderStatusCanceled, 4),
+			order(exchange_domain.OrderStatusExecuted, 6),
+		}, 10, true},
+		{"remainder cancelled but not yet replaced", StatusTriggered, []TriggerOrder{pendingFollowUp}, 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger := &Trigger{Status: tt.status, Orders: tt.orders}
+			filled, settled := trigger.FilledQuantity()
+			assert.Equal(t, tt.filled, filled)
+			assert.Equal(t, tt.settled, settled)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package trigger_domain
+
+import (
+	"errors"
+	"fmt"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+)
+
+// WeatherMetric represents which observed quantity a weather rule looks at
+type WeatherMetric string
+
+// Temperature is the most recent observation.
+// DailyMaxTemperature is the highest temperature observed so far in the station's day.
+const (
+	MetricTemperature         WeatherMetric = "TEMPERATURE"
+	MetricDailyMaxTemperature WeatherMetric = "DAILY_MAX_TEMPERATURE"
+)
+
+func (m WeatherMetric) String() string {
+	return string(m)
+}
+
+func (m WeatherMetric) IsValid() bool {
+	switch m {
+	case MetricTemperature, MetricDailyMaxTemperature:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewWeatherMetric(s string) (WeatherMetric, error) {
+	switch s {
+	case "TEMPERATURE":
+		return MetricTemperature, nil
+	case "DAILY_MAX_TEMPERATURE":
+		return MetricDailyMaxTemperature, nil
+	default:
+		return "", fmt.Errorf("invalid WeatherMetric: %s", s)
+	}
+}
+
+// WeatherRule represents a rule on observations from an NWS station,
+// e.g. "KNYC temperature at or above 80°F"
+type WeatherRule struct {
+	StationID string
+	Metric    WeatherMetric
+	Threshold weather_domain.Temperature
+	Direction Direction
+}
+
+func newWeatherRule(
+	stationID string,
+	metric WeatherMetric,
+	threshold weather_domain.Temperature,
+	direction Direction,
+) (*WeatherRule, error) {
+	rule := &WeatherRule{
+		StationID: stationID,
+		Metric:    metric,
+		Threshold: threshold,
+		Direction: direction,
+	}
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (w WeatherRule) validate() error {
+	if w.StationID == "" {
+		return errors.New("weather rule must have a station")
+	}
+	if !w.Metric.IsValid() {
+		return fmt.Errorf("invalid weather metric: %s", w.Metric)
+	}
+	if !w.Threshold.TemperatureUnit.IsValid() {
+		return fmt.Errorf("invalid temperature unit: %s", w.Threshold.TemperatureUnit)
+	}
+	if !w.Direction.IsValid() {
+		return fmt.Errorf("invalid direction: %s", w.Direction)
+	}
+	return nil
+}
+
+// Checks whether the station's weather satisfies the rule
+func (w WeatherRule) isSatisfied(weather StationWeather) (bool, error) {
+	var observed weather_domain.Temperature
+	switch w.Metric {
+	case MetricTemperature:
+		observed = weather.Temperature
+	case MetricDailyMaxTemperature:
+		observed = weather.DailyMaxTemperature
+	default:
+		return false, fmt.Errorf("invalid weather metric: %s", w.Metric)
+	}
+
+	// Compare in the rule's unit so thresholds like 80°F are exact
+	value := observed.In(w.Threshold.TemperatureUnit).Value
+
+	switch w.Direction {
+	case Above:
+		return value >= w.Threshold.Value, nil
+	case Below:
+		return value <= w.Threshold.Value, nil
+	default:
+		return false, fmt.Errorf("invalid direction: %s", w.Direction)
+	}
+}
+
+func (w WeatherRule) String() string {
+	return fmt.Sprintf("weather rule %s %s %s %.1f %s",
+		w.StationID, w.Metric, w.Direction, w.Threshold.Value, w.Threshold.TemperatureUnit)
+}
+
+// NewWeatherCondition creates a weather-based condition with validation
+func NewWeatherCondition(
+	stationID string,
+	metric WeatherMetric,
+	threshold weather_domain.Temperature,
+	direction Direction,
+) (*TriggerCondition, error) {
+	weatherRule, err := newWeatherRule(stationID, metric, threshold, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TriggerCondition{
+		Weather: weatherRule,
+	}, nil
+}
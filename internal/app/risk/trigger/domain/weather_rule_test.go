@@ -0,0 +1,108 @@
+package trigger_domain
+
+import (
+	"prediction-risk/internal/app/contract"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fahrenheit(value float64) weather_domain.Temperature {
+	return weather_domain.Temperature{Value: value, TemperatureUnit: weather_domain.Fahrenheit}
+}
+
+func celsius(value float64) weather_domain.Temperature {
+	return weather_domain.Temperature{Value: value, TemperatureUnit: weather_domain.Celsius}
+}
+
+func TestNewWeatherCondition(t *testing.T) {
+	tests := []struct {
+		name         string
+		stationID    string
+		metric       WeatherMetric
+		threshold    weather_domain.Temperature
+		direction    Direction
+		errorMessage string
+	}{
+		{name: "valid", stationID: "KNYC", metric: MetricTemperature, threshold: fahrenheit(80), direction: Above},
+		{name: "missing station", metric: MetricTemperature, threshold: fahrenheit(80), direction: Above, errorMessage: "must have a station"},
+		{name: "invalid metric", stationID: "KNYC", metric: "HUMIDITY", threshold: fahrenheit(80), direction: Above, errorMessage: "invalid weather metric"},
+		{name: "invalid unit", stationID: "KNYC", metric: MetricTemperature, threshold: weather_domain.Temperature{Value: 80}, direction: Above, errorMessage: "invalid temperature unit"},
+		{name: "invalid direction", stationID: "KNYC", metric: MetricTemperature, threshold: fahrenheit(80), direction: "UP", errorMessage: "invalid direction"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewWeatherCondition(tt.stationID, tt.metric, tt.threshold, tt.direction)
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, condition)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, condition.Validate())
+			assert.Equal(t, []string{tt.stationID}, condition.Stations())
+			assert.Empty(t, condition.Tickers())
+		})
+	}
+}
+
+func TestWeatherCondition_IsSatisfied(t *testing.T) {
+	snapshot := MarketSnapshot{
+		Weather: map[string]StationWeather{
+			// 26.7°C is just above 80°F
+			"KNYC": {Temperature: celsius(25), DailyMaxTemperature: celsius(26.7), ObservedAt: time.Now()},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		stationID string
+		metric    WeatherMetric
+		threshold weather_domain.Temperature
+		direction Direction
+		want      bool
+		wantError bool
+	}{
+		{name: "latest below threshold", stationID: "KNYC", metric: MetricTemperature, threshold: fahrenheit(80), direction: Above, want: false},
+		{name: "daily max above threshold", stationID: "KNYC", metric: MetricDailyMaxTemperature, threshold: fahrenheit(80), direction: Above, want: true},
+		{name: "latest below celsius threshold", stationID: "KNYC", metric: MetricTemperature, threshold: celsius(30), direction: Below, want: true},
+		{name: "unknown station", stationID: "KLGA", metric: MetricTemperature, threshold: fahrenheit(80), direction: Above, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewWeatherCondition(tt.stationID, tt.metric, tt.threshold, tt.direction)
+			require.NoError(t, err)
+
+			got, err := condition.IsSatisfied(snapshot)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWeatherCondition_CombinedWithPrice(t *testing.T) {
+	hot, err := NewWeatherCondition("KNYC", MetricDailyMaxTemperature, fahrenheit(80), Above)
+	require.NoError(t, err)
+	cheap, err := NewPriceCondition(contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}, 40, Below)
+	require.NoError(t, err)
+	condition, err := NewCompositeCondition(OperatorAnd, *hot, *cheap)
+	require.NoError(t, err)
+
+	snapshot := snapshotWithAsks(map[contract.Ticker]contract.ContractPrice{"FOO": 35})
+	snapshot.Weather["KNYC"] = StationWeather{Temperature: fahrenheit(79), DailyMaxTemperature: fahrenheit(81)}
+
+	satisfied, err := condition.IsSatisfied(snapshot)
+	require.NoError(t, err)
+	assert.True(t, satisfied)
+	assert.Equal(t, []contract.Ticker{"FOO"}, condition.Tickers())
+	assert.Equal(t, []string{"KNYC"}, condition.Stations())
+}
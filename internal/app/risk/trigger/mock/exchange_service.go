@@ -0,0 +1,80 @@
+package trigger_mock
+
+import (
+	"context"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	exchange_service "prediction-risk/internal/app/exchange/service"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExchangeService is a mock implementation of the ExchangeService interface
+type MockExchangeService struct {
+	mock.Mock
+}
+
+func (m *MockExchangeService) GetMarket(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Market, error) {
+	args := m.Called(ctx, ticker)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*exchange_domain.Market), args.Error(1)
+}
+
+func (m *MockExchangeService) GetMarkets(ctx context.Context, tickers []contract.Ticker) ([]*exchange_domain.Market, error) {
+	args := m.Called(ctx, tickers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*exchange_domain.Market), args.Error(1)
+}
+
+func (m *MockExchangeService) GetOrderbook(ctx context.Context, ticker contract.Ticker) (*exchange_domain.Orderbook, error) {
+	args := m.Called(ctx, ticker)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*exchange_domain.Orderbook), args.Error(1)
+}
+
+func (m *MockExchangeService) GetPositions(ctx context.Context) ([]*exchange_domain.Position, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*exchange_domain.Position), args.Error(1)
+}
+
+func (m *MockExchangeService) GetEventPositions(ctx context.Context) ([]*exchange_domain.EventPosition, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*exchange_domain.EventPosition), args.Error(1)
+}
+
+func (m *MockExchangeService) CreateOrder(ctx context.Context, orderParams exchange_service.OrderParams) (*exchange_domain.Order, error) {
+	args := m.Called(ctx, orderParams)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*exchange_domain.Order), args.Error(1)
+}
+
+func (m *MockExchangeService) GetOrders(ctx context.Context, since time.Time) ([]*exchange_domain.Order, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*exchange_domain.Order), args.Error(1)
+}
+
+func (m *MockExchangeService) CancelOrder(ctx context.Context, exchangeOrderID string) (*exchange_domain.Order, error) {
+	args := m.Called(ctx, exchangeOrderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*exchange_domain.Order), args.Error(1)
+}
@@ -0,0 +1,42 @@
+package trigger_mock
+
+import (
+	"context"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProtectionPolicyRepository is a mock implementation of ProtectionPolicyRepository
+type MockProtectionPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockProtectionPolicyRepository) Persist(ctx context.Context, policy *trigger_domain.ProtectionPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockProtectionPolicyRepository) Get(
+	ctx context.Context,
+	id trigger_domain.ProtectionPolicyID,
+) (*trigger_domain.ProtectionPolicy, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*trigger_domain.ProtectionPolicy), args.Error(1)
+}
+
+func (m *MockProtectionPolicyRepository) GetAll(ctx context.Context) ([]*trigger_domain.ProtectionPolicy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trigger_domain.ProtectionPolicy), args.Error(1)
+}
+
+func (m *MockProtectionPolicyRepository) Delete(ctx context.Context, id trigger_domain.ProtectionPolicyID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
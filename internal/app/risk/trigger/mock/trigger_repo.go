@@ -3,6 +3,7 @@ package trigger_mock
 import (
 	"context"
 	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -32,3 +33,108 @@ func (m *MockTriggerRepository) GetAll(ctx context.Context) ([]*trigger_domain.T
 	}
 	return args.Get(0).([]*trigger_domain.Trigger), args.Error(1)
 }
+
+func (m *MockTriggerRepository) UpdateTrailingStop(ctx context.Context, trigger *trigger_domain.Trigger) error {
+	args := m.Called(ctx, trigger)
+	return args.Error(0)
+}
+
+func (m *MockTriggerRepository) UpdateCostBasis(ctx context.Context, trigger *trigger_domain.Trigger) error {
+	args := m.Called(ctx, trigger)
+	return args.Error(0)
+}
+
+func (m *MockTriggerRepository) Expire(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	from trigger_domain.TriggerStatus,
+) error {
+	args := m.Called(ctx, trigger, from)
+	return args.Error(0)
+}
+
+func (m *MockTriggerRepository) UpdateConfirmation(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	at time.Time,
+) error {
+	args := m.Called(ctx, trigger, at)
+	return args.Error(0)
+}
+
+func (m *MockTriggerRepository) PersistGroup(
+	ctx context.Context,
+	group *trigger_domain.TriggerGroup,
+	triggers []*trigger_domain.Trigger,
+) error {
+	args := m.Called(ctx, group, triggers)
+	return args.Error(0)
+}
+
+func (m *MockTriggerRepository) GetGroup(ctx context.Context, id trigger_domain.TriggerGroupID) (*trigger_domain.TriggerGroup, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*trigger_domain.TriggerGroup), args.Error(1)
+}
+
+func (m *MockTriggerRepository) GetByGroup(ctx context.Context, id trigger_domain.TriggerGroupID) ([]*trigger_domain.Trigger, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trigger_domain.Trigger), args.Error(1)
+}
+
+func (m *MockTriggerRepository) GetPending(ctx context.Context) ([]*trigger_domain.Trigger, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*trigger_domain.Trigger), args.Error(1)
+}
+
+func (m *MockTriggerRepository) AppendHistory(ctx context.Context, changes []trigger_domain.TriggerChange) error {
+	args := m.Called(ctx, changes)
+	return args.Error(0)
+}
+
+func (m *MockTriggerRepository) GetHistory(ctx context.Context, id trigger_domain.TriggerID) ([]trigger_domain.TriggerChange, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]trigger_domain.TriggerChange), args.Error(1)
+}
+
+func (m *MockTriggerRepository) PersistOrders(ctx context.Context, orders []trigger_domain.TriggerOrder) error {
+	args := m.Called(ctx, orders)
+	return args.Error(0)
+}
+
+func (m *MockTriggerRepository) GetOrders(ctx context.Context) ([]trigger_domain.TriggerOrder, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]trigger_domain.TriggerOrder), args.Error(1)
+}
+
+func (m *MockTriggerRepository) GetOpenOrders(ctx context.Context) ([]trigger_domain.TriggerOrder, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]trigger_domain.TriggerOrder), args.Error(1)
+}
+
+// ClaimExecution may return a func to decide the claim when it is made, as the real
+// repository does under its lock
+func (m *MockTriggerRepository) ClaimExecution(ctx context.Context, group *trigger_domain.TriggerGroup, trigger *trigger_domain.Trigger) error {
+	args := m.Called(ctx, group, trigger)
+	if claim, ok := args.Get(0).(func(*trigger_domain.Trigger) error); ok {
+		return claim(trigger)
+	}
+	return args.Error(0)
+}
@@ -0,0 +1,277 @@
+package trigger_repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"time"
+)
+
+// ConditionDB is the JSONB representation of a condition tree
+type ConditionDB struct {
+	Operator string          `json:"operator,omitempty"`
+	Children []ConditionDB   `json:"children,omitempty"`
+	Contract *ContractDB     `json:"contract,omitempty"`
+	Price    *PriceRuleDB    `json:"price,omitempty"`
+	Weather  *WeatherRuleDB  `json:"weather,omitempty"`
+	Forecast *ForecastRuleDB `json:"forecast,omitempty"`
+	Time     *TimeRuleDB     `json:"time,omitempty"`
+	Event    *EventRuleDB    `json:"event,omitempty"`
+}
+
+type ContractDB struct {
+	Ticker string `json:"ticker"`
+	Side   string `json:"side"`
+}
+
+type PriceRuleDB struct {
+	Threshold int    `json:"threshold"`
+	Direction string `json:"direction"`
+	Source    string `json:"source,omitempty"` // empty for conditions stored before sources existed
+}
+
+type WeatherRuleDB struct {
+	StationID string  `json:"station_id"`
+	Metric    string  `json:"metric"`
+	Threshold float64 `json:"threshold"`
+	Unit      string  `json:"unit"`
+	Direction string  `json:"direction"`
+}
+
+type ForecastRuleDB struct {
+	OfficeID  string  `json:"office_id"`
+	GridX     int     `json:"grid_x"`
+	GridY     int     `json:"grid_y"`
+	Metric    string  `json:"metric"`
+	DayOffset int     `json:"day_offset"`
+	Threshold float64 `json:"threshold"`
+	Unit      string  `json:"unit"`
+	Direction string  `json:"direction"`
+}
+
+type EventRuleDB struct {
+	EventTicker string `json:"event_ticker"`
+	Metric      string `json:"metric"`
+	Threshold   int    `json:"threshold"`
+	Direction   string `json:"direction"`
+}
+
+type TimeRuleDB struct {
+	Reference     string     `json:"reference"`
+	At            *time.Time `json:"at,omitempty"`
+	Ticker        string     `json:"ticker,omitempty"`
+	OffsetSeconds int64      `json:"offset_seconds,omitempty"`
+}
+
+func toConditionDB(condition trigger_domain.TriggerCondition) ConditionDB {
+	if condition.IsComposite() {
+		children := make([]ConditionDB, 0, len(condition.Children))
+		for _, child := range condition.Children {
+			children = append(children, toConditionDB(child))
+		}
+		return ConditionDB{
+			Operator: condition.Operator.String(),
+			Children: children,
+		}
+	}
+
+	if condition.Event != nil {
+		return ConditionDB{
+			Event: &EventRuleDB{
+				EventTicker: string(condition.Event.EventTicker),
+				Metric:      condition.Event.Metric.String(),
+				Threshold:   condition.Event.Threshold,
+				Direction:   condition.Event.Direction.String(),
+			},
+		}
+	}
+
+	if condition.Time != nil {
+		return ConditionDB{
+			Time: &TimeRuleDB{
+				Reference:     condition.Time.Reference.String(),
+				At:            condition.Time.At,
+				Ticker:        string(condition.Time.Ticker),
+				OffsetSeconds: int64(condition.Time.Offset / time.Second),
+			},
+		}
+	}
+
+	if condition.Weather != nil {
+		return ConditionDB{
+			Weather: &WeatherRuleDB{
+				StationID: condition.Weather.StationID,
+				Metric:    condition.Weather.Metric.String(),
+				Threshold: condition.Weather.Threshold.Value,
+				Unit:      string(condition.Weather.Threshold.TemperatureUnit),
+				Direction: condition.Weather.Direction.String(),
+			},
+		}
+	}
+
+	if condition.Forecast != nil {
+		return ConditionDB{
+			Forecast: &ForecastRuleDB{
+				OfficeID:  condition.Forecast.Gridpoint.OfficeID,
+				GridX:     condition.Forecast.Gridpoint.X,
+				GridY:     condition.Forecast.Gridpoint.Y,
+				Metric:    condition.Forecast.Metric.String(),
+				DayOffset: condition.Forecast.DayOffset,
+				Threshold: condition.Forecast.Threshold.Value,
+				Unit:      string(condition.Forecast.Threshold.TemperatureUnit),
+				Direction: condition.Forecast.Direction.String(),
+			},
+		}
+	}
+
+	conditionDB := ConditionDB{
+		Contract: &ContractDB{
+			Ticker: string(condition.Contract.Ticker),
+			Side:   condition.Contract.Side.String(),
+		},
+	}
+	if condition.Price != nil {
+		conditionDB.Price = &PriceRuleDB{
+			Threshold: condition.Price.Threshold.Value(),
+			Direction: condition.Price.Direction.String(),
+			Source:    condition.Price.Source.String(),
+		}
+	}
+	return conditionDB
+}
+
+func (c ConditionDB) toDomain() (*trigger_domain.TriggerCondition, error) {
+	if c.Operator != "" {
+		operator, err := trigger_domain.NewLogicalOperator(c.Operator)
+		if err != nil {
+			return nil, err
+		}
+
+		children := make([]trigger_domain.TriggerCondition, 0, len(c.Children))
+		for _, childDB := range c.Children {
+			child, err := childDB.toDomain()
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, *child)
+		}
+		return trigger_domain.NewCompositeCondition(operator, children...)
+	}
+
+	if c.Event != nil {
+		metric, err := trigger_domain.NewEventMetric(c.Event.Metric)
+		if err != nil {
+			return nil, err
+		}
+		return trigger_domain.NewEventCondition(
+			contract.Ticker(c.Event.EventTicker),
+			metric,
+			c.Event.Threshold,
+			trigger_domain.Direction(c.Event.Direction),
+		)
+	}
+
+	if c.Time != nil {
+		reference, err := trigger_domain.NewTimeReference(c.Time.Reference)
+		if err != nil {
+			return nil, err
+		}
+		if reference == trigger_domain.TimeReferenceAbsolute {
+			if c.Time.At == nil {
+				return nil, fmt.Errorf("absolute time rule missing time")
+			}
+			return trigger_domain.NewAbsoluteTimeCondition(*c.Time.At)
+		}
+		return trigger_domain.NewMarketTimeCondition(
+			contract.Ticker(c.Time.Ticker),
+			reference,
+			time.Duration(c.Time.OffsetSeconds)*time.Second,
+		)
+	}
+
+	if c.Weather != nil {
+		metric, err := trigger_domain.NewWeatherMetric(c.Weather.Metric)
+		if err != nil {
+			return nil, err
+		}
+		unit, err := weather_domain.NewTemperatureUnit(c.Weather.Unit)
+		if err != nil {
+			return nil, err
+		}
+		return trigger_domain.NewWeatherCondition(
+			c.Weather.StationID,
+			metric,
+			weather_domain.Temperature{Value: c.Weather.Threshold, TemperatureUnit: unit},
+			trigger_domain.Direction(c.Weather.Direction),
+		)
+	}
+
+	if c.Forecast != nil {
+		gridpoint, err := weather_domain.NewGridpoint(c.Forecast.OfficeID, c.Forecast.GridX, c.Forecast.GridY)
+		if err != nil {
+			return nil, err
+		}
+		metric, err := trigger_domain.NewForecastMetric(c.Forecast.Metric)
+		if err != nil {
+			return nil, err
+		}
+		unit, err := weather_domain.NewTemperatureUnit(c.Forecast.Unit)
+		if err != nil {
+			return nil, err
+		}
+		return trigger_domain.NewForecastCondition(
+			gridpoint,
+			metric,
+			c.Forecast.DayOffset,
+			weather_domain.Temperature{Value: c.Forecast.Threshold, TemperatureUnit: unit},
+			trigger_domain.Direction(c.Forecast.Direction),
+		)
+	}
+
+	if c.Contract == nil {
+		return nil, fmt.Errorf("leaf condition missing contract")
+	}
+	side, err := contract.NewSide(c.Contract.Side)
+	if err != nil {
+		return nil, fmt.Errorf("create side: %w", err)
+	}
+	contractID := contract.ContractIdentifier{
+		Ticker: contract.Ticker(c.Contract.Ticker),
+		Side:   side,
+	}
+
+	if c.Price == nil {
+		return nil, fmt.Errorf("leaf condition missing rule")
+	}
+	if c.Price.Source == "" {
+		return trigger_domain.NewPriceCondition(
+			contractID,
+			contract.ContractPrice(c.Price.Threshold),
+			trigger_domain.Direction(c.Price.Direction),
+		)
+	}
+	source, err := trigger_domain.NewPriceSource(c.Price.Source)
+	if err != nil {
+		return nil, err
+	}
+	return trigger_domain.NewPriceConditionWithSource(
+		contractID,
+		contract.ContractPrice(c.Price.Threshold),
+		trigger_domain.Direction(c.Price.Direction),
+		source,
+	)
+}
+
+func marshalCondition(condition trigger_domain.TriggerCondition) ([]byte, error) {
+	return json.Marshal(toConditionDB(condition))
+}
+
+func unmarshalCondition(data []byte) (*trigger_domain.TriggerCondition, error) {
+	var conditionDB ConditionDB
+	if err := json.Unmarshal(data, &conditionDB); err != nil {
+		return nil, fmt.Errorf("unmarshal condition: %w", err)
+	}
+	return conditionDB.toDomain()
+}
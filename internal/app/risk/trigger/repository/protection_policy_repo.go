@@ -0,0 +1,165 @@
+package trigger_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrProtectionPolicyNotFound = errors.New("protection policy not found")
+
+type ProtectionPolicyDB struct {
+	PolicyID     uuid.UUID      `db:"policy_id"`
+	Ticker       string         `db:"ticker"`
+	ContractSide sql.NullString `db:"contract_side"`
+	OffsetType   string         `db:"offset_type"`
+	OffsetAmount int            `db:"offset_amount"`
+	Enabled      bool           `db:"enabled"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"`
+}
+
+func (p ProtectionPolicyDB) toDomain() (*trigger_domain.ProtectionPolicy, error) {
+	var side contract.Side
+	if p.ContractSide.Valid {
+		s, err := contract.NewSide(p.ContractSide.String)
+		if err != nil {
+			return nil, fmt.Errorf("create side: %w", err)
+		}
+		side = s
+	}
+
+	offsetType, err := trigger_domain.NewTrailType(p.OffsetType)
+	if err != nil {
+		return nil, fmt.Errorf("create offset type: %w", err)
+	}
+
+	return &trigger_domain.ProtectionPolicy{
+		PolicyID:   trigger_domain.ProtectionPolicyID(p.PolicyID),
+		Ticker:     contract.Ticker(p.Ticker),
+		Side:       side,
+		OffsetType: offsetType,
+		Offset:     p.OffsetAmount,
+		Enabled:    p.Enabled,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+	}, nil
+}
+
+type ProtectionPolicyRepository struct {
+	db *sqlx.DB
+}
+
+func NewProtectionPolicyRepository(db *sqlx.DB) *ProtectionPolicyRepository {
+	return &ProtectionPolicyRepository{db: db}
+}
+
+// Persist stores a new policy or updates an existing one
+func (r *ProtectionPolicyRepository) Persist(ctx context.Context, policy *trigger_domain.ProtectionPolicy) error {
+	var side *string
+	if policy.Side != nil {
+		s := policy.Side.String()
+		side = &s
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO event_contract.protection_policy (
+			policy_id, ticker, contract_side, offset_type, offset_amount, enabled,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (policy_id) DO UPDATE SET
+			ticker = EXCLUDED.ticker,
+			contract_side = EXCLUDED.contract_side,
+			offset_type = EXCLUDED.offset_type,
+			offset_amount = EXCLUDED.offset_amount,
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at
+	`,
+		uuid.UUID(policy.PolicyID),
+		policy.Ticker,
+		side,
+		policy.OffsetType,
+		policy.Offset,
+		policy.Enabled,
+		policy.CreatedAt,
+		policy.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert protection policy: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a policy by its ID
+func (r *ProtectionPolicyRepository) Get(
+	ctx context.Context,
+	id trigger_domain.ProtectionPolicyID,
+) (*trigger_domain.ProtectionPolicy, error) {
+	var policyDB ProtectionPolicyDB
+	err := r.db.GetContext(ctx, &policyDB, `
+		SELECT policy_id, ticker, contract_side, offset_type, offset_amount, enabled, created_at, updated_at
+		FROM event_contract.protection_policy
+		WHERE policy_id = $1
+	`, uuid.UUID(id))
+	if err == sql.ErrNoRows {
+		return nil, ErrProtectionPolicyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query protection policy: %w", err)
+	}
+
+	return policyDB.toDomain()
+}
+
+// GetAll retrieves every policy, oldest first
+func (r *ProtectionPolicyRepository) GetAll(ctx context.Context) ([]*trigger_domain.ProtectionPolicy, error) {
+	var policyDBs []ProtectionPolicyDB
+	err := r.db.SelectContext(ctx, &policyDBs, `
+		SELECT policy_id, ticker, contract_side, offset_type, offset_amount, enabled, created_at, updated_at
+		FROM event_contract.protection_policy
+		ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query protection policies: %w", err)
+	}
+
+	policies := make([]*trigger_domain.ProtectionPolicy, 0, len(policyDBs))
+	for _, policyDB := range policyDBs {
+		policy, err := policyDB.toDomain()
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", policyDB.PolicyID, err)
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// Delete removes a policy. Stops it placed stay, but are no longer linked to it.
+func (r *ProtectionPolicyRepository) Delete(ctx context.Context, id trigger_domain.ProtectionPolicyID) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM event_contract.protection_policy
+		WHERE policy_id = $1
+	`, uuid.UUID(id))
+	if err != nil {
+		return fmt.Errorf("delete protection policy: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete protection policy: %w", err)
+	}
+	if rows == 0 {
+		return ErrProtectionPolicyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package trigger_repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	"prediction-risk/internal/app/testutil"
+)
+
+func TestProtectionPolicyRepository(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Close(t)
+
+	repo := NewProtectionPolicyRepository(testDB.DB())
+	triggerRepo := NewTriggerRepository(testDB.DB())
+
+	t.Run("persists and updates policy", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		policy, err := trigger_domain.NewProtectionPolicy("KXHIGHNY", contract.SideYes, trigger_domain.TrailTypeCents, 12)
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), policy))
+
+		policy.Offset = 8
+		policy.Enabled = false
+		require.NoError(t, repo.Persist(context.Background(), policy))
+
+		saved, err := repo.Get(context.Background(), policy.PolicyID)
+		require.NoError(t, err)
+		assert.Equal(t, policy.Ticker, saved.Ticker)
+		assert.Equal(t, contract.SideYes, saved.Side)
+		assert.Equal(t, trigger_domain.TrailTypeCents, saved.OffsetType)
+		assert.Equal(t, 8, saved.Offset)
+		assert.False(t, saved.Enabled)
+	})
+
+	t.Run("policy without side", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		policy, err := trigger_domain.NewProtectionPolicy("KXHIGHNY-25FEB13", nil, trigger_domain.TrailTypePercent, 20)
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), policy))
+
+		policies, err := repo.GetAll(context.Background())
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+		assert.Nil(t, policies[0].Side)
+	})
+
+	t.Run("links stops and unlinks them on delete", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		policy, err := trigger_domain.NewProtectionPolicy("KXHIGHNY", contract.SideYes, trigger_domain.TrailTypeCents, 12)
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), policy))
+
+		stop, err := policy.NewStop(exchange_domain.Position{
+			ContractID: contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB13-T40", Side: contract.SideYes},
+			Quantity:   10,
+			CostBasis:  500,
+		})
+		require.NoError(t, err)
+		require.NoError(t, triggerRepo.Persist(context.Background(), stop))
+
+		saved, err := triggerRepo.Get(context.Background(), stop.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, &policy.PolicyID, saved.PolicyID)
+
+		require.NoError(t, repo.Delete(context.Background(), policy.PolicyID))
+
+		_, err = repo.Get(context.Background(), policy.PolicyID)
+		assert.ErrorIs(t, err, ErrProtectionPolicyNotFound)
+		assert.ErrorIs(t, repo.Delete(context.Background(), policy.PolicyID), ErrProtectionPolicyNotFound)
+
+		saved, err = triggerRepo.Get(context.Background(), stop.TriggerID)
+		require.NoError(t, err)
+		assert.Nil(t, saved.PolicyID)
+	})
+}
@@ -6,48 +6,192 @@ import (
 	"errors"
 	"fmt"
 	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
 	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // postgres driver
+	"github.com/lib/pq"
 )
 
 var (
 	ErrTriggerNotFound      = errors.New("trigger not found")
 	ErrTriggerAlreadyExists = errors.New("active trigger already exists for this contract and type")
+	ErrTriggerGroupNotFound = errors.New("trigger group not found")
 )
 
 // Database models for scanning
 type TriggerDB struct {
-	TriggerID uuid.UUID `db:"trigger_id"`
-	Type      string    `db:"trigger_type"`
-	Status    string    `db:"status"`
+	TriggerID uuid.UUID     `db:"trigger_id"`
+	Type      string        `db:"trigger_type"`
+	Status    string        `db:"status"`
+	Condition []byte        `db:"condition"`
+	GroupID   uuid.NullUUID `db:"group_id"`
+	PolicyID  uuid.NullUUID `db:"policy_id"`
+	ArmedBy   uuid.NullUUID `db:"armed_by"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+
+	ReconcileAttempts int `db:"reconcile_attempts"`
+}
+
+type TriggerGroupDB struct {
+	GroupID   uuid.UUID `db:"group_id"`
+	GroupType string    `db:"group_type"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
 
-type PriceConditionDB struct {
+type TriggerActionDB struct {
+	ActionID       uuid.UUID      `db:"action_id"`
+	TriggerID      uuid.UUID      `db:"trigger_id"`
+	ContractTicker sql.NullString `db:"contract_ticker"`
+	ContractSide   sql.NullString `db:"contract_side"`
+	EventTicker    sql.NullString `db:"event_ticker"`
+	OrderSide      string         `db:"order_side"`
+	OrderSize      sql.NullInt64  `db:"order_size"`
+	LimitPrice     sql.NullInt64  `db:"limit_price"`
+	MaxCost        sql.NullInt64  `db:"max_cost"`
+	SizePercent    sql.NullInt64  `db:"size_percent"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+type TrailingStopDB struct {
+	TriggerID     uuid.UUID `db:"trigger_id"`
+	TrailType     string    `db:"trail_type"`
+	TrailAmount   int       `db:"trail_amount"`
+	HighWaterMark int       `db:"high_water_mark"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+type CostBasisStopDB struct {
+	TriggerID    uuid.UUID `db:"trigger_id"`
+	OffsetType   string    `db:"offset_type"`
+	OffsetAmount int       `db:"offset_amount"`
+	AverageEntry int       `db:"average_entry"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+type TriggerExpiryDB struct {
+	TriggerID        uuid.UUID      `db:"trigger_id"`
+	Reason           string         `db:"reason"`
+	MarketTicker     string         `db:"market_ticker"`
+	SettlementResult sql.NullString `db:"settlement_result"`
+	ExpiredAt        time.Time      `db:"expired_at"`
+}
+
+type TriggerConfirmationDB struct {
+	TriggerID               uuid.UUID    `db:"trigger_id"`
+	RequiredEvaluations     int          `db:"required_evaluations"`
+	RequiredDurationSeconds int          `db:"required_duration_seconds"`
+	SatisfiedCount          int          `db:"satisfied_count"`
+	SatisfiedSince          sql.NullTime `db:"satisfied_since"`
+}
+
+type TriggerGuardDB struct {
+	TriggerID    uuid.UUID     `db:"trigger_id"`
+	MaxSpread    sql.NullInt64 `db:"max_spread"`
+	MinLiquidity sql.NullInt64 `db:"min_liquidity"`
+	MinVolume24H sql.NullInt64 `db:"min_volume_24h"`
+	MinDepth     sql.NullInt64 `db:"min_depth"`
+	OnFailure    string        `db:"on_failure"`
+}
+
+type TriggerFillPolicyDB struct {
 	TriggerID      uuid.UUID `db:"trigger_id"`
-	ContractTicker string    `db:"contract_ticker"`
-	ContractSide   string    `db:"contract_side"`
-	ThresholdPrice int       `db:"threshold_price"`
-	Direction      string    `db:"direction"`
-	CreatedAt      time.Time `db:"created_at"`
-	UpdatedAt      time.Time `db:"updated_at"`
+	TimeoutSeconds int       `db:"timeout_seconds"`
+	Action         string    `db:"action"`
 }
 
-type TriggerActionDB struct {
-	ActionID       uuid.UUID     `db:"action_id"`
-	TriggerID      uuid.UUID     `db:"trigger_id"`
-	ContractTicker string        `db:"contract_ticker"`
-	ContractSide   string        `db:"contract_side"`
-	OrderSide      string        `db:"order_side"`
-	OrderSize      sql.NullInt64 `db:"order_size"`
-	LimitPrice     sql.NullInt64 `db:"limit_price"`
-	CreatedAt      time.Time     `db:"created_at"`
-	UpdatedAt      time.Time     `db:"updated_at"`
+type TriggerGuardFailureDB struct {
+	TriggerID uuid.UUID `db:"trigger_id"`
+	Reason    string    `db:"reason"`
+	Action    string    `db:"action"`
+	FailedAt  time.Time `db:"failed_at"`
+}
+
+type TriggerHistoryDB struct {
+	TriggerID uuid.UUID      `db:"trigger_id"`
+	Field     string         `db:"field"`
+	OldValue  sql.NullString `db:"old_value"`
+	NewValue  sql.NullString `db:"new_value"`
+	Actor     string         `db:"actor"`
+	ChangedAt time.Time      `db:"changed_at"`
+}
+
+type TriggerOrderDB struct {
+	OrderID         uuid.UUID `db:"order_id"`
+	TriggerID       uuid.UUID `db:"trigger_id"`
+	ActionIndex     int       `db:"action_index"`
+	Attempt         int       `db:"attempt"`
+	Exchange        string    `db:"exchange"`
+	ExchangeOrderID string    `db:"exchange_order_id"`
+	Reference       string    `db:"reference"`
+	ContractTicker  string    `db:"contract_ticker"`
+	ContractSide    string    `db:"contract_side"`
+	OrderSide       string    `db:"order_side"`
+	OrderType       string    `db:"order_type"`
+	Status          string    `db:"status"`
+	Quantity        int       `db:"quantity"`
+	LimitPrice      *int      `db:"limit_price"`
+	FilledQuantity  int       `db:"filled_quantity"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+
+	FollowUpAttempt   sql.NullInt64  `db:"follow_up_attempt"`
+	FollowUpRemaining sql.NullInt64  `db:"follow_up_remaining"`
+	FollowUpAction    sql.NullString `db:"follow_up_action"`
+}
+
+func (o TriggerOrderDB) toDomain() (trigger_domain.TriggerOrder, error) {
+	side, err := contract.NewSide(o.ContractSide)
+	if err != nil {
+		return trigger_domain.TriggerOrder{}, fmt.Errorf("create side: %w", err)
+	}
+
+	order := exchange_domain.Order{
+		OrderID:         exchange_domain.OrderID(o.OrderID),
+		ExchangeOrderID: o.ExchangeOrderID,
+		Exchange:        exchange_domain.Exchange(o.Exchange),
+		Reference:       o.Reference,
+		Ticker:          o.ContractTicker,
+		Side:            side,
+		Action:          exchange_domain.OrderAction(o.OrderSide),
+		OrderType:       exchange_domain.MarketOrderType(o.OrderType),
+		Quantity:        uint(o.Quantity),
+		FilledQuantity:  uint(o.FilledQuantity),
+		Status:          o.Status,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
+	}
+	if o.LimitPrice != nil {
+		limitPrice := contract.ContractPrice(*o.LimitPrice)
+		order.LimitPrice = &limitPrice
+	}
+
+	triggerOrder := trigger_domain.TriggerOrder{
+		TriggerID:   trigger_domain.TriggerID(o.TriggerID),
+		ActionIndex: o.ActionIndex,
+		Attempt:     o.Attempt,
+		Order:       order,
+	}
+	if o.FollowUpAttempt.Valid {
+		action, err := trigger_domain.NewFillAction(o.FollowUpAction.String)
+		if err != nil {
+			return trigger_domain.TriggerOrder{}, fmt.Errorf("create follow-up action: %w", err)
+		}
+		triggerOrder.FollowUp = &trigger_domain.PendingFollowUp{
+			Attempt:   int(o.FollowUpAttempt.Int64),
+			Remaining: uint(o.FollowUpRemaining.Int64),
+			Action:    action,
+		}
+	}
+
+	return triggerOrder, nil
 }
 
 type TriggerRepository struct {
@@ -66,19 +210,341 @@ func (r *TriggerRepository) Persist(ctx context.Context, trigger *trigger_domain
 	}
 	defer tx.Rollback()
 
-	// Upsert main trigger record
+	if err := r.persistTrigger(ctx, tx, trigger); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateTrailingStop stores a trailing stop's high water mark and the stop level that
+// follows it. Nothing else is written, and only while the trigger is still active with
+// the trail it was read with, so returns trigger_domain.ErrTriggerChanged if it was
+// cancelled or edited in the meantime.
+func (r *TriggerRepository) UpdateTrailingStop(ctx context.Context, trigger *trigger_domain.Trigger) error {
+	if trigger.TrailingStop == nil || trigger.Condition.Price == nil {
+		return fmt.Errorf("trigger %s is not a trailing stop", trigger.TriggerID)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Updating the trigger row first locks it against a concurrent cancel or edit
+	result, err := tx.ExecContext(ctx, `
+		UPDATE event_contract.trigger
+		SET condition = jsonb_set(condition, '{price,threshold}', to_jsonb($2::int)),
+			updated_at = $3
+		WHERE trigger_id = $1 AND status = $4
+	`,
+		uuid.UUID(trigger.TriggerID),
+		int(trigger.Condition.Price.Threshold),
+		trigger.UpdatedAt,
+		trigger_domain.StatusActive,
+	)
+	if err != nil {
+		return fmt.Errorf("update trigger stop level: %w", err)
+	}
+	if err := requireApplied(result); err != nil {
+		return err
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		UPDATE event_contract.trailing_stop
+		SET high_water_mark = $2, updated_at = $3
+		WHERE trigger_id = $1 AND trail_type = $4 AND trail_amount = $5
+	`,
+		uuid.UUID(trigger.TriggerID),
+		int(trigger.TrailingStop.HighWaterMark),
+		trigger.UpdatedAt,
+		trigger.TrailingStop.TrailType,
+		trigger.TrailingStop.TrailAmount,
+	)
+	if err != nil {
+		return fmt.Errorf("update trailing stop: %w", err)
+	}
+	if err := requireApplied(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateCostBasis stores a cost basis stop's average entry and the stop level that follows
+// it. Nothing else is written, and only while the trigger is still active with the offset
+// it was read with, so returns trigger_domain.ErrTriggerChanged if it was cancelled or
+// edited in the meantime.
+func (r *TriggerRepository) UpdateCostBasis(ctx context.Context, trigger *trigger_domain.Trigger) error {
+	if trigger.CostBasis == nil || trigger.Condition.Price == nil {
+		return fmt.Errorf("trigger %s is not relative to cost basis", trigger.TriggerID)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Updating the trigger row first locks it against a concurrent cancel or edit
+	result, err := tx.ExecContext(ctx, `
+		UPDATE event_contract.trigger
+		SET condition = jsonb_set(condition, '{price,threshold}', to_jsonb($2::int)),
+			updated_at = $3
+		WHERE trigger_id = $1 AND status = $4
+	`,
+		uuid.UUID(trigger.TriggerID),
+		int(trigger.Condition.Price.Threshold),
+		trigger.UpdatedAt,
+		trigger_domain.StatusActive,
+	)
+	if err != nil {
+		return fmt.Errorf("update trigger stop level: %w", err)
+	}
+	if err := requireApplied(result); err != nil {
+		return err
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		UPDATE event_contract.cost_basis_stop
+		SET average_entry = $2, updated_at = $3
+		WHERE trigger_id = $1 AND offset_type = $4 AND offset_amount = $5
+	`,
+		uuid.UUID(trigger.TriggerID),
+		int(trigger.CostBasis.AverageEntry),
+		trigger.UpdatedAt,
+		trigger.CostBasis.OffsetType,
+		trigger.CostBasis.Offset,
+	)
+	if err != nil {
+		return fmt.Errorf("update cost basis stop: %w", err)
+	}
+	if err := requireApplied(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Expire stores a trigger's move to EXPIRED and why. Only the status and expiry are
+// written, and only while the trigger is still in the status the move was checked from, so
+// returns trigger_domain.ErrTriggerChanged if it was cancelled or moved on in the meantime.
+func (r *TriggerRepository) Expire(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	from trigger_domain.TriggerStatus,
+) error {
+	if trigger.Status != trigger_domain.StatusExpired || trigger.Expiry == nil {
+		return fmt.Errorf("trigger %s has not expired", trigger.TriggerID)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE event_contract.trigger
+		SET status = $2, updated_at = $3
+		WHERE trigger_id = $1 AND status = $4
+	`,
+		uuid.UUID(trigger.TriggerID),
+		trigger.Status,
+		trigger.UpdatedAt,
+		from,
+	)
+	if err != nil {
+		return fmt.Errorf("update trigger status: %w", err)
+	}
+	if err := requireApplied(result); err != nil {
+		return err
+	}
+
+	if err := r.persistExpiry(ctx, tx, trigger); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateConfirmation stores the progress of a trigger's confirmation policy as of at.
+// Nothing else is written, and only while the trigger is still active with the policy it
+// was read with, so returns trigger_domain.ErrTriggerChanged if it was cancelled or its
+// policy replaced in the meantime.
+func (r *TriggerRepository) UpdateConfirmation(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	at time.Time,
+) error {
+	if trigger.Confirmation == nil {
+		return fmt.Errorf("trigger %s has no confirmation policy", trigger.TriggerID)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE event_contract.trigger_confirmation
+		SET satisfied_count = $2, satisfied_since = $3, updated_at = $4
+		WHERE trigger_id = $1
+			AND required_evaluations = $5
+			AND required_duration_seconds = $6
+			AND EXISTS (
+				SELECT 1 FROM event_contract.trigger
+				WHERE trigger_id = $1 AND status = $7
+			)
+	`,
+		uuid.UUID(trigger.TriggerID),
+		trigger.Confirmation.SatisfiedCount,
+		trigger.Confirmation.SatisfiedSince,
+		at,
+		trigger.Confirmation.RequiredEvaluations,
+		int(trigger.Confirmation.RequiredDuration/time.Second),
+		trigger_domain.StatusActive,
+	)
+	if err != nil {
+		return fmt.Errorf("update trigger confirmation: %w", err)
+	}
+
+	return requireApplied(result)
+}
+
+// PersistGroup stores a trigger group and its member triggers in a single transaction
+func (r *TriggerRepository) PersistGroup(
+	ctx context.Context,
+	group *trigger_domain.TriggerGroup,
+	triggers []*trigger_domain.Trigger,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	groupQuery := `
+			INSERT INTO event_contract.trigger_group (
+				group_id, group_type, created_at, updated_at
+			) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (group_id) DO UPDATE SET
+				updated_at = EXCLUDED.updated_at
+		`
+	_, err = tx.ExecContext(ctx, groupQuery,
+		uuid.UUID(group.GroupID),
+		group.GroupType,
+		group.CreatedAt,
+		group.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert trigger group: %w", err)
+	}
+
+	for _, trigger := range triggers {
+		if err := r.persistTrigger(ctx, tx, trigger); err != nil {
+			return fmt.Errorf("persist trigger %s: %w", trigger.TriggerID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ClaimExecution stores a grouped trigger that has started executing. Every member of the
+// group is locked while the group checks the claim, so two members of an OCO group cannot
+// both start executing.
+func (r *TriggerRepository) ClaimExecution(
+	ctx context.Context,
+	group *trigger_domain.TriggerGroup,
+	trigger *trigger_domain.Trigger,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock members in a fixed order so concurrent claims cannot deadlock
+	rows, err := tx.QueryContext(ctx, `
+		SELECT trigger_id, status
+		FROM event_contract.trigger
+		WHERE group_id = $1
+		ORDER BY trigger_id
+		FOR UPDATE
+	`, uuid.UUID(group.GroupID))
+	if err != nil {
+		return fmt.Errorf("lock group triggers: %w", err)
+	}
+	members := make(map[trigger_domain.TriggerID]trigger_domain.TriggerStatus)
+	for rows.Next() {
+		var id uuid.UUID
+		var status string
+		if err := rows.Scan(&id, &status); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan group trigger: %w", err)
+		}
+		members[trigger_domain.TriggerID(id)] = trigger_domain.TriggerStatus(status)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("close group triggers: %w", err)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read group triggers: %w", err)
+	}
+
+	if err := group.CheckExecution(trigger.TriggerID, members); err != nil {
+		return err
+	}
+
+	if err := r.persistTrigger(ctx, tx, trigger); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *TriggerRepository) persistTrigger(ctx context.Context, tx *sql.Tx, trigger *trigger_domain.Trigger) error {
+	var groupID *uuid.UUID
+	if trigger.GroupID != nil {
+		id := uuid.UUID(*trigger.GroupID)
+		groupID = &id
+	}
+	var policyID *uuid.UUID
+	if trigger.PolicyID != nil {
+		id := uuid.UUID(*trigger.PolicyID)
+		policyID = &id
+	}
+	var armedBy *uuid.UUID
+	if trigger.ArmedBy != nil {
+		id := uuid.UUID(*trigger.ArmedBy)
+		armedBy = &id
+	}
+
+	condition, err := marshalCondition(trigger.Condition)
+	if err != nil {
+		return fmt.Errorf("marshal condition: %w", err)
+	}
+
+	// Upsert main trigger record, with the condition tree stored as JSONB
 	triggerQuery := `
 			INSERT INTO event_contract.trigger (
-				trigger_id, trigger_type, status, created_at, updated_at
-			) VALUES ($1, $2, $3, $4, $5)
+				trigger_id, trigger_type, status, condition, group_id, policy_id, armed_by,
+				reconcile_attempts, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 			ON CONFLICT (trigger_id) DO UPDATE SET
 				status = EXCLUDED.status,
+				condition = EXCLUDED.condition,
+				group_id = EXCLUDED.group_id,
+				policy_id = EXCLUDED.policy_id,
+				armed_by = EXCLUDED.armed_by,
+				reconcile_attempts = EXCLUDED.reconcile_attempts,
 				updated_at = EXCLUDED.updated_at
 		`
 	_, err = tx.ExecContext(ctx, triggerQuery,
 		uuid.UUID(trigger.TriggerID),
-		trigger_domain.TriggerTypeStop,
+		trigger.TriggerType,
 		trigger.Status,
+		condition,
+		groupID,
+		policyID,
+		armedBy,
+		trigger.ReconcileAttempts,
 		trigger.CreatedAt,
 		trigger.UpdatedAt,
 	)
@@ -86,30 +552,190 @@ func (r *TriggerRepository) Persist(ctx context.Context, trigger *trigger_domain
 		return fmt.Errorf("upsert trigger: %w", err)
 	}
 
-	// Upsert price condition
-	conditionQuery := `
-			INSERT INTO event_contract.price_trigger_condition (
-				trigger_id, contract_ticker, contract_side,
-				threshold_price, direction, created_at, updated_at
+	// Upsert trailing stop state
+	if trigger.TrailingStop != nil {
+		trailingQuery := `
+			INSERT INTO event_contract.trailing_stop (
+				trigger_id, trail_type, trail_amount, high_water_mark,
+				created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (trigger_id) DO UPDATE SET
+				trail_type = EXCLUDED.trail_type,
+				trail_amount = EXCLUDED.trail_amount,
+				high_water_mark = EXCLUDED.high_water_mark,
+				updated_at = EXCLUDED.updated_at
+		`
+		_, err = tx.ExecContext(ctx, trailingQuery,
+			uuid.UUID(trigger.TriggerID),
+			trigger.TrailingStop.TrailType,
+			trigger.TrailingStop.TrailAmount,
+			int(trigger.TrailingStop.HighWaterMark),
+			trigger.CreatedAt,
+			trigger.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert trailing stop: %w", err)
+		}
+	}
+
+	// Upsert cost basis stop state
+	if trigger.CostBasis != nil {
+		costBasisQuery := `
+			INSERT INTO event_contract.cost_basis_stop (
+				trigger_id, offset_type, offset_amount, average_entry,
+				created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (trigger_id) DO UPDATE SET
+				offset_type = EXCLUDED.offset_type,
+				offset_amount = EXCLUDED.offset_amount,
+				average_entry = EXCLUDED.average_entry,
+				updated_at = EXCLUDED.updated_at
+		`
+		_, err = tx.ExecContext(ctx, costBasisQuery,
+			uuid.UUID(trigger.TriggerID),
+			trigger.CostBasis.OffsetType,
+			trigger.CostBasis.Offset,
+			int(trigger.CostBasis.AverageEntry),
+			trigger.CreatedAt,
+			trigger.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert cost basis stop: %w", err)
+		}
+	}
+
+	// Record why the trigger expired
+	if trigger.Expiry != nil {
+		if err := r.persistExpiry(ctx, tx, trigger); err != nil {
+			return err
+		}
+	}
+
+	// Upsert confirmation policy and progress, removing it if the policy was cleared
+	if trigger.Confirmation != nil {
+		confirmationQuery := `
+			INSERT INTO event_contract.trigger_confirmation (
+				trigger_id, required_evaluations, required_duration_seconds,
+				satisfied_count, satisfied_since, created_at, updated_at
 			) VALUES ($1, $2, $3, $4, $5, $6, $7)
 			ON CONFLICT (trigger_id) DO UPDATE SET
-				contract_ticker = EXCLUDED.contract_ticker,
-				contract_side = EXCLUDED.contract_side,
-				threshold_price = EXCLUDED.threshold_price,
-				direction = EXCLUDED.direction,
+				required_evaluations = EXCLUDED.required_evaluations,
+				required_duration_seconds = EXCLUDED.required_duration_seconds,
+				satisfied_count = EXCLUDED.satisfied_count,
+				satisfied_since = EXCLUDED.satisfied_since,
 				updated_at = EXCLUDED.updated_at
 		`
-	_, err = tx.ExecContext(ctx, conditionQuery,
-		uuid.UUID(trigger.TriggerID),
-		trigger.Condition.Contract.Ticker,
-		trigger.Condition.Contract.Side,
-		int(trigger.Condition.Price.Threshold),
-		trigger.Condition.Price.Direction,
-		trigger.CreatedAt,
-		trigger.UpdatedAt,
-	)
-	if err != nil {
-		return fmt.Errorf("upsert condition: %w", err)
+		_, err = tx.ExecContext(ctx, confirmationQuery,
+			uuid.UUID(trigger.TriggerID),
+			trigger.Confirmation.RequiredEvaluations,
+			int(trigger.Confirmation.RequiredDuration/time.Second),
+			trigger.Confirmation.SatisfiedCount,
+			trigger.Confirmation.SatisfiedSince,
+			trigger.CreatedAt,
+			trigger.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert trigger confirmation: %w", err)
+		}
+	} else {
+		_, err = tx.ExecContext(ctx,
+			"DELETE FROM event_contract.trigger_confirmation WHERE trigger_id = $1",
+			uuid.UUID(trigger.TriggerID),
+		)
+		if err != nil {
+			return fmt.Errorf("delete trigger confirmation: %w", err)
+		}
+	}
+
+	// Upsert the trigger's own pre-trade guard, removing it if it was cleared
+	if trigger.Guard != nil {
+		guardQuery := `
+			INSERT INTO event_contract.trigger_guard (
+				trigger_id, max_spread, min_liquidity, min_volume_24h, min_depth,
+				on_failure, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (trigger_id) DO UPDATE SET
+				max_spread = EXCLUDED.max_spread,
+				min_liquidity = EXCLUDED.min_liquidity,
+				min_volume_24h = EXCLUDED.min_volume_24h,
+				min_depth = EXCLUDED.min_depth,
+				on_failure = EXCLUDED.on_failure,
+				updated_at = EXCLUDED.updated_at
+		`
+		_, err = tx.ExecContext(ctx, guardQuery,
+			uuid.UUID(trigger.TriggerID),
+			trigger.Guard.MaxSpread,
+			trigger.Guard.MinLiquidity,
+			trigger.Guard.MinVolume24H,
+			trigger.Guard.MinDepth,
+			trigger.Guard.OnFailure,
+			trigger.CreatedAt,
+			trigger.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert trigger guard: %w", err)
+		}
+	} else {
+		_, err = tx.ExecContext(ctx,
+			"DELETE FROM event_contract.trigger_guard WHERE trigger_id = $1",
+			uuid.UUID(trigger.TriggerID),
+		)
+		if err != nil {
+			return fmt.Errorf("delete trigger guard: %w", err)
+		}
+	}
+
+	// Upsert the fill policy, removing it if it was cleared
+	if trigger.FillPolicy != nil {
+		fillPolicyQuery := `
+			INSERT INTO event_contract.trigger_fill_policy (
+				trigger_id, timeout_seconds, action, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (trigger_id) DO UPDATE SET
+				timeout_seconds = EXCLUDED.timeout_seconds,
+				action = EXCLUDED.action,
+				updated_at = EXCLUDED.updated_at
+		`
+		_, err = tx.ExecContext(ctx, fillPolicyQuery,
+			uuid.UUID(trigger.TriggerID),
+			int(trigger.FillPolicy.Timeout/time.Second),
+			trigger.FillPolicy.Action,
+			trigger.CreatedAt,
+			trigger.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert trigger fill policy: %w", err)
+		}
+	} else {
+		_, err = tx.ExecContext(ctx,
+			"DELETE FROM event_contract.trigger_fill_policy WHERE trigger_id = $1",
+			uuid.UUID(trigger.TriggerID),
+		)
+		if err != nil {
+			return fmt.Errorf("delete trigger fill policy: %w", err)
+		}
+	}
+
+	// Record the most recent guard failure
+	if trigger.GuardFailure != nil {
+		guardFailureQuery := `
+			INSERT INTO event_contract.trigger_guard_failure (
+				trigger_id, reason, action, failed_at
+			) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (trigger_id) DO UPDATE SET
+				reason = EXCLUDED.reason,
+				action = EXCLUDED.action,
+				failed_at = EXCLUDED.failed_at
+		`
+		_, err = tx.ExecContext(ctx, guardFailureQuery,
+			uuid.UUID(trigger.TriggerID),
+			trigger.GuardFailure.Reason,
+			trigger.GuardFailure.Action,
+			trigger.GuardFailure.FailedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert trigger guard failure: %w", err)
+		}
 	}
 
 	// For actions, still need to delete and reinsert since they're a collection
@@ -124,12 +750,23 @@ func (r *TriggerRepository) Persist(ctx context.Context, trigger *trigger_domain
 	// Insert actions
 	actionQuery := `
 			INSERT INTO event_contract.trigger_action (
-				trigger_id, contract_ticker, contract_side,
-				order_side, order_size, limit_price,
+				trigger_id, position, contract_ticker, contract_side, event_ticker,
+				order_side, order_size, limit_price, max_cost, size_percent,
 				created_at, updated_at
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		`
-	for _, action := range trigger.Actions {
+	for position, action := range trigger.Actions {
+		// Event flatten actions name an event instead of a contract
+		var contractTicker, contractSide, eventTicker *string
+		if action.FlattensEvent() {
+			event := string(action.Event)
+			eventTicker = &event
+		} else {
+			ticker := string(action.Contract.Ticker)
+			side := action.Contract.Side.String()
+			contractTicker = &ticker
+			contractSide = &side
+		}
 		var size *int64
 		if action.Size != nil {
 			i64 := int64(*action.Size)
@@ -143,11 +780,15 @@ func (r *TriggerRepository) Persist(ctx context.Context, trigger *trigger_domain
 
 		_, err = tx.ExecContext(ctx, actionQuery,
 			uuid.UUID(trigger.TriggerID),
-			action.Contract.Ticker,
-			action.Contract.Side,
+			position,
+			contractTicker,
+			contractSide,
+			eventTicker,
 			action.Side,
 			size,
 			limitPrice,
+			action.MaxCost,
+			action.SizePercent,
 			trigger.CreatedAt,
 			trigger.UpdatedAt,
 		)
@@ -156,152 +797,716 @@ func (r *TriggerRepository) Persist(ctx context.Context, trigger *trigger_domain
 		}
 	}
 
-	return tx.Commit()
+	return nil
 }
 
 // Get retrieves a trigger by its ID
+// persistExpiry records why the trigger expired
+func (r *TriggerRepository) persistExpiry(ctx context.Context, tx *sql.Tx, trigger *trigger_domain.Trigger) error {
+	expiryQuery := `
+		INSERT INTO event_contract.trigger_expiry (
+			trigger_id, reason, market_ticker, settlement_result, expired_at
+		) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (trigger_id) DO UPDATE SET
+			reason = EXCLUDED.reason,
+			market_ticker = EXCLUDED.market_ticker,
+			settlement_result = EXCLUDED.settlement_result,
+			expired_at = EXCLUDED.expired_at
+	`
+	_, err := tx.ExecContext(ctx, expiryQuery,
+		uuid.UUID(trigger.TriggerID),
+		trigger.Expiry.Reason,
+		trigger.Expiry.Ticker,
+		trigger.Expiry.SettlementResult,
+		trigger.Expiry.ExpiredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert trigger expiry: %w", err)
+	}
+	return nil
+}
+
 func (r *TriggerRepository) Get(ctx context.Context, id trigger_domain.TriggerID) (*trigger_domain.Trigger, error) {
-	// Get main trigger record
-	var triggerDB TriggerDB
-	err := r.db.GetContext(ctx, &triggerDB, `
-		SELECT trigger_id, trigger_type, status, created_at, updated_at
-		FROM event_contract.trigger
-		WHERE trigger_id = $1
-	`, uuid.UUID(id))
-	if err == sql.ErrNoRows {
+	triggers, err := r.getTriggers(ctx, `WHERE trigger_id = $1`, uuid.UUID(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(triggers) == 0 {
 		return nil, ErrTriggerNotFound
 	}
+	return triggers[0], nil
+}
+
+// GetAll retrieves all triggers
+func (r *TriggerRepository) GetAll(ctx context.Context) ([]*trigger_domain.Trigger, error) {
+	return r.getTriggers(ctx, `ORDER BY created_at`)
+}
+
+// getTriggers loads the triggers matching the clause with everything stored alongside them.
+// Each child table is read once for all of them, so the number of queries does not grow
+// with the number of triggers.
+func (r *TriggerRepository) getTriggers(ctx context.Context, clause string, args ...any) ([]*trigger_domain.Trigger, error) {
+	var triggerDBs []TriggerDB
+	err := r.db.SelectContext(ctx, &triggerDBs, `
+		SELECT trigger_id, trigger_type, status, condition, group_id, policy_id, armed_by,
+			reconcile_attempts, created_at, updated_at
+		FROM event_contract.trigger
+		`+clause, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query trigger: %w", err)
+		return nil, fmt.Errorf("query triggers: %w", err)
+	}
+	if len(triggerDBs) == 0 {
+		return nil, nil
 	}
 
-	// Get condition
-	var conditionDB PriceConditionDB
-	err = r.db.GetContext(ctx, &conditionDB, `
-		SELECT contract_ticker, contract_side, threshold_price, direction
-		FROM event_contract.price_trigger_condition
-		WHERE trigger_id = $1
-	`, uuid.UUID(id))
+	triggerIDs := make([]string, len(triggerDBs))
+	for i, triggerDB := range triggerDBs {
+		triggerIDs[i] = triggerDB.TriggerID.String()
+	}
+	ids := pq.Array(triggerIDs)
+
+	actions, err := r.getActions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	trailingStops, err := r.getTrailingStops(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	costBases, err := r.getCostBasisStops(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	expiries, err := r.getExpiries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	confirmations, err := r.getConfirmations(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	guards, err := r.getGuards(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	guardFailures, err := r.getGuardFailures(ctx, ids)
 	if err != nil {
-		return nil, fmt.Errorf("query condition: %w", err)
+		return nil, err
+	}
+	fillPolicies, err := r.getFillPolicies(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := r.getOrders(ctx, `WHERE trigger_id = ANY($1::uuid[]) ORDER BY action_index, attempt`, ids)
+	if err != nil {
+		return nil, err
+	}
+	ordersByTrigger := make(map[trigger_domain.TriggerID][]trigger_domain.TriggerOrder)
+	for _, order := range orders {
+		ordersByTrigger[order.TriggerID] = append(ordersByTrigger[order.TriggerID], order)
 	}
 
-	side, err := contract.NewSide(conditionDB.ContractSide)
+	triggers := make([]*trigger_domain.Trigger, 0, len(triggerDBs))
+	for _, triggerDB := range triggerDBs {
+		trigger, err := triggerDB.toDomain()
+		if err != nil {
+			return nil, fmt.Errorf("create trigger %s: %w", triggerDB.TriggerID, err)
+		}
+		id := triggerDB.TriggerID
+		trigger.Actions = actions[id]
+		trigger.TrailingStop = trailingStops[id]
+		trigger.CostBasis = costBases[id]
+		trigger.Expiry = expiries[id]
+		trigger.Confirmation = confirmations[id]
+		trigger.Guard = guards[id]
+		trigger.GuardFailure = guardFailures[id]
+		trigger.FillPolicy = fillPolicies[id]
+		trigger.Orders = ordersByTrigger[trigger.TriggerID]
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, nil
+}
+
+func (t TriggerDB) toDomain() (*trigger_domain.Trigger, error) {
+	condition, err := unmarshalCondition(t.Condition)
 	if err != nil {
-		return nil, fmt.Errorf("create side: %w", err)
+		return nil, fmt.Errorf("create condition: %w", err)
 	}
 
-	condition, err := trigger_domain.NewPriceCondition(
-		contract.ContractIdentifier{
-			Ticker: contract.Ticker(conditionDB.ContractTicker),
-			Side:   side,
-		},
-		contract.ContractPrice(conditionDB.ThresholdPrice),
-		trigger_domain.Direction(conditionDB.Direction),
-	)
+	var groupID *trigger_domain.TriggerGroupID
+	if t.GroupID.Valid {
+		id := trigger_domain.TriggerGroupID(t.GroupID.UUID)
+		groupID = &id
+	}
+	var policyID *trigger_domain.ProtectionPolicyID
+	if t.PolicyID.Valid {
+		id := trigger_domain.ProtectionPolicyID(t.PolicyID.UUID)
+		policyID = &id
+	}
+	var armedBy *trigger_domain.TriggerID
+	if t.ArmedBy.Valid {
+		id := trigger_domain.TriggerID(t.ArmedBy.UUID)
+		armedBy = &id
+	}
+
+	triggerType, err := trigger_domain.NewTriggerType(t.Type)
+	if err != nil {
+		return nil, fmt.Errorf("create trigger type: %w", err)
+	}
+
+	status, err := trigger_domain.NewTriggerStatus(t.Status)
 	if err != nil {
-		return nil, fmt.Errorf("create price condition: %w", err)
+		return nil, fmt.Errorf("create trigger status: %w", err)
 	}
 
-	// Get actions
+	return &trigger_domain.Trigger{
+		TriggerID:   trigger_domain.TriggerID(t.TriggerID),
+		TriggerType: triggerType,
+		Status:      status,
+		Condition:   *condition,
+		GroupID:     groupID,
+		PolicyID:    policyID,
+		ArmedBy:     armedBy,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+
+		ReconcileAttempts: t.ReconcileAttempts,
+	}, nil
+}
+
+// getActions loads the actions of the given triggers, each trigger's in the order it stored them
+func (r *TriggerRepository) getActions(ctx context.Context, ids any) (map[uuid.UUID][]trigger_domain.TriggerAction, error) {
 	var actionsDB []TriggerActionDB
-	err = r.db.SelectContext(ctx, &actionsDB, `
-		SELECT contract_ticker, contract_side, order_side, order_size, limit_price
+	err := r.db.SelectContext(ctx, &actionsDB, `
+		SELECT trigger_id, contract_ticker, contract_side, event_ticker, order_side, order_size, limit_price, max_cost, size_percent
 		FROM event_contract.trigger_action
-		WHERE trigger_id = $1
-		ORDER BY created_at
-	`, uuid.UUID(id))
+		WHERE trigger_id = ANY($1::uuid[])
+		ORDER BY trigger_id, position
+	`, ids)
 	if err != nil {
 		return nil, fmt.Errorf("query actions: %w", err)
 	}
 
-	var actions []trigger_domain.TriggerAction
+	actions := make(map[uuid.UUID][]trigger_domain.TriggerAction)
 	for _, actionDB := range actionsDB {
-		var size *uint
-		if actionDB.OrderSize.Valid {
-			uintSize := uint(actionDB.OrderSize.Int64)
-			size = &uintSize
+		action, err := actionDB.toDomain()
+		if err != nil {
+			return nil, err
 		}
+		actions[actionDB.TriggerID] = append(actions[actionDB.TriggerID], *action)
+	}
+	return actions, nil
+}
+
+func (a TriggerActionDB) toDomain() (*trigger_domain.TriggerAction, error) {
+	var size *uint
+	if a.OrderSize.Valid {
+		uintSize := uint(a.OrderSize.Int64)
+		size = &uintSize
+	}
+
+	var limitPrice *contract.ContractPrice
+	if a.LimitPrice.Valid {
+		price := contract.ContractPrice(a.LimitPrice.Int64)
+		limitPrice = &price
+	}
 
-		var limitPrice *contract.ContractPrice
-		if actionDB.LimitPrice.Valid {
-			price := contract.ContractPrice(actionDB.LimitPrice.Int64)
-			limitPrice = &price
+	if a.EventTicker.Valid {
+		action, err := trigger_domain.NewFlattenEventAction(contract.Ticker(a.EventTicker.String))
+		if err != nil {
+			return nil, fmt.Errorf("create trigger action: %w", err)
 		}
+		return action, nil
+	}
+
+	contractSide, err := contract.NewSide(a.ContractSide.String)
+	if err != nil {
+		return nil, fmt.Errorf("create side: %w", err)
+	}
 
-		contractSide, err := contract.NewSide(conditionDB.ContractSide)
+	orderSide, err := trigger_domain.NewOrderSide(a.OrderSide)
+	if err != nil {
+		return nil, fmt.Errorf("create order side: %w", err)
+	}
+
+	action, err := trigger_domain.NewTriggerAction(
+		contract.ContractIdentifier{
+			Ticker: contract.Ticker(a.ContractTicker.String),
+			Side:   contractSide,
+		},
+		orderSide,
+		size,
+		limitPrice,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create trigger action: %w", err)
+	}
+	if a.MaxCost.Valid {
+		maxCost := int(a.MaxCost.Int64)
+		action.MaxCost = &maxCost
+	}
+	action.SizePercent = nullIntPtr(a.SizePercent)
+	return action, nil
+}
+
+// getTrailingStops loads the trailing stop state of those of the given triggers that have one
+func (r *TriggerRepository) getTrailingStops(ctx context.Context, ids any) (map[uuid.UUID]*trigger_domain.TrailingStop, error) {
+	var trailingStopsDB []TrailingStopDB
+	err := r.db.SelectContext(ctx, &trailingStopsDB, `
+		SELECT trigger_id, trail_type, trail_amount, high_water_mark
+		FROM event_contract.trailing_stop
+		WHERE trigger_id = ANY($1::uuid[])
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query trailing stops: %w", err)
+	}
+
+	trailingStops := make(map[uuid.UUID]*trigger_domain.TrailingStop, len(trailingStopsDB))
+	for _, trailingStopDB := range trailingStopsDB {
+		trailType, err := trigger_domain.NewTrailType(trailingStopDB.TrailType)
 		if err != nil {
-			return nil, fmt.Errorf("create side: %w", err)
+			return nil, fmt.Errorf("create trail type: %w", err)
 		}
+		trailingStops[trailingStopDB.TriggerID] = &trigger_domain.TrailingStop{
+			TrailType:     trailType,
+			TrailAmount:   trailingStopDB.TrailAmount,
+			HighWaterMark: contract.ContractPrice(trailingStopDB.HighWaterMark),
+		}
+	}
+	return trailingStops, nil
+}
 
-		orderSide, err := trigger_domain.NewOrderSide(actionDB.OrderSide)
+// getCostBasisStops loads the cost basis stop state of those of the given triggers that have one
+func (r *TriggerRepository) getCostBasisStops(ctx context.Context, ids any) (map[uuid.UUID]*trigger_domain.CostBasisStop, error) {
+	var costBasesDB []CostBasisStopDB
+	err := r.db.SelectContext(ctx, &costBasesDB, `
+		SELECT trigger_id, offset_type, offset_amount, average_entry
+		FROM event_contract.cost_basis_stop
+		WHERE trigger_id = ANY($1::uuid[])
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query cost basis stops: %w", err)
+	}
+
+	costBases := make(map[uuid.UUID]*trigger_domain.CostBasisStop, len(costBasesDB))
+	for _, costBasisDB := range costBasesDB {
+		offsetType, err := trigger_domain.NewTrailType(costBasisDB.OffsetType)
 		if err != nil {
-			return nil, fmt.Errorf("create order side: %w", err)
+			return nil, fmt.Errorf("create offset type: %w", err)
 		}
+		costBases[costBasisDB.TriggerID] = &trigger_domain.CostBasisStop{
+			OffsetType:   offsetType,
+			Offset:       costBasisDB.OffsetAmount,
+			AverageEntry: contract.ContractPrice(costBasisDB.AverageEntry),
+		}
+	}
+	return costBases, nil
+}
 
-		action, err := trigger_domain.NewTriggerAction(
-			contract.ContractIdentifier{
-				Ticker: contract.Ticker(actionDB.ContractTicker),
-				Side:   contractSide,
-			},
-			orderSide,
-			size,
-			limitPrice,
-		)
+// getExpiries loads the expiry details of those of the given triggers that have expired
+func (r *TriggerRepository) getExpiries(ctx context.Context, ids any) (map[uuid.UUID]*trigger_domain.TriggerExpiry, error) {
+	var expiriesDB []TriggerExpiryDB
+	err := r.db.SelectContext(ctx, &expiriesDB, `
+		SELECT trigger_id, reason, market_ticker, settlement_result, expired_at
+		FROM event_contract.trigger_expiry
+		WHERE trigger_id = ANY($1::uuid[])
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query trigger expiries: %w", err)
+	}
+
+	expiries := make(map[uuid.UUID]*trigger_domain.TriggerExpiry, len(expiriesDB))
+	for _, expiryDB := range expiriesDB {
+		reason, err := trigger_domain.NewExpiryReason(expiryDB.Reason)
 		if err != nil {
-			return nil, fmt.Errorf("create trigger action: %w", err)
+			return nil, fmt.Errorf("create expiry reason: %w", err)
+		}
+		var settlementResult *string
+		if expiryDB.SettlementResult.Valid {
+			result := expiryDB.SettlementResult.String
+			settlementResult = &result
+		}
+		expiries[expiryDB.TriggerID] = &trigger_domain.TriggerExpiry{
+			Reason:           reason,
+			Ticker:           contract.Ticker(expiryDB.MarketTicker),
+			SettlementResult: settlementResult,
+			ExpiredAt:        expiryDB.ExpiredAt,
 		}
-		actions = append(actions, *action)
 	}
+	return expiries, nil
+}
 
-	status, err := trigger_domain.NewTriggerStatus(triggerDB.Status)
+// getConfirmations loads the confirmation policy and progress of those of the given triggers
+// that have one
+func (r *TriggerRepository) getConfirmations(ctx context.Context, ids any) (map[uuid.UUID]*trigger_domain.Confirmation, error) {
+	var confirmationsDB []TriggerConfirmationDB
+	err := r.db.SelectContext(ctx, &confirmationsDB, `
+		SELECT trigger_id, required_evaluations, required_duration_seconds, satisfied_count, satisfied_since
+		FROM event_contract.trigger_confirmation
+		WHERE trigger_id = ANY($1::uuid[])
+	`, ids)
 	if err != nil {
-		return nil, fmt.Errorf("create trigger status: %w", err)
+		return nil, fmt.Errorf("query trigger confirmations: %w", err)
 	}
 
-	return &trigger_domain.Trigger{
-		TriggerID: trigger_domain.TriggerID(triggerDB.TriggerID),
-		Status:    status,
-		Condition: *condition,
-		Actions:   actions,
-		CreatedAt: triggerDB.CreatedAt,
-		UpdatedAt: triggerDB.UpdatedAt,
-	}, nil
+	confirmations := make(map[uuid.UUID]*trigger_domain.Confirmation, len(confirmationsDB))
+	for _, confirmationDB := range confirmationsDB {
+		confirmation := &trigger_domain.Confirmation{
+			RequiredEvaluations: confirmationDB.RequiredEvaluations,
+			RequiredDuration:    time.Duration(confirmationDB.RequiredDurationSeconds) * time.Second,
+			SatisfiedCount:      confirmationDB.SatisfiedCount,
+		}
+		if confirmationDB.SatisfiedSince.Valid {
+			since := confirmationDB.SatisfiedSince.Time
+			confirmation.SatisfiedSince = &since
+		}
+		confirmations[confirmationDB.TriggerID] = confirmation
+	}
+	return confirmations, nil
 }
 
-// GetAll retrieves all triggers
-func (r *TriggerRepository) GetAll(ctx context.Context) ([]*trigger_domain.Trigger, error) {
-	var triggerDBs []TriggerDB
-	err := r.db.SelectContext(ctx, &triggerDBs, `
-		SELECT DISTINCT t.trigger_id, t.trigger_type, t.status, t.created_at, t.updated_at
-		FROM event_contract.trigger t
-		JOIN event_contract.price_trigger_condition pc ON t.trigger_id = pc.trigger_id
-	`)
+// getGuards loads the pre-trade guards of those of the given triggers that have one
+func (r *TriggerRepository) getGuards(ctx context.Context, ids any) (map[uuid.UUID]*trigger_domain.ExecutionGuard, error) {
+	var guardsDB []TriggerGuardDB
+	err := r.db.SelectContext(ctx, &guardsDB, `
+		SELECT trigger_id, max_spread, min_liquidity, min_volume_24h, min_depth, on_failure
+		FROM event_contract.trigger_guard
+		WHERE trigger_id = ANY($1::uuid[])
+	`, ids)
 	if err != nil {
-		return nil, fmt.Errorf("query triggers: %w", err)
+		return nil, fmt.Errorf("query trigger guards: %w", err)
 	}
 
-	var triggers []*trigger_domain.Trigger
-	for _, t := range triggerDBs {
-		trigger, err := r.Get(ctx, trigger_domain.TriggerID(t.TriggerID))
+	guards := make(map[uuid.UUID]*trigger_domain.ExecutionGuard, len(guardsDB))
+	for _, guardDB := range guardsDB {
+		onFailure, err := trigger_domain.NewGuardFailureAction(guardDB.OnFailure)
 		if err != nil {
-			return nil, fmt.Errorf("get trigger %s: %w", t.TriggerID, err)
+			return nil, fmt.Errorf("create guard failure action: %w", err)
+		}
+		guards[guardDB.TriggerID] = &trigger_domain.ExecutionGuard{
+			MaxSpread:    nullIntPtr(guardDB.MaxSpread),
+			MinLiquidity: nullIntPtr(guardDB.MinLiquidity),
+			MinVolume24H: nullIntPtr(guardDB.MinVolume24H),
+			MinDepth:     nullIntPtr(guardDB.MinDepth),
+			OnFailure:    onFailure,
+		}
+	}
+	return guards, nil
+}
+
+// getGuardFailures loads the most recent guard failure of those of the given triggers that
+// have failed one
+func (r *TriggerRepository) getGuardFailures(ctx context.Context, ids any) (map[uuid.UUID]*trigger_domain.GuardFailure, error) {
+	var guardFailuresDB []TriggerGuardFailureDB
+	err := r.db.SelectContext(ctx, &guardFailuresDB, `
+		SELECT trigger_id, reason, action, failed_at
+		FROM event_contract.trigger_guard_failure
+		WHERE trigger_id = ANY($1::uuid[])
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query trigger guard failures: %w", err)
+	}
+
+	guardFailures := make(map[uuid.UUID]*trigger_domain.GuardFailure, len(guardFailuresDB))
+	for _, guardFailureDB := range guardFailuresDB {
+		action, err := trigger_domain.NewGuardFailureAction(guardFailureDB.Action)
+		if err != nil {
+			return nil, fmt.Errorf("create guard failure action: %w", err)
+		}
+		guardFailures[guardFailureDB.TriggerID] = &trigger_domain.GuardFailure{
+			Reason:   guardFailureDB.Reason,
+			Action:   action,
+			FailedAt: guardFailureDB.FailedAt,
+		}
+	}
+	return guardFailures, nil
+}
+
+// getFillPolicies loads the fill policies of those of the given triggers that have one
+func (r *TriggerRepository) getFillPolicies(ctx context.Context, ids any) (map[uuid.UUID]*trigger_domain.FillPolicy, error) {
+	var fillPoliciesDB []TriggerFillPolicyDB
+	err := r.db.SelectContext(ctx, &fillPoliciesDB, `
+		SELECT trigger_id, timeout_seconds, action
+		FROM event_contract.trigger_fill_policy
+		WHERE trigger_id = ANY($1::uuid[])
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query trigger fill policies: %w", err)
+	}
+
+	fillPolicies := make(map[uuid.UUID]*trigger_domain.FillPolicy, len(fillPoliciesDB))
+	for _, fillPolicyDB := range fillPoliciesDB {
+		action, err := trigger_domain.NewFillAction(fillPolicyDB.Action)
+		if err != nil {
+			return nil, fmt.Errorf("create fill action: %w", err)
+		}
+		fillPolicies[fillPolicyDB.TriggerID] = &trigger_domain.FillPolicy{
+			Timeout: time.Duration(fillPolicyDB.TimeoutSeconds) * time.Second,
+			Action:  action,
 		}
-		triggers = append(triggers, trigger)
 	}
+	return fillPolicies, nil
+}
 
+// GetGroup retrieves a trigger group by its ID
+func (r *TriggerRepository) GetGroup(ctx context.Context, id trigger_domain.TriggerGroupID) (*trigger_domain.TriggerGroup, error) {
+	var groupDB TriggerGroupDB
+	err := r.db.GetContext(ctx, &groupDB, `
+		SELECT group_id, group_type, created_at, updated_at
+		FROM event_contract.trigger_group
+		WHERE group_id = $1
+	`, uuid.UUID(id))
+	if err == sql.ErrNoRows {
+		return nil, ErrTriggerGroupNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query trigger group: %w", err)
+	}
+
+	groupType, err := trigger_domain.NewTriggerGroupType(groupDB.GroupType)
+	if err != nil {
+		return nil, fmt.Errorf("create group type: %w", err)
+	}
+
+	return &trigger_domain.TriggerGroup{
+		GroupID:   trigger_domain.TriggerGroupID(groupDB.GroupID),
+		GroupType: groupType,
+		CreatedAt: groupDB.CreatedAt,
+		UpdatedAt: groupDB.UpdatedAt,
+	}, nil
+}
+
+// GetByGroup retrieves all triggers belonging to a group
+func (r *TriggerRepository) GetByGroup(ctx context.Context, id trigger_domain.TriggerGroupID) ([]*trigger_domain.Trigger, error) {
+	triggers, err := r.getTriggers(ctx, `WHERE group_id = $1 ORDER BY created_at`, uuid.UUID(id))
+	if err != nil {
+		return nil, fmt.Errorf("get group triggers: %w", err)
+	}
 	return triggers, nil
 }
 
+// GetPending retrieves the triggers waiting for another trigger's orders to fill
+func (r *TriggerRepository) GetPending(ctx context.Context) ([]*trigger_domain.Trigger, error) {
+	triggers, err := r.getTriggers(ctx, `WHERE status = $1 ORDER BY created_at`, trigger_domain.StatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("get pending triggers: %w", err)
+	}
+	return triggers, nil
+}
+
+// AppendHistory records changes to triggers. History is append-only, entries are never updated.
+func (r *TriggerRepository) AppendHistory(ctx context.Context, changes []trigger_domain.TriggerChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, change := range changes {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO event_contract.trigger_history (
+				trigger_id, field, old_value, new_value, actor, changed_at
+			) VALUES ($1, $2, $3, $4, $5, $6)
+		`,
+			uuid.UUID(change.TriggerID),
+			change.Field,
+			change.OldValue,
+			change.NewValue,
+			change.Actor,
+			change.ChangedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("insert history: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetHistory retrieves a trigger's changes, oldest first
+func (r *TriggerRepository) GetHistory(ctx context.Context, id trigger_domain.TriggerID) ([]trigger_domain.TriggerChange, error) {
+	var historyDB []TriggerHistoryDB
+	err := r.db.SelectContext(ctx, &historyDB, `
+		SELECT trigger_id, field, old_value, new_value, actor, changed_at
+		FROM event_contract.trigger_history
+		WHERE trigger_id = $1
+		ORDER BY changed_at, history_id
+	`, uuid.UUID(id))
+	if err != nil {
+		return nil, fmt.Errorf("query history: %w", err)
+	}
+
+	changes := make([]trigger_domain.TriggerChange, 0, len(historyDB))
+	for _, entry := range historyDB {
+		field, err := trigger_domain.NewChangeField(entry.Field)
+		if err != nil {
+			return nil, fmt.Errorf("create change field: %w", err)
+		}
+		changes = append(changes, trigger_domain.TriggerChange{
+			TriggerID: trigger_domain.TriggerID(entry.TriggerID),
+			Field:     field,
+			OldValue:  nullStringPtr(entry.OldValue),
+			NewValue:  nullStringPtr(entry.NewValue),
+			Actor:     trigger_domain.Actor(entry.Actor),
+			ChangedAt: entry.ChangedAt,
+		})
+	}
+
+	return changes, nil
+}
+
+// PersistOrders stores orders sent by trigger executions. An order the exchange already
+// reported is matched on its exchange order ID and has its status and any pending
+// follow-up brought up to date. The orders are stored together or not at all.
+func (r *TriggerRepository) PersistOrders(ctx context.Context, orders []trigger_domain.TriggerOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, triggerOrder := range orders {
+		order := triggerOrder.Order
+		var limitPrice *int
+		if order.LimitPrice != nil {
+			value := order.LimitPrice.Value()
+			limitPrice = &value
+		}
+		var followUpAttempt, followUpRemaining *int
+		var followUpAction *string
+		if followUp := triggerOrder.FollowUp; followUp != nil {
+			remaining := int(followUp.Remaining)
+			action := followUp.Action.String()
+			followUpAttempt, followUpRemaining, followUpAction = &followUp.Attempt, &remaining, &action
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO event_contract.trigger_order (
+				order_id, trigger_id, action_index, attempt, exchange, exchange_order_id, reference,
+				contract_ticker, contract_side, order_side, order_type, status, quantity,
+				limit_price, filled_quantity, created_at, updated_at,
+				follow_up_attempt, follow_up_remaining, follow_up_action
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			ON CONFLICT (exchange, exchange_order_id) DO UPDATE SET
+				status = EXCLUDED.status,
+				filled_quantity = EXCLUDED.filled_quantity,
+				updated_at = EXCLUDED.updated_at,
+				follow_up_attempt = EXCLUDED.follow_up_attempt,
+				follow_up_remaining = EXCLUDED.follow_up_remaining,
+				follow_up_action = EXCLUDED.follow_up_action
+		`,
+			uuid.UUID(order.OrderID),
+			uuid.UUID(triggerOrder.TriggerID),
+			triggerOrder.ActionIndex,
+			triggerOrder.Attempt,
+			order.Exchange,
+			order.ExchangeOrderID,
+			order.Reference,
+			order.Ticker,
+			order.Side.String(),
+			order.Action,
+			order.OrderType,
+			order.Status,
+			order.Quantity,
+			limitPrice,
+			order.FilledQuantity,
+			order.CreatedAt,
+			order.UpdatedAt,
+			followUpAttempt,
+			followUpRemaining,
+			followUpAction,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert order %s: %w", order.ExchangeOrderID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetOrders retrieves the orders sent by every trigger, newest first
+func (r *TriggerRepository) GetOrders(ctx context.Context) ([]trigger_domain.TriggerOrder, error) {
+	return r.getOrders(ctx, `ORDER BY created_at DESC, action_index DESC`)
+}
+
+// GetOpenOrders retrieves the orders that may still fill or whose remainder is waiting to
+// be replaced, oldest first
+func (r *TriggerRepository) GetOpenOrders(ctx context.Context) ([]trigger_domain.TriggerOrder, error) {
+	return r.getOrders(ctx, `WHERE status IN ($1, $2) OR follow_up_attempt IS NOT NULL ORDER BY created_at`,
+		exchange_domain.OrderStatusResting,
+		exchange_domain.OrderStatusPartiallyFilled,
+	)
+}
+
+func (r *TriggerRepository) getOrders(ctx context.Context, where string, args ...any) ([]trigger_domain.TriggerOrder, error) {
+	var ordersDB []TriggerOrderDB
+	err := r.db.SelectContext(ctx, &ordersDB, `
+		SELECT order_id, trigger_id, action_index, attempt, exchange, exchange_order_id, reference,
+			contract_ticker, contract_side, order_side, order_type, status, quantity,
+			limit_price, filled_quantity, created_at, updated_at,
+			follow_up_attempt, follow_up_remaining, follow_up_action
+		FROM event_contract.trigger_order
+		`+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query orders: %w", err)
+	}
+
+	var orders []trigger_domain.TriggerOrder
+	for _, orderDB := range ordersDB {
+		order, err := orderDB.toDomain()
+		if err != nil {
+			return nil, fmt.Errorf("create order %s: %w", orderDB.OrderID, err)
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
 // Helper method
 func (r *TriggerRepository) checkExists(ctx context.Context, trigger *trigger_domain.Trigger) (bool, error) {
 	var exists bool
 	err := r.db.QueryRowContext(ctx, `
         SELECT EXISTS (
-            SELECT 1 FROM event_contract.trigger t
-            JOIN event_contract.price_trigger_condition pc ON t.trigger_id = pc.trigger_id
-            WHERE t.trigger_id = $1
+            SELECT 1 FROM event_contract.trigger
+            WHERE trigger_id = $1
         )
     `, uuid.UUID(trigger.TriggerID)).Scan(&exists)
 	return exists, err
 }
+
+// requireApplied returns trigger_domain.ErrTriggerChanged if a guarded update matched no rows
+func requireApplied(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return trigger_domain.ErrTriggerChanged
+	}
+	return nil
+}
+
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	value := int(n.Int64)
+	return &value
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
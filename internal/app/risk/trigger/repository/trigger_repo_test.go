@@ -5,12 +5,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
 	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
 	"prediction-risk/internal/app/testutil"
+	weather_domain "prediction-risk/internal/app/weather/domain"
 )
 
 func createTestTrigger() *trigger_domain.Trigger {
@@ -41,12 +44,13 @@ func createTestTrigger() *trigger_domain.Trigger {
 	now := time.Now()
 
 	trigger := &trigger_domain.Trigger{
-		TriggerID: trigger_domain.NewTriggerID(),
-		Status:    trigger_domain.StatusActive,
-		Condition: *condition,
-		Actions:   []trigger_domain.TriggerAction{*action},
-		CreatedAt: now,
-		UpdatedAt: now,
+		TriggerID:   trigger_domain.NewTriggerID(),
+		TriggerType: trigger_domain.TriggerTypeStop,
+		Status:      trigger_domain.StatusActive,
+		Condition:   *condition,
+		Actions:     []trigger_domain.TriggerAction{*action},
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
 	return trigger
@@ -69,6 +73,7 @@ func TestTriggerRepository_Persist(t *testing.T) {
 		saved, err := repo.Get(context.Background(), trigger.TriggerID)
 		require.NoError(t, err)
 		assert.Equal(t, trigger.TriggerID, saved.TriggerID)
+		assert.Equal(t, trigger.TriggerType, saved.TriggerType)
 		assert.Equal(t, trigger.Status, saved.Status)
 		assert.Equal(t, trigger.Condition.Contract, saved.Condition.Contract)
 		assert.Equal(t, trigger.Condition.Price.Threshold, saved.Condition.Price.Threshold)
@@ -78,6 +83,329 @@ func TestTriggerRepository_Persist(t *testing.T) {
 		assert.Equal(t, trigger.Actions[0].Side, saved.Actions[0].Side)
 	})
 
+	t.Run("persists take profit trigger type", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger, err := trigger_domain.NewTakeProfitTrigger(
+			contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes},
+			contract.ContractPrice(80),
+			nil,
+		)
+		require.NoError(t, err)
+
+		err = repo.Persist(context.Background(), trigger)
+		require.NoError(t, err)
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.TriggerTypeTakeProfit, saved.TriggerType)
+		assert.Equal(t, trigger_domain.Above, saved.Condition.Price.Direction)
+	})
+
+	t.Run("persists entry trigger buy sizing", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		limitPrice := contract.ContractPrice(21)
+		maxCost := 250
+		trigger, err := trigger_domain.NewEntryTrigger(
+			contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes},
+			trigger_domain.Below,
+			contract.ContractPrice(20),
+			10,
+			&limitPrice,
+			&maxCost,
+		)
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.TriggerTypeEntry, saved.TriggerType)
+		require.Len(t, saved.Actions, 1)
+		assert.Equal(t, trigger.Actions[0], saved.Actions[0])
+	})
+
+	t.Run("persists cost basis stop", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		position := exchange_domain.Position{
+			ContractID: contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes},
+			Quantity:   10,
+			CostBasis:  600,
+		}
+		trigger, err := trigger_domain.NewCostBasisStopTrigger(position, trigger_domain.TrailTypePercent, 15)
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger.CostBasis, saved.CostBasis)
+		assert.Equal(t, contract.ContractPrice(51), saved.Condition.Price.Threshold)
+	})
+
+	t.Run("persists hedge trigger across contracts", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		watched := contract.ContractIdentifier{Ticker: "FOO-B40", Side: contract.SideYes}
+		adjacent := contract.ContractIdentifier{Ticker: "FOO-B42", Side: contract.SideYes}
+		buy, err := trigger_domain.NewBuyAction(adjacent, 10, nil, nil)
+		require.NoError(t, err)
+		trigger, err := trigger_domain.NewHedgeTrigger(watched, trigger_domain.Below, 30, []trigger_domain.TriggerAction{*buy})
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.TriggerTypeHedge, saved.TriggerType)
+		assert.Equal(t, watched, saved.Condition.Contract)
+		require.Len(t, saved.Actions, 1)
+		assert.Equal(t, adjacent, saved.Actions[0].Contract)
+	})
+
+	t.Run("persists price source", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		require.NoError(t, trigger.Condition.SetPriceSource(trigger_domain.PriceSourceBid))
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.PriceSourceBid, saved.Condition.Price.Source)
+	})
+
+	t.Run("persists trailing stop high water mark", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger, err := trigger_domain.NewTrailingStopTrigger(
+			contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes},
+			trigger_domain.TrailTypeCents,
+			10,
+			contract.ContractPrice(60),
+		)
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		_, err = trigger.UpdateTrailingStop(contract.ContractPrice(85))
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		require.NotNil(t, saved.TrailingStop)
+		assert.Equal(t, contract.ContractPrice(85), saved.TrailingStop.HighWaterMark)
+		assert.Equal(t, contract.ContractPrice(75), saved.Condition.Price.Threshold)
+	})
+
+	t.Run("persists composite condition", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+		bar := contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideNo}
+		fooAbove, err := trigger_domain.NewPriceCondition(foo, 60, trigger_domain.Above)
+		require.NoError(t, err)
+		barBelow, err := trigger_domain.NewPriceCondition(bar, 30, trigger_domain.Below)
+		require.NoError(t, err)
+		notBar, err := trigger_domain.NewCompositeCondition(trigger_domain.OperatorNot, *barBelow)
+		require.NoError(t, err)
+		condition, err := trigger_domain.NewCompositeCondition(trigger_domain.OperatorAnd, *fooAbove, *notBar)
+		require.NoError(t, err)
+
+		action, err := trigger_domain.NewTriggerAction(bar, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+		trigger, err := trigger_domain.NewConditionalTrigger(*condition, []trigger_domain.TriggerAction{*action})
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.TriggerTypeConditional, saved.TriggerType)
+		assert.Equal(t, *condition, saved.Condition)
+		assert.Equal(t, bar, saved.Actions[0].Contract)
+	})
+
+	t.Run("persists weather condition", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+		condition, err := trigger_domain.NewWeatherCondition(
+			"KNYC",
+			trigger_domain.MetricDailyMaxTemperature,
+			weather_domain.Temperature{Value: 80, TemperatureUnit: weather_domain.Fahrenheit},
+			trigger_domain.Above,
+		)
+		require.NoError(t, err)
+
+		action, err := trigger_domain.NewTriggerAction(foo, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+		trigger, err := trigger_domain.NewConditionalTrigger(*condition, []trigger_domain.TriggerAction{*action})
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, *condition, saved.Condition)
+	})
+
+	t.Run("persists forecast condition", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+		condition, err := trigger_domain.NewForecastCondition(
+			weather_domain.Gridpoint{OfficeID: "OKX", X: 33, Y: 37},
+			trigger_domain.ForecastDailyHigh,
+			1,
+			weather_domain.Temperature{Value: 78, TemperatureUnit: weather_domain.Fahrenheit},
+			trigger_domain.Below,
+		)
+		require.NoError(t, err)
+
+		action, err := trigger_domain.NewTriggerAction(foo, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+		trigger, err := trigger_domain.NewConditionalTrigger(*condition, []trigger_domain.TriggerAction{*action})
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, *condition, saved.Condition)
+	})
+
+	t.Run("persists time condition", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+		beforeClose, err := trigger_domain.NewMarketTimeCondition("FOO", trigger_domain.TimeReferenceMarketClose, -30*time.Minute)
+		require.NoError(t, err)
+		stillLow, err := trigger_domain.NewPriceCondition(foo, 40, trigger_domain.Below)
+		require.NoError(t, err)
+		condition, err := trigger_domain.NewCompositeCondition(trigger_domain.OperatorAnd, *beforeClose, *stillLow)
+		require.NoError(t, err)
+
+		action, err := trigger_domain.NewTriggerAction(foo, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+		trigger, err := trigger_domain.NewConditionalTrigger(*condition, []trigger_domain.TriggerAction{*action})
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, *condition, saved.Condition)
+	})
+
+	t.Run("persists expiry", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		result := "no"
+		expiry := trigger_domain.TriggerExpiry{
+			Reason:           trigger_domain.ExpiryReasonMarketSettled,
+			Ticker:           "FOO",
+			SettlementResult: &result,
+			ExpiredAt:        time.Now().UTC().Truncate(time.Microsecond),
+		}
+		require.NoError(t, trigger.Expire(expiry))
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusExpired, saved.Status)
+		require.NotNil(t, saved.Expiry)
+		assert.Equal(t, expiry.Reason, saved.Expiry.Reason)
+		assert.Equal(t, expiry.Ticker, saved.Expiry.Ticker)
+		assert.Equal(t, &result, saved.Expiry.SettlementResult)
+		assert.True(t, expiry.ExpiredAt.Equal(saved.Expiry.ExpiredAt))
+	})
+
+	t.Run("persists confirmation progress", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		confirmation, err := trigger_domain.NewDurationConfirmation(30 * time.Second)
+		require.NoError(t, err)
+		since := time.Now().UTC().Truncate(time.Microsecond)
+		confirmation.Observe(true, since)
+		trigger.Confirmation = confirmation
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		require.NotNil(t, saved.Confirmation)
+		assert.Equal(t, 30*time.Second, saved.Confirmation.RequiredDuration)
+		assert.Equal(t, 1, saved.Confirmation.SatisfiedCount)
+		require.NotNil(t, saved.Confirmation.SatisfiedSince)
+		assert.True(t, since.Equal(*saved.Confirmation.SatisfiedSince))
+
+		// Clearing the policy removes it
+		trigger.Confirmation = nil
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+		saved, err = repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Nil(t, saved.Confirmation)
+	})
+
+	t.Run("persists reconcile attempts", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		trigger.ReconcileAttempts = 3
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, saved.ReconcileAttempts)
+	})
+
+	t.Run("persists guard and guard failure", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		maxSpread, minDepth := 5, 20
+		guard := &trigger_domain.ExecutionGuard{
+			MaxSpread: &maxSpread,
+			MinDepth:  &minDepth,
+			OnFailure: trigger_domain.GuardFailureFlag,
+		}
+		require.NoError(t, trigger.SetGuard(guard))
+		failure := trigger_domain.GuardFailure{
+			Reason:   "FOO resting depth 3 below 20",
+			Action:   trigger_domain.GuardFailureFlag,
+			FailedAt: time.Now().UTC().Truncate(time.Microsecond),
+		}
+		require.NoError(t, trigger.FailGuard(failure))
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusFlagged, saved.Status)
+		assert.Equal(t, guard, saved.Guard)
+		require.NotNil(t, saved.GuardFailure)
+		assert.Equal(t, failure.Reason, saved.GuardFailure.Reason)
+		assert.True(t, failure.FailedAt.Equal(saved.GuardFailure.FailedAt))
+	})
+
+	t.Run("persists and clears fill policy", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		policy := &trigger_domain.FillPolicy{Timeout: 90 * time.Second, Action: trigger_domain.FillActionReprice}
+		require.NoError(t, trigger.SetFillPolicy(policy))
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, policy, saved.FillPolicy)
+
+		require.NoError(t, trigger.SetFillPolicy(nil))
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err = repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Nil(t, saved.FillPolicy)
+	})
+
 	t.Run("updates existing trigger", func(t *testing.T) {
 		defer testDB.Cleanup(t)
 
@@ -111,6 +439,58 @@ func TestTriggerRepository_Persist(t *testing.T) {
 		assert.Len(t, updated.Actions, 2)
 		assert.Equal(t, "BAR", string(updated.Actions[1].Contract.Ticker))
 	})
+
+	t.Run("keeps the order of actions sharing a timestamp", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		// Every action is stored with the trigger's timestamps, so only their
+		// position tells them apart
+		trigger := createTestTrigger()
+		tickers := []contract.Ticker{"FOO", "QUX", "BAR", "BAZ", "ABC"}
+		trigger.Actions = nil
+		for _, ticker := range tickers {
+			action, err := trigger_domain.NewTriggerAction(
+				contract.ContractIdentifier{Ticker: ticker, Side: contract.SideYes},
+				trigger_domain.Sell,
+				nil,
+				nil,
+			)
+			require.NoError(t, err)
+			trigger.Actions = append(trigger.Actions, *action)
+		}
+
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		require.Len(t, saved.Actions, len(tickers))
+		for i, action := range saved.Actions {
+			assert.Equal(t, tickers[i], action.Contract.Ticker)
+		}
+	})
+
+	t.Run("persists event exposure trigger", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger, err := trigger_domain.NewEventExposureTrigger(
+			"KXHIGHNY-25FEB13",
+			trigger_domain.EventMetricPNL,
+			-20000,
+			trigger_domain.Below,
+		)
+		require.NoError(t, err)
+
+		err = repo.Persist(context.Background(), trigger)
+		require.NoError(t, err)
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.TriggerTypeEventExposure, saved.TriggerType)
+		assert.Equal(t, trigger.Condition.Event, saved.Condition.Event)
+		require.Len(t, saved.Actions, 1)
+		assert.Equal(t, contract.Ticker("KXHIGHNY-25FEB13"), saved.Actions[0].Event)
+		assert.Empty(t, saved.Actions[0].Contract.Ticker)
+	})
 }
 
 func TestTriggerRepository_Get(t *testing.T) {
@@ -169,6 +549,35 @@ func TestTriggerRepository_GetAll(t *testing.T) {
 		assert.Contains(t, triggerIDs, trigger2.TriggerID)
 	})
 
+	t.Run("attaches child rows to the trigger they belong to", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		// Child rows of every trigger are loaded together, so each must end up on its own trigger
+		plain := createTestTrigger()
+		require.NoError(t, repo.Persist(context.Background(), plain))
+
+		withPolicy := createTestTrigger()
+		withPolicy.Condition.Contract.Ticker = "BAR"
+		withPolicy.Actions[0].Contract.Ticker = "BAR"
+		withPolicy.FillPolicy = &trigger_domain.FillPolicy{Timeout: time.Minute, Action: trigger_domain.FillActionMarket}
+		require.NoError(t, repo.Persist(context.Background(), withPolicy))
+
+		triggers, err := repo.GetAll(context.Background())
+		require.NoError(t, err)
+		require.Len(t, triggers, 2)
+
+		byID := make(map[trigger_domain.TriggerID]*trigger_domain.Trigger)
+		for _, trigger := range triggers {
+			byID[trigger.TriggerID] = trigger
+		}
+		require.Len(t, byID[plain.TriggerID].Actions, 1)
+		assert.Equal(t, contract.Ticker("FOO"), byID[plain.TriggerID].Actions[0].Contract.Ticker)
+		assert.Nil(t, byID[plain.TriggerID].FillPolicy)
+		require.Len(t, byID[withPolicy.TriggerID].Actions, 1)
+		assert.Equal(t, contract.Ticker("BAR"), byID[withPolicy.TriggerID].Actions[0].Contract.Ticker)
+		assert.Equal(t, withPolicy.FillPolicy, byID[withPolicy.TriggerID].FillPolicy)
+	})
+
 	t.Run("get all with no triggers", func(t *testing.T) {
 		defer testDB.Cleanup(t)
 
@@ -177,3 +586,503 @@ func TestTriggerRepository_GetAll(t *testing.T) {
 		assert.Empty(t, triggers)
 	})
 }
+
+func TestTriggerRepository_GuardedUpdates(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Close(t)
+
+	repo := NewTriggerRepository(testDB.DB())
+
+	newTrailingStop := func(t *testing.T) *trigger_domain.Trigger {
+		trigger, err := trigger_domain.NewTrailingStopTrigger(
+			contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes},
+			trigger_domain.TrailTypeCents,
+			10,
+			contract.ContractPrice(60),
+		)
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+		return trigger
+	}
+
+	t.Run("updates trailing stop high water mark and stop level", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := newTrailingStop(t)
+		_, err := trigger.UpdateTrailingStop(contract.ContractPrice(85))
+		require.NoError(t, err)
+		require.NoError(t, repo.UpdateTrailingStop(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, contract.ContractPrice(85), saved.TrailingStop.HighWaterMark)
+		assert.Equal(t, contract.ContractPrice(75), saved.Condition.Price.Threshold)
+		assert.Equal(t, trigger.Condition, saved.Condition)
+	})
+
+	t.Run("does not undo a cancel made after the trailing stop was read", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := newTrailingStop(t)
+		cancelled := *trigger
+		cancelled.Status = trigger_domain.StatusCancelled
+		require.NoError(t, repo.Persist(context.Background(), &cancelled))
+
+		_, err := trigger.UpdateTrailingStop(contract.ContractPrice(85))
+		require.NoError(t, err)
+		err = repo.UpdateTrailingStop(context.Background(), trigger)
+		assert.ErrorIs(t, err, trigger_domain.ErrTriggerChanged)
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusCancelled, saved.Status)
+		assert.Equal(t, contract.ContractPrice(60), saved.TrailingStop.HighWaterMark)
+	})
+
+	t.Run("does not undo a trail edited after the trailing stop was read", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := newTrailingStop(t)
+		edited := *trigger
+		trail := *trigger.TrailingStop
+		trail.TrailAmount = 5
+		edited.TrailingStop = &trail
+		require.NoError(t, repo.Persist(context.Background(), &edited))
+
+		_, err := trigger.UpdateTrailingStop(contract.ContractPrice(85))
+		require.NoError(t, err)
+		err = repo.UpdateTrailingStop(context.Background(), trigger)
+		assert.ErrorIs(t, err, trigger_domain.ErrTriggerChanged)
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, 5, saved.TrailingStop.TrailAmount)
+		assert.Equal(t, contract.ContractPrice(50), saved.Condition.Price.Threshold)
+	})
+
+	t.Run("updates cost basis stop average entry and stop level", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+		trigger, err := trigger_domain.NewCostBasisStopTrigger(
+			exchange_domain.Position{ContractID: contractID, Quantity: 10, CostBasis: 500},
+			trigger_domain.TrailTypeCents,
+			10,
+		)
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		_, err = trigger.UpdateCostBasis(exchange_domain.Position{ContractID: contractID, Quantity: 5, CostBasis: 200})
+		require.NoError(t, err)
+		require.NoError(t, repo.UpdateCostBasis(context.Background(), trigger))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, contract.ContractPrice(40), saved.CostBasis.AverageEntry)
+		assert.Equal(t, contract.ContractPrice(30), saved.Condition.Price.Threshold)
+	})
+
+	t.Run("does not undo a cancel made after the cost basis stop was read", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+		trigger, err := trigger_domain.NewCostBasisStopTrigger(
+			exchange_domain.Position{ContractID: contractID, Quantity: 10, CostBasis: 500},
+			trigger_domain.TrailTypeCents,
+			10,
+		)
+		require.NoError(t, err)
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+		cancelled := *trigger
+		cancelled.Status = trigger_domain.StatusCancelled
+		require.NoError(t, repo.Persist(context.Background(), &cancelled))
+
+		_, err = trigger.UpdateCostBasis(exchange_domain.Position{ContractID: contractID, Quantity: 5, CostBasis: 200})
+		require.NoError(t, err)
+		err = repo.UpdateCostBasis(context.Background(), trigger)
+		assert.ErrorIs(t, err, trigger_domain.ErrTriggerChanged)
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusCancelled, saved.Status)
+		assert.Equal(t, contract.ContractPrice(50), saved.CostBasis.AverageEntry)
+	})
+
+	t.Run("expires trigger still in the status it was read in", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		expiry := trigger_domain.TriggerExpiry{
+			Reason:    trigger_domain.ExpiryReasonMarketClosed,
+			Ticker:    "FOO",
+			ExpiredAt: time.Now().UTC().Truncate(time.Microsecond),
+		}
+		require.NoError(t, trigger.Expire(expiry))
+		require.NoError(t, repo.Expire(context.Background(), trigger, trigger_domain.StatusActive))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusExpired, saved.Status)
+		require.NotNil(t, saved.Expiry)
+		assert.Equal(t, expiry.Reason, saved.Expiry.Reason)
+	})
+
+	t.Run("does not undo a cancel made after the trigger was read", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+		cancelled := *trigger
+		cancelled.Status = trigger_domain.StatusCancelled
+		require.NoError(t, repo.Persist(context.Background(), &cancelled))
+
+		require.NoError(t, trigger.Expire(trigger_domain.TriggerExpiry{
+			Reason:    trigger_domain.ExpiryReasonMarketClosed,
+			Ticker:    "FOO",
+			ExpiredAt: time.Now(),
+		}))
+		err := repo.Expire(context.Background(), trigger, trigger_domain.StatusActive)
+		assert.ErrorIs(t, err, trigger_domain.ErrTriggerChanged)
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusCancelled, saved.Status)
+		assert.Nil(t, saved.Expiry)
+	})
+
+	t.Run("updates confirmation progress", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		confirmation, err := trigger_domain.NewEvaluationConfirmation(3)
+		require.NoError(t, err)
+		trigger.Confirmation = confirmation
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		since := time.Now().UTC().Truncate(time.Microsecond)
+		trigger.Confirm(true, since)
+		require.NoError(t, repo.UpdateConfirmation(context.Background(), trigger, since))
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		require.NotNil(t, saved.Confirmation)
+		assert.Equal(t, 1, saved.Confirmation.SatisfiedCount)
+		require.NotNil(t, saved.Confirmation.SatisfiedSince)
+		assert.True(t, since.Equal(*saved.Confirmation.SatisfiedSince))
+	})
+
+	t.Run("does not store confirmation progress once the trigger is cancelled", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		confirmation, err := trigger_domain.NewEvaluationConfirmation(3)
+		require.NoError(t, err)
+		trigger.Confirmation = confirmation
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+		cancelled := *trigger
+		cancelled.Status = trigger_domain.StatusCancelled
+		require.NoError(t, repo.Persist(context.Background(), &cancelled))
+
+		trigger.Confirm(true, time.Now())
+		err = repo.UpdateConfirmation(context.Background(), trigger, time.Now())
+		assert.ErrorIs(t, err, trigger_domain.ErrTriggerChanged)
+
+		saved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusCancelled, saved.Status)
+		assert.Equal(t, 0, saved.Confirmation.SatisfiedCount)
+	})
+}
+
+func TestTriggerRepository_PersistGroup(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Close(t)
+
+	repo := NewTriggerRepository(testDB.DB())
+
+	t.Run("persists group and members", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+		entry, err := trigger_domain.NewEntryTrigger(contractID, trigger_domain.Below, 60, 10, nil, nil)
+		require.NoError(t, err)
+		bracket, err := trigger_domain.NewBracket(
+			entry,
+			contract.ContractPrice(40),
+			nil,
+			contract.ContractPrice(80),
+			nil,
+		)
+		require.NoError(t, err)
+
+		err = repo.PersistGroup(context.Background(), bracket.Group, bracket.Triggers())
+		require.NoError(t, err)
+
+		group, err := repo.GetGroup(context.Background(), bracket.Group.GroupID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.GroupTypeOCO, group.GroupType)
+
+		members, err := repo.GetByGroup(context.Background(), bracket.Group.GroupID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		for _, member := range members {
+			require.NotNil(t, member.GroupID)
+			assert.Equal(t, bracket.Group.GroupID, *member.GroupID)
+			assert.Equal(t, trigger_domain.StatusPending, member.Status)
+			require.NotNil(t, member.ArmedBy)
+			assert.Equal(t, entry.TriggerID, *member.ArmedBy)
+		}
+
+		retrieved, err := repo.Get(context.Background(), entry.TriggerID)
+		require.NoError(t, err)
+		assert.Nil(t, retrieved.GroupID)
+		assert.Nil(t, retrieved.ArmedBy)
+	})
+
+	t.Run("persists ladder tier sizing", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		ladder, err := trigger_domain.NewStopLadder(
+			contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes},
+			[]trigger_domain.LadderTier{
+				{TriggerPrice: 45, Share: 50},
+				{TriggerPrice: 35, Share: 50},
+			},
+		)
+		require.NoError(t, err)
+
+		err = repo.PersistGroup(context.Background(), ladder.Group, ladder.Triggers())
+		require.NoError(t, err)
+
+		group, err := repo.GetGroup(context.Background(), ladder.Group.GroupID)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.GroupTypeLadder, group.GroupType)
+
+		for _, tier := range ladder.Tiers {
+			retrieved, err := repo.Get(context.Background(), tier.TriggerID)
+			require.NoError(t, err)
+			assert.Equal(t, tier.Actions[0].SizePercent, retrieved.Actions[0].SizePercent)
+		}
+	})
+
+	t.Run("only one OCO member claims execution", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+		entry, err := trigger_domain.NewEntryTrigger(contractID, trigger_domain.Below, 60, 10, nil, nil)
+		require.NoError(t, err)
+		bracket, err := trigger_domain.NewBracket(entry, 40, nil, 80, nil)
+		require.NoError(t, err)
+		for _, leg := range bracket.Exits() {
+			require.NoError(t, leg.Arm(10))
+		}
+		require.NoError(t, repo.PersistGroup(context.Background(), bracket.Group, bracket.Triggers()))
+
+		// Both legs fire at once and race to claim the group
+		errs := make(chan error, 2)
+		for _, leg := range bracket.Exits() {
+			go func(leg trigger_domain.Trigger) {
+				leg.Status = trigger_domain.StatusExecuting
+				errs <- repo.ClaimExecution(context.Background(), bracket.Group, &leg)
+			}(*leg)
+		}
+		var claimed, refused int
+		for range 2 {
+			if err := <-errs; err == nil {
+				claimed++
+			} else {
+				assert.ErrorIs(t, err, trigger_domain.ErrGroupHeld)
+				refused++
+			}
+		}
+		assert.Equal(t, 1, claimed)
+		assert.Equal(t, 1, refused)
+
+		members, err := repo.GetByGroup(context.Background(), bracket.Group.GroupID)
+		require.NoError(t, err)
+		executing := lo.CountBy(members, func(m *trigger_domain.Trigger) bool {
+			return m.Status == trigger_domain.StatusExecuting
+		})
+		assert.Equal(t, 1, executing)
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		_, err := repo.GetGroup(context.Background(), trigger_domain.NewTriggerGroupID())
+		assert.ErrorIs(t, err, ErrTriggerGroupNotFound)
+	})
+}
+
+func TestTriggerRepository_History(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Close(t)
+
+	repo := NewTriggerRepository(testDB.DB())
+
+	t.Run("appends and retrieves changes in order", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		changedAt := time.Now().UTC().Truncate(time.Microsecond)
+		before := trigger.Snapshot()
+		trigger.Condition.Price.Threshold = 35
+		moved := trigger.ChangesSince(before, "trader", changedAt)
+		require.NoError(t, repo.AppendHistory(context.Background(), moved))
+
+		before = trigger.Snapshot()
+		trigger.Status = trigger_domain.StatusCancelled
+		cancelled := trigger.ChangesSince(before, "trader", changedAt.Add(time.Minute))
+		require.NoError(t, repo.AppendHistory(context.Background(), cancelled))
+
+		history, err := repo.GetHistory(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, trigger_domain.ChangeFieldTriggerPrice, history[0].Field)
+		assert.Equal(t, "35", *history[0].NewValue)
+		assert.Equal(t, trigger_domain.ChangeFieldStatus, history[1].Field)
+		assert.Equal(t, "CANCELLED", *history[1].NewValue)
+		assert.Equal(t, trigger_domain.Actor("trader"), history[1].Actor)
+	})
+
+	t.Run("no history", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		history, err := repo.GetHistory(context.Background(), trigger_domain.NewTriggerID())
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+}
+
+func TestTriggerRepository_Orders(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Close(t)
+
+	repo := NewTriggerRepository(testDB.DB())
+
+	t.Run("persists orders and loads them with the trigger", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		order := exchange_domain.NewOrder(
+			"kalshi-1",
+			exchange_domain.ExchangeKalshi,
+			trigger.TriggerID.OrderReference(0),
+			"FOO",
+			contract.SideYes,
+			exchange_domain.OrderActionSell,
+			exchange_domain.OrderTypeLimit,
+			"resting",
+		)
+		order.Quantity = 10
+		limitPrice := contract.ContractPrice(40)
+		order.LimitPrice = &limitPrice
+		order.CreatedAt = order.CreatedAt.UTC().Truncate(time.Microsecond)
+		order.UpdatedAt = order.CreatedAt
+		triggerOrder := trigger_domain.TriggerOrder{TriggerID: trigger.TriggerID, Order: *order}
+		require.NoError(t, repo.PersistOrders(context.Background(), []trigger_domain.TriggerOrder{triggerOrder}))
+
+		// Reported again by the exchange under a new local ID, the stored order is updated in place
+		reported := *order
+		reported.OrderID = exchange_domain.NewOrderID()
+		reported.Status = "executed"
+		reported.UpdatedAt = order.UpdatedAt.Add(time.Minute)
+		require.NoError(t, repo.PersistOrders(context.Background(), []trigger_domain.TriggerOrder{
+			{TriggerID: trigger.TriggerID, Order: reported},
+		}))
+
+		retrieved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		require.Len(t, retrieved.Orders, 1)
+		assert.Equal(t, order.OrderID, retrieved.Orders[0].Order.OrderID)
+		assert.Equal(t, "executed", retrieved.Orders[0].Order.Status)
+		assert.Equal(t, uint(10), retrieved.Orders[0].Order.Quantity)
+		assert.Equal(t, &limitPrice, retrieved.Orders[0].Order.LimitPrice)
+
+		orders, err := repo.GetOrders(context.Background())
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		assert.Equal(t, trigger.TriggerID, orders[0].TriggerID)
+	})
+
+	t.Run("tracks fills and lists open orders", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		trigger := createTestTrigger()
+		require.NoError(t, repo.Persist(context.Background(), trigger))
+
+		newOrder := func(exchangeOrderID string, reference string) exchange_domain.Order {
+			order := exchange_domain.NewOrder(
+				exchangeOrderID,
+				exchange_domain.ExchangeKalshi,
+				reference,
+				"FOO",
+				contract.SideYes,
+				exchange_domain.OrderActionSell,
+				exchange_domain.OrderTypeLimit,
+				exchange_domain.OrderStatusResting,
+			)
+			order.Quantity = 10
+			order.CreatedAt = order.CreatedAt.UTC().Truncate(time.Microsecond)
+			order.UpdatedAt = order.CreatedAt
+			return *order
+		}
+		original := trigger_domain.TriggerOrder{
+			TriggerID: trigger.TriggerID,
+			Order:     newOrder("kalshi-1", trigger.TriggerID.OrderReference(0)),
+		}
+		require.NoError(t, repo.PersistOrders(context.Background(), []trigger_domain.TriggerOrder{original}))
+
+		// The remainder of a partly filled order is canceled and replaced by a follow-up,
+		// which stays listed as open until the replacement is stored
+		original.Order.Status = exchange_domain.OrderStatusCanceled
+		original.Order.FilledQuantity = 4
+		original.StartFollowUp(trigger_domain.FillActionMarket)
+		require.NoError(t, repo.PersistOrders(context.Background(), []trigger_domain.TriggerOrder{original}))
+
+		open, err := repo.GetOpenOrders(context.Background())
+		require.NoError(t, err)
+		require.Len(t, open, 1)
+		assert.Equal(t, &trigger_domain.PendingFollowUp{
+			Attempt:   1,
+			Remaining: 6,
+			Action:    trigger_domain.FillActionMarket,
+		}, open[0].FollowUp)
+
+		original.FollowUp = nil
+		followUp := trigger_domain.TriggerOrder{
+			TriggerID: trigger.TriggerID,
+			Attempt:   1,
+			Order:     newOrder("kalshi-2", trigger.TriggerID.FollowUpReference(0, 1)),
+		}
+		followUp.Order.Quantity = 6
+		require.NoError(t, repo.PersistOrders(context.Background(), []trigger_domain.TriggerOrder{original, followUp}))
+
+		retrieved, err := repo.Get(context.Background(), trigger.TriggerID)
+		require.NoError(t, err)
+		require.Len(t, retrieved.Orders, 2)
+		assert.Equal(t, exchange_domain.OrderStatusCanceled, retrieved.Orders[0].Order.Status)
+		assert.Equal(t, uint(4), retrieved.Orders[0].Order.FilledQuantity)
+		assert.Equal(t, 1, retrieved.Orders[1].Attempt)
+		assert.Nil(t, retrieved.Orders[0].FollowUp)
+
+		open, err = repo.GetOpenOrders(context.Background())
+		require.NoError(t, err)
+		require.Len(t, open, 1)
+		assert.Equal(t, "kalshi-2", open[0].Order.ExchangeOrderID)
+	})
+
+	t.Run("no orders", func(t *testing.T) {
+		defer testDB.Cleanup(t)
+
+		orders, err := repo.GetOrders(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, orders)
+	})
+}
@@ -0,0 +1,149 @@
+package trigger_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	exchange_service "prediction-risk/internal/app/exchange/service"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	weather_service "prediction-risk/internal/app/weather/service"
+	"slices"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// Forecasts older than this have stopped being updated and never fire triggers
+const maxForecastAge = 12 * time.Hour
+
+// ForecastTriggerEvaluator evaluates triggers with forecast conditions each time
+// a gridpoint's hourly forecast is polled, executing the ones that are satisfied
+type ForecastTriggerEvaluator struct {
+	triggerService  *TriggerService
+	triggerExecutor *TriggerExecutor
+	exchangeService exchange_service.ExchangeService
+	tickers         *TickerLocks
+	forecastService weather_service.ForecastService
+	location        *time.Location // defines the day forecast rules look at
+	now             func() time.Time
+}
+
+func NewForecastTriggerEvaluator(
+	triggerService *TriggerService,
+	triggerExecutor *TriggerExecutor,
+	exchangeService exchange_service.ExchangeService,
+	tickers *TickerLocks,
+	forecastService weather_service.ForecastService,
+	location *time.Location,
+) *ForecastTriggerEvaluator {
+	return &ForecastTriggerEvaluator{
+		triggerService:  triggerService,
+		triggerExecutor: triggerExecutor,
+		exchangeService: exchangeService,
+		tickers:         tickers,
+		forecastService: forecastService,
+		location:        location,
+		now:             time.Now,
+	}
+}
+
+// HandleForecast implements weather_service.ForecastHandler
+func (e *ForecastTriggerEvaluator) HandleForecast(ctx context.Context, forecast *weather_domain.TemperatureForecast) error {
+	if e.now().Sub(forecast.GeneratedAt) > maxForecastAge {
+		log.Printf("Ignoring forecast for %s generated at %v", forecast.Gridpoint, forecast.GeneratedAt)
+		return nil
+	}
+
+	triggers, err := e.triggerService.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("getting triggers: %w", err)
+	}
+	watching := lo.Filter(triggers, func(t *trigger_domain.Trigger, _ int) bool {
+		return t.Status == trigger_domain.StatusActive &&
+			slices.Contains(t.Condition.Gridpoints(), forecast.Gridpoint)
+	})
+	if len(watching) == 0 {
+		return nil
+	}
+	log.Printf("Evaluating %d forecast triggers for gridpoint %s", len(watching), forecast.Gridpoint)
+
+	var evaluationErrors []error
+	for _, trigger := range watching {
+		if err := e.evaluateTrigger(ctx, trigger, forecast); err != nil {
+			evaluationErrors = append(evaluationErrors, fmt.Errorf("trigger %s: %w", trigger.TriggerID, err))
+		}
+	}
+
+	return errors.Join(evaluationErrors...)
+}
+
+func (e *ForecastTriggerEvaluator) evaluateTrigger(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	forecast *weather_domain.TemperatureForecast,
+) error {
+	// Conditions may also reference other gridpoints or market prices
+	data, err := fetchMarketData(ctx, e.exchangeService, []*trigger_domain.Trigger{trigger})
+	if err != nil {
+		return err
+	}
+	// The trigger monitor and order tracker may be trading the same tickers
+	unlock := e.tickers.Lock(data.tickersActedOn(trigger)...)
+	defer unlock()
+
+	snapshot, err := data.snapshot(trigger, e.now())
+	if err != nil {
+		return err
+	}
+
+	// Stop watching markets that can no longer trade
+	if expiry := snapshot.Expiry(); expiry != nil {
+		_, err := e.triggerService.ExpireTrigger(ctx, trigger, *expiry)
+		return err
+	}
+	snapshot.Forecasts[forecast.Gridpoint] = e.getGridpointForecast(forecast)
+
+	for _, gridpoint := range trigger.Condition.Gridpoints() {
+		if gridpoint == forecast.Gridpoint {
+			continue
+		}
+		latest, err := e.forecastService.GetHourlyForecast(gridpoint)
+		if err != nil {
+			return fmt.Errorf("get hourly forecast for %s: %w", gridpoint, err)
+		}
+		snapshot.Forecasts[gridpoint] = e.getGridpointForecast(latest)
+	}
+
+	isSatisfied, err := trigger.Condition.IsSatisfied(snapshot)
+	if err != nil {
+		return err
+	}
+
+	// Wait out the confirmation policy, if any, before firing
+	confirmed, err := e.triggerService.ConfirmTrigger(ctx, trigger, isSatisfied, snapshot.Time)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	executedTrigger, err := e.triggerExecutor.ExecuteTrigger(ctx, trigger)
+	if err != nil {
+		return err
+	}
+	log.Printf("Executed forecast trigger %s", executedTrigger.TriggerID)
+
+	return nil
+}
+
+func (e *ForecastTriggerEvaluator) getGridpointForecast(
+	forecast *weather_domain.TemperatureForecast,
+) trigger_domain.GridpointForecast {
+	return trigger_domain.GridpointForecast{
+		TemperatureForecast: *forecast,
+		Location:            e.location,
+	}
+}
@@ -0,0 +1,206 @@
+package trigger_service
+
+import (
+	"context"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_mock "prediction-risk/internal/app/risk/trigger/mock"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	weather_mocks "prediction-risk/internal/app/weather/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecastTriggerEvaluator_HandleForecast(t *testing.T) {
+	bracket := contract.ContractIdentifier{Ticker: "KXHIGHNY-25JUL01-T80", Side: contract.SideYes}
+	centralPark := weather_domain.Gridpoint{OfficeID: "OKX", X: 33, Y: 37}
+	est := time.FixedZone("EST", -5*60*60)
+	now := time.Date(2025, 7, 1, 9, 0, 0, 0, est)
+
+	// Sell the ≥80°F bracket if today's forecast high drops to 78°F or below
+	newForecastTrigger := func(t *testing.T) *trigger_domain.Trigger {
+		condition, err := trigger_domain.NewForecastCondition(
+			centralPark,
+			trigger_domain.ForecastDailyHigh,
+			0,
+			weather_domain.Temperature{Value: 78, TemperatureUnit: weather_domain.Fahrenheit},
+			trigger_domain.Below,
+		)
+		require.NoError(t, err)
+		action, err := trigger_domain.NewTriggerAction(bracket, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+		trigger, err := trigger_domain.NewConditionalTrigger(*condition, []trigger_domain.TriggerAction{*action})
+		require.NoError(t, err)
+		return trigger
+	}
+
+	newForecast := func(gridpoint weather_domain.Gridpoint, generatedAt time.Time, temperatures ...float64) *weather_domain.TemperatureForecast {
+		periods := make([]weather_domain.ForecastPeriod, 0, len(temperatures))
+		for i, temperature := range temperatures {
+			start := now.Add(time.Duration(i) * time.Hour)
+			periods = append(periods, weather_domain.ForecastPeriod{
+				StartTime:   start,
+				EndTime:     start.Add(time.Hour),
+				Temperature: weather_domain.Temperature{Value: temperature, TemperatureUnit: weather_domain.Fahrenheit},
+			})
+		}
+		return &weather_domain.TemperatureForecast{Gridpoint: gridpoint, Periods: periods, GeneratedAt: generatedAt}
+	}
+
+	openMarket := &exchange_domain.Market{
+		Ticker: bracket.Ticker,
+		Status: exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+	}
+
+	tests := []struct {
+		name          string
+		temperatures  []float64
+		expectExecute bool
+	}{
+		{name: "forecast high drops below threshold", temperatures: []float64{74, 77, 76}, expectExecute: true},
+		{name: "forecast high above threshold", temperatures: []float64{74, 81, 76}, expectExecute: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger := newForecastTrigger(t)
+			forecast := newForecast(centralPark, now.Add(-30*time.Minute), tt.temperatures...)
+
+			repo := new(trigger_mock.MockTriggerRepository)
+			repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{trigger}, nil)
+			exchange := new(trigger_mock.MockExchangeService)
+			exchange.On("GetMarkets", mock.Anything, []contract.Ticker{bracket.Ticker}).Return([]*exchange_domain.Market{openMarket}, nil)
+
+			if tt.expectExecute {
+				exchange.On("CreateOrder", mock.Anything, mock.Anything).Return(&exchange_domain.Order{}, nil).Once()
+				repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+				repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+					return t.Status == trigger_domain.StatusExecuting
+				})).Return(nil).Once()
+				repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+					return t.Status == trigger_domain.StatusTriggered
+				})).Return(nil).Once()
+				repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Twice()
+				repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			triggerService := NewTriggerService(repo)
+			evaluator := NewForecastTriggerEvaluator(
+				triggerService,
+				NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil),
+				exchange,
+				NewTickerLocks(),
+				new(weather_mocks.MockForecastService),
+				est,
+			)
+			evaluator.now = func() time.Time { return now }
+
+			err := evaluator.HandleForecast(context.Background(), forecast)
+
+			require.NoError(t, err)
+			if !tt.expectExecute {
+				exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+			}
+			repo.AssertExpectations(t)
+			exchange.AssertExpectations(t)
+		})
+	}
+
+	t.Run("fetches other gridpoints in the condition", func(t *testing.T) {
+		laGuardia := weather_domain.Gridpoint{OfficeID: "OKX", X: 37, Y: 39}
+		coolHere, err := trigger_domain.NewForecastCondition(centralPark, trigger_domain.ForecastDailyHigh, 0,
+			weather_domain.Temperature{Value: 78, TemperatureUnit: weather_domain.Fahrenheit}, trigger_domain.Below)
+		require.NoError(t, err)
+		coolThere, err := trigger_domain.NewForecastCondition(laGuardia, trigger_domain.ForecastDailyHigh, 0,
+			weather_domain.Temperature{Value: 78, TemperatureUnit: weather_domain.Fahrenheit}, trigger_domain.Below)
+		require.NoError(t, err)
+		condition, err := trigger_domain.NewCompositeCondition(trigger_domain.OperatorAnd, *coolHere, *coolThere)
+		require.NoError(t, err)
+		action, err := trigger_domain.NewTriggerAction(bracket, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+		trigger, err := trigger_domain.NewConditionalTrigger(*condition, []trigger_domain.TriggerAction{*action})
+		require.NoError(t, err)
+
+		repo := new(trigger_mock.MockTriggerRepository)
+		repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{trigger}, nil)
+		exchange := new(trigger_mock.MockExchangeService)
+		exchange.On("GetMarkets", mock.Anything, []contract.Ticker{bracket.Ticker}).Return([]*exchange_domain.Market{openMarket}, nil)
+		forecasts := new(weather_mocks.MockForecastService)
+		forecasts.On("GetHourlyForecast", laGuardia).Return(newForecast(laGuardia, now, 79, 80), nil).Once()
+
+		triggerService := NewTriggerService(repo)
+		evaluator := NewForecastTriggerEvaluator(
+			triggerService,
+			NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil),
+			exchange,
+			NewTickerLocks(),
+			forecasts,
+			est,
+		)
+		evaluator.now = func() time.Time { return now }
+
+		require.NoError(t, evaluator.HandleForecast(context.Background(), newForecast(centralPark, now, 74, 77)))
+		exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+		forecasts.AssertExpectations(t)
+	})
+
+	t.Run("expires trigger when market settled", func(t *testing.T) {
+		trigger := newForecastTrigger(t)
+
+		result := "no"
+		repo := new(trigger_mock.MockTriggerRepository)
+		repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{trigger}, nil)
+		repo.On("Expire", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.Status == trigger_domain.StatusExpired &&
+				t.Expiry.Reason == trigger_domain.ExpiryReasonMarketSettled
+		}), trigger_domain.StatusActive).Return(nil).Once()
+		repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+		exchange := new(trigger_mock.MockExchangeService)
+		exchange.On("GetMarkets", mock.Anything, []contract.Ticker{bracket.Ticker}).Return([]*exchange_domain.Market{{
+			Ticker: bracket.Ticker,
+			Status: exchange_domain.MarketStatus{State: exchange_domain.MarketStateSettled, Result: &result},
+		}}, nil)
+
+		triggerService := NewTriggerService(repo)
+		evaluator := NewForecastTriggerEvaluator(
+			triggerService,
+			NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil),
+			exchange,
+			NewTickerLocks(),
+			new(weather_mocks.MockForecastService),
+			est,
+		)
+		evaluator.now = func() time.Time { return now }
+
+		require.NoError(t, evaluator.HandleForecast(context.Background(), newForecast(centralPark, now, 70)))
+		exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ignores stale forecasts", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		evaluator := NewForecastTriggerEvaluator(NewTriggerService(repo), nil, nil, nil, nil, est)
+		evaluator.now = func() time.Time { return now }
+
+		assert.NoError(t, evaluator.HandleForecast(context.Background(), newForecast(centralPark, now.Add(-24*time.Hour), 70)))
+		repo.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+
+	t.Run("ignores other gridpoints", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{newForecastTrigger(t)}, nil)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		evaluator := NewForecastTriggerEvaluator(NewTriggerService(repo), nil, exchange, nil, nil, est)
+		evaluator.now = func() time.Time { return now }
+
+		other := weather_domain.Gridpoint{OfficeID: "LOX", X: 1, Y: 1}
+		assert.NoError(t, evaluator.HandleForecast(context.Background(), newForecast(other, now, 70)))
+		exchange.AssertNotCalled(t, "GetMarkets", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,63 @@
+package trigger_service
+
+import "sync"
+
+// keyedMutex serializes work per key while work on different keys runs in parallel. A key's
+// lock is dropped once nothing holds or waits on it, so keys that come and go do not pile up.
+type keyedMutex[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	refs int // holders and waiters
+}
+
+func newKeyedMutex[K comparable]() *keyedMutex[K] {
+	return &keyedMutex[K]{locks: make(map[K]*keyedLock)}
+}
+
+// Lock blocks until the key is free and returns the function that frees it
+func (k *keyedMutex[K]) Lock(key K) func() {
+	k.mu.Lock()
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		k.locks[key] = lock
+	}
+	lock.refs++
+	k.mu.Unlock()
+
+	lock.Lock()
+	return k.unlocker(key, lock)
+}
+
+// TryLock takes the key only if it is free, returning the function that frees it and
+// whether it was taken
+func (k *keyedMutex[K]) TryLock(key K) (func(), bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		k.locks[key] = lock
+	}
+	if !lock.TryLock() {
+		return nil, false
+	}
+	lock.refs++
+	return k.unlocker(key, lock), true
+}
+
+func (k *keyedMutex[K]) unlocker(key K, lock *keyedLock) func() {
+	return func() {
+		lock.Unlock()
+		k.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
@@ -0,0 +1,58 @@
+package trigger_service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	t.Run("serializes work on the same key", func(t *testing.T) {
+		locks := newKeyedMutex[string]()
+
+		var mu sync.Mutex
+		running, maxRunning := 0, 0
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock := locks.Lock("FOO")
+				defer unlock()
+
+				mu.Lock()
+				running++
+				maxRunning = max(maxRunning, running)
+				mu.Unlock()
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				running--
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, maxRunning)
+		assert.Empty(t, locks.locks)
+	})
+
+	t.Run("different keys do not block each other", func(t *testing.T) {
+		locks := newKeyedMutex[string]()
+		unlock := locks.Lock("FOO")
+		defer unlock()
+
+		acquired := make(chan struct{})
+		go func() {
+			locks.Lock("BAR")()
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("lock on another key was held up")
+		}
+	})
+}
@@ -0,0 +1,271 @@
+package trigger_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	exchange_service "prediction-risk/internal/app/exchange/service"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	"sync"
+	"time"
+)
+
+// maxContractPrice is the most a contract can cost in cents, used to cap market buys that
+// replace an unfilled remainder
+const maxContractPrice = 100
+
+// OrderTracker follows the orders triggers have sent until they are done. It records each
+// order's status and fills as the exchange reports them, and once an order has rested past
+// its trigger's fill timeout it cancels the remainder and replaces it as the policy says.
+// Triggers pending on another trigger's fills are armed once those orders are done.
+type OrderTracker struct {
+	triggerService  *TriggerService
+	exchangeService exchange_service.ExchangeService
+	tickers         *TickerLocks
+	interval        time.Duration
+	done            chan struct{}
+	cancel          context.CancelFunc
+	running         sync.WaitGroup
+	now             func() time.Time
+}
+
+func NewOrderTracker(
+	triggerService *TriggerService,
+	exchangeService exchange_service.ExchangeService,
+	tickers *TickerLocks,
+	interval time.Duration,
+) *OrderTracker {
+	log.Printf("Initializing OrderTracker with interval: %v", interval)
+	return &OrderTracker{
+		triggerService:  triggerService,
+		exchangeService: exchangeService,
+		tickers:         tickers,
+		interval:        interval,
+		done:            make(chan struct{}),
+		now:             time.Now,
+	}
+}
+
+func (m *OrderTracker) Start() {
+	log.Println("Starting OrderTracker")
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.running.Add(1)
+	go func() {
+		defer m.running.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.done:
+				log.Println("OrderTracker stopped")
+				return
+			case <-ticker.C:
+				if err := m.trackOrders(ctx); err != nil {
+					log.Printf("Error during order tracking: %v", err)
+				}
+				// Fills recorded above may complete an entry, arming the exits waiting on it
+				if err := m.triggerService.ArmPendingTriggers(ctx); err != nil {
+					log.Printf("Error arming pending triggers: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop cancels the pass in progress and waits for it to return. A follow-up cut short is
+// finished on the next start.
+func (m *OrderTracker) Stop() {
+	log.Println("Stopping OrderTracker...")
+	if m.cancel != nil {
+		m.cancel()
+	}
+	close(m.done)
+	m.running.Wait()
+}
+
+func (m *OrderTracker) trackOrders(ctx context.Context) error {
+	tracked, err := m.triggerService.GetOpenOrders(ctx)
+	if err != nil {
+		return fmt.Errorf("getting open orders: %w", err)
+	}
+	if len(tracked) == 0 {
+		return nil
+	}
+
+	// Open orders come oldest first, so the first bounds the exchange's listing
+	reported, err := m.exchangeService.GetOrders(ctx, tracked[0].Order.CreatedAt.Add(-orderClockSkew))
+	if err != nil {
+		return fmt.Errorf("getting orders: %w", err)
+	}
+	byExchangeID := make(map[string]*exchange_domain.Order, len(reported))
+	byReference := make(map[string]*exchange_domain.Order, len(reported))
+	for _, order := range reported {
+		byExchangeID[order.ExchangeOrderID] = order
+		byReference[order.Reference] = order
+	}
+	log.Printf("Tracking %d open orders", len(tracked))
+
+	triggers := make(map[trigger_domain.TriggerID]*trigger_domain.Trigger)
+	var errs []error
+	for _, triggerOrder := range tracked {
+		if order, ok := byExchangeID[triggerOrder.Order.ExchangeOrderID]; ok && m.applyReport(&triggerOrder.Order, order) {
+			log.Printf("Order %s for trigger %s is %s, %d of %d filled",
+				triggerOrder.Order.ExchangeOrderID, triggerOrder.TriggerID, triggerOrder.Order.Status,
+				triggerOrder.Order.FilledQuantity, triggerOrder.Order.Quantity)
+			// The report is applied in memory, so the order is still followed up below. The
+			// stored order stays stale until a later pass, which sees the change again.
+			if err := m.triggerService.RecordOrders(ctx, []trigger_domain.TriggerOrder{triggerOrder}); err != nil {
+				errs = append(errs, fmt.Errorf("record order %s: %w", triggerOrder.Order.ExchangeOrderID, err))
+			}
+		}
+		// A follow-up interrupted on an earlier pass is finished before anything else
+		if triggerOrder.FollowUp != nil {
+			if err := m.followUp(ctx, triggerOrder, byReference); err != nil {
+				errs = append(errs, fmt.Errorf("follow up order %s: %w", triggerOrder.Order.ExchangeOrderID, err))
+			}
+			continue
+		}
+		if !triggerOrder.Order.IsOpen() {
+			continue
+		}
+
+		trigger, ok := triggers[triggerOrder.TriggerID]
+		if !ok {
+			trigger, err = m.triggerService.GetByID(ctx, triggerOrder.TriggerID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("get trigger %s: %w", triggerOrder.TriggerID, err))
+				continue
+			}
+			triggers[triggerOrder.TriggerID] = trigger
+		}
+		if trigger.FillPolicy == nil || !trigger.FillPolicy.Due(triggerOrder.Order, m.now()) {
+			continue
+		}
+
+		// Stored before the order is cancelled, so if anything below fails the next pass
+		// still replaces the remainder
+		triggerOrder.StartFollowUp(trigger.FillPolicy.Action)
+		if err := m.triggerService.RecordOrders(ctx, []trigger_domain.TriggerOrder{triggerOrder}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := m.followUp(ctx, triggerOrder, byReference); err != nil {
+			errs = append(errs, fmt.Errorf("follow up order %s: %w", triggerOrder.Order.ExchangeOrderID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// followUp carries out the order's pending follow-up: it cancels what is left of the order
+// and places the remainder again, either at the current price or at market. The follow-up
+// is only cleared together with the replacement, so a step that fails is taken again on
+// the next pass, and a replacement the exchange already has, found by its reference, is
+// recorded rather than sent twice. The order's ticker is held throughout, so no trigger
+// trades it in between.
+func (m *OrderTracker) followUp(
+	ctx context.Context,
+	triggerOrder trigger_domain.TriggerOrder,
+	byReference map[string]*exchange_domain.Order,
+) error {
+	unlock := m.tickers.Lock(contract.Ticker(triggerOrder.Order.Ticker))
+	defer unlock()
+
+	pending := triggerOrder.FollowUp
+	order := triggerOrder.Order
+	if order.IsOpen() {
+		canceled, err := m.exchangeService.CancelOrder(ctx, order.ExchangeOrderID)
+		if err != nil {
+			return fmt.Errorf("cancel order: %w", err)
+		}
+		m.applyReport(&triggerOrder.Order, canceled)
+		order = triggerOrder.Order
+	}
+
+	// The order may have filled before the cancel reached the exchange
+	pending.Remaining = order.Remaining()
+	if pending.Remaining == 0 {
+		triggerOrder.FollowUp = nil
+		return m.triggerService.RecordOrders(ctx, []trigger_domain.TriggerOrder{triggerOrder})
+	}
+	if err := m.triggerService.RecordOrders(ctx, []trigger_domain.TriggerOrder{triggerOrder}); err != nil {
+		return err
+	}
+
+	reference := triggerOrder.TriggerID.FollowUpReference(triggerOrder.ActionIndex, pending.Attempt)
+	replacement, sent := byReference[reference]
+	if !sent {
+		var err error
+		replacement, err = m.replaceRemainder(ctx, order, reference, *pending)
+		if err != nil {
+			return err
+		}
+		log.Printf("Replaced remainder of order %s for trigger %s with %s order %s for %d",
+			order.ExchangeOrderID, triggerOrder.TriggerID, pending.Action, replacement.ExchangeOrderID, pending.Remaining)
+	}
+
+	triggerOrder.FollowUp = nil
+	return m.triggerService.RecordOrders(ctx, []trigger_domain.TriggerOrder{triggerOrder, {
+		TriggerID:   triggerOrder.TriggerID,
+		ActionIndex: triggerOrder.ActionIndex,
+		Attempt:     pending.Attempt,
+		Order:       *replacement,
+	}})
+}
+
+// replaceRemainder sends the order replacing the remainder of order
+func (m *OrderTracker) replaceRemainder(
+	ctx context.Context,
+	order exchange_domain.Order,
+	reference string,
+	pending trigger_domain.PendingFollowUp,
+) (*exchange_domain.Order, error) {
+	var market *exchange_domain.Market
+	if pending.Action == trigger_domain.FillActionReprice {
+		var err error
+		market, err = m.exchangeService.GetMarket(ctx, contract.Ticker(order.Ticker))
+		if err != nil {
+			return nil, fmt.Errorf("get market: %w", err)
+		}
+	}
+	limitPrice, err := pending.Action.FollowUpLimit(order, market)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := pending.Remaining
+	orderParams := exchange_service.OrderParams{
+		ContractID: contract.ContractIdentifier{Ticker: contract.Ticker(order.Ticker), Side: order.Side},
+		Quantity:   &remaining,
+		Action:     order.Action,
+		Reference:  reference,
+		LimitPrice: limitPrice,
+	}
+	if order.Action == exchange_domain.OrderActionBuy && limitPrice == nil {
+		maxCost := int(remaining) * maxContractPrice
+		orderParams.MaxCost = &maxCost
+	}
+
+	replacement, err := m.exchangeService.CreateOrder(ctx, orderParams)
+	if err != nil {
+		return nil, fmt.Errorf("replace remainder of %d: %w", remaining, err)
+	}
+	return replacement, nil
+}
+
+// applyReport copies the exchange's view of an order onto the tracked one, reporting
+// whether it changed
+func (m *OrderTracker) applyReport(order *exchange_domain.Order, reported *exchange_domain.Order) bool {
+	if order.Status == reported.Status && order.FilledQuantity == reported.FilledQuantity {
+		return false
+	}
+	order.Status = reported.Status
+	order.FilledQuantity = reported.FilledQuantity
+	order.UpdatedAt = m.now()
+	return true
+}
@@ -0,0 +1,372 @@
+package trigger_service
+
+import (
+	"context"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	exchange_service "prediction-risk/internal/app/exchange/service"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_mock "prediction-risk/internal/app/risk/trigger/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderTracker_trackOrders(t *testing.T) {
+	now := time.Date(2025, 2, 23, 12, 0, 0, 0, time.UTC)
+	stop, err := trigger_domain.NewStopTrigger(
+		contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB23-T40", Side: contract.SideYes}, 30, nil,
+	)
+	require.NoError(t, err)
+
+	limitPrice := contract.ContractPrice(30)
+	restingOrder := func(createdAt time.Time) trigger_domain.TriggerOrder {
+		return trigger_domain.TriggerOrder{
+			TriggerID:   stop.TriggerID,
+			ActionIndex: 0,
+			Order: exchange_domain.Order{
+				OrderID:         exchange_domain.NewOrderID(),
+				ExchangeOrderID: "order-1",
+				Exchange:        exchange_domain.ExchangeKalshi,
+				Reference:       stop.TriggerID.OrderReference(0),
+				Ticker:          "KXHIGHNY-25FEB23-T40",
+				Side:            contract.SideYes,
+				Action:          exchange_domain.OrderActionSell,
+				OrderType:       exchange_domain.OrderTypeLimit,
+				Quantity:        10,
+				LimitPrice:      &limitPrice,
+				Status:          exchange_domain.OrderStatusResting,
+				CreatedAt:       createdAt,
+				UpdatedAt:       createdAt,
+			},
+		}
+	}
+	withPolicy := func(policy *trigger_domain.FillPolicy) *trigger_domain.Trigger {
+		trigger := *stop
+		trigger.Status = trigger_domain.StatusTriggered
+		trigger.FillPolicy = policy
+		return &trigger
+	}
+	newTracker := func(repo *trigger_mock.MockTriggerRepository, exchange *trigger_mock.MockExchangeService) *OrderTracker {
+		tracker := NewOrderTracker(NewTriggerService(repo), exchange, NewTickerLocks(), time.Second)
+		tracker.now = func() time.Time { return now }
+		return tracker
+	}
+
+	t.Run("records fills reported by the exchange", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		tracked := restingOrder(now.Add(-10 * time.Second))
+		repo.On("GetOpenOrders", mock.Anything).Return([]trigger_domain.TriggerOrder{tracked}, nil)
+		exchange.On("GetOrders", mock.Anything, tracked.Order.CreatedAt.Add(-orderClockSkew)).Return([]*exchange_domain.Order{
+			{ExchangeOrderID: "order-1", Status: exchange_domain.OrderStatusPartiallyFilled, Quantity: 10, FilledQuantity: 4},
+		}, nil)
+		var recorded []trigger_domain.TriggerOrder
+		repo.On("PersistOrders", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			recorded = args.Get(1).([]trigger_domain.TriggerOrder)
+		}).Return(nil).Once()
+		repo.On("Get", mock.Anything, stop.TriggerID).Return(withPolicy(nil), nil)
+
+		require.NoError(t, newTracker(repo, exchange).trackOrders(context.Background()))
+
+		require.Len(t, recorded, 1)
+		assert.Equal(t, tracked.Order.OrderID, recorded[0].Order.OrderID)
+		assert.Equal(t, exchange_domain.OrderStatusPartiallyFilled, recorded[0].Order.Status)
+		assert.Equal(t, uint(4), recorded[0].Order.FilledQuantity)
+		assert.Equal(t, now, recorded[0].Order.UpdatedAt)
+		exchange.AssertNotCalled(t, "CancelOrder", mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("leaves finished orders alone", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		tracked := restingOrder(now.Add(-time.Hour))
+		repo.On("GetOpenOrders", mock.Anything).Return([]trigger_domain.TriggerOrder{tracked}, nil)
+		exchange.On("GetOrders", mock.Anything, mock.Anything).Return([]*exchange_domain.Order{
+			{ExchangeOrderID: "order-1", Status: exchange_domain.OrderStatusExecuted, Quantity: 10, FilledQuantity: 10},
+		}, nil)
+		repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Once()
+
+		require.NoError(t, newTracker(repo, exchange).trackOrders(context.Background()))
+
+		repo.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+		exchange.AssertNotCalled(t, "CancelOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reprices the remainder once the timeout passes", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		tracked := restingOrder(now.Add(-2 * time.Minute))
+		tracked.Order.Status = exchange_domain.OrderStatusPartiallyFilled
+		tracked.Order.FilledQuantity = 4
+		repo.On("GetOpenOrders", mock.Anything).Return([]trigger_domain.TriggerOrder{tracked}, nil)
+		exchange.On("GetOrders", mock.Anything, mock.Anything).Return([]*exchange_domain.Order{
+			{ExchangeOrderID: "order-1", Status: exchange_domain.OrderStatusPartiallyFilled, Quantity: 10, FilledQuantity: 4},
+		}, nil)
+		repo.On("Get", mock.Anything, stop.TriggerID).Return(
+			withPolicy(&trigger_domain.FillPolicy{Timeout: time.Minute, Action: trigger_domain.FillActionReprice}), nil,
+		)
+		exchange.On("CancelOrder", mock.Anything, "order-1").Return(&exchange_domain.Order{
+			ExchangeOrderID: "order-1", Status: exchange_domain.OrderStatusCanceled, Quantity: 10, FilledQuantity: 4,
+		}, nil)
+		exchange.On("GetMarket", mock.Anything, contract.Ticker("KXHIGHNY-25FEB23-T40")).Return(&exchange_domain.Market{
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Bid: 27, Ask: 29}},
+		}, nil)
+
+		var params exchange_service.OrderParams
+		exchange.On("CreateOrder", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			params = args.Get(1).(exchange_service.OrderParams)
+		}).Return(&exchange_domain.Order{ExchangeOrderID: "order-2", Status: exchange_domain.OrderStatusResting, Quantity: 6}, nil)
+
+		var recorded [][]trigger_domain.TriggerOrder
+		repo.On("PersistOrders", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			recorded = append(recorded, args.Get(1).([]trigger_domain.TriggerOrder))
+		}).Return(nil)
+
+		require.NoError(t, newTracker(repo, exchange).trackOrders(context.Background()))
+
+		require.NotNil(t, params.Quantity)
+		assert.Equal(t, uint(6), *params.Quantity)
+		assert.Equal(t, exchange_domain.OrderActionSell, params.Action)
+		assert.Equal(t, stop.TriggerID.FollowUpReference(0, 1), params.Reference)
+		require.NotNil(t, params.LimitPrice)
+		assert.Equal(t, contract.ContractPrice(27), *params.LimitPrice)
+		assert.Nil(t, params.MaxCost)
+
+		// The follow-up is stored before the cancel and cleared with the replacement
+		require.Len(t, recorded, 3)
+		require.NotNil(t, recorded[0][0].FollowUp)
+		assert.Equal(t, 1, recorded[0][0].FollowUp.Attempt)
+		assert.Equal(t, trigger_domain.FillActionReprice, recorded[0][0].FollowUp.Action)
+		assert.Equal(t, exchange_domain.OrderStatusCanceled, recorded[1][0].Order.Status)
+		assert.Equal(t, uint(4), recorded[1][0].Order.FilledQuantity)
+		assert.Equal(t, uint(6), recorded[1][0].FollowUp.Remaining)
+		require.Len(t, recorded[2], 2)
+		assert.Nil(t, recorded[2][0].FollowUp)
+		assert.Equal(t, "order-2", recorded[2][1].Order.ExchangeOrderID)
+		assert.Equal(t, 0, recorded[2][1].ActionIndex)
+		assert.Equal(t, 1, recorded[2][1].Attempt)
+	})
+
+	t.Run("applies the fill policy when recording a fill fails", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		tracked := restingOrder(now.Add(-2 * time.Minute))
+		repo.On("GetOpenOrders", mock.Anything).Return([]trigger_domain.TriggerOrder{tracked}, nil)
+		exchange.On("GetOrders", mock.Anything, mock.Anything).Return([]*exchange_domain.Order{
+			{ExchangeOrderID: "order-1", Status: exchange_domain.OrderStatusPartiallyFilled, Quantity: 10, FilledQuantity: 4},
+		}, nil)
+		repo.On("PersistOrders", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+		repo.On("Get", mock.Anything, stop.TriggerID).Return(
+			withPolicy(&trigger_domain.FillPolicy{Timeout: time.Minute, Action: trigger_domain.FillActionMarket}), nil,
+		)
+		exchange.On("CancelOrder", mock.Anything, "order-1").Return(&exchange_domain.Order{
+			ExchangeOrderID: "order-1", Status: exchange_domain.OrderStatusCanceled, Quantity: 10, FilledQuantity: 4,
+		}, nil)
+		var params exchange_service.OrderParams
+		exchange.On("CreateOrder", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			params = args.Get(1).(exchange_service.OrderParams)
+		}).Return(&exchange_domain.Order{ExchangeOrderID: "order-2", Status: exchange_domain.OrderStatusExecuted}, nil)
+		repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Times(3)
+
+		err := newTracker(repo, exchange).trackOrders(context.Background())
+
+		assert.ErrorIs(t, err, assert.AnError)
+		// Only the unfilled remainder is replaced
+		require.NotNil(t, params.Quantity)
+		assert.Equal(t, uint(6), *params.Quantity)
+		repo.AssertExpectations(t)
+		exchange.AssertExpectations(t)
+	})
+
+	t.Run("converts a buy remainder to a capped market order", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		tracked := restingOrder(now.Add(-2 * time.Minute))
+		tracked.ActionIndex = 1
+		tracked.Attempt = 1
+		tracked.Order.Action = exchange_domain.OrderActionBuy
+		repo.On("GetOpenOrders", mock.Anything).Return([]trigger_domain.TriggerOrder{tracked}, nil)
+		exchange.On("GetOrders", mock.Anything, mock.Anything).Return([]*exchange_domain.Order{}, nil)
+		repo.On("Get", mock.Anything, stop.TriggerID).Return(
+			withPolicy(&trigger_domain.FillPolicy{Timeout: time.Minute, Action: trigger_domain.FillActionMarket}), nil,
+		)
+		exchange.On("CancelOrder", mock.Anything, "order-1").Return(&exchange_domain.Order{
+			ExchangeOrderID: "order-1", Status: exchange_domain.OrderStatusCanceled, Quantity: 10,
+		}, nil)
+
+		var params exchange_service.OrderParams
+		exchange.On("CreateOrder", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			params = args.Get(1).(exchange_service.OrderParams)
+		}).Return(&exchange_domain.Order{ExchangeOrderID: "order-2", Status: exchange_domain.OrderStatusExecuted}, nil)
+		repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Times(3)
+
+		require.NoError(t, newTracker(repo, exchange).trackOrders(context.Background()))
+
+		exchange.AssertNotCalled(t, "GetMarket", mock.Anything, mock.Anything)
+		assert.Nil(t, params.LimitPrice)
+		require.NotNil(t, params.MaxCost)
+		assert.Equal(t, 1000, *params.MaxCost)
+		assert.Equal(t, stop.TriggerID.FollowUpReference(1, 2), params.Reference)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("places nothing when the cancel finds the order filled", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		tracked := restingOrder(now.Add(-2 * time.Minute))
+		repo.On("GetOpenOrders", mock.Anything).Return([]trigger_domain.TriggerOrder{tracked}, nil)
+		exchange.On("GetOrders", mock.Anything, mock.Anything).Return([]*exchange_domain.Order{}, nil)
+		repo.On("Get", mock.Anything, stop.TriggerID).Return(
+			withPolicy(&trigger_domain.FillPolicy{Timeout: time.Minute, Action: trigger_domain.FillActionMarket}), nil,
+		)
+		exchange.On("CancelOrder", mock.Anything, "order-1").Return(&exchange_domain.Order{
+			ExchangeOrderID: "order-1", Status: exchange_domain.OrderStatusExecuted, Quantity: 10, FilledQuantity: 10,
+		}, nil)
+		var recorded []trigger_domain.TriggerOrder
+		repo.On("PersistOrders", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			recorded = args.Get(1).([]trigger_domain.TriggerOrder)
+		}).Return(nil).Twice()
+
+		require.NoError(t, newTracker(repo, exchange).trackOrders(context.Background()))
+
+		exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+		require.Len(t, recorded, 1)
+		assert.Nil(t, recorded[0].FollowUp)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("finishes a follow-up interrupted on an earlier pass", func(t *testing.T) {
+		// The order was cancelled but the pass stopped before its replacement was recorded
+		interrupted := func() trigger_domain.TriggerOrder {
+			tracked := restingOrder(now.Add(-2 * time.Minute))
+			tracked.StartFollowUp(trigger_domain.FillActionMarket)
+			return tracked
+		}
+		canceled := &exchange_domain.Order{
+			ExchangeOrderID: "order-1", Status: exchange_domain.OrderStatusCanceled, Quantity: 10, FilledQuantity: 4,
+		}
+
+		tests := []struct {
+			name      string
+			reported  []*exchange_domain.Order
+			createErr error
+			sends     bool
+			replaced  bool
+		}{
+			{
+				name:     "sends the replacement",
+				reported: []*exchange_domain.Order{canceled},
+				sends:    true,
+				replaced: true,
+			},
+			{
+				name: "records a replacement the exchange already has",
+				reported: []*exchange_domain.Order{canceled, {
+					ExchangeOrderID: "order-2",
+					Reference:       stop.TriggerID.FollowUpReference(0, 1),
+					Status:          exchange_domain.OrderStatusResting,
+					Quantity:        6,
+				}},
+				replaced: true,
+			},
+			{
+				name:      "keeps the follow-up pending when the replacement fails",
+				reported:  []*exchange_domain.Order{canceled},
+				createErr: assert.AnError,
+				sends:     true,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := new(trigger_mock.MockTriggerRepository)
+				exchange := new(trigger_mock.MockExchangeService)
+
+				repo.On("GetOpenOrders", mock.Anything).Return([]trigger_domain.TriggerOrder{interrupted()}, nil)
+				exchange.On("GetOrders", mock.Anything, mock.Anything).Return(tt.reported, nil)
+				var params exchange_service.OrderParams
+				exchange.On("CreateOrder", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					params = args.Get(1).(exchange_service.OrderParams)
+				}).Return(&exchange_domain.Order{ExchangeOrderID: "order-2"}, tt.createErr).Maybe()
+				var recorded []trigger_domain.TriggerOrder
+				repo.On("PersistOrders", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					recorded = args.Get(1).([]trigger_domain.TriggerOrder)
+				}).Return(nil)
+
+				err := newTracker(repo, exchange).trackOrders(context.Background())
+
+				exchange.AssertNotCalled(t, "CancelOrder", mock.Anything, mock.Anything)
+				repo.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+				if tt.sends {
+					require.NotNil(t, params.Quantity)
+					assert.Equal(t, uint(6), *params.Quantity)
+					assert.Equal(t, stop.TriggerID.FollowUpReference(0, 1), params.Reference)
+				} else {
+					exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+				}
+				if !tt.replaced {
+					assert.ErrorIs(t, err, assert.AnError)
+					require.Len(t, recorded, 1)
+					require.NotNil(t, recorded[0].FollowUp)
+					assert.Equal(t, uint(6), recorded[0].FollowUp.Remaining)
+					return
+				}
+				require.NoError(t, err)
+				require.Len(t, recorded, 2)
+				assert.Nil(t, recorded[0].FollowUp)
+				assert.Equal(t, "order-2", recorded[1].Order.ExchangeOrderID)
+				assert.Equal(t, 1, recorded[1].Attempt)
+			})
+		}
+	})
+
+	t.Run("waits for the timeout and for a policy", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			age    time.Duration
+			policy *trigger_domain.FillPolicy
+		}{
+			{"no policy", time.Hour, nil},
+			{"before timeout", 30 * time.Second, &trigger_domain.FillPolicy{Timeout: time.Minute, Action: trigger_domain.FillActionMarket}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := new(trigger_mock.MockTriggerRepository)
+				exchange := new(trigger_mock.MockExchangeService)
+
+				tracked := restingOrder(now.Add(-tt.age))
+				repo.On("GetOpenOrders", mock.Anything).Return([]trigger_domain.TriggerOrder{tracked}, nil)
+				exchange.On("GetOrders", mock.Anything, mock.Anything).Return([]*exchange_domain.Order{}, nil)
+				repo.On("Get", mock.Anything, stop.TriggerID).Return(withPolicy(tt.policy), nil)
+
+				require.NoError(t, newTracker(repo, exchange).trackOrders(context.Background()))
+
+				exchange.AssertNotCalled(t, "CancelOrder", mock.Anything, mock.Anything)
+				repo.AssertNotCalled(t, "PersistOrders", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("skips the exchange when nothing is open", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		repo.On("GetOpenOrders", mock.Anything).Return([]trigger_domain.TriggerOrder{}, nil)
+
+		require.NoError(t, newTracker(repo, exchange).trackOrders(context.Background()))
+
+		exchange.AssertNotCalled(t, "GetOrders", mock.Anything, mock.Anything)
+	})
+}
@@ -1,162 +1,179 @@
 package trigger_service
 
-// import (
-// 	"fmt"
-// 	"log"
-// 	"prediction-risk/internal/app/contract"
-// 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
-// 	exchange_service "prediction-risk/internal/app/exchange/service"
-// 	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
-// 	"time"
-// )
-
-// type PositionMonitor struct {
-// 	exchangeService  exchange_service.ExchangeService
-// 	stopOrderService StopOrderService
-// 	interval         time.Duration
-// 	done             chan struct{}
-// }
-
-// func NewPositionMonitor(
-// 	exchangeService exchange_service.ExchangeService,
-// 	stopOrderService StopOrderService,
-// 	interval time.Duration,
-// ) *PositionMonitor {
-// 	log.Printf("Initializing PositionMonitor with interval: %v", interval)
-// 	return &PositionMonitor{
-// 		exchangeService:  exchangeService,
-// 		stopOrderService: stopOrderService,
-// 		interval:         interval,
-// 		done:             make(chan struct{}),
-// 	}
-// }
-
-// func (m *PositionMonitor) Start() {
-// 	log.Println("Starting PositionMonitor")
-
-// 	// Initial sync
-// 	if err := m.syncPositions(); err != nil {
-// 		log.Printf("Error during initial position sync: %v", err)
-// 	}
-
-// 	go func() {
-// 		ticker := time.NewTicker(m.interval)
-// 		defer ticker.Stop()
-
-// 		for {
-// 			select {
-// 			case <-m.done:
-// 				log.Println("PositionMonitor stopped")
-// 				return
-// 			case <-ticker.C:
-// 				log.Println("Running position check...")
-// 				if err := m.syncPositions(); err != nil {
-// 					log.Printf("Error during position sync: %v", err)
-// 				}
-// 			}
-// 		}
-// 	}()
-// }
-
-// func (m *PositionMonitor) Stop() {
-// 	log.Println("Stopping PositionMonitor...")
-// 	close(m.done)
-// }
-
-// func (m *PositionMonitor) syncPositions() error {
-// 	// Get open positions from exchange
-// 	positions, err := m.exchangeService.GetPositions()
-// 	if err != nil {
-// 		return fmt.Errorf("getting positions: %w", err)
-// 	}
-// 	log.Printf("Found %d positions", len(positions.MarketPositions))
-
-// 	// Get active stop orders
-// 	activeOrders, err := m.stopOrderService.GetActiveOrders()
-// 	if err != nil {
-// 		return fmt.Errorf("getting active stop orders: %w", err)
-// 	}
-// 	log.Printf("Found %d active stop orders", len(activeOrders))
-
-// 	// Create mapping of stop orders by ticker - buying Sides offset so we don't need to key by side
-// 	ordersByTicker := make(map[string]*trigger_domain.StopOrder)
-// 	for _, order := range activeOrders {
-// 		ordersByTicker[order.Ticker()] = order
-// 	}
-
-// 	// Process each position
-// 	for _, position := range positions.MarketPositions {
-// 		log.Printf("Processing position %s", position.Ticker)
-
-// 		if position.Position == 0 {
-// 			// Position is closed, cancel any existing stop order
-// 			if order, exists := ordersByTicker[position.Ticker]; exists {
-// 				log.Printf("Cancelling stop order for closed position %s", position.Ticker)
-// 				if _, err := m.stopOrderService.CancelOrder(order.ID()); err != nil {
-// 					log.Printf("Error cancelling stop order for closed position %s: %v", position.Ticker, err)
-// 				}
-// 			}
-// 			continue
-// 		}
-
-// 		// Check if there is a stop order for this position
-// 		if _, exists := ordersByTicker[position.Ticker]; !exists {
-// 			log.Printf("No stop order found for position %s, creating new stop order", position.Ticker)
-
-// 			triggerPrice, err := m.calculateStopPrice(position)
-// 			if err != nil {
-// 				log.Printf("Error calculating stop price for %s: %v", position.Ticker, err)
-// 				continue
-// 			}
-
-// 			// Determine side based on position
-// 			var side contract.Side
-// 			if position.Position > 0 {
-// 				side = contract.SideYes
-// 			} else {
-// 				side = contract.SideNo
-// 			}
-
-// 			if _, err := m.stopOrderService.CreateOrder(
-// 				position.Ticker,
-// 				side,
-// 				triggerPrice,
-// 				nil,
-// 			); err != nil {
-// 				log.Printf("Error creating stop order for %s: %v", position.Ticker, err)
-
-// 			} else {
-// 				log.Printf("Successfully created stop order for %s", position.Ticker)
-// 			}
-// 		}
-// 	}
-
-// 	// Cancel stop orders for positions that are no longer open
-// 	positionsByTicker := make(map[string]bool)
-// 	for _, pos := range positions.MarketPositions {
-// 		positionsByTicker[pos.Ticker] = true
-// 	}
-
-// 	for ticker, order := range ordersByTicker {
-// 		if _, exists := positionsByTicker[ticker]; !exists {
-// 			log.Printf("Cancelling orphaned stop order for %s", ticker)
-// 			if _, err := m.stopOrderService.CancelOrder(order.ID()); err != nil {
-// 				log.Printf("Error cancelling orphaned stop order for %s: %v", ticker, err)
-// 			}
-// 		}
-// 	}
-
-// 	return nil
-// }
-
-// func (m *PositionMonitor) calculateStopPrice(position kalshi.MarketPosition) (contract.ContractPrice, error) {
-// 	market, err := m.exchangeService.GetMarket(position.Ticker)
-// 	if err != nil {
-// 		return contract.ContractPrice(0), fmt.Errorf("getting market data: %w", err)
-// 	}
-
-// 	// Example: Set stop loss 10% away from current price -- TODO: move to config
-// 	stopPrice := market.LastPrice
-// 	stopPrice = int(float64(market.LastPrice) * 0.9)
-// 	return contract.NewContractPrice(stopPrice)
-// }
+import (
+	"context"
+	"fmt"
+	"log"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	exchange_service "prediction-risk/internal/app/exchange/service"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	"sync"
+	"time"
+)
+
+// PositionMonitor keeps open positions protected by their series or event policies. It
+// places a stop on each new position a policy covers, and cancels policy stops once the
+// position they protect has been closed.
+type PositionMonitor struct {
+	policyService   *ProtectionPolicyService
+	triggerService  *TriggerService
+	exchangeService exchange_service.ExchangeService
+	tickers         *TickerLocks
+	interval        time.Duration
+	done            chan struct{}
+	cancel          context.CancelFunc
+	running         sync.WaitGroup
+}
+
+func NewPositionMonitor(
+	policyService *ProtectionPolicyService,
+	triggerService *TriggerService,
+	exchangeService exchange_service.ExchangeService,
+	tickers *TickerLocks,
+	interval time.Duration,
+) *PositionMonitor {
+	log.Printf("Initializing PositionMonitor with interval: %v", interval)
+	return &PositionMonitor{
+		policyService:   policyService,
+		triggerService:  triggerService,
+		exchangeService: exchangeService,
+		tickers:         tickers,
+		interval:        interval,
+		done:            make(chan struct{}),
+	}
+}
+
+func (m *PositionMonitor) Start() {
+	log.Println("Starting PositionMonitor")
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.running.Add(1)
+	go func() {
+		defer m.running.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.done:
+				log.Println("PositionMonitor stopped")
+				return
+			case <-ticker.C:
+				log.Println("Running position check...")
+				if err := m.syncPositions(ctx); err != nil {
+					log.Printf("Error during position sync: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop cancels the sync in progress and waits for it to return
+func (m *PositionMonitor) Stop() {
+	log.Println("Stopping PositionMonitor...")
+	if m.cancel != nil {
+		m.cancel()
+	}
+	close(m.done)
+	m.running.Wait()
+}
+
+func (m *PositionMonitor) syncPositions(ctx context.Context) error {
+	policies, err := m.policyService.GetPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("getting policies: %w", err)
+	}
+
+	positions, err := m.exchangeService.GetPositions(ctx)
+	if err != nil {
+		return fmt.Errorf("getting positions: %w", err)
+	}
+
+	triggers, err := m.triggerService.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("getting triggers: %w", err)
+	}
+
+	openPositions := make(map[contract.ContractIdentifier]*exchange_domain.Position)
+	for _, position := range positions {
+		if position.Quantity > 0 {
+			openPositions[position.ContractID] = position
+		}
+	}
+	log.Printf("Found %d open positions", len(openPositions))
+
+	// A position already behind a stop, whoever placed it, needs no policy stop
+	protected := make(map[contract.ContractIdentifier]bool)
+	for _, trigger := range triggers {
+		if isActiveStop(trigger) {
+			protected[trigger.Condition.Contract] = true
+		}
+	}
+
+	syncErrors := make([]error, 0)
+
+	for contractID, position := range openPositions {
+		if protected[contractID] {
+			continue
+		}
+		stop, err := m.protectPosition(ctx, policies, position)
+		if err != nil {
+			syncErrors = append(syncErrors, fmt.Errorf("protect position %s %s: %w", contractID.Ticker, contractID.Side, err))
+			continue
+		}
+		if stop != nil {
+			log.Printf("Placed policy %s stop %s on %s %s", stop.PolicyID, stop.TriggerID, contractID.Ticker, contractID.Side)
+		}
+	}
+
+	// Policy stops are removed once the position they protect is closed
+	for _, trigger := range triggers {
+		if trigger.PolicyID == nil || trigger.Status.IsTerminal() {
+			continue
+		}
+		if _, open := openPositions[trigger.Condition.Contract]; open {
+			continue
+		}
+		if err := m.cancelPolicyStop(ctx, trigger); err != nil {
+			syncErrors = append(syncErrors, fmt.Errorf("cancel policy stop %s: %w", trigger.TriggerID, err))
+			continue
+		}
+		log.Printf("Cancelled policy stop %s for closed position %s", trigger.TriggerID, trigger.Condition.Contract.Ticker)
+	}
+
+	log.Printf("Encountered %d position sync errors", len(syncErrors))
+	for _, err := range syncErrors {
+		log.Printf("Position sync error: %v", err)
+	}
+
+	return nil
+}
+
+// protectPosition places the position's policy stop, if any, while no trigger trades its ticker
+func (m *PositionMonitor) protectPosition(
+	ctx context.Context,
+	policies []*trigger_domain.ProtectionPolicy,
+	position *exchange_domain.Position,
+) (*trigger_domain.Trigger, error) {
+	unlock := m.tickers.Lock(position.ContractID.Ticker)
+	defer unlock()
+	return m.policyService.ProtectPosition(ctx, policies, *position)
+}
+
+// cancelPolicyStop cancels the stop, waiting out any execution of it already under way
+func (m *PositionMonitor) cancelPolicyStop(ctx context.Context, trigger *trigger_domain.Trigger) error {
+	unlock := m.tickers.Lock(trigger.Tickers()...)
+	defer unlock()
+	_, err := m.triggerService.CancelTrigger(ctx, trigger.TriggerID, trigger_domain.ActorSystem)
+	return err
+}
+
+// isActiveStop reports whether the trigger is a live stop protecting its contract
+func isActiveStop(trigger *trigger_domain.Trigger) bool {
+	if trigger.Status.IsTerminal() {
+		return false
+	}
+	return trigger.TriggerType == trigger_domain.TriggerTypeStop ||
+		trigger.TriggerType == trigger_domain.TriggerTypeTrailingStop
+}
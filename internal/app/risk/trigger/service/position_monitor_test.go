@@ -1,188 +1,105 @@
 package trigger_service
 
-// import (
-// 	"prediction-risk/internal/app/contract"
-// 	"prediction-risk/internal/app/exchange/infrastructure/kalshi"
-// 	exchange_mocks "prediction-risk/internal/app/exchange/mocks"
-// 	"testing"
-// 	"time"
-
-// 	"github.com/stretchr/testify/assert"
-// )
-
-// func TestPositionMonitor(t *testing.T) {
-// 	t.Run("lifecycle", func(t *testing.T) {
-// 		// Test basic start/stop functionality
-// 		mockExchange := new(exchange_mocks.MockExchangeService)
-// 		mockStopOrder := new(orderMocks.MockStopOrderService)
-
-// 		// Setup initial sync expectations
-// 		mockExchange.On("GetPositions").Return(&kalshi.PositionsResult{
-// 			MarketPositions: []kalshi.MarketPosition{},
-// 		}, nil).Once()
-// 		mockStopOrder.On("GetActiveOrders").Return([]*order.StopOrder{}, nil).Once()
-
-// 		monitor := NewPositionMonitor(mockExchange, mockStopOrder, time.Millisecond*100)
-// 		monitor.Start()
-// 		time.Sleep(time.Millisecond * 50) // Let it run briefly
-// 		monitor.Stop()
-
-// 		mockExchange.AssertExpectations(t)
-// 		mockStopOrder.AssertExpectations(t)
-// 	})
-
-// 	t.Run("creates stop orders for new positions", func(t *testing.T) {
-// 		mockExchange := new(exchangeMocks.MockExchangeService)
-// 		mockStopOrder := new(orderMocks.MockStopOrderService)
-
-// 		// Setup market data for stop price calculation
-// 		mockExchange.On("GetMarket", "AAPL").Return(&kalshi.Market{
-// 			LastPrice: 100,
-// 		}, nil)
-
-// 		// Setup position data with one long position
-// 		mockExchange.On("GetPositions").Return(&kalshi.PositionsResult{
-// 			MarketPositions: []kalshi.MarketPosition{
-// 				{Ticker: "AAPL", Position: 10},
-// 			},
-// 		}, nil)
-
-// 		// No existing stop orders
-// 		mockStopOrder.On("GetActiveOrders").Return([]*order.StopOrder{}, nil)
-
-// 		// Expect creation of new stop order
-// 		stopPrice, _ := contract.NewContractPrice(90) // 90 is 10% below 100
-// 		mockStopOrder.On("CreateOrder",
-// 			"AAPL",
-// 			contract.SideYes,
-// 			stopPrice,
-// 			(*contract.ContractPrice)(nil),
-// 		).Return(&order.StopOrder{}, nil)
-
-// 		monitor := NewPositionMonitor(mockExchange, mockStopOrder, time.Second)
-// 		monitor.syncPositions() // Test single sync
-
-// 		mockExchange.AssertExpectations(t)
-// 		mockStopOrder.AssertExpectations(t)
-// 	})
-
-// 	t.Run("handles short positions correctly", func(t *testing.T) {
-// 		mockExchange := new(exchangeMocks.MockExchangeService)
-// 		mockStopOrder := new(orderMocks.MockStopOrderService)
-
-// 		mockExchange.On("GetMarket", "AAPL").Return(&kalshi.Market{
-// 			LastPrice: 100,
-// 		}, nil)
-
-// 		mockExchange.On("GetPositions").Return(&kalshi.PositionsResult{
-// 			MarketPositions: []kalshi.MarketPosition{
-// 				{Ticker: "AAPL", Position: -10}, // Short position
-// 			},
-// 		}, nil)
-
-// 		mockStopOrder.On("GetActiveOrders").Return([]*order.StopOrder{}, nil)
-
-// 		stopPrice, _ := contract.NewContractPrice(90)
-// 		mockStopOrder.On("CreateOrder",
-// 			"AAPL",
-// 			contract.SideNo,
-// 			stopPrice,
-// 			(*contract.ContractPrice)(nil),
-// 		).Return(&order.StopOrder{}, nil)
-
-// 		monitor := NewPositionMonitor(mockExchange, mockStopOrder, time.Second)
-// 		monitor.syncPositions()
-
-// 		mockExchange.AssertExpectations(t)
-// 		mockStopOrder.AssertExpectations(t)
-// 	})
-
-// 	t.Run("cancels stop orders for closed positions", func(t *testing.T) {
-// 		mockExchange := new(exchangeMocks.MockExchangeService)
-// 		mockStopOrder := new(orderMocks.MockStopOrderService)
-
-// 		price, err := contract.NewContractPrice(90)
-// 		assert.NoError(t, err)
-// 		existingOrder := order.NewStopOrder("AAPL", contract.SideYes, price, nil, nil)
-
-// 		mockExchange.On("GetPositions").Return(&kalshi.PositionsResult{
-// 			MarketPositions: []kalshi.MarketPosition{
-// 				{Ticker: "AAPL", Position: 0}, // Closed position
-// 			},
-// 		}, nil)
-
-// 		mockStopOrder.On("GetActiveOrders").Return([]*order.StopOrder{
-// 			existingOrder,
-// 		}, nil)
-
-// 		mockStopOrder.On("CancelOrder", existingOrder.ID()).Return(existingOrder, nil)
-
-// 		monitor := NewPositionMonitor(mockExchange, mockStopOrder, time.Second)
-// 		monitor.syncPositions()
-
-// 		mockExchange.AssertExpectations(t)
-// 		mockStopOrder.AssertExpectations(t)
-// 	})
-
-// 	t.Run("cancels orphaned stop orders", func(t *testing.T) {
-// 		mockExchange := new(exchangeMocks.MockExchangeService)
-// 		mockStopOrder := new(orderMocks.MockStopOrderService)
-
-// 		price, err := contract.NewContractPrice(90)
-// 		assert.NoError(t, err)
-// 		existingOrder := order.NewStopOrder("AAPL", contract.SideYes, price, nil, nil)
-
-// 		// No positions returned from exchange
-// 		mockExchange.On("GetPositions").Return(&kalshi.PositionsResult{
-// 			MarketPositions: []kalshi.MarketPosition{},
-// 		}, nil)
-
-// 		// But we have an active stop order
-// 		mockStopOrder.On("GetActiveOrders").Return([]*order.StopOrder{
-// 			existingOrder,
-// 		}, nil)
-
-// 		mockStopOrder.On("CancelOrder", existingOrder.ID()).Return(existingOrder, nil)
-
-// 		monitor := NewPositionMonitor(mockExchange, mockStopOrder, time.Second)
-// 		monitor.syncPositions()
-
-// 		mockExchange.AssertExpectations(t)
-// 		mockStopOrder.AssertExpectations(t)
-// 	})
-
-// 	t.Run("handles exchange errors gracefully", func(t *testing.T) {
-// 		mockExchange := new(exchangeMocks.MockExchangeService)
-// 		mockStopOrder := new(orderMocks.MockStopOrderService)
-
-// 		mockExchange.On("GetPositions").Return((*kalshi.PositionsResult)(nil),
-// 			assert.AnError)
-
-// 		monitor := NewPositionMonitor(mockExchange, mockStopOrder, time.Second)
-// 		err := monitor.syncPositions()
-
-// 		assert.Error(t, err)
-// 		mockExchange.AssertExpectations(t)
-// 		mockStopOrder.AssertExpectations(t)
-// 	})
-
-// 	t.Run("handles stop order service errors gracefully", func(t *testing.T) {
-// 		mockExchange := new(exchangeMocks.MockExchangeService)
-// 		mockStopOrder := new(orderMocks.MockStopOrderService)
-
-// 		mockExchange.On("GetPositions").Return(&kalshi.PositionsResult{
-// 			MarketPositions: []kalshi.MarketPosition{
-// 				{Ticker: "AAPL", Position: 10},
-// 			},
-// 		}, nil)
-
-// 		mockStopOrder.On("GetActiveOrders").Return(nil, assert.AnError)
-
-// 		monitor := NewPositionMonitor(mockExchange, mockStopOrder, time.Second)
-// 		err := monitor.syncPositions()
-
-// 		assert.Error(t, err)
-// 		mockExchange.AssertExpectations(t)
-// 		mockStopOrder.AssertExpectations(t)
-// 	})
-// }
+import (
+	"context"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_mock "prediction-risk/internal/app/risk/trigger/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositionMonitor_syncPositions(t *testing.T) {
+	covered := contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB13-T40", Side: contract.SideYes}
+	uncovered := contract.ContractIdentifier{Ticker: "KXHIGHCHI-25FEB13-T30", Side: contract.SideYes}
+
+	policy, err := trigger_domain.NewProtectionPolicy("KXHIGHNY", contract.SideYes, trigger_domain.TrailTypeCents, 12)
+	require.NoError(t, err)
+
+	newMonitor := func(
+		policyRepo *trigger_mock.MockProtectionPolicyRepository,
+		repo *trigger_mock.MockTriggerRepository,
+		exchange *trigger_mock.MockExchangeService,
+	) *PositionMonitor {
+		triggerService := NewTriggerService(repo)
+		policyService := NewProtectionPolicyService(policyRepo, triggerService)
+		return NewPositionMonitor(policyService, triggerService, exchange, NewTickerLocks(), time.Second)
+	}
+
+	t.Run("places policy stop on new position", func(t *testing.T) {
+		policyRepo := new(trigger_mock.MockProtectionPolicyRepository)
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		policyRepo.On("GetAll", mock.Anything).Return([]*trigger_domain.ProtectionPolicy{policy}, nil)
+		exchange.On("GetPositions", mock.Anything, mock.Anything).Return([]*exchange_domain.Position{
+			{ContractID: covered, Quantity: 10, CostBasis: 500},
+			{ContractID: uncovered, Quantity: 5, CostBasis: 250},
+		}, nil)
+		repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{}, nil)
+
+		var placed *trigger_domain.Trigger
+		repo.On("Persist", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			placed = args.Get(1).(*trigger_domain.Trigger)
+		}).Return(nil).Once()
+		repo.On("Get", mock.Anything, mock.Anything).Return(&trigger_domain.Trigger{}, nil).Once()
+
+		require.NoError(t, newMonitor(policyRepo, repo, exchange).syncPositions(context.Background()))
+
+		require.NotNil(t, placed)
+		assert.Equal(t, covered, placed.Condition.Contract)
+		assert.Equal(t, &policy.PolicyID, placed.PolicyID)
+		assert.Equal(t, contract.ContractPrice(38), placed.Condition.Price.Threshold)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("leaves protected position alone", func(t *testing.T) {
+		policyRepo := new(trigger_mock.MockProtectionPolicyRepository)
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		existing, err := trigger_domain.NewStopTrigger(covered, 30, nil)
+		require.NoError(t, err)
+
+		policyRepo.On("GetAll", mock.Anything).Return([]*trigger_domain.ProtectionPolicy{policy}, nil)
+		exchange.On("GetPositions", mock.Anything, mock.Anything).Return([]*exchange_domain.Position{
+			{ContractID: covered, Quantity: 10, CostBasis: 500},
+		}, nil)
+		repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{existing}, nil)
+
+		require.NoError(t, newMonitor(policyRepo, repo, exchange).syncPositions(context.Background()))
+
+		repo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+
+	t.Run("cancels policy stop once position closes", func(t *testing.T) {
+		policyRepo := new(trigger_mock.MockProtectionPolicyRepository)
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		stop, err := policy.NewStop(exchange_domain.Position{ContractID: covered, Quantity: 10, CostBasis: 500})
+		require.NoError(t, err)
+		manual, err := trigger_domain.NewStopTrigger(uncovered, 20, nil)
+		require.NoError(t, err)
+
+		policyRepo.On("GetAll", mock.Anything).Return([]*trigger_domain.ProtectionPolicy{policy}, nil)
+		exchange.On("GetPositions", mock.Anything, mock.Anything).Return([]*exchange_domain.Position{
+			{ContractID: covered, Quantity: 0},
+		}, nil)
+		repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{stop, manual}, nil)
+		repo.On("Get", mock.Anything, stop.TriggerID).Return(stop, nil)
+		repo.On("Persist", mock.Anything, stop).Return(nil).Once()
+		repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+
+		require.NoError(t, newMonitor(policyRepo, repo, exchange).syncPositions(context.Background()))
+
+		assert.Equal(t, trigger_domain.StatusCancelled, stop.Status)
+		assert.Equal(t, trigger_domain.StatusActive, manual.Status)
+		repo.AssertExpectations(t)
+	})
+}
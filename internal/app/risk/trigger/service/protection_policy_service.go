@@ -0,0 +1,162 @@
+package trigger_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	"time"
+)
+
+var ErrInvalidProtectionPolicy = errors.New("invalid protection policy")
+
+type ProtectionPolicyRepository interface {
+	Persist(ctx context.Context, policy *trigger_domain.ProtectionPolicy) error
+	Get(ctx context.Context, id trigger_domain.ProtectionPolicyID) (*trigger_domain.ProtectionPolicy, error)
+	GetAll(ctx context.Context) ([]*trigger_domain.ProtectionPolicy, error)
+	Delete(ctx context.Context, id trigger_domain.ProtectionPolicyID) error
+}
+
+type ProtectionPolicyService struct {
+	repository     ProtectionPolicyRepository
+	triggerService *TriggerService
+}
+
+func NewProtectionPolicyService(
+	repository ProtectionPolicyRepository,
+	triggerService *TriggerService,
+) *ProtectionPolicyService {
+	return &ProtectionPolicyService{
+		repository:     repository,
+		triggerService: triggerService,
+	}
+}
+
+// CreatePolicy creates a policy that stops out every matching position the given offset below entry
+func (s *ProtectionPolicyService) CreatePolicy(
+	ctx context.Context,
+	ticker contract.Ticker,
+	side contract.Side,
+	offsetType trigger_domain.TrailType,
+	offset int,
+) (*trigger_domain.ProtectionPolicy, error) {
+	policy, err := trigger_domain.NewProtectionPolicy(ticker, side, offsetType, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProtectionPolicy, err)
+	}
+
+	if err := s.repository.Persist(ctx, policy); err != nil {
+		return nil, fmt.Errorf("save policy: %w", err)
+	}
+
+	savedPolicy, err := s.repository.Get(ctx, policy.PolicyID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved policy: %w", err)
+	}
+
+	return savedPolicy, nil
+}
+
+// GetPolicy retrieves a specific policy by its ID
+func (s *ProtectionPolicyService) GetPolicy(
+	ctx context.Context,
+	policyID trigger_domain.ProtectionPolicyID,
+) (*trigger_domain.ProtectionPolicy, error) {
+	policy, err := s.repository.Get(ctx, policyID)
+	if err != nil {
+		return nil, fmt.Errorf("get policy: %w", err)
+	}
+	return policy, nil
+}
+
+// GetPolicies retrieves all policies
+func (s *ProtectionPolicyService) GetPolicies(ctx context.Context) ([]*trigger_domain.ProtectionPolicy, error) {
+	policies, err := s.repository.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get all policies: %w", err)
+	}
+	return policies, nil
+}
+
+// UpdatePolicy changes a policy's offset or enables/disables it. Changes apply to
+// stops placed from then on; stops the policy already placed are left as they are.
+func (s *ProtectionPolicyService) UpdatePolicy(
+	ctx context.Context,
+	policyID trigger_domain.ProtectionPolicyID,
+	offsetType *trigger_domain.TrailType,
+	offset *int,
+	enabled *bool,
+) (*trigger_domain.ProtectionPolicy, error) {
+	policy, err := s.repository.Get(ctx, policyID)
+	if err != nil {
+		return nil, fmt.Errorf("get policy: %w", err)
+	}
+
+	if offsetType != nil {
+		policy.OffsetType = *offsetType
+	}
+	if offset != nil {
+		policy.Offset = *offset
+	}
+	if enabled != nil {
+		policy.Enabled = *enabled
+	}
+	policy.UpdatedAt = time.Now()
+
+	if err := trigger_domain.ValidateProtectionPolicy(policy); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProtectionPolicy, err)
+	}
+
+	if err := s.repository.Persist(ctx, policy); err != nil {
+		return nil, fmt.Errorf("update policy: %w", err)
+	}
+
+	updatedPolicy, err := s.repository.Get(ctx, policyID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated policy: %w", err)
+	}
+
+	return updatedPolicy, nil
+}
+
+// DeletePolicy cancels the stops the policy placed that are still active, then deletes it
+func (s *ProtectionPolicyService) DeletePolicy(ctx context.Context, policyID trigger_domain.ProtectionPolicyID) error {
+	if _, err := s.repository.Get(ctx, policyID); err != nil {
+		return fmt.Errorf("get policy: %w", err)
+	}
+
+	stops, err := s.triggerService.GetByPolicy(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	for _, stop := range stops {
+		if stop.Status.IsTerminal() {
+			continue
+		}
+		if _, err := s.triggerService.CancelTrigger(ctx, stop.TriggerID, trigger_domain.ActorSystem); err != nil {
+			return fmt.Errorf("cancel stop %s: %w", stop.TriggerID, err)
+		}
+	}
+
+	if err := s.repository.Delete(ctx, policyID); err != nil {
+		return fmt.Errorf("delete policy: %w", err)
+	}
+
+	return nil
+}
+
+// ProtectPosition places the matching policy's stop on a position, returning nil if no
+// enabled policy covers it
+func (s *ProtectionPolicyService) ProtectPosition(
+	ctx context.Context,
+	policies []*trigger_domain.ProtectionPolicy,
+	position exchange_domain.Position,
+) (*trigger_domain.Trigger, error) {
+	policy := trigger_domain.MatchPolicy(policies, position)
+	if policy == nil {
+		return nil, nil
+	}
+	return s.triggerService.ApplyProtectionPolicy(ctx, *policy, position)
+}
@@ -0,0 +1,55 @@
+package trigger_service
+
+import (
+	"prediction-risk/internal/app/contract"
+	"slices"
+)
+
+// TickerLocks keeps everything that places or cancels orders from acting on the same ticker
+// at once. The trigger monitor, the weather and forecast evaluators, the order tracker and
+// the position monitor all share one.
+type TickerLocks struct {
+	tickers *keyedMutex[contract.Ticker]
+}
+
+func NewTickerLocks() *TickerLocks {
+	return &TickerLocks{tickers: newKeyedMutex[contract.Ticker]()}
+}
+
+// Lock blocks until every ticker is free and returns the function that frees them. Tickers
+// are taken in order, so callers locking overlapping sets cannot deadlock.
+func (l *TickerLocks) Lock(tickers ...contract.Ticker) func() {
+	var unlocks []func()
+	for _, ticker := range sortedTickers(tickers) {
+		unlocks = append(unlocks, l.tickers.Lock(ticker))
+	}
+	return unlockAll(unlocks)
+}
+
+// TryLock takes every ticker if all of them are free, and none of them otherwise
+func (l *TickerLocks) TryLock(tickers ...contract.Ticker) (func(), bool) {
+	var unlocks []func()
+	for _, ticker := range sortedTickers(tickers) {
+		unlock, ok := l.tickers.TryLock(ticker)
+		if !ok {
+			unlockAll(unlocks)()
+			return nil, false
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return unlockAll(unlocks), true
+}
+
+func sortedTickers(tickers []contract.Ticker) []contract.Ticker {
+	sorted := slices.Clone(tickers)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
+
+func unlockAll(unlocks []func()) func() {
+	return func() {
+		for _, unlock := range slices.Backward(unlocks) {
+			unlock()
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package trigger_service
+
+import (
+	"prediction-risk/internal/app/contract"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTickerLocks(t *testing.T) {
+	t.Run("try lock takes nothing if any ticker is busy", func(t *testing.T) {
+		locks := NewTickerLocks()
+		unlock := locks.Lock("BAR")
+
+		_, ok := locks.TryLock("FOO", "BAR")
+		assert.False(t, ok)
+
+		// FOO was released when BAR turned out to be busy
+		unlockFoo, ok := locks.TryLock("FOO")
+		require.True(t, ok)
+		unlockFoo()
+
+		unlock()
+		unlockBoth, ok := locks.TryLock("FOO", "BAR")
+		require.True(t, ok)
+		unlockBoth()
+		assert.Empty(t, locks.tickers.locks)
+	})
+
+	t.Run("overlapping sets locked in any order do not deadlock", func(t *testing.T) {
+		locks := NewTickerLocks()
+		done := make(chan struct{})
+		go func() {
+			for range 100 {
+				locks.Lock("FOO", "BAR")()
+			}
+			close(done)
+		}()
+		for range 100 {
+			locks.Lock("BAR", "FOO", "BAR")()
+		}
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("locking overlapping tickers deadlocked")
+		}
+	})
+
+	t.Run("a held ticker blocks lock until freed", func(t *testing.T) {
+		locks := NewTickerLocks()
+		unlock := locks.Lock(contract.Ticker("FOO"))
+
+		acquired := make(chan struct{})
+		go func() {
+			locks.Lock("FOO")()
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("lock taken while the ticker was held")
+		case <-time.After(10 * time.Millisecond):
+		}
+		unlock()
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("lock not taken once the ticker was freed")
+		}
+	})
+}
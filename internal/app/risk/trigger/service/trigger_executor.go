@@ -1,41 +1,105 @@
 package trigger_service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"prediction-risk/internal/app/contract"
 	exchange_domain "prediction-risk/internal/app/exchange/domain"
 	exchange_service "prediction-risk/internal/app/exchange/service"
 	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+const (
+	// executionGracePeriod is how long a trigger may be executing before it is presumed
+	// interrupted. Executions that are still running finish well within it.
+	executionGracePeriod = 30 * time.Second
+	// orderClockSkew widens the search for a trigger's orders to allow for the exchange's
+	// clock running behind ours
+	orderClockSkew = time.Minute
+	// maxReconcileAttempts is how many times settling an interrupted execution may fail
+	// before the trigger is flagged for review
+	maxReconcileAttempts = 5
 )
 
+// TriggerExecutor sends the orders of triggers that fire. Every monitor shares one, and
+// it runs a trigger's executions and reconciliations one at a time. Callers executing a
+// trigger hold its tickers; reconciling takes them itself.
 type TriggerExecutor struct {
 	triggerService  *TriggerService
 	exchangeService exchange_service.ExchangeService
+	tickers         *TickerLocks
+	defaultGuard    *trigger_domain.ExecutionGuard // applies to triggers without their own guard, nil for none
+	executions      *keyedMutex[trigger_domain.TriggerID]
 }
 
 func NewTriggerExecutor(
 	triggerService *TriggerService,
 	exchangeService exchange_service.ExchangeService,
+	tickers *TickerLocks,
+	defaultGuard *trigger_domain.ExecutionGuard,
 ) *TriggerExecutor {
 	return &TriggerExecutor{
 		triggerService:  triggerService,
 		exchangeService: exchangeService,
+		tickers:         tickers,
+		defaultGuard:    defaultGuard,
+		executions:      newKeyedMutex[trigger_domain.TriggerID](),
 	}
 }
 
-func (t *TriggerExecutor) ExecuteTrigger(trigger *trigger_domain.Trigger) (*trigger_domain.Trigger, error) {
+func (t *TriggerExecutor) ExecuteTrigger(ctx context.Context, trigger *trigger_domain.Trigger) (*trigger_domain.Trigger, error) {
 	// Check if the trigger is exeutable (it must be active)
 	if trigger.Status != trigger_domain.StatusActive {
 		return nil, fmt.Errorf("trigger is not active, status: %s", trigger.Status)
 	}
 
+	// The weather and forecast evaluators fire triggers alongside the monitor, so only one
+	// of them gets to execute the trigger, and never while it is being reconciled
+	unlock := t.executions.Lock(trigger.TriggerID)
+	defer unlock()
+
+	// The caller's copy may be stale: another evaluator may have executed the trigger while
+	// this one waited, a sibling firing earlier in the pass may have cancelled it, or it may
+	// have been edited. Everything from here on works from the stored trigger.
+	current, err := t.triggerService.GetByID(ctx, trigger.TriggerID)
+	if err != nil {
+		return nil, fmt.Errorf("refresh trigger: %w", err)
+	}
+	if current.Status != trigger_domain.StatusActive {
+		return nil, fmt.Errorf("trigger is not active, status: %s", current.Status)
+	}
+
+	// Expand event flatten actions into the positions held right now
+	actions, err := t.resolveActions(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hold the orders back if any market is too thin to trade into
+	if err := t.checkGuard(ctx, current, actions); err != nil {
+		return nil, err
+	}
+
+	// Record that orders are going out before sending any. If sending fails or the
+	// process dies part way, the trigger stays executing until it is reconciled.
+	if _, err := t.triggerService.UpdateTriggerStatus(ctx, current.TriggerID, trigger_domain.StatusExecuting, trigger_domain.ActorSystem); err != nil {
+		return nil, fmt.Errorf("mark trigger executing: %w", err)
+	}
+
 	// Execute all the actions in the trigger
-	_, err := t.executeActions(trigger.TriggerID, trigger.Actions)
+	_, err = t.executeActions(ctx, current.TriggerID, numberActions(actions))
 	if err != nil {
 		return nil, fmt.Errorf("execute actions: %w", err)
 	}
 
 	// Update the trigger status to executed
-	updatedTrigger, err := t.triggerService.UpdateTriggerStatus(trigger.TriggerID, trigger_domain.StatusTriggered)
+	updatedTrigger, err := t.triggerService.UpdateTriggerStatus(ctx, current.TriggerID, trigger_domain.StatusTriggered, trigger_domain.ActorSystem)
 	if err != nil {
 		return nil, fmt.Errorf("update trigger status: %w", err)
 	}
@@ -43,50 +107,327 @@ func (t *TriggerExecutor) ExecuteTrigger(trigger *trigger_domain.Trigger) (*trig
 	return updatedTrigger, nil
 }
 
+// ReconcileExecutions settles every one of the given triggers left executing for longer
+// than the grace period, so each interrupted execution ends in exactly one outcome. A
+// trigger whose tickers are busy is left for the next call.
+func (t *TriggerExecutor) ReconcileExecutions(ctx context.Context, triggers []*trigger_domain.Trigger) error {
+	cutoff := time.Now().Add(-executionGracePeriod)
+	var errs []error
+	for _, trigger := range triggers {
+		if trigger.Status != trigger_domain.StatusExecuting || trigger.UpdatedAt.After(cutoff) {
+			continue
+		}
+		reconciled, err := t.reconcile(ctx, trigger, cutoff)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reconcile trigger %s: %w", trigger.TriggerID, err))
+			continue
+		}
+		if reconciled != nil {
+			log.Printf("Reconciled trigger %s to %s", reconciled.TriggerID, reconciled.Status)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// reconcile settles one interrupted execution under the trigger's locks, returning nil if
+// there was nothing left to settle. A failed attempt is counted, and once too many have
+// failed the trigger is flagged rather than retried on every pass.
+func (t *TriggerExecutor) reconcile(
+	ctx context.Context,
+	loaded *trigger_domain.Trigger,
+	cutoff time.Time,
+) (*trigger_domain.Trigger, error) {
+	triggerID := loaded.TriggerID
+	var eventPositions []*exchange_domain.EventPosition
+	if len(eventsActedOn(loaded)) > 0 {
+		var err error
+		eventPositions, err = t.exchangeService.GetEventPositions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get event positions: %w", err)
+		}
+	}
+	unlockTickers, ok := t.tickers.TryLock(tickersActedOn(loaded, eventPositions)...)
+	if !ok {
+		log.Printf("Leaving trigger %s to reconcile later, its tickers are busy", triggerID)
+		return nil, nil
+	}
+	defer unlockTickers()
+
+	unlock := t.executions.Lock(triggerID)
+	defer unlock()
+
+	// An execution still running when the pass loaded the trigger has finished by the time
+	// the lock is free, so only act on what is stored now
+	trigger, err := t.triggerService.GetByID(ctx, triggerID)
+	if err != nil {
+		return nil, err
+	}
+	if trigger.Status != trigger_domain.StatusExecuting || trigger.UpdatedAt.After(cutoff) {
+		return nil, nil
+	}
+
+	reconciled, err := t.reconcileExecution(ctx, trigger)
+	if err == nil {
+		return reconciled, nil
+	}
+
+	failed, recordErr := t.triggerService.RecordReconcileFailure(ctx, triggerID, maxReconcileAttempts)
+	if recordErr != nil {
+		return nil, errors.Join(err, recordErr)
+	}
+	if failed.Status == trigger_domain.StatusFlagged {
+		return nil, fmt.Errorf("flagged for review after %d failed attempts: %w", failed.ReconcileAttempts, err)
+	}
+	return nil, fmt.Errorf("attempt %d of %d: %w", failed.ReconcileAttempts, maxReconcileAttempts, err)
+}
+
+// reconcileExecution looks for the orders an executing trigger placed. If none reached
+// the exchange the trigger goes back to active and may fire again. Otherwise any orders
+// it had yet to send are sent, and it is marked triggered.
+func (t *TriggerExecutor) reconcileExecution(ctx context.Context, trigger *trigger_domain.Trigger) (*trigger_domain.Trigger, error) {
+	orders, err := t.exchangeService.GetOrders(ctx, trigger.UpdatedAt.Add(-orderClockSkew))
+	if err != nil {
+		return nil, fmt.Errorf("get orders: %w", err)
+	}
+	placed := lo.Filter(orders, func(o *exchange_domain.Order, _ int) bool {
+		return trigger.TriggerID.PlacedOrder(o.Reference)
+	})
+
+	if len(placed) == 0 {
+		return t.triggerService.UpdateTriggerStatus(ctx, trigger.TriggerID, trigger_domain.StatusActive, trigger_domain.ActorSystem)
+	}
+
+	// Orders sent before the interruption may not have been recorded
+	placedOrders := lo.Map(placed, func(o *exchange_domain.Order, _ int) trigger_domain.TriggerOrder {
+		index, _ := trigger.TriggerID.OrderIndex(o.Reference)
+		return trigger_domain.TriggerOrder{TriggerID: trigger.TriggerID, ActionIndex: index, Order: *o}
+	})
+	if err := t.triggerService.RecordOrders(ctx, placedOrders); err != nil {
+		return nil, err
+	}
+
+	remaining, err := t.unsentActions(ctx, trigger, placedOrders)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.executeActions(ctx, trigger.TriggerID, remaining); err != nil {
+		return nil, fmt.Errorf("execute remaining actions: %w", err)
+	}
+
+	return t.triggerService.UpdateTriggerStatus(ctx, trigger.TriggerID, trigger_domain.StatusTriggered, trigger_domain.ActorSystem)
+}
+
+// unsentActions works out which of an interrupted trigger's actions have no order yet. Each
+// order's reference carries the index of the action it was sent for, so those indexes are
+// done. Event flatten actions are resolved afresh from what is held now, which renumbers
+// them, so instead positions already being sold drop out and the rest are numbered after
+// every order placed so far.
+func (t *TriggerExecutor) unsentActions(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	placed []trigger_domain.TriggerOrder,
+) ([]numberedAction, error) {
+	sent := make(map[int]bool, len(placed))
+	for _, order := range placed {
+		sent[order.ActionIndex] = true
+	}
+
+	if !lo.SomeBy(trigger.Actions, func(a trigger_domain.TriggerAction) bool { return a.FlattensEvent() }) {
+		var remaining []numberedAction
+		for _, action := range numberActions(trigger.Actions) {
+			if !sent[action.index] {
+				remaining = append(remaining, action)
+			}
+		}
+		return remaining, nil
+	}
+
+	actions, err := t.resolveActions(ctx, trigger)
+	if err != nil {
+		return nil, err
+	}
+	next := lo.Max(lo.Keys(sent)) + 1
+	var remaining []numberedAction
+	for _, action := range actions {
+		if lo.SomeBy(placed, func(o trigger_domain.TriggerOrder) bool {
+			return o.Order.Ticker == string(action.Contract.Ticker) && o.Order.Side == action.Contract.Side
+		}) {
+			continue
+		}
+		remaining = append(remaining, numberedAction{index: next, action: action})
+		next++
+	}
+	return remaining, nil
+}
+
+// resolveActions returns the orders the trigger places, with any event flatten action
+// replaced by a sell of each position currently held in the event
+func (t *TriggerExecutor) resolveActions(ctx context.Context, trigger *trigger_domain.Trigger) ([]trigger_domain.TriggerAction, error) {
+	if !lo.SomeBy(trigger.Actions, func(a trigger_domain.TriggerAction) bool { return a.FlattensEvent() }) {
+		return trigger.Actions, nil
+	}
+
+	eventPositions, err := t.exchangeService.GetEventPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get event positions: %w", err)
+	}
+	events := make(map[contract.Ticker]exchange_domain.EventPosition, len(eventPositions))
+	for _, event := range eventPositions {
+		events[event.EventTicker] = *event
+	}
+
+	return trigger_domain.ResolveEventActions(trigger.Actions, events), nil
+}
+
+// checkGuard runs the trigger's pre-trade guard, or the default guard, against every action's
+// market before any order is sent, recording the failure if one of them does not pass
+func (t *TriggerExecutor) checkGuard(ctx context.Context, trigger *trigger_domain.Trigger, actions []trigger_domain.TriggerAction) error {
+	guard := trigger.Guard
+	if guard == nil {
+		guard = t.defaultGuard
+	}
+	if guard == nil {
+		return nil
+	}
+
+	var reasons []string
+	for _, action := range actions {
+		market, err := t.exchangeService.GetMarket(ctx, action.Contract.Ticker)
+		if err != nil {
+			return fmt.Errorf("get market %s for guard: %w", action.Contract.Ticker, err)
+		}
+
+		var orderbook *exchange_domain.Orderbook
+		if guard.RequiresOrderbook() {
+			orderbook, err = t.exchangeService.GetOrderbook(ctx, action.Contract.Ticker)
+			if err != nil {
+				return fmt.Errorf("get orderbook %s for guard: %w", action.Contract.Ticker, err)
+			}
+		}
+
+		reasons = append(reasons, guard.Check(action, market, orderbook)...)
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	failure := trigger_domain.GuardFailure{
+		Reason:   strings.Join(reasons, "; "),
+		Action:   guard.OnFailure,
+		FailedAt: time.Now(),
+	}
+	if _, err := t.triggerService.RecordGuardFailure(ctx, trigger, failure); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", ErrGuardFailed, failure.Reason)
+}
+
+// numberedAction is an action with the index its order reference carries
+type numberedAction struct {
+	index  int
+	action trigger_domain.TriggerAction
+}
+
+// numberActions numbers actions by their position, as when a trigger first executes
+func numberActions(actions []trigger_domain.TriggerAction) []numberedAction {
+	return lo.Map(actions, func(action trigger_domain.TriggerAction, i int) numberedAction {
+		return numberedAction{index: i, action: action}
+	})
+}
+
+// executeActions sends an order for each action, with a client order ID carrying the
+// action's index, and records each order against the trigger once the exchange accepts it
 func (t *TriggerExecutor) executeActions(
+	ctx context.Context,
 	triggerID trigger_domain.TriggerID,
-	actions []trigger_domain.TriggerAction,
+	actions []numberedAction,
 ) ([]*exchange_domain.Order, error) {
 	var orders []*exchange_domain.Order
-	for _, action := range actions {
-		order, err := t.executeAction(triggerID, action)
+	for _, numbered := range actions {
+		order, err := t.executeAction(ctx, triggerID.OrderReference(numbered.index), numbered.action)
 		if err != nil {
 			return nil, fmt.Errorf("execute action: %w", err)
 		}
 		orders = append(orders, order)
+
+		// The order is live whether or not it is recorded, so the remaining actions are still
+		// sent. A trigger that cannot be stored stays executing, and reconciling it records
+		// the orders the exchange holds.
+		triggerOrder := trigger_domain.TriggerOrder{TriggerID: triggerID, ActionIndex: numbered.index, Order: *order}
+		if err := t.triggerService.RecordOrders(ctx, []trigger_domain.TriggerOrder{triggerOrder}); err != nil {
+			log.Printf("Error recording order %s for trigger %s: %v", order.ExchangeOrderID, triggerID, err)
+		}
 	}
 
 	return orders, nil
 }
 
 func (t *TriggerExecutor) executeAction(
-	triggerID trigger_domain.TriggerID,
+	ctx context.Context,
+	reference string,
 	action trigger_domain.TriggerAction,
 ) (*exchange_domain.Order, error) {
 	// Map trigger action side to exchange order action
-	var orderAction exchange_domain.OrderAction
-	switch action.Side {
-	case trigger_domain.Buy:
-		orderAction = exchange_domain.OrderActionBuy
-	case trigger_domain.Sell:
-		orderAction = exchange_domain.OrderActionSell
-	default:
+	orderAction := orderActionFor(action.Side)
+	if orderAction == "" {
 		return nil, fmt.Errorf("invalid action side: %s", action.Side)
 	}
 
+	// Percentage sells are sized against the position held right now
+	quantity := action.Size
+	if action.SizePercent != nil && quantity == nil {
+		size, err := t.sizeFromPosition(ctx, action)
+		if err != nil {
+			return nil, err
+		}
+		quantity = &size
+	}
+
 	// Create the order parameters once we know the action is valid
 	orderParams := exchange_service.OrderParams{
 		ContractID: action.Contract,
-		Quantity:   action.Size,
+		Quantity:   quantity,
 		Action:     orderAction,
-		Reference:  triggerID.String(),
+		Reference:  reference,
 		LimitPrice: action.LimitPrice,
+		MaxCost:    action.MaxCost,
 	}
 
-	order, err := t.exchangeService.CreateOrder(orderParams)
+	order, err := t.exchangeService.CreateOrder(ctx, orderParams)
 	if err != nil {
 		return nil, fmt.Errorf("create %s order: %w", string(orderAction), err)
 	}
 
 	return order, nil
 }
+
+// orderActionFor maps a trigger action side to an exchange order action, or empty if the side is invalid
+func orderActionFor(side trigger_domain.OrderSide) exchange_domain.OrderAction {
+	switch side {
+	case trigger_domain.Buy:
+		return exchange_domain.OrderActionBuy
+	case trigger_domain.Sell:
+		return exchange_domain.OrderActionSell
+	default:
+		return ""
+	}
+}
+
+// sizeFromPosition resolves a percentage sell to a quantity of the position currently held
+func (t *TriggerExecutor) sizeFromPosition(ctx context.Context, action trigger_domain.TriggerAction) (uint, error) {
+	positions, err := t.exchangeService.GetPositions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get positions: %w", err)
+	}
+
+	position, ok := lo.Find(positions, func(p *exchange_domain.Position) bool {
+		return p.ContractID == action.Contract
+	})
+	if !ok || position.Quantity == 0 {
+		return 0, fmt.Errorf("no position to sell in %s", action.Contract.Ticker)
+	}
+
+	return action.QuantityFor(position.Quantity), nil
+}
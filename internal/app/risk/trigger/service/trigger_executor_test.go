@@ -1 +1,490 @@
 package trigger_service
+
+import (
+	"context"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	exchange_service "prediction-risk/internal/app/exchange/service"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_mock "prediction-risk/internal/app/risk/trigger/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newArmedBracket creates a bracket whose entry has filled, so its exits are active
+func newArmedBracket(t *testing.T, contractID contract.ContractIdentifier) *trigger_domain.Bracket {
+	t.Helper()
+	entry, err := trigger_domain.NewEntryTrigger(contractID, trigger_domain.Below, 60, 10, nil, nil)
+	require.NoError(t, err)
+	bracket, err := trigger_domain.NewBracket(entry, 40, nil, 80, nil)
+	require.NoError(t, err)
+	for _, exit := range bracket.Exits() {
+		require.NoError(t, exit.Arm(10))
+	}
+	return bracket
+}
+
+func TestExecuteTrigger_SkipsCancelledGroupMember(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bracket := newArmedBracket(t, contractID)
+
+	// The sibling fired earlier in the pass, so the stored copy is already cancelled
+	stored := *bracket.Stop
+	stored.Status = trigger_domain.StatusCancelled
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("Get", mock.Anything, bracket.Stop.TriggerID).Return(&stored, nil).Once()
+	exchange := new(trigger_mock.MockExchangeService)
+
+	executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+	executed, err := executor.ExecuteTrigger(context.Background(), bracket.Stop)
+
+	assert.ErrorContains(t, err, "trigger is not active")
+	assert.Nil(t, executed)
+	exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestExecuteTrigger_UsesStoredTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	stale, err := trigger_domain.NewStopTrigger(contractID, 40, ptr(contract.ContractPrice(39)))
+	require.NoError(t, err)
+
+	// The limit price was edited after the pass loaded the trigger
+	stored := *stale
+	action, err := trigger_domain.NewTriggerAction(contractID, trigger_domain.Sell, nil, ptr(contract.ContractPrice(38)))
+	require.NoError(t, err)
+	stored.Actions = []trigger_domain.TriggerAction{*action}
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("Get", mock.Anything, stale.TriggerID).Return(&stored, nil)
+	repo.On("Persist", mock.Anything, mock.Anything).Return(nil).Twice()
+	repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Twice()
+	repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Once()
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("CreateOrder", mock.Anything, mock.MatchedBy(func(params exchange_service.OrderParams) bool {
+		return params.LimitPrice != nil && *params.LimitPrice == 38
+	})).Return(&exchange_domain.Order{}, nil).Once()
+
+	executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+	_, err = executor.ExecuteTrigger(context.Background(), stale)
+
+	require.NoError(t, err)
+	exchange.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestExecuteTrigger_OCOSiblingsCannotBothExecute(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bracket := newArmedBracket(t, contractID)
+
+	// Both legs were active when the pass began. The stop claims the group first, then its
+	// order fails, leaving it executing with the position perhaps already sold.
+	repo := new(trigger_mock.MockTriggerRepository)
+	for _, leg := range bracket.Exits() {
+		stored := *leg
+		repo.On("Get", mock.Anything, leg.TriggerID).Return(&stored, nil)
+	}
+	repo.On("GetGroup", mock.Anything, bracket.Group.GroupID).Return(bracket.Group, nil)
+	statuses := map[trigger_domain.TriggerID]trigger_domain.TriggerStatus{
+		bracket.Stop.TriggerID:       trigger_domain.StatusActive,
+		bracket.TakeProfit.TriggerID: trigger_domain.StatusActive,
+	}
+	repo.On("ClaimExecution", mock.Anything, bracket.Group, mock.Anything).Return(func(trigger *trigger_domain.Trigger) error {
+		if err := bracket.Group.CheckExecution(trigger.TriggerID, statuses); err != nil {
+			return err
+		}
+		statuses[trigger.TriggerID] = trigger.Status
+		return nil
+	}).Twice()
+	repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("CreateOrder", mock.Anything, mock.MatchedBy(func(params exchange_service.OrderParams) bool {
+		return params.Reference == bracket.Stop.TriggerID.OrderReference(0)
+	})).Return(nil, assert.AnError).Once()
+
+	executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+
+	_, err := executor.ExecuteTrigger(context.Background(), bracket.Stop)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	executed, err := executor.ExecuteTrigger(context.Background(), bracket.TakeProfit)
+	assert.ErrorIs(t, err, trigger_domain.ErrGroupHeld)
+	assert.Nil(t, executed)
+	exchange.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestExecuteTrigger_GuardFailure(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	thinMarket := &exchange_domain.Market{
+		Ticker:  contractID.Ticker,
+		Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Bid: 1, Ask: 40}},
+	}
+	maxSpread := 5
+
+	tests := []struct {
+		name           string
+		onFailure      trigger_domain.GuardFailureAction
+		triggerGuard   bool // guard set on the trigger instead of the executor default
+		expectedStatus trigger_domain.TriggerStatus
+	}{
+		{name: "default guard defers", onFailure: trigger_domain.GuardFailureDefer, expectedStatus: trigger_domain.StatusActive},
+		{name: "trigger guard flags", onFailure: trigger_domain.GuardFailureFlag, triggerGuard: true, expectedStatus: trigger_domain.StatusFlagged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+			require.NoError(t, err)
+			guard := &trigger_domain.ExecutionGuard{MaxSpread: &maxSpread, OnFailure: tt.onFailure}
+			var defaultGuard *trigger_domain.ExecutionGuard
+			if tt.triggerGuard {
+				trigger.Guard = guard
+			} else {
+				defaultGuard = guard
+			}
+
+			repo := new(trigger_mock.MockTriggerRepository)
+			repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+			repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+				return t.GuardFailure != nil && t.GuardFailure.Reason == "FOO spread 1-40 exceeds 5"
+			})).Return(nil).Once()
+			if tt.expectedStatus != trigger_domain.StatusActive {
+				repo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+					return len(changes) == 1 &&
+						changes[0].Field == trigger_domain.ChangeFieldStatus &&
+						*changes[0].NewValue == tt.expectedStatus.String()
+				})).Return(nil).Once()
+			}
+			exchange := new(trigger_mock.MockExchangeService)
+			exchange.On("GetMarket", mock.Anything, contractID.Ticker).Return(thinMarket, nil)
+
+			executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), defaultGuard)
+			executed, err := executor.ExecuteTrigger(context.Background(), trigger)
+
+			assert.ErrorIs(t, err, ErrGuardFailed)
+			assert.Nil(t, executed)
+			assert.Equal(t, tt.expectedStatus, trigger.Status)
+			assert.Equal(t, tt.onFailure, trigger.GuardFailure.Action)
+			exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+			exchange.AssertNotCalled(t, "GetOrderbook", mock.Anything, mock.Anything)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExecuteTrigger_LadderTier(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	tests := []struct {
+		name             string
+		tier             int
+		expectedQuantity uint
+		cancelsSiblings  bool
+	}{
+		{name: "upper tier sells its share of the live position", tier: 0, expectedQuantity: 10},
+		{name: "lowest tier sells the rest and retires the ladder", tier: 2, expectedQuantity: 30, cancelsSiblings: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ladder, err := trigger_domain.NewStopLadder(contractID, []trigger_domain.LadderTier{
+				{TriggerPrice: 45, Share: 33},
+				{TriggerPrice: 40, Share: 33},
+				{TriggerPrice: 35, Share: 34},
+			})
+			require.NoError(t, err)
+			tier := ladder.Tiers[tt.tier]
+
+			repo := new(trigger_mock.MockTriggerRepository)
+			repo.On("Get", mock.Anything, tier.TriggerID).Return(tier, nil)
+			repo.On("GetGroup", mock.Anything, ladder.Group.GroupID).Return(ladder.Group, nil)
+			// The tier claims the group before its order is sent
+			repo.On("ClaimExecution", mock.Anything, ladder.Group, tier).Return(nil).Once()
+			if tt.cancelsSiblings {
+				repo.On("GetByGroup", mock.Anything, ladder.Group.GroupID).Return(ladder.Tiers, nil)
+				repo.On("PersistGroup", mock.Anything, ladder.Group, mock.MatchedBy(func(triggers []*trigger_domain.Trigger) bool {
+					return len(triggers) == 3
+				})).Return(nil).Once()
+			} else {
+				repo.On("Persist", mock.Anything, tier).Return(nil).Once()
+			}
+			repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Twice()
+			repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Once()
+			exchange := new(trigger_mock.MockExchangeService)
+			exchange.On("GetPositions", mock.Anything, mock.Anything).Return([]*exchange_domain.Position{{ContractID: contractID, Quantity: 30}}, nil)
+			exchange.On("CreateOrder", mock.Anything, mock.MatchedBy(func(params exchange_service.OrderParams) bool {
+				return params.Quantity != nil && *params.Quantity == tt.expectedQuantity
+			})).Return(&exchange_domain.Order{}, nil).Once()
+
+			executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+			executed, err := executor.ExecuteTrigger(context.Background(), tier)
+
+			require.NoError(t, err)
+			assert.Equal(t, trigger_domain.StatusTriggered, executed.Status)
+			for i, sibling := range ladder.Tiers {
+				if i == tt.tier {
+					continue
+				}
+				if tt.cancelsSiblings {
+					assert.Equal(t, trigger_domain.StatusCancelled, sibling.Status)
+				} else {
+					assert.Equal(t, trigger_domain.StatusActive, sibling.Status)
+				}
+			}
+			exchange.AssertExpectations(t)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReconcileExecutions(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bar := contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideNo}
+
+	newExecutingTrigger := func(t *testing.T, executingFor time.Duration) *trigger_domain.Trigger {
+		trigger, err := trigger_domain.NewStopTrigger(foo, 40, nil)
+		require.NoError(t, err)
+		sellBar, err := trigger_domain.NewTriggerAction(bar, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+		trigger.Actions = append(trigger.Actions, *sellBar)
+		trigger.Status = trigger_domain.StatusExecuting
+		trigger.UpdatedAt = time.Now().Add(-executingFor)
+		return trigger
+	}
+
+	t.Run("returns trigger to active when no order was placed", func(t *testing.T) {
+		trigger := newExecutingTrigger(t, time.Minute)
+
+		repo := new(trigger_mock.MockTriggerRepository)
+		repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+		repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.Status == trigger_domain.StatusActive
+		})).Return(nil).Once()
+		repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+		exchange := new(trigger_mock.MockExchangeService)
+		exchange.On("GetOrders", mock.Anything, mock.Anything).Return([]*exchange_domain.Order{
+			{Reference: "someone-else", Ticker: "FOO", Side: contract.SideYes, Action: exchange_domain.OrderActionSell},
+		}, nil)
+
+		executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+
+		require.NoError(t, executor.ReconcileExecutions(context.Background(), []*trigger_domain.Trigger{trigger}))
+		exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("sends the orders not yet placed and marks trigger triggered", func(t *testing.T) {
+		trigger := newExecutingTrigger(t, time.Minute)
+
+		repo := new(trigger_mock.MockTriggerRepository)
+		repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+		repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.Status == trigger_domain.StatusTriggered
+		})).Return(nil).Once()
+		repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+		// The order already placed is recorded alongside the one sent now
+		for i := range 2 {
+			repo.On("PersistOrders", mock.Anything, mock.MatchedBy(func(orders []trigger_domain.TriggerOrder) bool {
+				return len(orders) == 1 && orders[0].TriggerID == trigger.TriggerID && orders[0].ActionIndex == i
+			})).Return(nil).Once()
+		}
+		exchange := new(trigger_mock.MockExchangeService)
+		exchange.On("GetOrders", mock.Anything, mock.Anything).Return([]*exchange_domain.Order{{
+			Reference: trigger.TriggerID.OrderReference(0),
+			Ticker:    "FOO",
+			Side:      contract.SideYes,
+			Action:    exchange_domain.OrderActionSell,
+		}}, nil)
+		exchange.On("CreateOrder", mock.Anything, mock.MatchedBy(func(params exchange_service.OrderParams) bool {
+			return params.ContractID == bar && params.Reference == trigger.TriggerID.OrderReference(1)
+		})).Return(&exchange_domain.Order{}, nil).Once()
+
+		executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+
+		require.NoError(t, executor.ReconcileExecutions(context.Background(), []*trigger_domain.Trigger{trigger}))
+		exchange.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("leaves a trigger whose tickers are busy for later", func(t *testing.T) {
+		trigger := newExecutingTrigger(t, time.Minute)
+
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+		tickers := NewTickerLocks()
+		executor := NewTriggerExecutor(NewTriggerService(repo), exchange, tickers, nil)
+
+		// The order tracker is replacing an order in BAR
+		unlock := tickers.Lock("BAR")
+		defer unlock()
+
+		require.NoError(t, executor.ReconcileExecutions(context.Background(), []*trigger_domain.Trigger{trigger}))
+		exchange.AssertNotCalled(t, "GetOrders", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
+	t.Run("matches placed orders by their action index", func(t *testing.T) {
+		trigger := newExecutingTrigger(t, time.Minute)
+		// Two sells of the same contract only differ by the index in their references
+		size := uint(5)
+		sellSome, err := trigger_domain.NewTriggerAction(foo, trigger_domain.Sell, &size, nil)
+		require.NoError(t, err)
+		trigger.Actions = append(trigger.Actions, *sellSome)
+
+		repo := new(trigger_mock.MockTriggerRepository)
+		repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+		repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.Status == trigger_domain.StatusTriggered
+		})).Return(nil).Once()
+		repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Times(3)
+		exchange := new(trigger_mock.MockExchangeService)
+		exchange.On("GetOrders", mock.Anything, mock.Anything).Return([]*exchange_domain.Order{{
+			Reference: trigger.TriggerID.OrderReference(2),
+			Ticker:    "FOO",
+			Side:      contract.SideYes,
+			Action:    exchange_domain.OrderActionSell,
+		}}, nil)
+		for _, index := range []int{0, 1} {
+			exchange.On("CreateOrder", mock.Anything, mock.MatchedBy(func(params exchange_service.OrderParams) bool {
+				return params.Reference == trigger.TriggerID.OrderReference(index)
+			})).Return(&exchange_domain.Order{}, nil).Once()
+		}
+
+		executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+
+		require.NoError(t, executor.ReconcileExecutions(context.Background(), []*trigger_domain.Trigger{trigger}))
+		exchange.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("counts failed attempts and flags the trigger at the limit", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			failedAlready  int
+			expectedStatus trigger_domain.TriggerStatus
+		}{
+			{name: "below the limit", failedAlready: 0, expectedStatus: trigger_domain.StatusExecuting},
+			{name: "at the limit", failedAlready: maxReconcileAttempts - 1, expectedStatus: trigger_domain.StatusFlagged},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				trigger := newExecutingTrigger(t, time.Minute)
+				trigger.ReconcileAttempts = tt.failedAlready
+
+				repo := new(trigger_mock.MockTriggerRepository)
+				repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+				repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+					return t.ReconcileAttempts == tt.failedAlready+1 && t.Status == tt.expectedStatus
+				})).Return(nil).Once()
+				repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Maybe()
+				exchange := new(trigger_mock.MockExchangeService)
+				exchange.On("GetOrders", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+				executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+
+				err := executor.ReconcileExecutions(context.Background(), []*trigger_domain.Trigger{trigger})
+				assert.ErrorIs(t, err, assert.AnError)
+				assert.Equal(t, tt.expectedStatus, trigger.Status)
+				repo.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("skips a trigger that finished executing since it was loaded", func(t *testing.T) {
+		loaded := newExecutingTrigger(t, time.Minute)
+		stored := *loaded
+		stored.Status = trigger_domain.StatusTriggered
+
+		repo := new(trigger_mock.MockTriggerRepository)
+		repo.On("Get", mock.Anything, loaded.TriggerID).Return(&stored, nil)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+
+		require.NoError(t, executor.ReconcileExecutions(context.Background(), []*trigger_domain.Trigger{loaded}))
+		exchange.AssertNotCalled(t, "GetOrders", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+
+	t.Run("leaves executions within the grace period alone", func(t *testing.T) {
+		trigger := newExecutingTrigger(t, time.Second)
+
+		repo := new(trigger_mock.MockTriggerRepository)
+		exchange := new(trigger_mock.MockExchangeService)
+
+		executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+
+		require.NoError(t, executor.ReconcileExecutions(context.Background(), []*trigger_domain.Trigger{trigger}))
+		assert.Equal(t, trigger_domain.StatusExecuting, trigger.Status)
+		exchange.AssertNotCalled(t, "GetOrders", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+}
+
+func TestExecuteTrigger_OrderFailureLeavesTriggerExecuting(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+	require.NoError(t, err)
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+	repo.On("Persist", mock.Anything, trigger).Return(nil).Once()
+	repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("CreateOrder", mock.Anything, mock.MatchedBy(func(params exchange_service.OrderParams) bool {
+		return params.Reference == trigger.TriggerID.String()
+	})).Return(nil, assert.AnError).Once()
+
+	executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+	executed, err := executor.ExecuteTrigger(context.Background(), trigger)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, executed)
+	assert.Equal(t, trigger_domain.StatusExecuting, trigger.Status)
+	exchange.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestReconcileExecutions_WaitsForRunningExecution(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	stored, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+	require.NoError(t, err)
+	fired := *stored
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("Get", mock.Anything, stored.TriggerID).Return(stored, nil)
+	repo.On("Persist", mock.Anything, stored).Return(nil).Twice()
+	repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Twice()
+	repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Once()
+	exchange := new(trigger_mock.MockExchangeService)
+	executor := NewTriggerExecutor(NewTriggerService(repo), exchange, NewTickerLocks(), nil)
+
+	// The exchange is slow enough that the monitor's pass finds the trigger executing past
+	// the grace period and tries to reconcile it
+	reconciled := make(chan error, 1)
+	exchange.On("CreateOrder", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		stale := *stored
+		stale.UpdatedAt = time.Now().Add(-time.Hour)
+		go func() {
+			reconciled <- executor.ReconcileExecutions(context.Background(), []*trigger_domain.Trigger{&stale})
+		}()
+		time.Sleep(50 * time.Millisecond)
+	}).Return(&exchange_domain.Order{}, nil).Once()
+
+	executed, err := executor.ExecuteTrigger(context.Background(), &fired)
+	require.NoError(t, err)
+	require.NoError(t, <-reconciled)
+
+	assert.Equal(t, trigger_domain.StatusTriggered, executed.Status)
+	exchange.AssertNotCalled(t, "GetOrders", mock.Anything, mock.Anything)
+	exchange.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
@@ -1,45 +1,74 @@
 package trigger_service
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
 	exchange_service "prediction-risk/internal/app/exchange/service"
 	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/samber/lo"
 )
 
+// TriggerMonitor evaluates price-driven triggers on a fixed interval. Each pass
+// spreads the triggers over a bounded pool of workers, so a slow trigger only
+// holds up those sharing its tickers.
 type TriggerMonitor struct {
 	triggerService  *TriggerService
 	triggerExecutor *TriggerExecutor
 	exchangeService exchange_service.ExchangeService
+	tickers         *TickerLocks
 	interval        time.Duration
+	triggerTimeout  time.Duration
+	workers         chan struct{} // one slot per worker, shared by every pass
+	running         sync.WaitGroup
 	done            chan struct{}
-	isDryRun        bool
+	cancel          context.CancelFunc
 }
 
 func NewTriggerMonitor(
 	triggerService *TriggerService,
 	triggerExecutor *TriggerExecutor,
 	exchangeService exchange_service.ExchangeService,
+	tickers *TickerLocks,
 	interval time.Duration,
-	isDryRun bool,
+	workers int,
+	triggerTimeout time.Duration,
 ) *TriggerMonitor {
-	log.Printf("Initializing TriggerMonitor with interval: %v", interval)
+	log.Printf("Initializing TriggerMonitor with interval: %v, workers: %d", interval, workers)
 	return &TriggerMonitor{
 		triggerService:  triggerService,
 		triggerExecutor: triggerExecutor,
 		exchangeService: exchangeService,
+		tickers:         tickers,
 		interval:        interval,
+		triggerTimeout:  triggerTimeout,
+		workers:         make(chan struct{}, max(workers, 1)),
 		done:            make(chan struct{}),
 	}
 }
 
 func (m *TriggerMonitor) Start() {
 	log.Printf("Starting TriggerMonitor")
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.running.Add(1)
 	go func() {
+		defer m.running.Done()
+
+		// Settle executions a previous run left unfinished before evaluating anything
+		if triggers, err := m.triggerService.Get(ctx); err != nil {
+			log.Printf("Error getting triggers to reconcile: %v", err)
+		} else if err := m.triggerExecutor.ReconcileExecutions(ctx, triggers); err != nil {
+			log.Printf("Error reconciling executions: %v", err)
+		}
+
 		ticker := time.NewTicker(m.interval)
 		defer ticker.Stop()
 
@@ -50,7 +79,7 @@ func (m *TriggerMonitor) Start() {
 				return
 			case <-ticker.C:
 				log.Println("Running trigger check...")
-				if err := m.checkTriggers(); err != nil {
+				if err := m.checkTriggers(ctx); err != nil {
 					log.Printf("Error checking triggers: %v", err)
 				}
 			}
@@ -58,119 +87,415 @@ func (m *TriggerMonitor) Start() {
 	}()
 }
 
+// Stop cancels any pass in progress and waits for its workers to return. Triggers not yet
+// executed are left for the next start.
 func (m *TriggerMonitor) Stop() {
 	log.Println("Stopping TriggerMonitor...")
+	if m.cancel != nil {
+		m.cancel()
+	}
 	close(m.done)
+	m.running.Wait()
 }
 
-func (m *TriggerMonitor) checkTriggers() error {
-	triggers, err := m.triggerService.Get()
+// checkTriggers starts evaluating the active triggers and returns once every group of
+// them has a worker. Workers outlive the pass, so a slow trigger never delays the next
+// one; a group whose tickers are still held, by a worker from an earlier pass or by
+// anything else trading them, is skipped until a later pass.
+func (m *TriggerMonitor) checkTriggers(ctx context.Context) error {
+	// Triggers are loaded once per pass, for reconciling and evaluating alike
+	triggers, err := m.triggerService.Get(ctx)
 	if err != nil {
-		return fmt.Errorf("getting orders: %w", err)
+		return fmt.Errorf("getting triggers: %w", err)
 	}
+
+	// Executions interrupted since the last pass are settled before they could fire again.
+	// A trigger reconciled back to active is still executing in this pass's copy, so it is
+	// evaluated from the next pass on.
+	if err := m.triggerExecutor.ReconcileExecutions(ctx, triggers); err != nil {
+		log.Printf("Error reconciling executions: %v", err)
+	}
+	// Triggers watching the weather are evaluated as observations and forecasts arrive instead
 	activeTriggers := lo.Filter(triggers, func(o *trigger_domain.Trigger, _ int) bool {
-		return o.Status == trigger_domain.StatusActive
+		return o.Status == trigger_domain.StatusActive &&
+			len(o.Condition.Stations()) == 0 &&
+			len(o.Condition.Gridpoints()) == 0
 	})
 	log.Printf("Found %d active stop triggers", len(activeTriggers))
 
-	executedTriggers := make([]*trigger_domain.Trigger, 0)
-	executionErrors := make([]error, 0)
+	// Cost basis stops follow the position, so fetch positions once per pass if any need them
+	positions, err := m.getCostBasisPositions(ctx, activeTriggers)
+	if err != nil {
+		return err
+	}
 
-	for _, trigger := range activeTriggers {
-		log.Printf("Checking %s trigger %s...",
-			trigger.TriggerType,
-			trigger.TriggerID,
-		)
+	// Fetch every referenced market once for the whole pass
+	data, err := fetchMarketData(ctx, m.exchangeService, activeTriggers)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
 
-		// Get current price of the contract
-		market, err := m.exchangeService.GetMarket(trigger.Condition.Contract.Ticker)
-		if err != nil {
-			executionErrors = append(executionErrors, err)
-			continue
+	// Triggers sharing a ticker run in order on the same worker, which holds their tickers
+	// until the whole group is done
+	for _, group := range groupByTicker(activeTriggers, data) {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-
-		// Get the current price of the contract
-		var currentPrice contract.ContractPrice
-		switch trigger.Condition.Contract.Side {
-		case contract.SideYes:
-			currentPrice = market.Pricing.YesSide.Ask
-		case contract.SideNo:
-			currentPrice = market.Pricing.NoSide.Ask
-		default:
-			executionErrors = append(executionErrors, fmt.Errorf("invalid contract side: %s", trigger.Condition.Contract.Side))
-			continue
+		select {
+		case m.workers <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
-
-		// Check if the trigger condition is met
-		isSatisfed, err := trigger.Condition.IsSatisfied(currentPrice)
-		if err != nil {
-			executionErrors = append(executionErrors, err)
+		tickers := lo.FlatMap(group, func(t *trigger_domain.Trigger, _ int) []contract.Ticker {
+			return data.tickersActedOn(t)
+		})
+		unlock, ok := m.tickers.TryLock(tickers...)
+		if !ok {
+			<-m.workers
+			log.Printf("Skipping %d triggers until their tickers are free", len(group))
 			continue
 		}
 
-		// If the trigger condition is met, execute the trigger
-		if isSatisfed {
-			executedTrigger, err := m.triggerExecutor.ExecuteTrigger(trigger)
-			if err != nil {
-				executionErrors = append(executionErrors, err)
-				continue
-			} else {
-				executedTriggers = append(executedTriggers, executedTrigger)
+		m.running.Add(1)
+		go func() {
+			defer m.running.Done()
+			defer func() { <-m.workers }()
+			defer unlock()
+			for _, trigger := range group {
+				result, err := m.evaluateTrigger(ctx, trigger, data, positions, now)
+				logResult(result, err)
 			}
-		}
+		}()
 	}
 
-	// Log the executed triggers and errors
-	log.Printf("Executed %d triggers", len(executedTriggers))
-	for _, trigger := range executedTriggers {
-		log.Printf("Executed trigger %s", trigger.TriggerID)
-	}
-	log.Printf("Encountered %d execution errors", len(executionErrors))
-	for _, err := range executionErrors {
+	return ctx.Err()
+}
+
+// logResult reports what evaluating a trigger came to
+func logResult(result *trigger_domain.Trigger, err error) {
+	switch {
+	case err != nil:
 		log.Printf("Execution error: %v", err)
+	case result.Status == trigger_domain.StatusTriggered:
+		log.Printf("Executed trigger %s", result.TriggerID)
+	case result.Status == trigger_domain.StatusExpired:
+		log.Printf("Expired trigger %s: %s %s", result.TriggerID, result.Expiry.Reason, result.Expiry.Ticker)
 	}
+}
 
-	return nil
+// processTrigger fetches the market data for a single trigger and evaluates it
+func (m *TriggerMonitor) processTrigger(ctx context.Context, trigger *trigger_domain.Trigger) (*trigger_domain.Trigger, error) {
+	data, err := fetchMarketData(ctx, m.exchangeService, []*trigger_domain.Trigger{trigger})
+	if err != nil {
+		return nil, err
+	}
+	positions, err := m.getCostBasisPositions(ctx, []*trigger_domain.Trigger{trigger})
+	if err != nil {
+		return nil, err
+	}
+	unlock := m.tickers.Lock(data.tickersActedOn(trigger)...)
+	defer unlock()
+	return m.evaluateTrigger(ctx, trigger, data, positions, time.Now())
 }
 
-func (m *TriggerMonitor) processTrigger(trigger *trigger_domain.Trigger) (*trigger_domain.Trigger, error) {
+// evaluateTrigger runs one trigger against the pass's market data. The trigger's
+// deadline and any cancellation are checked between steps, and always before an
+// order is placed, so a cancelled trigger never starts executing.
+func (m *TriggerMonitor) evaluateTrigger(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	data *marketData,
+	positions map[contract.ContractIdentifier]*exchange_domain.Position,
+	now time.Time,
+) (*trigger_domain.Trigger, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.triggerTimeout)
+	defer cancel()
+
+	checkpoint := func() error {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("trigger %s: %w", trigger.TriggerID, err)
+		}
+		return nil
+	}
+	if err := checkpoint(); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Checking %s trigger %s...",
 		trigger.TriggerType,
 		trigger.TriggerID,
 	)
 
-	// Get current price of the contract
-	market, err := m.exchangeService.GetMarket(trigger.Condition.Contract.Ticker)
+	// Get the current state of every market the trigger references
+	snapshot, err := data.snapshot(trigger, now)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get the current price of the contract
-	var currentPrice contract.ContractPrice
-	switch trigger.Condition.Contract.Side {
-	case contract.SideYes:
-		currentPrice = market.Pricing.YesSide.Ask
-	case contract.SideNo:
-		currentPrice = market.Pricing.NoSide.Ask
-	default:
-		return nil, fmt.Errorf("invalid contract side: %s", trigger.Condition.Contract.Side)
+	// Stop watching markets that can no longer trade
+	if expiry := snapshot.Expiry(); expiry != nil {
+		return m.triggerService.ExpireTrigger(ctx, trigger, *expiry)
 	}
 
-	// Check if the trigger condition is met
-	isSatisfed, err := trigger.Condition.IsSatisfied(currentPrice)
+	// Cost basis stops follow the position's average entry
+	trigger, err = m.updateCostBasis(ctx, trigger, positions)
 	if err != nil {
 		return nil, err
 	}
 
-	// If the trigger condition is met, execute the trigger
-	if isSatisfed {
-		executedTrigger, err := m.triggerExecutor.ExecuteTrigger(trigger)
+	// Trailing stops follow the price up before being evaluated
+	if trigger.TrailingStop != nil {
+		currentPrice, err := snapshot.Price(trigger.Condition.Contract, trigger.Condition.Price.Source)
+		if err != nil {
+			return nil, err
+		}
+		trigger, err = m.triggerService.UpdateTrailingStop(ctx, trigger, currentPrice)
 		if err != nil {
 			return nil, err
-		} else {
-			return executedTrigger, nil
 		}
 	}
 
+	// Check if the trigger condition is met
+	isSatisfed, err := trigger.Condition.IsSatisfied(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkpoint(); err != nil {
+		return nil, err
+	}
+
+	// Hold the trigger until its confirmation policy, if any, is met
+	confirmed, err := m.triggerService.ConfirmTrigger(ctx, trigger, isSatisfed, snapshot.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the trigger condition is confirmed, execute the trigger
+	if confirmed {
+		if err := checkpoint(); err != nil {
+			return nil, err
+		}
+		return m.triggerExecutor.ExecuteTrigger(ctx, trigger)
+	}
+
 	return trigger, nil
 }
+
+// groupByTicker partitions triggers so that any two acting on the same ticker land
+// in the same group, keeping the order they were given in
+func groupByTicker(triggers []*trigger_domain.Trigger, data *marketData) [][]*trigger_domain.Trigger {
+	parent := make([]int, len(triggers))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	owners := make(map[contract.Ticker]int)
+	for i, trigger := range triggers {
+		for _, ticker := range data.tickersActedOn(trigger) {
+			if j, ok := owners[ticker]; ok {
+				parent[find(i)] = find(j)
+			} else {
+				owners[ticker] = i
+			}
+		}
+	}
+
+	groups := make(map[int][]*trigger_domain.Trigger)
+	roots := make([]int, 0)
+	for i, trigger := range triggers {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], trigger)
+	}
+	return lo.Map(roots, func(root int, _ int) []*trigger_domain.Trigger {
+		return groups[root]
+	})
+}
+
+// getCostBasisPositions fetches current positions by contract, or nil if no trigger follows its cost basis
+func (m *TriggerMonitor) getCostBasisPositions(
+	ctx context.Context,
+	triggers []*trigger_domain.Trigger,
+) (map[contract.ContractIdentifier]*exchange_domain.Position, error) {
+	if !lo.SomeBy(triggers, func(t *trigger_domain.Trigger) bool { return t.CostBasis != nil }) {
+		return nil, nil
+	}
+
+	positions, err := m.exchangeService.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get positions: %w", err)
+	}
+	return lo.KeyBy(positions, func(p *exchange_domain.Position) contract.ContractIdentifier {
+		return p.ContractID
+	}), nil
+}
+
+// updateCostBasis moves a cost basis stop to follow its position. Triggers whose position
+// has been closed keep their last stop level.
+func (m *TriggerMonitor) updateCostBasis(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	positions map[contract.ContractIdentifier]*exchange_domain.Position,
+) (*trigger_domain.Trigger, error) {
+	if trigger.CostBasis == nil {
+		return trigger, nil
+	}
+	position, ok := positions[trigger.Condition.Contract]
+	if !ok {
+		return trigger, nil
+	}
+	return m.triggerService.UpdateCostBasis(ctx, trigger, *position)
+}
+
+// marketData is the exchange state fetched once and shared by every trigger
+// evaluated against it
+type marketData struct {
+	markets        map[contract.Ticker]*exchange_domain.Market
+	eventPositions []*exchange_domain.EventPosition
+}
+
+// fetchMarketData gets every market the triggers reference in one batch, so the
+// cost of a pass scales with the distinct tickers rather than the triggers. If any
+// trigger watches or flattens an event, event positions and the markets they hold are
+// fetched too.
+func fetchMarketData(
+	ctx context.Context,
+	exchangeService exchange_service.ExchangeService,
+	triggers []*trigger_domain.Trigger,
+) (*marketData, error) {
+	data := &marketData{markets: make(map[contract.Ticker]*exchange_domain.Market)}
+	tickers := lo.FlatMap(triggers, func(t *trigger_domain.Trigger, _ int) []contract.Ticker {
+		return t.Tickers()
+	})
+
+	if lo.SomeBy(triggers, func(t *trigger_domain.Trigger) bool { return len(eventsActedOn(t)) > 0 }) {
+		eventPositions, err := exchangeService.GetEventPositions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get event positions: %w", err)
+		}
+		data.eventPositions = eventPositions
+		for _, event := range eventPositions {
+			for _, position := range event.Positions {
+				tickers = append(tickers, position.ContractID.Ticker)
+			}
+		}
+	}
+
+	tickers = lo.Uniq(tickers)
+	if len(tickers) == 0 {
+		return data, nil
+	}
+	markets, err := exchangeService.GetMarkets(ctx, tickers)
+	if err != nil {
+		return nil, fmt.Errorf("get markets: %w", err)
+	}
+	for _, market := range markets {
+		data.markets[market.Ticker] = market
+	}
+
+	return data, nil
+}
+
+// market returns a fetched market, failing only the trigger that needs a market the exchange did not return
+func (d *marketData) market(ticker contract.Ticker) (*exchange_domain.Market, error) {
+	market, ok := d.markets[ticker]
+	if !ok {
+		return nil, fmt.Errorf("get market %s: %w", ticker, core.NewErrNotFound("market", string(ticker)))
+	}
+	return market, nil
+}
+
+// snapshot builds the trigger's view of the fetched markets
+func (d *marketData) snapshot(trigger *trigger_domain.Trigger, now time.Time) (trigger_domain.MarketSnapshot, error) {
+	var markets []*exchange_domain.Market
+	for _, ticker := range trigger.Tickers() {
+		market, err := d.market(ticker)
+		if err != nil {
+			return trigger_domain.MarketSnapshot{}, err
+		}
+		markets = append(markets, market)
+	}
+
+	snapshot := trigger_domain.NewMarketSnapshot(markets, now)
+
+	// Event conditions look at everything held across the event's markets
+	for _, eventTicker := range trigger.Condition.Events() {
+		exposure, err := d.eventExposure(eventTicker)
+		if err != nil {
+			return trigger_domain.MarketSnapshot{}, err
+		}
+		snapshot.Events[eventTicker] = exposure
+	}
+
+	return snapshot, nil
+}
+
+// tickersActedOn lists every ticker the trigger watches or may trade
+func (d *marketData) tickersActedOn(trigger *trigger_domain.Trigger) []contract.Ticker {
+	return tickersActedOn(trigger, d.eventPositions)
+}
+
+// tickersActedOn lists every ticker the trigger watches or may trade, including the events
+// it watches or flattens and the markets held in them
+func tickersActedOn(
+	trigger *trigger_domain.Trigger,
+	eventPositions []*exchange_domain.EventPosition,
+) []contract.Ticker {
+	tickers := append([]contract.Ticker{}, trigger.Tickers()...)
+	for _, eventTicker := range eventsActedOn(trigger) {
+		tickers = append(tickers, eventTicker)
+		for _, event := range eventPositions {
+			if event.EventTicker != eventTicker {
+				continue
+			}
+			for _, position := range event.Positions {
+				tickers = append(tickers, position.ContractID.Ticker)
+			}
+		}
+	}
+	return tickers
+}
+
+// eventsActedOn lists the events the trigger's condition watches or its actions flatten
+func eventsActedOn(trigger *trigger_domain.Trigger) []contract.Ticker {
+	events := trigger.Condition.Events()
+	for _, action := range trigger.Actions {
+		if action.FlattensEvent() && !slices.Contains(events, action.Event) {
+			events = append(events, action.Event)
+		}
+	}
+	return events
+}
+
+// eventExposure marks the event's open positions to their markets. An event
+// Kalshi does not report has nothing held in it.
+func (d *marketData) eventExposure(eventTicker contract.Ticker) (trigger_domain.EventExposure, error) {
+	position := exchange_domain.EventPosition{EventTicker: eventTicker}
+	if found, ok := lo.Find(d.eventPositions, func(p *exchange_domain.EventPosition) bool {
+		return p.EventTicker == eventTicker
+	}); ok {
+		position = *found
+	}
+
+	markets := make(map[contract.Ticker]*exchange_domain.Market, len(position.Positions))
+	for _, p := range position.Positions {
+		market, err := d.market(p.ContractID.Ticker)
+		if err != nil {
+			return trigger_domain.EventExposure{}, err
+		}
+		markets[p.ContractID.Ticker] = market
+	}
+
+	return trigger_domain.NewEventExposure(position, markets)
+}
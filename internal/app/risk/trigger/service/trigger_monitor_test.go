@@ -1 +1,484 @@
 package trigger_service
+
+import (
+	"context"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	exchange_service "prediction-risk/internal/app/exchange/service"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_mock "prediction-risk/internal/app/risk/trigger/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerMonitor_processTrigger_ExpiresEndedMarkets(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	tests := []struct {
+		name           string
+		state          exchange_domain.MarketState
+		expectedStatus trigger_domain.TriggerStatus
+		expectedReason trigger_domain.ExpiryReason
+	}{
+		{name: "open market stays active", state: exchange_domain.MarketStateOpen, expectedStatus: trigger_domain.StatusActive},
+		{
+			name:           "closed market",
+			state:          exchange_domain.MarketStateClosed,
+			expectedStatus: trigger_domain.StatusExpired,
+			expectedReason: trigger_domain.ExpiryReasonMarketClosed,
+		},
+		{
+			name:           "settled market",
+			state:          exchange_domain.MarketStateSettled,
+			expectedStatus: trigger_domain.StatusExpired,
+			expectedReason: trigger_domain.ExpiryReasonMarketSettled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := trigger_domain.NewStopTrigger(foo, 40, nil)
+			require.NoError(t, err)
+
+			repo := new(trigger_mock.MockTriggerRepository)
+			exchange := new(trigger_mock.MockExchangeService)
+			exchange.On("GetMarkets", mock.Anything, []contract.Ticker{foo.Ticker}).Return([]*exchange_domain.Market{{
+				Ticker:  foo.Ticker,
+				Status:  exchange_domain.MarketStatus{State: tt.state},
+				Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 60}},
+			}}, nil)
+			if tt.expectedStatus == trigger_domain.StatusExpired {
+				repo.On("Expire", mock.Anything, trigger, trigger_domain.StatusActive).Return(nil).Once()
+				repo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+					return len(changes) == 1 && *changes[0].NewValue == trigger_domain.StatusExpired.String()
+				})).Return(nil).Once()
+			}
+
+			triggerService := NewTriggerService(repo)
+			monitor := NewTriggerMonitor(triggerService, NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil), exchange, NewTickerLocks(), time.Second, 4, time.Second)
+
+			result, err := monitor.processTrigger(context.Background(), trigger)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			if tt.expectedReason != "" {
+				require.NotNil(t, result.Expiry)
+				assert.Equal(t, tt.expectedReason, result.Expiry.Reason)
+				assert.Equal(t, foo.Ticker, result.Expiry.Ticker)
+			}
+			exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTriggerMonitor_processTrigger_WaitsForConfirmation(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	trigger, err := trigger_domain.NewStopTrigger(foo, 40, nil)
+	require.NoError(t, err)
+	trigger.Confirmation, err = trigger_domain.NewEvaluationConfirmation(3)
+	require.NoError(t, err)
+
+	market := func(ask contract.ContractPrice) *exchange_domain.Market {
+		return &exchange_domain.Market{
+			Ticker:  foo.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: ask}},
+		}
+	}
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("GetMarkets", mock.Anything, []contract.Ticker{foo.Ticker}).Return([]*exchange_domain.Market{market(35)}, nil).Twice()
+	exchange.On("GetMarkets", mock.Anything, []contract.Ticker{foo.Ticker}).Return([]*exchange_domain.Market{market(45)}, nil).Once()
+	repo.On("UpdateConfirmation", mock.Anything, trigger, mock.Anything).Return(nil).Times(3)
+
+	triggerService := NewTriggerService(repo)
+	monitor := NewTriggerMonitor(triggerService, NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil), exchange, NewTickerLocks(), time.Second, 4, time.Second)
+
+	// Two spikes below the stop are recorded but do not fire
+	for i := 1; i <= 2; i++ {
+		result, err := monitor.processTrigger(context.Background(), trigger)
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusActive, result.Status)
+		assert.Equal(t, i, result.Confirmation.SatisfiedCount)
+	}
+
+	// Recovery resets the pending confirmation
+	result, err := monitor.processTrigger(context.Background(), trigger)
+	require.NoError(t, err)
+	assert.Equal(t, trigger_domain.StatusActive, result.Status)
+	assert.False(t, result.Confirmation.IsPending())
+
+	exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestTriggerMonitor_processTrigger_FollowsCostBasis(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	// 10 contracts at 50c, stop 10c below entry
+	trigger, err := trigger_domain.NewCostBasisStopTrigger(
+		exchange_domain.Position{ContractID: foo, Quantity: 10, CostBasis: 500},
+		trigger_domain.TrailTypeCents,
+		10,
+	)
+	require.NoError(t, err)
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("GetMarkets", mock.Anything, []contract.Ticker{foo.Ticker}).Return([]*exchange_domain.Market{{
+		Ticker:  foo.Ticker,
+		Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+		Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 45}},
+	}}, nil)
+	// Averaging up to 60c moves the stop to 50c
+	exchange.On("GetPositions", mock.Anything, mock.Anything).Return([]*exchange_domain.Position{
+		{ContractID: foo, Quantity: 20, CostBasis: 1200},
+	}, nil)
+	repo.On("UpdateCostBasis", mock.Anything, trigger).Return(nil).Once()
+	exchange.On("CreateOrder", mock.Anything, mock.Anything).Return(&exchange_domain.Order{}, nil).Once()
+	repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Once()
+	repo.On("Persist", mock.Anything, trigger).Return(nil).Twice()
+	repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Times(3)
+	repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+
+	triggerService := NewTriggerService(repo)
+	monitor := NewTriggerMonitor(triggerService, NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil), exchange, NewTickerLocks(), time.Second, 4, time.Second)
+
+	_, err = monitor.processTrigger(context.Background(), trigger)
+
+	require.NoError(t, err)
+	assert.Equal(t, contract.ContractPrice(60), trigger.CostBasis.AverageEntry)
+	assert.Equal(t, contract.ContractPrice(50), trigger.Condition.Price.Threshold)
+	exchange.AssertExpectations(t)
+}
+
+func TestTriggerMonitor_processTrigger_FlattensEventOnLoss(t *testing.T) {
+	yes := contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB13-T40", Side: contract.SideYes}
+	no := contract.ContractIdentifier{Ticker: "KXHIGHNY-25FEB13-B41.5", Side: contract.SideNo}
+
+	// Flatten once the event has lost $40
+	trigger, err := trigger_domain.NewEventExposureTrigger("KXHIGHNY-25FEB13", trigger_domain.EventMetricPNL, -4000, trigger_domain.Below)
+	require.NoError(t, err)
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	exchange := new(trigger_mock.MockExchangeService)
+	// $100 in, now worth 200*10 + 100*35 = $55 at the bid, so down $45
+	exchange.On("GetEventPositions", mock.Anything, mock.Anything).Return([]*exchange_domain.EventPosition{
+		{
+			EventTicker: "KXHIGHNY-25FEB13",
+			Exposure:    10000,
+			Positions: []*exchange_domain.Position{
+				{ContractID: yes, Quantity: 200, CostBasis: 6000},
+				{ContractID: no, Quantity: 100, CostBasis: 4000},
+			},
+		},
+	}, nil)
+	// Markets for both positions come back in a single batch
+	exchange.On("GetMarkets", mock.Anything, []contract.Ticker{yes.Ticker, no.Ticker}).Return([]*exchange_domain.Market{
+		{
+			Ticker:  yes.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Bid: 10, Ask: 12}},
+		},
+		{
+			Ticker:  no.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{NoSide: exchange_domain.PricingSide{Bid: 35, Ask: 37}},
+		},
+	}, nil).Once()
+	// Each order carries its own client order ID derived from the trigger ID
+	for i, contractID := range []contract.ContractIdentifier{yes, no} {
+		exchange.On("CreateOrder", mock.Anything, exchange_service.OrderParams{
+			ContractID: contractID,
+			Action:     exchange_domain.OrderActionSell,
+			Reference:  trigger.TriggerID.OrderReference(i),
+		}).Return(&exchange_domain.Order{ExchangeOrderID: string(contractID.Ticker)}, nil).Once()
+		repo.On("PersistOrders", mock.Anything, []trigger_domain.TriggerOrder{{
+			TriggerID:   trigger.TriggerID,
+			ActionIndex: i,
+			Order:       exchange_domain.Order{ExchangeOrderID: string(contractID.Ticker)},
+		}}).Return(nil).Once()
+	}
+	repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+	repo.On("Persist", mock.Anything, trigger).Return(nil).Twice()
+	repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Twice()
+
+	triggerService := NewTriggerService(repo)
+	monitor := NewTriggerMonitor(triggerService, NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil), exchange, NewTickerLocks(), time.Second, 4, time.Second)
+
+	executed, err := monitor.processTrigger(context.Background(), trigger)
+
+	require.NoError(t, err)
+	assert.Equal(t, trigger_domain.StatusTriggered, executed.Status)
+	exchange.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestTriggerMonitor_checkTriggers_FetchesMarketsOncePerPass(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bar := contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideNo}
+
+	fooStop, err := trigger_domain.NewStopTrigger(foo, 40, nil)
+	require.NoError(t, err)
+	fooLowerStop, err := trigger_domain.NewStopTrigger(foo, 30, nil)
+	require.NoError(t, err)
+	barStop, err := trigger_domain.NewStopTrigger(bar, 40, nil)
+	require.NoError(t, err)
+	// MISSING is not returned by the exchange, which fails only its own trigger
+	missingStop, err := trigger_domain.NewStopTrigger(contract.ContractIdentifier{Ticker: "MISSING", Side: contract.SideYes}, 40, nil)
+	require.NoError(t, err)
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{fooStop, fooLowerStop, barStop, missingStop}, nil)
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("GetMarkets", mock.Anything, []contract.Ticker{"FOO", "BAR", "MISSING"}).Return([]*exchange_domain.Market{
+		{
+			Ticker:  foo.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 60}},
+		},
+		{
+			Ticker:  bar.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{NoSide: exchange_domain.PricingSide{Ask: 60}},
+		},
+	}, nil).Once()
+
+	triggerService := NewTriggerService(repo)
+	monitor := NewTriggerMonitor(triggerService, NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil), exchange, NewTickerLocks(), time.Second, 4, time.Second)
+
+	require.NoError(t, monitor.checkTriggers(context.Background()))
+	monitor.running.Wait()
+
+	exchange.AssertExpectations(t)
+	exchange.AssertNotCalled(t, "GetMarket", mock.Anything, mock.Anything)
+	exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+}
+
+func TestGroupByTicker(t *testing.T) {
+	newStop := func(ticker contract.Ticker) *trigger_domain.Trigger {
+		trigger, err := trigger_domain.NewStopTrigger(contract.ContractIdentifier{Ticker: ticker, Side: contract.SideYes}, 40, nil)
+		require.NoError(t, err)
+		return trigger
+	}
+	fooStop := newStop("KXHIGHNY-25FEB13-T40")
+	fooLowerStop := newStop("KXHIGHNY-25FEB13-T40")
+	barStop := newStop("KXHIGHNY-25FEB13-B41.5")
+	quxStop := newStop("QUX")
+	eventTrigger, err := trigger_domain.NewEventExposureTrigger("KXHIGHNY-25FEB13", trigger_domain.EventMetricPNL, -4000, trigger_domain.Below)
+	require.NoError(t, err)
+
+	// The event holds both bracket markets, so flattening it trades FOO and BAR
+	data := &marketData{
+		eventPositions: []*exchange_domain.EventPosition{
+			{
+				EventTicker: "KXHIGHNY-25FEB13",
+				Positions: []*exchange_domain.Position{
+					{ContractID: fooStop.Condition.Contract, Quantity: 10},
+					{ContractID: barStop.Condition.Contract, Quantity: 10},
+				},
+			},
+		},
+	}
+
+	t.Run("triggers on different tickers run apart", func(t *testing.T) {
+		groups := groupByTicker([]*trigger_domain.Trigger{fooStop, barStop, quxStop}, data)
+		assert.Equal(t, [][]*trigger_domain.Trigger{{fooStop}, {barStop}, {quxStop}}, groups)
+	})
+
+	t.Run("triggers sharing a ticker run together in order", func(t *testing.T) {
+		groups := groupByTicker([]*trigger_domain.Trigger{fooStop, quxStop, fooLowerStop}, data)
+		assert.Equal(t, [][]*trigger_domain.Trigger{{fooStop, fooLowerStop}, {quxStop}}, groups)
+	})
+
+	t.Run("event triggers join the triggers on the markets they hold", func(t *testing.T) {
+		groups := groupByTicker([]*trigger_domain.Trigger{fooStop, barStop, quxStop, eventTrigger}, data)
+		assert.Equal(t, [][]*trigger_domain.Trigger{{fooStop, barStop, eventTrigger}, {quxStop}}, groups)
+	})
+}
+
+func TestTriggerMonitor_checkTriggers_EvaluatesInParallel(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bar := contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes}
+	fooStop, err := trigger_domain.NewStopTrigger(foo, 40, nil)
+	require.NoError(t, err)
+	barStop, err := trigger_domain.NewStopTrigger(bar, 40, nil)
+	require.NoError(t, err)
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{fooStop, barStop}, nil)
+	repo.On("Get", mock.Anything, fooStop.TriggerID).Return(fooStop, nil)
+	repo.On("Get", mock.Anything, barStop.TriggerID).Return(barStop, nil)
+	repo.On("Persist", mock.Anything, mock.Anything).Return(nil)
+	repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil)
+	repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil)
+
+	// Both stops are hit
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("GetMarkets", mock.Anything, []contract.Ticker{"FOO", "BAR"}).Return([]*exchange_domain.Market{
+		{
+			Ticker:  foo.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 30}},
+		},
+		{
+			Ticker:  bar.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 30}},
+		},
+	}, nil)
+
+	// FOO's order hangs until BAR's is placed, which only happens if they run concurrently
+	barPlaced := make(chan struct{})
+	exchange.On("CreateOrder", mock.Anything, mock.MatchedBy(func(p exchange_service.OrderParams) bool {
+		return p.ContractID == foo
+	})).Run(func(mock.Arguments) {
+		select {
+		case <-barPlaced:
+		case <-time.After(time.Second):
+			t.Error("FOO's order blocked BAR's")
+		}
+	}).Return(&exchange_domain.Order{}, nil).Once()
+	exchange.On("CreateOrder", mock.Anything, mock.MatchedBy(func(p exchange_service.OrderParams) bool {
+		return p.ContractID == bar
+	})).Run(func(mock.Arguments) {
+		close(barPlaced)
+	}).Return(&exchange_domain.Order{}, nil).Once()
+
+	triggerService := NewTriggerService(repo)
+	monitor := NewTriggerMonitor(triggerService, NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil), exchange, NewTickerLocks(), time.Second, 2, time.Second)
+
+	require.NoError(t, monitor.checkTriggers(context.Background()))
+	monitor.running.Wait()
+
+	exchange.AssertExpectations(t)
+}
+
+func TestTriggerMonitor_checkTriggers_StopsWhenCancelled(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	fooStop, err := trigger_domain.NewStopTrigger(foo, 40, nil)
+	require.NoError(t, err)
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{fooStop}, nil)
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("GetMarkets", mock.Anything, []contract.Ticker{"FOO"}).Return([]*exchange_domain.Market{
+		{
+			Ticker:  foo.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 30}},
+		},
+	}, nil)
+
+	triggerService := NewTriggerService(repo)
+	monitor := NewTriggerMonitor(triggerService, NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil), exchange, NewTickerLocks(), time.Second, 2, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, monitor.checkTriggers(ctx), context.Canceled)
+	monitor.running.Wait()
+	exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+}
+
+func TestTriggerMonitor_checkTriggers_SkipsBusyTickers(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bar := contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes}
+	fooStop, err := trigger_domain.NewStopTrigger(foo, 40, nil)
+	require.NoError(t, err)
+	barStop, err := trigger_domain.NewStopTrigger(bar, 40, nil)
+	require.NoError(t, err)
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{fooStop, barStop}, nil)
+	repo.On("Get", mock.Anything, barStop.TriggerID).Return(barStop, nil)
+	repo.On("Persist", mock.Anything, barStop).Return(nil)
+	repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil)
+	repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil)
+
+	// Both stops are hit
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("GetMarkets", mock.Anything, []contract.Ticker{"FOO", "BAR"}).Return([]*exchange_domain.Market{
+		{
+			Ticker:  foo.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 30}},
+		},
+		{
+			Ticker:  bar.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 30}},
+		},
+	}, nil)
+	exchange.On("CreateOrder", mock.Anything, mock.MatchedBy(func(p exchange_service.OrderParams) bool {
+		return p.ContractID == bar
+	})).Return(&exchange_domain.Order{}, nil).Once()
+
+	// A worker from an earlier pass is still acting on FOO
+	tickers := NewTickerLocks()
+	unlock := tickers.Lock("FOO")
+	defer unlock()
+
+	triggerService := NewTriggerService(repo)
+	monitor := NewTriggerMonitor(triggerService, NewTriggerExecutor(triggerService, exchange, tickers, nil), exchange, tickers, time.Second, 2, time.Second)
+
+	require.NoError(t, monitor.checkTriggers(context.Background()))
+	monitor.running.Wait()
+
+	exchange.AssertExpectations(t)
+	exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.MatchedBy(func(p exchange_service.OrderParams) bool {
+		return p.ContractID == foo
+	}))
+	repo.AssertNotCalled(t, "Get", mock.Anything, fooStop.TriggerID)
+}
+
+func TestTriggerMonitor_Stop_WaitsForWorkers(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	fooStop, err := trigger_domain.NewStopTrigger(foo, 40, nil)
+	require.NoError(t, err)
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{fooStop}, nil)
+	repo.On("Get", mock.Anything, fooStop.TriggerID).Return(fooStop, nil)
+	repo.On("Persist", mock.Anything, fooStop).Return(nil)
+	repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil)
+
+	exchange := new(trigger_mock.MockExchangeService)
+	exchange.On("GetMarkets", mock.Anything, []contract.Ticker{"FOO"}).Return([]*exchange_domain.Market{
+		{
+			Ticker:  foo.Ticker,
+			Status:  exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			Pricing: exchange_domain.MarketPricing{YesSide: exchange_domain.PricingSide{Ask: 30}},
+		},
+	}, nil)
+
+	// The order is still being sent when the monitor is stopped, and gives up once cancelled
+	sending := make(chan struct{})
+	returned := false
+	exchange.On("CreateOrder", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(sending)
+		<-args.Get(0).(context.Context).Done()
+		time.Sleep(10 * time.Millisecond)
+		returned = true
+	}).Return(nil, context.Canceled).Once()
+
+	triggerService := NewTriggerService(repo)
+	monitor := NewTriggerMonitor(triggerService, NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil), exchange, NewTickerLocks(), time.Second, 2, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor.cancel = cancel
+
+	require.NoError(t, monitor.checkTriggers(ctx))
+	<-sending
+	monitor.Stop()
+
+	assert.True(t, returned)
+	exchange.AssertExpectations(t)
+}
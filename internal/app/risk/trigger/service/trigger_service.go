@@ -5,20 +5,39 @@ import (
 	"errors"
 	"fmt"
 	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
 	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
 	"time"
+
+	"github.com/samber/lo"
 )
 
 var (
 	ErrInvalidTrigger     = errors.New("invalid trigger")
 	ErrTriggerNotFound    = errors.New("trigger not found")
 	ErrInvalidTriggerType = errors.New("invalid trigger type")
+	ErrGuardFailed        = errors.New("pre-trade guard failed")
 )
 
 type TriggerRepository interface {
 	Persist(ctx context.Context, trigger *trigger_domain.Trigger) error
+	UpdateTrailingStop(ctx context.Context, trigger *trigger_domain.Trigger) error
+	UpdateCostBasis(ctx context.Context, trigger *trigger_domain.Trigger) error
+	Expire(ctx context.Context, trigger *trigger_domain.Trigger, from trigger_domain.TriggerStatus) error
+	UpdateConfirmation(ctx context.Context, trigger *trigger_domain.Trigger, at time.Time) error
 	Get(ctx context.Context, id trigger_domain.TriggerID) (*trigger_domain.Trigger, error)
 	GetAll(ctx context.Context) ([]*trigger_domain.Trigger, error)
+	PersistGroup(ctx context.Context, group *trigger_domain.TriggerGroup, triggers []*trigger_domain.Trigger) error
+	ClaimExecution(ctx context.Context, group *trigger_domain.TriggerGroup, trigger *trigger_domain.Trigger) error
+	GetGroup(ctx context.Context, id trigger_domain.TriggerGroupID) (*trigger_domain.TriggerGroup, error)
+	GetByGroup(ctx context.Context, id trigger_domain.TriggerGroupID) ([]*trigger_domain.Trigger, error)
+	GetPending(ctx context.Context) ([]*trigger_domain.Trigger, error)
+	AppendHistory(ctx context.Context, changes []trigger_domain.TriggerChange) error
+	GetHistory(ctx context.Context, id trigger_domain.TriggerID) ([]trigger_domain.TriggerChange, error)
+	PersistOrders(ctx context.Context, orders []trigger_domain.TriggerOrder) error
+	GetOrders(ctx context.Context) ([]trigger_domain.TriggerOrder, error)
+	GetOpenOrders(ctx context.Context) ([]trigger_domain.TriggerOrder, error)
 }
 
 type TriggerService struct {
@@ -32,8 +51,8 @@ func NewTriggerService(repository TriggerRepository) *TriggerService {
 }
 
 // GetByID retrieves a specific trigger by its ID
-func (s *TriggerService) GetByID(triggerID trigger_domain.TriggerID) (*trigger_domain.Trigger, error) {
-	trigger, err := s.repository.Get(context.Background(), triggerID)
+func (s *TriggerService) GetByID(ctx context.Context, triggerID trigger_domain.TriggerID) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
 	if err != nil {
 		return nil, fmt.Errorf("get trigger: %w", err)
 	}
@@ -41,32 +60,91 @@ func (s *TriggerService) GetByID(triggerID trigger_domain.TriggerID) (*trigger_d
 }
 
 // Get retrieves all triggers
-func (s *TriggerService) Get() ([]*trigger_domain.Trigger, error) {
-	triggers, err := s.repository.GetAll(context.Background())
+func (s *TriggerService) Get(ctx context.Context) ([]*trigger_domain.Trigger, error) {
+	triggers, err := s.repository.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get all triggers: %w", err)
 	}
 	return triggers, nil
 }
 
+// GetByType retrieves all triggers of the given type
+func (s *TriggerService) GetByType(ctx context.Context, triggerType trigger_domain.TriggerType) ([]*trigger_domain.Trigger, error) {
+	triggers, err := s.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Filter(triggers, func(t *trigger_domain.Trigger, _ int) bool {
+		return t.TriggerType == triggerType
+	}), nil
+}
+
+// GetByPolicy retrieves all triggers placed by the given protection policy
+func (s *TriggerService) GetByPolicy(ctx context.Context, policyID trigger_domain.ProtectionPolicyID) ([]*trigger_domain.Trigger, error) {
+	triggers, err := s.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Filter(triggers, func(t *trigger_domain.Trigger, _ int) bool {
+		return t.PolicyID != nil && *t.PolicyID == policyID
+	}), nil
+}
+
 // CancelTrigger cancels an active trigger
-func (s *TriggerService) CancelTrigger(triggerID trigger_domain.TriggerID) (*trigger_domain.Trigger, error) {
-	trigger, err := s.repository.Get(context.Background(), triggerID)
+func (s *TriggerService) CancelTrigger(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+
+	return s.cancelTrigger(ctx, trigger, actor)
+}
+
+// CancelTriggerOfType cancels a trigger only if it is of the given type, so a route for one
+// trigger type cannot cancel another. A trigger of a different type is reported as not found.
+func (s *TriggerService) CancelTriggerOfType(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	triggerType trigger_domain.TriggerType,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
 	if err != nil {
 		return nil, fmt.Errorf("get trigger: %w", err)
 	}
+	if trigger.TriggerType != triggerType {
+		return nil, core.NewErrNotFound(fmt.Sprintf("%s trigger", triggerType), triggerID.String())
+	}
 
+	return s.cancelTrigger(ctx, trigger, actor)
+}
+
+func (s *TriggerService) cancelTrigger(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	triggerID := trigger.TriggerID
 	if err := s.validateStatusTransition(trigger.Status, trigger_domain.StatusCancelled); err != nil {
-		return nil, fmt.Errorf("invalid status transition: %w", err)
+		return nil, fmt.Errorf("%w: invalid status transition: %v", ErrInvalidTrigger, err)
 	}
 
+	before := trigger.Snapshot()
 	trigger.Status = trigger_domain.StatusCancelled
-	err = s.repository.Persist(context.Background(), trigger)
+	err := s.repository.Persist(ctx, trigger)
 	if err != nil {
 		return nil, fmt.Errorf("update trigger: %w", err)
 	}
 
-	updatedTrigger, err := s.repository.Get(context.Background(), triggerID)
+	if err := s.recordChanges(ctx, actor, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
 	if err != nil {
 		return nil, fmt.Errorf("get cancelled trigger: %w", err)
 	}
@@ -76,9 +154,11 @@ func (s *TriggerService) CancelTrigger(triggerID trigger_domain.TriggerID) (*tri
 
 // CreateStopTrigger creates a new stop trigger with optional limit price
 func (s *TriggerService) CreateStopTrigger(
+	ctx context.Context,
 	contract contract.ContractIdentifier,
 	triggerPrice contract.ContractPrice,
 	limitPrice *contract.ContractPrice,
+	priceSource *trigger_domain.PriceSource,
 ) (*trigger_domain.Trigger, error) {
 	// Create base stop trigger
 	trigger, err := trigger_domain.NewStopTrigger(contract, triggerPrice, limitPrice)
@@ -91,13 +171,71 @@ func (s *TriggerService) CreateStopTrigger(
 		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
 	}
 
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
 	// Save trigger
-	err = s.repository.Persist(context.Background(), trigger)
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("save trigger: %w", err)
+	}
+
+	savedTrigger, err := s.repository.Get(ctx, trigger.TriggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved trigger: %w", err)
+	}
+
+	return savedTrigger, nil
+}
+
+// CreateCostBasisStopTrigger creates a stop the given offset below the position's average entry
+func (s *TriggerService) CreateCostBasisStopTrigger(
+	ctx context.Context,
+	position exchange_domain.Position,
+	offsetType trigger_domain.TrailType,
+	offset int,
+	priceSource *trigger_domain.PriceSource,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := trigger_domain.NewCostBasisStopTrigger(position, offsetType, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("save trigger: %w", err)
+	}
+
+	savedTrigger, err := s.repository.Get(ctx, trigger.TriggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved trigger: %w", err)
+	}
+
+	return savedTrigger, nil
+}
+
+// ApplyProtectionPolicy places the policy's cost basis stop on a position
+func (s *TriggerService) ApplyProtectionPolicy(
+	ctx context.Context,
+	policy trigger_domain.ProtectionPolicy,
+	position exchange_domain.Position,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := policy.NewStop(position)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	err = s.repository.Persist(ctx, trigger)
 	if err != nil {
 		return nil, fmt.Errorf("save trigger: %w", err)
 	}
 
-	savedTrigger, err := s.repository.Get(context.Background(), trigger.TriggerID)
+	savedTrigger, err := s.repository.Get(ctx, trigger.TriggerID)
 	if err != nil {
 		return nil, fmt.Errorf("get saved trigger: %w", err)
 	}
@@ -107,20 +245,34 @@ func (s *TriggerService) CreateStopTrigger(
 
 // UpdateStopTrigger updates an existing stop trigger's prices
 func (s *TriggerService) UpdateStopTrigger(
+	ctx context.Context,
 	triggerID trigger_domain.TriggerID,
 	triggerPrice *contract.ContractPrice,
 	limitPrice *contract.ContractPrice,
+	priceSource *trigger_domain.PriceSource,
+	actor trigger_domain.Actor,
 ) (*trigger_domain.Trigger, error) {
-	trigger, err := s.repository.Get(context.Background(), triggerID)
+	trigger, err := s.repository.Get(ctx, triggerID)
 	if err != nil {
 		return nil, fmt.Errorf("get trigger: %w", err)
 	}
+	before := trigger.Snapshot()
 
 	// Verify it's a stop trigger
 	if trigger.TriggerType != trigger_domain.TriggerTypeStop {
 		return nil, fmt.Errorf("%w: expected stop trigger", ErrInvalidTriggerType)
 	}
 
+	// Ladder tiers are repriced together so they stay in order
+	if trigger.Actions[0].SizePercent != nil {
+		return nil, fmt.Errorf("%w: ladder tiers must be updated through their ladder", ErrInvalidTrigger)
+	}
+
+	// A cost basis stop's level follows its position and the next pass would overwrite it
+	if triggerPrice != nil && trigger.CostBasis != nil {
+		return nil, fmt.Errorf("%w: the trigger price of a cost basis stop follows its position", ErrInvalidTrigger)
+	}
+
 	// Update trigger price if provided
 	if triggerPrice != nil {
 		trigger.Condition.Price.Threshold = *triggerPrice
@@ -136,13 +288,21 @@ func (s *TriggerService) UpdateStopTrigger(
 		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
 	}
 
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
 	// Save updates
-	err = s.repository.Persist(context.Background(), trigger)
+	err = s.repository.Persist(ctx, trigger)
 	if err != nil {
 		return nil, fmt.Errorf("update trigger: %w", err)
 	}
 
-	updatedTrigger, err := s.repository.Get(context.Background(), triggerID)
+	if err := s.recordChanges(ctx, actor, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
 	if err != nil {
 		return nil, fmt.Errorf("get updated trigger: %w", err)
 	}
@@ -150,42 +310,1285 @@ func (s *TriggerService) UpdateStopTrigger(
 	return updatedTrigger, nil
 }
 
-func (s *TriggerService) UpdateTriggerStatus(
+// CreateTakeProfitTrigger creates a new take profit trigger with optional limit price
+func (s *TriggerService) CreateTakeProfitTrigger(
+	ctx context.Context,
+	contract contract.ContractIdentifier,
+	triggerPrice contract.ContractPrice,
+	limitPrice *contract.ContractPrice,
+	priceSource *trigger_domain.PriceSource,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := trigger_domain.NewTakeProfitTrigger(contract, triggerPrice, limitPrice)
+	if err != nil {
+		return nil, fmt.Errorf("create take profit trigger: %w", err)
+	}
+
+	if err := trigger_domain.ValidateTakeProfitTrigger(trigger); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("save trigger: %w", err)
+	}
+
+	savedTrigger, err := s.repository.Get(ctx, trigger.TriggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved trigger: %w", err)
+	}
+
+	return savedTrigger, nil
+}
+
+// UpdateTakeProfitTrigger updates an existing take profit trigger's prices
+func (s *TriggerService) UpdateTakeProfitTrigger(
+	ctx context.Context,
 	triggerID trigger_domain.TriggerID,
-	newStatus trigger_domain.TriggerStatus,
+	triggerPrice *contract.ContractPrice,
+	limitPrice *contract.ContractPrice,
+	priceSource *trigger_domain.PriceSource,
+	actor trigger_domain.Actor,
 ) (*trigger_domain.Trigger, error) {
-	trigger, err := s.repository.Get(context.Background(), triggerID)
+	trigger, err := s.repository.Get(ctx, triggerID)
 	if err != nil {
 		return nil, fmt.Errorf("get trigger: %w", err)
 	}
+	before := trigger.Snapshot()
 
-	if err := s.validateStatusTransition(trigger.Status, newStatus); err != nil {
-		return nil, fmt.Errorf("invalid status transition: %w", err)
+	if trigger.TriggerType != trigger_domain.TriggerTypeTakeProfit {
+		return nil, fmt.Errorf("%w: expected take profit trigger", ErrInvalidTriggerType)
 	}
 
-	trigger.Status = newStatus
+	if triggerPrice != nil {
+		trigger.Condition.Price.Threshold = *triggerPrice
+	}
+
+	if limitPrice != nil {
+		trigger.Actions[0].LimitPrice = limitPrice
+	}
+
+	if err := trigger_domain.ValidateTakeProfitTrigger(trigger); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, actor, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
+}
+
+// CreateTrailingStopTrigger creates a new trailing stop trigger starting from a reference price
+func (s *TriggerService) CreateTrailingStopTrigger(
+	ctx context.Context,
+	contract contract.ContractIdentifier,
+	trailType trigger_domain.TrailType,
+	trailAmount int,
+	referencePrice contract.ContractPrice,
+	priceSource *trigger_domain.PriceSource,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := trigger_domain.NewTrailingStopTrigger(contract, trailType, trailAmount, referencePrice)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("save trigger: %w", err)
+	}
+
+	savedTrigger, err := s.repository.Get(ctx, trigger.TriggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved trigger: %w", err)
+	}
+
+	return savedTrigger, nil
+}
+
+// UpdateTrailingStopTrigger changes the trailing distance of an existing trailing stop.
+// The stop level is recomputed from the current high water mark.
+func (s *TriggerService) UpdateTrailingStopTrigger(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	trailType *trigger_domain.TrailType,
+	trailAmount *int,
+	priceSource *trigger_domain.PriceSource,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+	before := trigger.Snapshot()
+
+	if trigger.TriggerType != trigger_domain.TriggerTypeTrailingStop || trigger.TrailingStop == nil {
+		return nil, fmt.Errorf("%w: expected trailing stop trigger", ErrInvalidTriggerType)
+	}
+
+	if trailType != nil {
+		trigger.TrailingStop.TrailType = *trailType
+	}
+	if trailAmount != nil {
+		trigger.TrailingStop.TrailAmount = *trailAmount
+	}
+	trigger.Condition.Price.Threshold = trigger.TrailingStop.StopPrice()
 	trigger.UpdatedAt = time.Now()
-	err = s.repository.Persist(context.Background(), trigger)
+
+	if err := trigger_domain.ValidateTrailingStopTrigger(trigger); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Persist(ctx, trigger)
 	if err != nil {
 		return nil, fmt.Errorf("update trigger: %w", err)
 	}
 
-	return trigger, nil
+	if err := s.recordChanges(ctx, actor, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
 }
 
-// validateStatusTransition checks if a status transition is valid
-func (s *TriggerService) validateStatusTransition(
-	currentStatus trigger_domain.TriggerStatus,
-	newStatus trigger_domain.TriggerStatus,
-) error {
-	// Validate that both statuses are valid
-	if !currentStatus.IsValid() || !newStatus.IsValid() {
-		return errors.New("invalid status")
+// CreateEntryTrigger creates a trigger that buys into a position when the price crosses the entry level
+func (s *TriggerService) CreateEntryTrigger(
+	ctx context.Context,
+	contract contract.ContractIdentifier,
+	direction trigger_domain.Direction,
+	triggerPrice contract.ContractPrice,
+	size uint,
+	limitPrice *contract.ContractPrice,
+	maxCost *int,
+	priceSource *trigger_domain.PriceSource,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := trigger_domain.NewEntryTrigger(contract, direction, triggerPrice, size, limitPrice, maxCost)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
 	}
 
-	// Cannot transition from a terminal state
-	if currentStatus.IsTerminal() {
-		return fmt.Errorf("cannot transition from terminal status %s", currentStatus)
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("save trigger: %w", err)
+	}
+
+	savedTrigger, err := s.repository.Get(ctx, trigger.TriggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved trigger: %w", err)
+	}
+
+	return savedTrigger, nil
+}
+
+// UpdateEntryTrigger updates an existing entry trigger's level and order sizing
+func (s *TriggerService) UpdateEntryTrigger(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	triggerPrice *contract.ContractPrice,
+	size *uint,
+	limitPrice *contract.ContractPrice,
+	maxCost *int,
+	priceSource *trigger_domain.PriceSource,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+	before := trigger.Snapshot()
+
+	if trigger.TriggerType != trigger_domain.TriggerTypeEntry {
+		return nil, fmt.Errorf("%w: expected entry trigger", ErrInvalidTriggerType)
+	}
+
+	if triggerPrice != nil {
+		trigger.Condition.Price.Threshold = *triggerPrice
+	}
+	if size != nil {
+		trigger.Actions[0].Size = size
+	}
+	if limitPrice != nil {
+		trigger.Actions[0].LimitPrice = limitPrice
+	}
+	if maxCost != nil {
+		trigger.Actions[0].MaxCost = maxCost
+	}
+	trigger.UpdatedAt = time.Now()
+
+	if err := trigger_domain.ValidateEntryTrigger(trigger); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, actor, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
+}
+
+// CreateHedgeTrigger creates a trigger that trades other contracts when the watched contract's price crosses the trigger price
+func (s *TriggerService) CreateHedgeTrigger(
+	ctx context.Context,
+	contract contract.ContractIdentifier,
+	direction trigger_domain.Direction,
+	triggerPrice contract.ContractPrice,
+	actions []trigger_domain.TriggerAction,
+	priceSource *trigger_domain.PriceSource,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := trigger_domain.NewHedgeTrigger(contract, direction, triggerPrice, actions)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("save trigger: %w", err)
+	}
+
+	savedTrigger, err := s.repository.Get(ctx, trigger.TriggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved trigger: %w", err)
+	}
+
+	return savedTrigger, nil
+}
+
+// UpdateHedgeTrigger updates an existing hedge trigger's level, and replaces its actions if any are given
+func (s *TriggerService) UpdateHedgeTrigger(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	triggerPrice *contract.ContractPrice,
+	actions []trigger_domain.TriggerAction,
+	priceSource *trigger_domain.PriceSource,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+	before := trigger.Snapshot()
+
+	if trigger.TriggerType != trigger_domain.TriggerTypeHedge {
+		return nil, fmt.Errorf("%w: expected hedge trigger", ErrInvalidTriggerType)
+	}
+
+	if triggerPrice != nil {
+		trigger.Condition.Price.Threshold = *triggerPrice
+	}
+	if actions != nil {
+		trigger.Actions = actions
+	}
+	trigger.UpdatedAt = time.Now()
+
+	if err := trigger_domain.ValidateHedgeTrigger(trigger); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := applyPriceSource(trigger, priceSource); err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, actor, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
+}
+
+// CreateEventExposureTrigger creates a trigger that flattens every position in an event when its exposure crosses the threshold
+func (s *TriggerService) CreateEventExposureTrigger(
+	ctx context.Context,
+	eventTicker contract.Ticker,
+	metric trigger_domain.EventMetric,
+	threshold int,
+	direction trigger_domain.Direction,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := trigger_domain.NewEventExposureTrigger(eventTicker, metric, threshold, direction)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("save trigger: %w", err)
+	}
+
+	savedTrigger, err := s.repository.Get(ctx, trigger.TriggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved trigger: %w", err)
+	}
+
+	return savedTrigger, nil
+}
+
+// UpdateEventExposureTrigger updates an existing event exposure trigger's threshold or direction
+func (s *TriggerService) UpdateEventExposureTrigger(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	threshold *int,
+	direction *trigger_domain.Direction,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+	before := trigger.Snapshot()
+
+	if trigger.TriggerType != trigger_domain.TriggerTypeEventExposure {
+		return nil, fmt.Errorf("%w: expected event exposure trigger", ErrInvalidTriggerType)
+	}
+
+	if threshold != nil {
+		trigger.Condition.Event.Threshold = *threshold
+	}
+	if direction != nil {
+		trigger.Condition.Event.Direction = *direction
+	}
+	trigger.UpdatedAt = time.Now()
+
+	if err := trigger_domain.ValidateEventExposureTrigger(trigger); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, actor, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
+}
+
+// CreateConditionalTrigger creates a trigger that fires its actions when a composite condition is satisfied
+func (s *TriggerService) CreateConditionalTrigger(
+	ctx context.Context,
+	condition trigger_domain.TriggerCondition,
+	actions []trigger_domain.TriggerAction,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := trigger_domain.NewConditionalTrigger(condition, actions)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("save trigger: %w", err)
+	}
+
+	savedTrigger, err := s.repository.Get(ctx, trigger.TriggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved trigger: %w", err)
+	}
+
+	return savedTrigger, nil
+}
+
+// UpdateConditionalTrigger replaces the condition tree of an existing conditional trigger
+func (s *TriggerService) UpdateConditionalTrigger(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	condition trigger_domain.TriggerCondition,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+	before := trigger.Snapshot()
+
+	if trigger.TriggerType != trigger_domain.TriggerTypeConditional {
+		return nil, fmt.Errorf("%w: expected conditional trigger", ErrInvalidTriggerType)
+	}
+
+	trigger.Condition = condition
+	trigger.UpdatedAt = time.Now()
+
+	if err := trigger_domain.ValidateConditionalTrigger(trigger); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, actor, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
+}
+
+// UpdateTrailingStop moves a trailing stop to follow the observed price, persisting any change
+func (s *TriggerService) UpdateTrailingStop(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	price contract.ContractPrice,
+) (*trigger_domain.Trigger, error) {
+	before := trigger.Snapshot()
+	changed, err := trigger.UpdateTrailingStop(price)
+	if err != nil {
+		return nil, fmt.Errorf("update trailing stop: %w", err)
+	}
+	if !changed {
+		return trigger, nil
+	}
+
+	// Only the stop's level is written, so an edit or cancel made since the trigger was
+	// read is not undone
+	if err := s.repository.UpdateTrailingStop(ctx, trigger); err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, trigger_domain.ActorSystem, trigger.UpdatedAt, trigger, before); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// UpdateCostBasis re-resolves a cost basis stop from the current position, persisting any change
+func (s *TriggerService) UpdateCostBasis(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	position exchange_domain.Position,
+) (*trigger_domain.Trigger, error) {
+	before := trigger.Snapshot()
+	changed, err := trigger.UpdateCostBasis(position)
+	if err != nil {
+		return nil, fmt.Errorf("update cost basis: %w", err)
+	}
+	if !changed {
+		return trigger, nil
+	}
+
+	// Only the stop's level is written, so an edit or cancel made since the trigger was
+	// read is not undone
+	if err := s.repository.UpdateCostBasis(ctx, trigger); err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, trigger_domain.ActorSystem, trigger.UpdatedAt, trigger, before); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// ExpireTrigger moves a trigger whose market can no longer trade to EXPIRED
+func (s *TriggerService) ExpireTrigger(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	expiry trigger_domain.TriggerExpiry,
+) (*trigger_domain.Trigger, error) {
+	if err := s.validateStatusTransition(trigger.Status, trigger_domain.StatusExpired); err != nil {
+		return nil, fmt.Errorf("invalid status transition: %w", err)
+	}
+	before := trigger.Snapshot()
+
+	if err := trigger.Expire(expiry); err != nil {
+		return nil, fmt.Errorf("expire trigger: %w", err)
+	}
+
+	// Stored only if the trigger is still in the status the transition was checked from,
+	// so a cancel made since it was read stands
+	if err := s.repository.Expire(ctx, trigger, before.Status); err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, trigger_domain.ActorSystem, expiry.ExpiredAt, trigger, before); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// SetConfirmation replaces the confirmation policy on an active trigger, or clears it if nil
+func (s *TriggerService) SetConfirmation(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	confirmation *trigger_domain.Confirmation,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+
+	if err := trigger.SetConfirmation(confirmation); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := s.repository.Persist(ctx, trigger); err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
+}
+
+// ConfirmTrigger records an evaluation of the trigger's condition, persisting any pending
+// confirmation progress, and reports whether the trigger should be executed
+func (s *TriggerService) ConfirmTrigger(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	satisfied bool,
+	at time.Time,
+) (bool, error) {
+	confirmed, changed := trigger.Confirm(satisfied, at)
+	if !changed {
+		return confirmed, nil
+	}
+
+	// Only the progress is written, so an edit or cancel made since the trigger was read
+	// is not undone, and the trigger does not fire on a confirmation that was not stored
+	if err := s.repository.UpdateConfirmation(ctx, trigger, at); err != nil {
+		return false, fmt.Errorf("update trigger confirmation: %w", err)
+	}
+
+	return confirmed, nil
+}
+
+// SetGuard replaces the pre-trade guard on a trigger, or clears it if nil so the default guards apply
+func (s *TriggerService) SetGuard(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	guard *trigger_domain.ExecutionGuard,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+
+	if err := trigger.SetGuard(guard); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := s.repository.Persist(ctx, trigger); err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
+}
+
+// SetFillPolicy replaces how a trigger's unfilled orders are followed up, or clears it if nil
+// so they rest until they fill
+func (s *TriggerService) SetFillPolicy(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	policy *trigger_domain.FillPolicy,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+
+	if err := trigger.SetFillPolicy(policy); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := s.repository.Persist(ctx, trigger); err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
+}
+
+// RecordGuardFailure records why a trigger's orders were held back, flagging it if the guard asks to
+func (s *TriggerService) RecordGuardFailure(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	failure trigger_domain.GuardFailure,
+) (*trigger_domain.Trigger, error) {
+	before := trigger.Snapshot()
+	if err := trigger.FailGuard(failure); err != nil {
+		return nil, fmt.Errorf("record guard failure: %w", err)
+	}
+
+	if err := s.repository.Persist(ctx, trigger); err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, trigger_domain.ActorSystem, failure.FailedAt, trigger, before); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// ResumeTrigger returns a trigger flagged by a failed guard to ACTIVE after review
+func (s *TriggerService) ResumeTrigger(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+	before := trigger.Snapshot()
+
+	if err := trigger.Resume(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	if err := s.repository.Persist(ctx, trigger); err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, actor, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	updatedTrigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get resumed trigger: %w", err)
+	}
+
+	return updatedTrigger, nil
+}
+
+func (s *TriggerService) UpdateTriggerStatus(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	newStatus trigger_domain.TriggerStatus,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+
+	if err := s.validateStatusTransition(trigger.Status, newStatus); err != nil {
+		return nil, fmt.Errorf("invalid status transition: %w", err)
+	}
+
+	before := trigger.Snapshot()
+	trigger.Status = newStatus
+	trigger.UpdatedAt = time.Now()
+	if newStatus == trigger_domain.StatusExecuting {
+		// A new execution has not failed to reconcile yet
+		trigger.ReconcileAttempts = 0
+	}
+
+	// A grouped trigger starts executing only while its siblings leave the group free, checked
+	// under a lock so two members of an OCO group cannot both send orders
+	if newStatus == trigger_domain.StatusExecuting && trigger.GroupID != nil {
+		group, err := s.repository.GetGroup(ctx, *trigger.GroupID)
+		if err != nil {
+			return nil, fmt.Errorf("get trigger group: %w", err)
+		}
+		if err := s.repository.ClaimExecution(ctx, group, trigger); err != nil {
+			return nil, fmt.Errorf("claim grouped trigger: %w", err)
+		}
+		if err := s.recordChanges(ctx, actor, trigger.UpdatedAt, trigger, before); err != nil {
+			return nil, err
+		}
+		return trigger, nil
+	}
+
+	// Triggering a grouped trigger may need to cancel its siblings in the same transaction
+	if newStatus == trigger_domain.StatusTriggered && trigger.GroupID != nil {
+		if err := s.persistTriggeredGroupMember(ctx, trigger, before, actor); err != nil {
+			return nil, fmt.Errorf("update grouped trigger: %w", err)
+		}
+		return trigger, nil
+	}
+
+	err = s.repository.Persist(ctx, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+
+	if err := s.recordChanges(ctx, actor, trigger.UpdatedAt, trigger, before); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// RecordReconcileFailure counts a failed attempt to settle an executing trigger. Once
+// maxAttempts have failed the trigger is flagged for review instead of being retried.
+// UpdatedAt is left alone until then, since it marks when the execution started.
+func (s *TriggerService) RecordReconcileFailure(
+	ctx context.Context,
+	triggerID trigger_domain.TriggerID,
+	maxAttempts int,
+) (*trigger_domain.Trigger, error) {
+	trigger, err := s.repository.Get(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+	if trigger.Status != trigger_domain.StatusExecuting {
+		return nil, fmt.Errorf("trigger is not executing, status: %s", trigger.Status)
+	}
+
+	before := trigger.Snapshot()
+	trigger.ReconcileAttempts++
+	if trigger.ReconcileAttempts >= maxAttempts {
+		trigger.Status = trigger_domain.StatusFlagged
+		trigger.UpdatedAt = time.Now()
+	}
+
+	if err := s.repository.Persist(ctx, trigger); err != nil {
+		return nil, fmt.Errorf("update trigger: %w", err)
+	}
+	if err := s.recordChanges(ctx, trigger_domain.ActorSystem, time.Now(), trigger, before); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// persistTriggeredGroupMember stores a triggered trigger together with its
+// cancelled OCO siblings so a stop and target can never both fire. A ladder
+// tier that sold the rest of the position likewise retires the tiers above it.
+func (s *TriggerService) persistTriggeredGroupMember(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	before trigger_domain.TriggerSnapshot,
+	actor trigger_domain.Actor,
+) error {
+	group, err := s.repository.GetGroup(ctx, *trigger.GroupID)
+	if err != nil {
+		return fmt.Errorf("get trigger group: %w", err)
+	}
+
+	cancelSiblings := group.GroupType == trigger_domain.GroupTypeOCO ||
+		(group.GroupType == trigger_domain.GroupTypeLadder && trigger.Actions[0].ClosesPosition())
+	if !cancelSiblings {
+		if err := s.repository.Persist(ctx, trigger); err != nil {
+			return err
+		}
+		return s.recordChanges(ctx, actor, trigger.UpdatedAt, trigger, before)
+	}
+
+	members, err := s.repository.GetByGroup(ctx, group.GroupID)
+	if err != nil {
+		return fmt.Errorf("get group triggers: %w", err)
+	}
+
+	updated := []*trigger_domain.Trigger{trigger}
+	snapshots := []trigger_domain.TriggerSnapshot{before}
+	for _, member := range members {
+		// An executing ladder tier may already have orders out, so it is left to finish. OCO
+		// siblings cannot be executing, since the group is claimed before orders go out.
+		if member.TriggerID == trigger.TriggerID || member.Status.IsTerminal() || member.Status == trigger_domain.StatusExecuting {
+			continue
+		}
+		snapshots = append(snapshots, member.Snapshot())
+		member.Status = trigger_domain.StatusCancelled
+		member.UpdatedAt = trigger.UpdatedAt
+		updated = append(updated, member)
+	}
+
+	group.UpdatedAt = trigger.UpdatedAt
+	if err := s.repository.PersistGroup(ctx, group, updated); err != nil {
+		return err
+	}
+
+	var changes []trigger_domain.TriggerChange
+	for i, member := range updated {
+		changes = append(changes, member.ChangesSince(snapshots[i], actor, trigger.UpdatedAt)...)
+	}
+	if err := s.repository.AppendHistory(ctx, changes); err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+	return nil
+}
+
+// recordChanges appends the trigger's changes since the snapshot to its history
+func (s *TriggerService) recordChanges(
+	ctx context.Context,
+	actor trigger_domain.Actor,
+	at time.Time,
+	trigger *trigger_domain.Trigger,
+	before trigger_domain.TriggerSnapshot,
+) error {
+	changes := trigger.ChangesSince(before, actor, at)
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := s.repository.AppendHistory(ctx, changes); err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+	return nil
+}
+
+// GetHistory retrieves a trigger's change history, oldest first
+func (s *TriggerService) GetHistory(ctx context.Context, triggerID trigger_domain.TriggerID) ([]trigger_domain.TriggerChange, error) {
+	if _, err := s.repository.Get(ctx, triggerID); err != nil {
+		return nil, fmt.Errorf("get trigger: %w", err)
+	}
+
+	history, err := s.repository.GetHistory(ctx, triggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get history: %w", err)
+	}
+	return history, nil
+}
+
+// RecordOrders stores orders sent by trigger executions, updating any already recorded
+func (s *TriggerService) RecordOrders(ctx context.Context, orders []trigger_domain.TriggerOrder) error {
+	if err := s.repository.PersistOrders(ctx, orders); err != nil {
+		return fmt.Errorf("persist orders: %w", err)
+	}
+	return nil
+}
+
+// GetOrders retrieves the orders sent by every trigger, newest first
+func (s *TriggerService) GetOrders(ctx context.Context) ([]trigger_domain.TriggerOrder, error) {
+	orders, err := s.repository.GetOrders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get orders: %w", err)
+	}
+	return orders, nil
+}
+
+// GetOpenOrders retrieves the orders that are resting or partially filled, oldest first
+func (s *TriggerService) GetOpenOrders(ctx context.Context) ([]trigger_domain.TriggerOrder, error) {
+	orders, err := s.repository.GetOpenOrders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get open orders: %w", err)
+	}
+	return orders, nil
+}
+
+// ArmPendingTriggers activates each pending trigger once the trigger it waits on is done and
+// its orders can fill no further. The pending trigger is sized to what those orders filled,
+// or cancelled if they filled nothing.
+func (s *TriggerService) ArmPendingTriggers(ctx context.Context) error {
+	pending, err := s.repository.GetPending(ctx)
+	if err != nil {
+		return fmt.Errorf("get pending triggers: %w", err)
+	}
+
+	armers := make(map[trigger_domain.TriggerID]*trigger_domain.Trigger)
+	var errs []error
+	for _, trigger := range pending {
+		if trigger.ArmedBy == nil {
+			continue
+		}
+		armer, ok := armers[*trigger.ArmedBy]
+		if !ok {
+			armer, err = s.repository.Get(ctx, *trigger.ArmedBy)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("get trigger %s arming %s: %w", *trigger.ArmedBy, trigger.TriggerID, err))
+				continue
+			}
+			armers[*trigger.ArmedBy] = armer
+		}
+
+		filled, settled := armer.FilledQuantity()
+		if !settled {
+			continue
+		}
+
+		before := trigger.Snapshot()
+		if filled == 0 {
+			trigger.Status = trigger_domain.StatusCancelled
+			trigger.UpdatedAt = time.Now()
+		} else if err := trigger.Arm(filled); err != nil {
+			errs = append(errs, fmt.Errorf("arm trigger %s: %w", trigger.TriggerID, err))
+			continue
+		}
+
+		if err := s.repository.Persist(ctx, trigger); err != nil {
+			errs = append(errs, fmt.Errorf("update trigger %s: %w", trigger.TriggerID, err))
+			continue
+		}
+		if err := s.recordChanges(ctx, trigger_domain.ActorSystem, trigger.UpdatedAt, trigger, before); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CreateBracket creates an entry trigger together with a stop and take profit on the same
+// contract. The exits are linked as an OCO group and stay pending until the entry fills.
+func (s *TriggerService) CreateBracket(
+	ctx context.Context,
+	contract contract.ContractIdentifier,
+	entryDirection trigger_domain.Direction,
+	entryPrice contract.ContractPrice,
+	entrySize uint,
+	entryLimitPrice *contract.ContractPrice,
+	entryMaxCost *int,
+	stopPrice contract.ContractPrice,
+	stopLimitPrice *contract.ContractPrice,
+	takeProfitPrice contract.ContractPrice,
+	takeProfitLimitPrice *contract.ContractPrice,
+	priceSource *trigger_domain.PriceSource,
+) (*trigger_domain.Bracket, error) {
+	entry, err := trigger_domain.NewEntryTrigger(contract, entryDirection, entryPrice, entrySize, entryLimitPrice, entryMaxCost)
+	if err != nil {
+		return nil, fmt.Errorf("%w: create entry trigger: %v", ErrInvalidTrigger, err)
+	}
+	bracket, err := trigger_domain.NewBracket(entry, stopPrice, stopLimitPrice, takeProfitPrice, takeProfitLimitPrice)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+	for _, trigger := range bracket.Triggers() {
+		if err := applyPriceSource(trigger, priceSource); err != nil {
+			return nil, err
+		}
+	}
+
+	// The entry is saved with its exits so it never goes out unprotected
+	err = s.repository.PersistGroup(ctx, bracket.Group, bracket.Triggers())
+	if err != nil {
+		return nil, fmt.Errorf("save bracket: %w", err)
+	}
+
+	return s.GetBracket(ctx, bracket.Group.GroupID)
+}
+
+// GetBracket retrieves a bracket by its group ID
+func (s *TriggerService) GetBracket(ctx context.Context, groupID trigger_domain.TriggerGroupID) (*trigger_domain.Bracket, error) {
+	group, err := s.repository.GetGroup(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger group: %w", err)
+	}
+
+	members, err := s.repository.GetByGroup(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("get group triggers: %w", err)
+	}
+
+	bracket := &trigger_domain.Bracket{Group: group}
+	for _, member := range members {
+		switch member.TriggerType {
+		case trigger_domain.TriggerTypeStop:
+			bracket.Stop = member
+		case trigger_domain.TriggerTypeTakeProfit:
+			bracket.TakeProfit = member
+		}
+	}
+
+	// The entry sits outside the group, linked from the exits it arms
+	if bracket.Stop != nil && bracket.Stop.ArmedBy != nil {
+		bracket.Entry, err = s.repository.Get(ctx, *bracket.Stop.ArmedBy)
+		if err != nil {
+			return nil, fmt.Errorf("get bracket entry: %w", err)
+		}
+	}
+
+	if err := trigger_domain.ValidateBracket(bracket); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	return bracket, nil
+}
+
+// CancelBracket cancels every trigger in a bracket that has not fired yet, the entry included
+func (s *TriggerService) CancelBracket(
+	ctx context.Context,
+	groupID trigger_domain.TriggerGroupID,
+	actor trigger_domain.Actor,
+) (*trigger_domain.Bracket, error) {
+	bracket, err := s.GetBracket(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cancelGroupMembers(ctx, bracket.Group, bracket.Triggers(), actor); err != nil {
+		return nil, err
+	}
+
+	return s.GetBracket(ctx, groupID)
+}
+
+// CreateStopLadder creates stops at descending prices that scale out of the position in tiers
+func (s *TriggerService) CreateStopLadder(
+	ctx context.Context,
+	contract contract.ContractIdentifier,
+	tiers []trigger_domain.LadderTier,
+	priceSource *trigger_domain.PriceSource,
+) (*trigger_domain.StopLadder, error) {
+	ladder, err := trigger_domain.NewStopLadder(contract, tiers)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+	for _, trigger := range ladder.Triggers() {
+		if err := applyPriceSource(trigger, priceSource); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repository.PersistGroup(ctx, ladder.Group, ladder.Triggers())
+	if err != nil {
+		return nil, fmt.Errorf("save ladder: %w", err)
+	}
+
+	return s.GetStopLadder(ctx, ladder.Group.GroupID)
+}
+
+// GetStopLadder retrieves a ladder by its group ID
+func (s *TriggerService) GetStopLadder(ctx context.Context, groupID trigger_domain.TriggerGroupID) (*trigger_domain.StopLadder, error) {
+	group, err := s.repository.GetGroup(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("get trigger group: %w", err)
+	}
+
+	members, err := s.repository.GetByGroup(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("get group triggers: %w", err)
+	}
+
+	trigger_domain.SortLadderTiers(members)
+	ladder := &trigger_domain.StopLadder{Group: group, Tiers: members}
+
+	if err := trigger_domain.ValidateStopLadder(ladder); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	return ladder, nil
+}
+
+// UpdateStopLadder reprices the tiers of a ladder that have not fired yet
+func (s *TriggerService) UpdateStopLadder(
+	ctx context.Context,
+	groupID trigger_domain.TriggerGroupID,
+	tiers []trigger_domain.LadderTier,
+	priceSource *trigger_domain.PriceSource,
+	actor trigger_domain.Actor,
+) (*trigger_domain.StopLadder, error) {
+	ladder, err := s.GetStopLadder(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The active tiers are updated in place and returned in the same order
+	var snapshots []trigger_domain.TriggerSnapshot
+	for _, trigger := range ladder.ActiveTiers() {
+		snapshots = append(snapshots, trigger.Snapshot())
+	}
+
+	updated, err := ladder.Update(tiers)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+	for _, trigger := range updated {
+		if err := applyPriceSource(trigger, priceSource); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repository.PersistGroup(ctx, ladder.Group, updated); err != nil {
+		return nil, fmt.Errorf("update ladder: %w", err)
+	}
+
+	var changes []trigger_domain.TriggerChange
+	for i, trigger := range updated {
+		changes = append(changes, trigger.ChangesSince(snapshots[i], actor, ladder.Group.UpdatedAt)...)
+	}
+	if len(changes) > 0 {
+		if err := s.repository.AppendHistory(ctx, changes); err != nil {
+			return nil, fmt.Errorf("record history: %w", err)
+		}
+	}
+
+	return s.GetStopLadder(ctx, groupID)
+}
+
+// CancelStopLadder cancels every tier of a ladder that has not fired yet
+func (s *TriggerService) CancelStopLadder(
+	ctx context.Context,
+	groupID trigger_domain.TriggerGroupID,
+	actor trigger_domain.Actor,
+) (*trigger_domain.StopLadder, error) {
+	ladder, err := s.GetStopLadder(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cancelGroupMembers(ctx, ladder.Group, ladder.Triggers(), actor); err != nil {
+		return nil, err
+	}
+
+	return s.GetStopLadder(ctx, groupID)
+}
+
+// cancelGroupMembers cancels every member of the group that may still be cancelled, in a
+// single write, and records each one's history. An executing member may already have
+// orders out, so like a fired one it is left alone.
+func (s *TriggerService) cancelGroupMembers(
+	ctx context.Context,
+	group *trigger_domain.TriggerGroup,
+	members []*trigger_domain.Trigger,
+	actor trigger_domain.Actor,
+) error {
+	currentTime := time.Now()
+	var cancelled []*trigger_domain.Trigger
+	var snapshots []trigger_domain.TriggerSnapshot
+	for _, member := range members {
+		if err := s.validateStatusTransition(member.Status, trigger_domain.StatusCancelled); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, member.Snapshot())
+		member.Status = trigger_domain.StatusCancelled
+		member.UpdatedAt = currentTime
+		cancelled = append(cancelled, member)
+	}
+
+	if len(cancelled) == 0 {
+		return fmt.Errorf("%w: invalid status transition: no trigger in the group can be cancelled", ErrInvalidTrigger)
+	}
+
+	group.UpdatedAt = currentTime
+	if err := s.repository.PersistGroup(ctx, group, cancelled); err != nil {
+		return fmt.Errorf("update trigger group: %w", err)
+	}
+
+	var changes []trigger_domain.TriggerChange
+	for i, member := range cancelled {
+		changes = append(changes, member.ChangesSince(snapshots[i], actor, currentTime)...)
+	}
+	if err := s.repository.AppendHistory(ctx, changes); err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+	return nil
+}
+
+// applyPriceSource sets the market price the trigger is evaluated against, if one was given
+func applyPriceSource(trigger *trigger_domain.Trigger, priceSource *trigger_domain.PriceSource) error {
+	if priceSource == nil {
+		return nil
+	}
+	if err := trigger.Condition.SetPriceSource(*priceSource); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+	return nil
+}
+
+// validateStatusTransition checks if a status transition is valid
+func (s *TriggerService) validateStatusTransition(
+	currentStatus trigger_domain.TriggerStatus,
+	newStatus trigger_domain.TriggerStatus,
+) error {
+	// Validate that both statuses are valid
+	if !currentStatus.IsValid() || !newStatus.IsValid() {
+		return errors.New("invalid status")
+	}
+
+	// Cannot transition from a terminal state
+	if currentStatus.IsTerminal() {
+		return fmt.Errorf("cannot transition from terminal status %s", currentStatus)
+	}
+
+	// A pending trigger is only armed or retired, and nothing goes back to pending
+	if newStatus == trigger_domain.StatusPending {
+		return fmt.Errorf("cannot transition from %s to %s", currentStatus, newStatus)
+	}
+	if currentStatus == trigger_domain.StatusPending {
+		switch newStatus {
+		case trigger_domain.StatusActive, trigger_domain.StatusCancelled, trigger_domain.StatusExpired:
+		default:
+			return fmt.Errorf("cannot transition from %s to %s", currentStatus, newStatus)
+		}
+	}
+
+	// Only an active trigger starts executing, and an executing one may already have
+	// orders out, so it can only finish, go back to active, or be held for review
+	if newStatus == trigger_domain.StatusExecuting && currentStatus != trigger_domain.StatusActive {
+		return fmt.Errorf("cannot start executing from status %s", currentStatus)
+	}
+	if currentStatus == trigger_domain.StatusExecuting {
+		switch newStatus {
+		case trigger_domain.StatusTriggered, trigger_domain.StatusActive, trigger_domain.StatusFlagged:
+		default:
+			return fmt.Errorf("cannot transition from %s to %s", currentStatus, newStatus)
+		}
 	}
 
 	return nil
@@ -1,11 +1,15 @@
 package trigger_service
 
 import (
+	"context"
 	"errors"
 	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
 	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
 	trigger_mock "prediction-risk/internal/app/risk/trigger/mock"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -47,7 +51,7 @@ func TestGetByID(t *testing.T) {
 			tt.mockSetup(mockRepo)
 
 			service := NewTriggerService(mockRepo)
-			trigger, err := service.GetByID(tt.triggerID)
+			trigger, err := service.GetByID(context.Background(), tt.triggerID)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -104,7 +108,7 @@ func TestGet(t *testing.T) {
 			tt.mockSetup(mockRepo)
 
 			service := NewTriggerService(mockRepo)
-			triggers, err := service.Get()
+			triggers, err := service.Get(context.Background())
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -142,6 +146,13 @@ func TestCancelTrigger(t *testing.T) {
 				repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
 					return t.Status == trigger_domain.StatusCancelled
 				})).Return(nil)
+				repo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+					return len(changes) == 1 &&
+						changes[0].Field == trigger_domain.ChangeFieldStatus &&
+						*changes[0].OldValue == "ACTIVE" &&
+						*changes[0].NewValue == "CANCELLED" &&
+						changes[0].Actor == "trader"
+				})).Return(nil).Once()
 				repo.On("Get", mock.Anything, triggerID).Return(cancelledTrigger, nil).Once()
 			},
 			expectError: false,
@@ -166,10 +177,10 @@ func TestCancelTrigger(t *testing.T) {
 			tt.mockSetup(mockRepo)
 
 			service := NewTriggerService(mockRepo)
-			trigger, err := service.CancelTrigger(tt.triggerID)
+			trigger, err := service.CancelTrigger(context.Background(), tt.triggerID, "trader")
 
 			if tt.expectError {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, ErrInvalidTrigger)
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, trigger)
@@ -180,6 +191,46 @@ func TestCancelTrigger(t *testing.T) {
 	}
 }
 
+func TestCancelTriggerOfType(t *testing.T) {
+	triggerID := trigger_domain.NewTriggerID()
+
+	t.Run("cancels trigger of the given type", func(t *testing.T) {
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		active := &trigger_domain.Trigger{
+			TriggerID:   triggerID,
+			TriggerType: trigger_domain.TriggerTypeTakeProfit,
+			Status:      trigger_domain.StatusActive,
+		}
+		mockRepo.On("Get", mock.Anything, triggerID).Return(active, nil)
+		mockRepo.On("Persist", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+
+		service := NewTriggerService(mockRepo)
+		trigger, err := service.CancelTriggerOfType(context.Background(), triggerID, trigger_domain.TriggerTypeTakeProfit, "trader")
+
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusCancelled, trigger.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reports other trigger types as not found", func(t *testing.T) {
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		stop := &trigger_domain.Trigger{
+			TriggerID:   triggerID,
+			TriggerType: trigger_domain.TriggerTypeStop,
+			Status:      trigger_domain.StatusActive,
+		}
+		mockRepo.On("Get", mock.Anything, triggerID).Return(stop, nil)
+
+		service := NewTriggerService(mockRepo)
+		_, err := service.CancelTriggerOfType(context.Background(), triggerID, trigger_domain.TriggerTypeTakeProfit, "trader")
+
+		var notFoundErr *core.ErrNotFound
+		assert.ErrorAs(t, err, &notFoundErr)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+}
+
 func TestCreateStopTrigger(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -228,7 +279,7 @@ func TestCreateStopTrigger(t *testing.T) {
 			tt.mockSetup(mockRepo)
 
 			service := NewTriggerService(mockRepo)
-			trigger, err := service.CreateStopTrigger(tt.contract, tt.triggerPrice, tt.limitPrice)
+			trigger, err := service.CreateStopTrigger(context.Background(), tt.contract, tt.triggerPrice, tt.limitPrice, nil)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -281,10 +332,39 @@ func TestUpdateStopTrigger(t *testing.T) {
 				repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
 					return t.TriggerType == trigger_domain.TriggerTypeStop
 				})).Return(nil)
+				// Only the limit price moved, so only it is recorded
+				repo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+					return len(changes) == 1 &&
+						changes[0].Field == trigger_domain.ChangeFieldLimitPrice &&
+						*changes[0].OldValue == "48" &&
+						*changes[0].NewValue == "49"
+				})).Return(nil).Once()
 				repo.On("Get", mock.Anything, triggerID).Return(existingTrigger, nil).Once()
 			},
 			expectError: false,
 		},
+		{
+			name:         "rejects trigger price of cost basis stop",
+			triggerID:    triggerID,
+			triggerPrice: ptr(contract.ContractPrice(30)),
+			mockSetup: func(repo *trigger_mock.MockTriggerRepository) {
+				existingTrigger, err := trigger_domain.NewCostBasisStopTrigger(
+					exchange_domain.Position{
+						ContractID: contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes},
+						Quantity:   10,
+						CostBasis:  500,
+					},
+					trigger_domain.TrailTypeCents,
+					10,
+				)
+				if err != nil {
+					panic(err)
+				}
+
+				repo.On("Get", mock.Anything, triggerID).Return(existingTrigger, nil).Once()
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -293,10 +373,11 @@ func TestUpdateStopTrigger(t *testing.T) {
 			tt.mockSetup(mockRepo)
 
 			service := NewTriggerService(mockRepo)
-			trigger, err := service.UpdateStopTrigger(tt.triggerID, tt.triggerPrice, tt.limitPrice)
+			trigger, err := service.UpdateStopTrigger(context.Background(), tt.triggerID, tt.triggerPrice, tt.limitPrice, nil, "trader")
 
 			if tt.expectError {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, ErrInvalidTrigger)
+				mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, trigger)
@@ -307,6 +388,770 @@ func TestUpdateStopTrigger(t *testing.T) {
 	}
 }
 
+func TestCreateTakeProfitTrigger(t *testing.T) {
+	tests := []struct {
+		name         string
+		contract     contract.ContractIdentifier
+		triggerPrice contract.ContractPrice
+		limitPrice   *contract.ContractPrice
+		mockSetup    func(*trigger_mock.MockTriggerRepository)
+		expectError  bool
+	}{
+		{
+			name: "successful creation",
+			contract: contract.ContractIdentifier{
+				Ticker: "FOO",
+				Side:   contract.SideYes,
+			},
+			triggerPrice: contract.ContractPrice(80),
+			limitPrice:   ptr(contract.ContractPrice(78)),
+			mockSetup: func(repo *trigger_mock.MockTriggerRepository) {
+				repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+					return t.TriggerType == trigger_domain.TriggerTypeTakeProfit &&
+						t.Condition.Price.Direction == trigger_domain.Above
+				})).Return(nil)
+				repo.On("Get", mock.Anything, mock.AnythingOfType("trigger_domain.TriggerID")).
+					Return(&trigger_domain.Trigger{
+						TriggerType: trigger_domain.TriggerTypeTakeProfit,
+						Status:      trigger_domain.StatusActive,
+					}, nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "limit price too low",
+			contract: contract.ContractIdentifier{
+				Ticker: "FOO",
+				Side:   contract.SideYes,
+			},
+			triggerPrice: contract.ContractPrice(80),
+			limitPrice:   ptr(contract.ContractPrice(60)),
+			mockSetup:    func(repo *trigger_mock.MockTriggerRepository) {},
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(trigger_mock.MockTriggerRepository)
+			tt.mockSetup(mockRepo)
+
+			service := NewTriggerService(mockRepo)
+			trigger, err := service.CreateTakeProfitTrigger(context.Background(), tt.contract, tt.triggerPrice, tt.limitPrice, nil)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, trigger)
+				assert.Equal(t, trigger_domain.TriggerTypeTakeProfit, trigger.TriggerType)
+				assert.Equal(t, trigger_domain.StatusActive, trigger.Status)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateTakeProfitTrigger(t *testing.T) {
+	triggerID := trigger_domain.NewTriggerID()
+	tests := []struct {
+		name         string
+		triggerType  trigger_domain.TriggerType
+		triggerPrice *contract.ContractPrice
+		expectError  error
+	}{
+		{
+			name:         "successful update",
+			triggerType:  trigger_domain.TriggerTypeTakeProfit,
+			triggerPrice: ptr(contract.ContractPrice(85)),
+		},
+		{
+			name:         "rejects stop trigger",
+			triggerType:  trigger_domain.TriggerTypeStop,
+			triggerPrice: ptr(contract.ContractPrice(85)),
+			expectError:  ErrInvalidTriggerType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(trigger_mock.MockTriggerRepository)
+			existingTrigger := &trigger_domain.Trigger{
+				TriggerID:   triggerID,
+				TriggerType: tt.triggerType,
+				Status:      trigger_domain.StatusActive,
+				Condition: trigger_domain.TriggerCondition{
+					Price: &trigger_domain.PriceRule{
+						Threshold: contract.ContractPrice(80),
+						Direction: trigger_domain.Above,
+					},
+				},
+				Actions: []trigger_domain.TriggerAction{
+					{Side: trigger_domain.Sell},
+				},
+			}
+			mockRepo.On("Get", mock.Anything, triggerID).Return(existingTrigger, nil)
+			if tt.expectError == nil {
+				mockRepo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+					return t.Condition.Price.Threshold == *tt.triggerPrice
+				})).Return(nil)
+				mockRepo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+					return len(changes) == 1 &&
+						changes[0].Field == trigger_domain.ChangeFieldTriggerPrice &&
+						*changes[0].OldValue == "80" &&
+						*changes[0].NewValue == "85" &&
+						changes[0].Actor == "trader"
+				})).Return(nil).Once()
+			}
+
+			service := NewTriggerService(mockRepo)
+			trigger, err := service.UpdateTakeProfitTrigger(context.Background(), triggerID, tt.triggerPrice, nil, nil, "trader")
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, *tt.triggerPrice, trigger.Condition.Price.Threshold)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateEntryTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	t.Run("successful creation", func(t *testing.T) {
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.TriggerType == trigger_domain.TriggerTypeEntry &&
+				t.Actions[0].Side == trigger_domain.Buy &&
+				*t.Actions[0].Size == 10 &&
+				*t.Actions[0].MaxCost == 200
+		})).Return(nil)
+		mockRepo.On("Get", mock.Anything, mock.AnythingOfType("trigger_domain.TriggerID")).
+			Return(&trigger_domain.Trigger{
+				TriggerType: trigger_domain.TriggerTypeEntry,
+				Status:      trigger_domain.StatusActive,
+			}, nil)
+
+		service := NewTriggerService(mockRepo)
+		trigger, err := service.CreateEntryTrigger(context.Background(), contractID, trigger_domain.Below, 20, 10, ptr(contract.ContractPrice(20)), ptr(200), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.TriggerTypeEntry, trigger.TriggerType)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects zero size", func(t *testing.T) {
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+
+		service := NewTriggerService(mockRepo)
+		_, err := service.CreateEntryTrigger(context.Background(), contractID, trigger_domain.Below, 20, 0, nil, nil, nil)
+
+		assert.ErrorIs(t, err, ErrInvalidTrigger)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateEntryTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	t.Run("updates level and size", func(t *testing.T) {
+		existing, err := trigger_domain.NewEntryTrigger(contractID, trigger_domain.Below, 20, 10, nil, ptr(200))
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Get", mock.Anything, existing.TriggerID).Return(existing, nil)
+		mockRepo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.Condition.Price.Threshold == 15 && *t.Actions[0].Size == 25
+		})).Return(nil)
+		mockRepo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+
+		service := NewTriggerService(mockRepo)
+		trigger, err := service.UpdateEntryTrigger(context.Background(), existing.TriggerID, ptr(contract.ContractPrice(15)), ptr(uint(25)), nil, nil, nil, "trader")
+
+		require.NoError(t, err)
+		assert.Equal(t, contract.ContractPrice(15), trigger.Condition.Price.Threshold)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects stop trigger", func(t *testing.T) {
+		existing, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Get", mock.Anything, existing.TriggerID).Return(existing, nil)
+
+		service := NewTriggerService(mockRepo)
+		_, err = service.UpdateEntryTrigger(context.Background(), existing.TriggerID, ptr(contract.ContractPrice(15)), nil, nil, nil, nil, "trader")
+
+		assert.ErrorIs(t, err, ErrInvalidTriggerType)
+	})
+}
+
+func TestCreateHedgeTrigger(t *testing.T) {
+	watched := contract.ContractIdentifier{Ticker: "FOO-B40", Side: contract.SideYes}
+	adjacent := contract.ContractIdentifier{Ticker: "FOO-B42", Side: contract.SideYes}
+
+	t.Run("successful creation", func(t *testing.T) {
+		buy, err := trigger_domain.NewBuyAction(adjacent, 10, nil, nil)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.TriggerType == trigger_domain.TriggerTypeHedge &&
+				t.Condition.Contract == watched &&
+				t.Actions[0].Contract == adjacent
+		})).Return(nil)
+		mockRepo.On("Get", mock.Anything, mock.AnythingOfType("trigger_domain.TriggerID")).
+			Return(&trigger_domain.Trigger{
+				TriggerType: trigger_domain.TriggerTypeHedge,
+				Status:      trigger_domain.StatusActive,
+			}, nil)
+
+		service := NewTriggerService(mockRepo)
+		trigger, err := service.CreateHedgeTrigger(context.Background(), watched, trigger_domain.Below, 30, []trigger_domain.TriggerAction{*buy}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.TriggerTypeHedge, trigger.TriggerType)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects actions on the watched contract only", func(t *testing.T) {
+		sell, err := trigger_domain.NewTriggerAction(watched, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+
+		service := NewTriggerService(mockRepo)
+		_, err = service.CreateHedgeTrigger(context.Background(), watched, trigger_domain.Below, 30, []trigger_domain.TriggerAction{*sell}, nil)
+
+		assert.ErrorIs(t, err, ErrInvalidTrigger)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreateEventExposureTrigger(t *testing.T) {
+	t.Run("successful creation", func(t *testing.T) {
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.TriggerType == trigger_domain.TriggerTypeEventExposure &&
+				t.Condition.Event.EventTicker == "KXHIGHNY-25FEB13" &&
+				t.Actions[0].Event == "KXHIGHNY-25FEB13"
+		})).Return(nil)
+		mockRepo.On("Get", mock.Anything, mock.AnythingOfType("trigger_domain.TriggerID")).
+			Return(&trigger_domain.Trigger{
+				TriggerType: trigger_domain.TriggerTypeEventExposure,
+				Status:      trigger_domain.StatusActive,
+			}, nil)
+
+		service := NewTriggerService(mockRepo)
+		trigger, err := service.CreateEventExposureTrigger(context.Background(), "KXHIGHNY-25FEB13", trigger_domain.EventMetricPNL, -20000, trigger_domain.Below)
+
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.TriggerTypeEventExposure, trigger.TriggerType)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects negative exposure threshold", func(t *testing.T) {
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+
+		service := NewTriggerService(mockRepo)
+		_, err := service.CreateEventExposureTrigger(context.Background(), "KXHIGHNY-25FEB13", trigger_domain.EventMetricExposure, -1, trigger_domain.Below)
+
+		assert.ErrorIs(t, err, ErrInvalidTrigger)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateTrailingStop(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	t.Run("persists new high water mark", func(t *testing.T) {
+		trigger, err := trigger_domain.NewTrailingStopTrigger(contractID, trigger_domain.TrailTypeCents, 10, 60)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("UpdateTrailingStop", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.TrailingStop.HighWaterMark == 80 && t.Condition.Price.Threshold == 70
+		})).Return(nil)
+		mockRepo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+			return len(changes) == 1 &&
+				changes[0].Field == trigger_domain.ChangeFieldTriggerPrice &&
+				*changes[0].OldValue == "50" &&
+				*changes[0].NewValue == "70" &&
+				changes[0].Actor == trigger_domain.ActorSystem
+		})).Return(nil).Once()
+
+		service := NewTriggerService(mockRepo)
+		updated, err := service.UpdateTrailingStop(context.Background(), trigger, contract.ContractPrice(80))
+
+		require.NoError(t, err)
+		assert.Equal(t, contract.ContractPrice(70), updated.Condition.Price.Threshold)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+
+	t.Run("returns error when the trigger changed since it was read", func(t *testing.T) {
+		trigger, err := trigger_domain.NewTrailingStopTrigger(contractID, trigger_domain.TrailTypeCents, 10, 60)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("UpdateTrailingStop", mock.Anything, trigger).Return(trigger_domain.ErrTriggerChanged)
+
+		service := NewTriggerService(mockRepo)
+		_, err = service.UpdateTrailingStop(context.Background(), trigger, contract.ContractPrice(80))
+
+		assert.ErrorIs(t, err, trigger_domain.ErrTriggerChanged)
+	})
+
+	t.Run("skips persist when price is below high water mark", func(t *testing.T) {
+		trigger, err := trigger_domain.NewTrailingStopTrigger(contractID, trigger_domain.TrailTypeCents, 10, 60)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+
+		service := NewTriggerService(mockRepo)
+		updated, err := service.UpdateTrailingStop(context.Background(), trigger, contract.ContractPrice(55))
+
+		require.NoError(t, err)
+		assert.Equal(t, contract.ContractPrice(50), updated.Condition.Price.Threshold)
+		mockRepo.AssertNotCalled(t, "UpdateTrailingStop", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreateCostBasisStopTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	t.Run("resolves stop from average entry", func(t *testing.T) {
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.CostBasis != nil && t.Condition.Price.Threshold == 51
+		})).Return(nil)
+		mockRepo.On("Get", mock.Anything, mock.AnythingOfType("trigger_domain.TriggerID")).
+			Return(&trigger_domain.Trigger{TriggerType: trigger_domain.TriggerTypeStop}, nil)
+
+		service := NewTriggerService(mockRepo)
+		position := exchange_domain.Position{ContractID: contractID, Quantity: 10, CostBasis: 600}
+		_, err := service.CreateCostBasisStopTrigger(context.Background(), position, trigger_domain.TrailTypePercent, 15, nil)
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects position without cost basis", func(t *testing.T) {
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+
+		service := NewTriggerService(mockRepo)
+		position := exchange_domain.Position{ContractID: contractID, Quantity: 10}
+		_, err := service.CreateCostBasisStopTrigger(context.Background(), position, trigger_domain.TrailTypeCents, 10, nil)
+
+		assert.ErrorIs(t, err, ErrInvalidTrigger)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateCostBasis(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	position := exchange_domain.Position{ContractID: contractID, Quantity: 10, CostBasis: 500}
+
+	t.Run("persists moved stop", func(t *testing.T) {
+		trigger, err := trigger_domain.NewCostBasisStopTrigger(position, trigger_domain.TrailTypeCents, 10)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("UpdateCostBasis", mock.Anything, trigger).Return(nil)
+		mockRepo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+			return len(changes) == 1 &&
+				changes[0].Field == trigger_domain.ChangeFieldTriggerPrice &&
+				*changes[0].OldValue == "40" &&
+				*changes[0].NewValue == "30" &&
+				changes[0].Actor == trigger_domain.ActorSystem
+		})).Return(nil).Once()
+
+		service := NewTriggerService(mockRepo)
+		updated, err := service.UpdateCostBasis(context.Background(), trigger, exchange_domain.Position{ContractID: contractID, Quantity: 5, CostBasis: 200})
+
+		require.NoError(t, err)
+		assert.Equal(t, contract.ContractPrice(30), updated.Condition.Price.Threshold)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+
+	t.Run("returns error when the trigger changed since it was read", func(t *testing.T) {
+		trigger, err := trigger_domain.NewCostBasisStopTrigger(position, trigger_domain.TrailTypeCents, 10)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("UpdateCostBasis", mock.Anything, trigger).Return(trigger_domain.ErrTriggerChanged)
+
+		service := NewTriggerService(mockRepo)
+		_, err = service.UpdateCostBasis(context.Background(), trigger, exchange_domain.Position{ContractID: contractID, Quantity: 5, CostBasis: 200})
+
+		assert.ErrorIs(t, err, trigger_domain.ErrTriggerChanged)
+	})
+
+	t.Run("skips persist when entry is unchanged", func(t *testing.T) {
+		trigger, err := trigger_domain.NewCostBasisStopTrigger(position, trigger_domain.TrailTypeCents, 10)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+
+		service := NewTriggerService(mockRepo)
+		updated, err := service.UpdateCostBasis(context.Background(), trigger, position)
+
+		require.NoError(t, err)
+		assert.Equal(t, contract.ContractPrice(40), updated.Condition.Price.Threshold)
+		mockRepo.AssertNotCalled(t, "UpdateCostBasis", mock.Anything, mock.Anything)
+	})
+}
+
+func TestExpireTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	expiry := trigger_domain.TriggerExpiry{
+		Reason:    trigger_domain.ExpiryReasonMarketClosed,
+		Ticker:    contractID.Ticker,
+		ExpiredAt: time.Now(),
+	}
+
+	t.Run("stores transition from the status it was read in and records it", func(t *testing.T) {
+		trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Expire", mock.Anything, trigger, trigger_domain.StatusActive).Return(nil).Once()
+		mockRepo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+			return len(changes) == 1 &&
+				changes[0].Field == trigger_domain.ChangeFieldStatus &&
+				*changes[0].OldValue == "ACTIVE" &&
+				*changes[0].NewValue == "EXPIRED" &&
+				changes[0].Actor == trigger_domain.ActorSystem
+		})).Return(nil).Once()
+
+		service := NewTriggerService(mockRepo)
+		updated, err := service.ExpireTrigger(context.Background(), trigger, expiry)
+
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusExpired, updated.Status)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects trigger in terminal status", func(t *testing.T) {
+		trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+		trigger.Status = trigger_domain.StatusCancelled
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+
+		service := NewTriggerService(mockRepo)
+		_, err = service.ExpireTrigger(context.Background(), trigger, expiry)
+
+		assert.Error(t, err)
+		assert.Equal(t, trigger_domain.StatusCancelled, trigger.Status)
+		mockRepo.AssertNotCalled(t, "Expire", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("records nothing when the trigger changed since it was read", func(t *testing.T) {
+		trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Expire", mock.Anything, trigger, trigger_domain.StatusActive).Return(trigger_domain.ErrTriggerChanged)
+
+		service := NewTriggerService(mockRepo)
+		_, err = service.ExpireTrigger(context.Background(), trigger, expiry)
+
+		assert.ErrorIs(t, err, trigger_domain.ErrTriggerChanged)
+		mockRepo.AssertNotCalled(t, "AppendHistory", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSetConfirmation(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	t.Run("replaces policy and clears pending progress", func(t *testing.T) {
+		trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+		trigger.Confirmation, err = trigger_domain.NewEvaluationConfirmation(3)
+		require.NoError(t, err)
+		trigger.Confirmation.Observe(true, time.Now())
+
+		confirmation, err := trigger_domain.NewDurationConfirmation(time.Minute)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+		mockRepo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.Confirmation.RequiredDuration == time.Minute && !t.Confirmation.IsPending()
+		})).Return(nil)
+
+		service := NewTriggerService(mockRepo)
+		updated, err := service.SetConfirmation(context.Background(), trigger.TriggerID, confirmation)
+
+		require.NoError(t, err)
+		assert.Equal(t, confirmation, updated.Confirmation)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid policy", func(t *testing.T) {
+		trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+
+		service := NewTriggerService(mockRepo)
+		_, err = service.SetConfirmation(context.Background(), trigger.TriggerID, &trigger_domain.Confirmation{})
+
+		assert.ErrorIs(t, err, ErrInvalidTrigger)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+}
+
+func TestConfirmTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	newTrigger := func(t *testing.T) *trigger_domain.Trigger {
+		trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+		trigger.Confirmation, err = trigger_domain.NewEvaluationConfirmation(2)
+		require.NoError(t, err)
+		return trigger
+	}
+
+	t.Run("stores only confirmation progress", func(t *testing.T) {
+		trigger := newTrigger(t)
+		at := time.Now()
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("UpdateConfirmation", mock.Anything, trigger, at).Return(nil).Once()
+
+		service := NewTriggerService(mockRepo)
+		confirmed, err := service.ConfirmTrigger(context.Background(), trigger, true, at)
+
+		require.NoError(t, err)
+		assert.False(t, confirmed)
+		assert.Equal(t, 1, trigger.Confirmation.SatisfiedCount)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not confirm when the trigger changed since it was read", func(t *testing.T) {
+		trigger := newTrigger(t)
+		trigger.Confirmation.Observe(true, time.Now())
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("UpdateConfirmation", mock.Anything, trigger, mock.Anything).Return(trigger_domain.ErrTriggerChanged)
+
+		service := NewTriggerService(mockRepo)
+		confirmed, err := service.ConfirmTrigger(context.Background(), trigger, true, time.Now())
+
+		assert.ErrorIs(t, err, trigger_domain.ErrTriggerChanged)
+		assert.False(t, confirmed)
+	})
+}
+
+func TestUpdateTrailingStopTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	trigger, err := trigger_domain.NewTrailingStopTrigger(contractID, trigger_domain.TrailTypeCents, 10, 80)
+	require.NoError(t, err)
+
+	mockRepo := new(trigger_mock.MockTriggerRepository)
+	mockRepo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+	mockRepo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+		return t.TrailingStop.TrailType == trigger_domain.TrailTypePercent &&
+			t.Condition.Price.Threshold == 60 &&
+			t.Condition.Price.Source == trigger_domain.PriceSourceBid
+	})).Return(nil)
+	mockRepo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+		return len(changes) == 1 &&
+			changes[0].Field == trigger_domain.ChangeFieldTriggerPrice &&
+			*changes[0].OldValue == "70" &&
+			*changes[0].NewValue == "60"
+	})).Return(nil).Once()
+
+	service := NewTriggerService(mockRepo)
+	updated, err := service.UpdateTrailingStopTrigger(
+		context.Background(),
+		trigger.TriggerID,
+		ptr(trigger_domain.TrailTypePercent),
+		ptr(25),
+		ptr(trigger_domain.PriceSourceBid),
+		"trader",
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, contract.ContractPrice(60), updated.Condition.Price.Threshold)
+	assert.Equal(t, trigger_domain.PriceSourceBid, updated.Condition.Price.Source)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestResumeTrigger(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	t.Run("reactivates a flagged trigger and records it", func(t *testing.T) {
+		trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+		trigger.Status = trigger_domain.StatusFlagged
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+		mockRepo.On("Persist", mock.Anything, trigger).Return(nil).Once()
+		mockRepo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+			return len(changes) == 1 &&
+				changes[0].Field == trigger_domain.ChangeFieldStatus &&
+				*changes[0].OldValue == "FLAGGED" &&
+				*changes[0].NewValue == "ACTIVE" &&
+				changes[0].Actor == "trader"
+		})).Return(nil).Once()
+
+		service := NewTriggerService(mockRepo)
+		resumed, err := service.ResumeTrigger(context.Background(), trigger.TriggerID, "trader")
+
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusActive, resumed.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects trigger that is not flagged", func(t *testing.T) {
+		trigger, err := trigger_domain.NewStopTrigger(contractID, 40, nil)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+
+		service := NewTriggerService(mockRepo)
+		_, err = service.ResumeTrigger(context.Background(), trigger.TriggerID, "trader")
+
+		assert.ErrorIs(t, err, ErrInvalidTrigger)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "AppendHistory", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreateConditionalTrigger(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bar := contract.ContractIdentifier{Ticker: "BAR", Side: contract.SideYes}
+	fooAbove, err := trigger_domain.NewPriceCondition(foo, 60, trigger_domain.Above)
+	require.NoError(t, err)
+	barBelow, err := trigger_domain.NewPriceCondition(bar, 30, trigger_domain.Below)
+	require.NoError(t, err)
+	condition, err := trigger_domain.NewCompositeCondition(trigger_domain.OperatorOr, *fooAbove, *barBelow)
+	require.NoError(t, err)
+	action, err := trigger_domain.NewTriggerAction(foo, trigger_domain.Sell, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		condition     trigger_domain.TriggerCondition
+		mockSetup     func(*trigger_mock.MockTriggerRepository)
+		expectedError error
+	}{
+		{
+			name:      "successful creation",
+			condition: *condition,
+			mockSetup: func(repo *trigger_mock.MockTriggerRepository) {
+				repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+					return t.TriggerType == trigger_domain.TriggerTypeConditional &&
+						t.Condition.Operator == trigger_domain.OperatorOr
+				})).Return(nil)
+				repo.On("Get", mock.Anything, mock.AnythingOfType("trigger_domain.TriggerID")).
+					Return(&trigger_domain.Trigger{
+						TriggerType: trigger_domain.TriggerTypeConditional,
+						Status:      trigger_domain.StatusActive,
+					}, nil)
+			},
+		},
+		{
+			name:          "invalid condition",
+			condition:     trigger_domain.TriggerCondition{Operator: trigger_domain.OperatorNot},
+			mockSetup:     func(repo *trigger_mock.MockTriggerRepository) {},
+			expectedError: ErrInvalidTrigger,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(trigger_mock.MockTriggerRepository)
+			tt.mockSetup(mockRepo)
+
+			service := NewTriggerService(mockRepo)
+			trigger, err := service.CreateConditionalTrigger(context.Background(), tt.condition, []trigger_domain.TriggerAction{*action})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, trigger_domain.TriggerTypeConditional, trigger.TriggerType)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateConditionalTrigger(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	fooAbove, err := trigger_domain.NewPriceCondition(foo, 60, trigger_domain.Above)
+	require.NoError(t, err)
+	notFoo, err := trigger_domain.NewCompositeCondition(trigger_domain.OperatorNot, *fooAbove)
+	require.NoError(t, err)
+
+	t.Run("rejects other trigger types", func(t *testing.T) {
+		stop, err := trigger_domain.NewStopTrigger(foo, 40, nil)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Get", mock.Anything, stop.TriggerID).Return(stop, nil)
+
+		service := NewTriggerService(mockRepo)
+		_, err = service.UpdateConditionalTrigger(context.Background(), stop.TriggerID, *notFoo, "trader")
+
+		assert.ErrorIs(t, err, ErrInvalidTriggerType)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+
+	t.Run("replaces the condition", func(t *testing.T) {
+		action, err := trigger_domain.NewTriggerAction(foo, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+		existing, err := trigger_domain.NewConditionalTrigger(*notFoo, []trigger_domain.TriggerAction{*action})
+		require.NoError(t, err)
+
+		orCondition, err := trigger_domain.NewCompositeCondition(trigger_domain.OperatorOr, *fooAbove, *notFoo)
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Get", mock.Anything, existing.TriggerID).Return(existing, nil)
+		mockRepo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.Condition.Operator == trigger_domain.OperatorOr
+		})).Return(nil)
+
+		service := NewTriggerService(mockRepo)
+		updated, err := service.UpdateConditionalTrigger(context.Background(), existing.TriggerID, *orCondition, "trader")
+
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.OperatorOr, updated.Condition.Operator)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetByType(t *testing.T) {
+	mockRepo := new(trigger_mock.MockTriggerRepository)
+	mockRepo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{
+		{TriggerID: trigger_domain.NewTriggerID(), TriggerType: trigger_domain.TriggerTypeStop},
+		{TriggerID: trigger_domain.NewTriggerID(), TriggerType: trigger_domain.TriggerTypeTakeProfit},
+	}, nil)
+
+	service := NewTriggerService(mockRepo)
+	triggers, err := service.GetByType(context.Background(), trigger_domain.TriggerTypeTakeProfit)
+
+	require.NoError(t, err)
+	require.Len(t, triggers, 1)
+	assert.Equal(t, trigger_domain.TriggerTypeTakeProfit, triggers[0].TriggerType)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestUpdateTriggerStatus(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -322,6 +1167,9 @@ func TestUpdateTriggerStatus(t *testing.T) {
 
 				repo.On("Get", mock.Anything, mock.Anything).Return(trigger, nil).Once()
 				repo.On("Persist", mock.Anything, mock.Anything).Return(nil)
+				repo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+					return len(changes) == 1 && *changes[0].NewValue == "TRIGGERED" && changes[0].Actor == trigger_domain.ActorSystem
+				})).Return(nil).Once()
 			},
 			currentStatus: trigger_domain.StatusActive,
 			newStatus:     trigger_domain.StatusTriggered,
@@ -355,7 +1203,7 @@ func TestUpdateTriggerStatus(t *testing.T) {
 			service := NewTriggerService(repo)
 
 			// Execute
-			updatedTrigger, err := service.UpdateTriggerStatus(trigger_domain.NewTriggerID(), tc.newStatus)
+			updatedTrigger, err := service.UpdateTriggerStatus(context.Background(), trigger_domain.NewTriggerID(), tc.newStatus, trigger_domain.ActorSystem)
 
 			// Verify
 			if tc.expectedError != "" {
@@ -374,6 +1222,354 @@ func TestUpdateTriggerStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateTriggerStatus_CancelsOCOSiblings(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	bracket := newArmedBracket(t, contractID)
+
+	repo := new(trigger_mock.MockTriggerRepository)
+	repo.On("Get", mock.Anything, bracket.Stop.TriggerID).Return(bracket.Stop, nil).Once()
+	repo.On("GetGroup", mock.Anything, bracket.Group.GroupID).Return(bracket.Group, nil).Once()
+	repo.On("GetByGroup", mock.Anything, bracket.Group.GroupID).
+		Return([]*trigger_domain.Trigger{bracket.Stop, bracket.TakeProfit}, nil).Once()
+	repo.On("PersistGroup", mock.Anything, bracket.Group, mock.MatchedBy(func(triggers []*trigger_domain.Trigger) bool {
+		return len(triggers) == 2 &&
+			triggers[0].Status == trigger_domain.StatusTriggered &&
+			triggers[1].Status == trigger_domain.StatusCancelled
+	})).Return(nil).Once()
+	// The sibling's cancellation is recorded alongside the trigger firing
+	repo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+		return len(changes) == 2 &&
+			changes[0].TriggerID == bracket.Stop.TriggerID &&
+			changes[1].TriggerID == bracket.TakeProfit.TriggerID &&
+			*changes[1].NewValue == "CANCELLED"
+	})).Return(nil).Once()
+
+	service := NewTriggerService(repo)
+	updatedTrigger, err := service.UpdateTriggerStatus(context.Background(), bracket.Stop.TriggerID, trigger_domain.StatusTriggered, trigger_domain.ActorSystem)
+
+	require.NoError(t, err)
+	assert.Equal(t, trigger_domain.StatusTriggered, updatedTrigger.Status)
+	assert.Equal(t, trigger_domain.StatusCancelled, bracket.TakeProfit.Status)
+	repo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateBracket(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	tests := []struct {
+		name            string
+		stopPrice       contract.ContractPrice
+		takeProfitPrice contract.ContractPrice
+		mockSetup       func(*trigger_mock.MockTriggerRepository)
+		expectedError   error
+	}{
+		{
+			name:            "valid bracket",
+			stopPrice:       40,
+			takeProfitPrice: 80,
+			mockSetup: func(repo *trigger_mock.MockTriggerRepository) {
+				// The bracket is read back once it has been saved
+				repo.On("PersistGroup", mock.Anything, mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						group := args.Get(1).(*trigger_domain.TriggerGroup)
+						saved := args.Get(2).([]*trigger_domain.Trigger)
+						repo.On("GetGroup", mock.Anything, group.GroupID).Return(group, nil).Once()
+						repo.On("GetByGroup", mock.Anything, group.GroupID).Return(saved[1:], nil).Once()
+						repo.On("Get", mock.Anything, saved[0].TriggerID).Return(saved[0], nil).Once()
+					}).Return(nil).Once()
+			},
+		},
+		{
+			name:            "stop above take profit",
+			stopPrice:       80,
+			takeProfitPrice: 40,
+			mockSetup:       func(repo *trigger_mock.MockTriggerRepository) {},
+			expectedError:   ErrInvalidTrigger,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(trigger_mock.MockTriggerRepository)
+			tt.mockSetup(repo)
+			service := NewTriggerService(repo)
+
+			bracket, err := service.CreateBracket(
+				context.Background(),
+				contractID, trigger_domain.Below, 60, 10, nil, nil,
+				tt.stopPrice, nil, tt.takeProfitPrice, nil, nil,
+			)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, bracket)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, contract.ContractPrice(60), bracket.Entry.Condition.Price.Threshold)
+				assert.Equal(t, trigger_domain.StatusActive, bracket.Entry.Status)
+				assert.Equal(t, tt.stopPrice, bracket.Stop.Condition.Price.Threshold)
+				assert.Equal(t, tt.takeProfitPrice, bracket.TakeProfit.Condition.Price.Threshold)
+				for _, exit := range bracket.Exits() {
+					assert.Equal(t, trigger_domain.StatusPending, exit.Status)
+				}
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestArmPendingTriggers(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	entryOrder := func(status string, filled uint) trigger_domain.TriggerOrder {
+		return trigger_domain.TriggerOrder{Order: exchange_domain.Order{
+			Ticker:         "FOO",
+			Side:           contract.SideYes,
+			Action:         exchange_domain.OrderActionBuy,
+			Quantity:       10,
+			FilledQuantity: filled,
+			Status:         status,
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		entryStatus trigger_domain.TriggerStatus
+		orders      []trigger_domain.TriggerOrder
+		exitStatus  trigger_domain.TriggerStatus // zero when the exits are left pending
+		exitSize    uint
+	}{
+		{
+			name:        "entry still waiting",
+			entryStatus: trigger_domain.StatusActive,
+		},
+		{
+			name:        "entry order still resting",
+			entryStatus: trigger_domain.StatusTriggered,
+			orders:      []trigger_domain.TriggerOrder{entryOrder(exchange_domain.OrderStatusPartiallyFilled, 4)},
+		},
+		{
+			name:        "entry filled in part",
+			entryStatus: trigger_domain.StatusTriggered,
+			orders:      []trigger_domain.TriggerOrder{entryOrder(exchange_domain.OrderStatusCanceled, 4)},
+			exitStatus:  trigger_domain.StatusActive,
+			exitSize:    4,
+		},
+		{
+			name:        "entry filled",
+			entryStatus: trigger_domain.StatusTriggered,
+			orders:      []trigger_domain.TriggerOrder{entryOrder(exchange_domain.OrderStatusExecuted, 10)},
+			exitStatus:  trigger_domain.StatusActive,
+			exitSize:    10,
+		},
+		{
+			name:        "entry order canceled unfilled",
+			entryStatus: trigger_domain.StatusTriggered,
+			orders:      []trigger_domain.TriggerOrder{entryOrder(exchange_domain.OrderStatusCanceled, 0)},
+			exitStatus:  trigger_domain.StatusCancelled,
+		},
+		{
+			name:        "entry cancelled before firing",
+			entryStatus: trigger_domain.StatusCancelled,
+			exitStatus:  trigger_domain.StatusCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := trigger_domain.NewEntryTrigger(contractID, trigger_domain.Below, 60, 10, nil, nil)
+			require.NoError(t, err)
+			bracket, err := trigger_domain.NewBracket(entry, 40, nil, 80, nil)
+			require.NoError(t, err)
+			entry.Status = tt.entryStatus
+			entry.Orders = tt.orders
+
+			repo := new(trigger_mock.MockTriggerRepository)
+			repo.On("GetPending", mock.Anything).Return(bracket.Exits(), nil).Once()
+			// Both exits wait on the same entry, which is loaded once
+			repo.On("Get", mock.Anything, entry.TriggerID).Return(entry, nil).Once()
+			if tt.exitStatus != "" {
+				for _, exit := range bracket.Exits() {
+					repo.On("Persist", mock.Anything, exit).Return(nil).Once()
+				}
+				repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Twice()
+			}
+
+			err = NewTriggerService(repo).ArmPendingTriggers(context.Background())
+
+			require.NoError(t, err)
+			for _, exit := range bracket.Exits() {
+				if tt.exitStatus == "" {
+					assert.Equal(t, trigger_domain.StatusPending, exit.Status)
+					continue
+				}
+				assert.Equal(t, tt.exitStatus, exit.Status)
+				if tt.exitSize > 0 {
+					require.NotNil(t, exit.Actions[0].Size)
+					assert.Equal(t, tt.exitSize, *exit.Actions[0].Size)
+				}
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateStopLadder(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	t.Run("successful creation", func(t *testing.T) {
+		tiers := []trigger_domain.LadderTier{
+			{TriggerPrice: 45, Share: 50},
+			{TriggerPrice: 35, Share: 50},
+		}
+
+		var persisted []*trigger_domain.Trigger
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("PersistGroup", mock.Anything, mock.AnythingOfType("*trigger_domain.TriggerGroup"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				group := args.Get(1).(*trigger_domain.TriggerGroup)
+				persisted = args.Get(2).([]*trigger_domain.Trigger)
+				mockRepo.On("GetGroup", mock.Anything, group.GroupID).Return(group, nil)
+				// Stored order is not guaranteed, the service sorts tiers by price
+				mockRepo.On("GetByGroup", mock.Anything, group.GroupID).
+					Return([]*trigger_domain.Trigger{persisted[1], persisted[0]}, nil)
+			}).
+			Return(nil)
+
+		service := NewTriggerService(mockRepo)
+		ladder, err := service.CreateStopLadder(context.Background(), contractID, tiers, nil)
+
+		require.NoError(t, err)
+		require.Len(t, ladder.Tiers, 2)
+		assert.Equal(t, contract.ContractPrice(45), ladder.Tiers[0].Condition.Price.Threshold)
+		assert.Equal(t, contract.ContractPrice(35), ladder.Tiers[1].Condition.Price.Threshold)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects shares that do not cover the position", func(t *testing.T) {
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+
+		service := NewTriggerService(mockRepo)
+		_, err := service.CreateStopLadder(context.Background(), contractID, []trigger_domain.LadderTier{
+			{TriggerPrice: 45, Share: 30},
+			{TriggerPrice: 35, Share: 30},
+		}, nil)
+
+		assert.ErrorIs(t, err, ErrInvalidTrigger)
+		mockRepo.AssertNotCalled(t, "PersistGroup", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("tiers cannot be updated individually", func(t *testing.T) {
+		ladder, err := trigger_domain.NewStopLadder(contractID, []trigger_domain.LadderTier{
+			{TriggerPrice: 45, Share: 50},
+			{TriggerPrice: 35, Share: 50},
+		})
+		require.NoError(t, err)
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("Get", mock.Anything, ladder.Tiers[0].TriggerID).Return(ladder.Tiers[0], nil)
+
+		service := NewTriggerService(mockRepo)
+		newPrice := contract.ContractPrice(30)
+		_, err = service.UpdateStopTrigger(context.Background(), ladder.Tiers[0].TriggerID, &newPrice, nil, nil, "trader")
+
+		assert.ErrorIs(t, err, ErrInvalidTrigger)
+		mockRepo.AssertNotCalled(t, "Persist", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateStopLadder(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	ladder, err := trigger_domain.NewStopLadder(contractID, []trigger_domain.LadderTier{
+		{TriggerPrice: 45, Share: 50},
+		{TriggerPrice: 35, Share: 50},
+	})
+	require.NoError(t, err)
+
+	mockRepo := new(trigger_mock.MockTriggerRepository)
+	mockRepo.On("GetGroup", mock.Anything, ladder.Group.GroupID).Return(ladder.Group, nil)
+	mockRepo.On("GetByGroup", mock.Anything, ladder.Group.GroupID).Return(ladder.Tiers, nil)
+	mockRepo.On("PersistGroup", mock.Anything, ladder.Group, ladder.Tiers).Return(nil).Once()
+	mockRepo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+		return len(changes) == 2 &&
+			changes[0].TriggerID == ladder.Tiers[0].TriggerID &&
+			*changes[0].OldValue == "45" &&
+			*changes[0].NewValue == "40" &&
+			changes[1].TriggerID == ladder.Tiers[1].TriggerID &&
+			*changes[1].OldValue == "35" &&
+			*changes[1].NewValue == "30" &&
+			changes[1].Actor == "trader"
+	})).Return(nil).Once()
+
+	service := NewTriggerService(mockRepo)
+	_, err = service.UpdateStopLadder(context.Background(), ladder.Group.GroupID, []trigger_domain.LadderTier{
+		{TriggerPrice: 40, Share: 50},
+		{TriggerPrice: 30, Share: 50},
+	}, nil, "trader")
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelStopLadder(t *testing.T) {
+	contractID := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+
+	newLadder := func(t *testing.T) *trigger_domain.StopLadder {
+		ladder, err := trigger_domain.NewStopLadder(contractID, []trigger_domain.LadderTier{
+			{TriggerPrice: 45, Share: 50},
+			{TriggerPrice: 35, Share: 50},
+		})
+		require.NoError(t, err)
+		return ladder
+	}
+
+	t.Run("cancels tiers that have not fired and records them", func(t *testing.T) {
+		ladder := newLadder(t)
+		waiting := ladder.Tiers[0]
+		executing := ladder.Tiers[1]
+		executing.Status = trigger_domain.StatusExecuting
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("GetGroup", mock.Anything, ladder.Group.GroupID).Return(ladder.Group, nil)
+		mockRepo.On("GetByGroup", mock.Anything, ladder.Group.GroupID).Return(ladder.Tiers, nil)
+		// The executing tier may already have orders out, so it is left to finish
+		mockRepo.On("PersistGroup", mock.Anything, ladder.Group, []*trigger_domain.Trigger{waiting}).Return(nil).Once()
+		mockRepo.On("AppendHistory", mock.Anything, mock.MatchedBy(func(changes []trigger_domain.TriggerChange) bool {
+			return len(changes) == 1 &&
+				changes[0].TriggerID == waiting.TriggerID &&
+				*changes[0].NewValue == "CANCELLED" &&
+				changes[0].Actor == "trader"
+		})).Return(nil).Once()
+
+		service := NewTriggerService(mockRepo)
+		_, err := service.CancelStopLadder(context.Background(), ladder.Group.GroupID, "trader")
+
+		require.NoError(t, err)
+		assert.Equal(t, trigger_domain.StatusExecuting, executing.Status)
+		assert.Equal(t, trigger_domain.StatusCancelled, waiting.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects ladder with nothing left to cancel", func(t *testing.T) {
+		ladder := newLadder(t)
+		for _, tier := range ladder.Tiers {
+			tier.Status = trigger_domain.StatusTriggered
+		}
+
+		mockRepo := new(trigger_mock.MockTriggerRepository)
+		mockRepo.On("GetGroup", mock.Anything, ladder.Group.GroupID).Return(ladder.Group, nil)
+		mockRepo.On("GetByGroup", mock.Anything, ladder.Group.GroupID).Return(ladder.Tiers, nil)
+
+		service := NewTriggerService(mockRepo)
+		_, err := service.CancelStopLadder(context.Background(), ladder.Group.GroupID, "trader")
+
+		assert.ErrorIs(t, err, ErrInvalidTrigger)
+		mockRepo.AssertNotCalled(t, "PersistGroup", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "AppendHistory", mock.Anything, mock.Anything)
+	})
+}
+
 func TestTriggerService_validateStatusTransition(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -403,6 +1599,23 @@ func TestTriggerService_validateStatusTransition(t *testing.T) {
 			newStatus:     "INVALID",
 			expectedError: "invalid status",
 		},
+		{
+			name:          "pending trigger is armed",
+			currentStatus: trigger_domain.StatusPending,
+			newStatus:     trigger_domain.StatusActive,
+		},
+		{
+			name:          "pending trigger cannot execute",
+			currentStatus: trigger_domain.StatusPending,
+			newStatus:     trigger_domain.StatusExecuting,
+			expectedError: "cannot transition from PENDING to EXECUTING",
+		},
+		{
+			name:          "nothing returns to pending",
+			currentStatus: trigger_domain.StatusActive,
+			newStatus:     trigger_domain.StatusPending,
+			expectedError: "cannot transition from ACTIVE to PENDING",
+		},
 		{
 			name:          "transition from terminal status",
 			currentStatus: trigger_domain.StatusTriggered,
@@ -0,0 +1,173 @@
+package trigger_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	exchange_service "prediction-risk/internal/app/exchange/service"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	weather_service "prediction-risk/internal/app/weather/service"
+	"slices"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// Observations older than this are historical backfill and never fire triggers
+const maxObservationAge = 2 * time.Hour
+
+// WeatherTriggerEvaluator evaluates triggers with weather conditions each time
+// a new temperature observation arrives, executing the ones that are satisfied
+type WeatherTriggerEvaluator struct {
+	triggerService  *TriggerService
+	triggerExecutor *TriggerExecutor
+	exchangeService exchange_service.ExchangeService
+	tickers         *TickerLocks
+	weatherService  weather_service.WeatherObservationService
+	location        *time.Location // defines the station's day for daily metrics
+	now             func() time.Time
+}
+
+func NewWeatherTriggerEvaluator(
+	triggerService *TriggerService,
+	triggerExecutor *TriggerExecutor,
+	exchangeService exchange_service.ExchangeService,
+	tickers *TickerLocks,
+	weatherService weather_service.WeatherObservationService,
+	location *time.Location,
+) *WeatherTriggerEvaluator {
+	return &WeatherTriggerEvaluator{
+		triggerService:  triggerService,
+		triggerExecutor: triggerExecutor,
+		exchangeService: exchangeService,
+		tickers:         tickers,
+		weatherService:  weatherService,
+		location:        location,
+		now:             time.Now,
+	}
+}
+
+// HandleObservation implements weather_service.ObservationHandler
+func (e *WeatherTriggerEvaluator) HandleObservation(ctx context.Context, observation *weather_domain.TemperatureObservation) error {
+	if e.now().Sub(observation.Timestamp) > maxObservationAge {
+		return nil
+	}
+
+	triggers, err := e.triggerService.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("getting triggers: %w", err)
+	}
+	watching := lo.Filter(triggers, func(t *trigger_domain.Trigger, _ int) bool {
+		return t.Status == trigger_domain.StatusActive &&
+			slices.Contains(t.Condition.Stations(), observation.StationID)
+	})
+	if len(watching) == 0 {
+		return nil
+	}
+	log.Printf("Evaluating %d weather triggers for station %s", len(watching), observation.StationID)
+
+	stationWeather, err := e.getStationWeather(observation)
+	if err != nil {
+		return err
+	}
+
+	var evaluationErrors []error
+	for _, trigger := range watching {
+		if err := e.evaluateTrigger(ctx, trigger, observation, stationWeather); err != nil {
+			evaluationErrors = append(evaluationErrors, fmt.Errorf("trigger %s: %w", trigger.TriggerID, err))
+		}
+	}
+
+	return errors.Join(evaluationErrors...)
+}
+
+func (e *WeatherTriggerEvaluator) evaluateTrigger(
+	ctx context.Context,
+	trigger *trigger_domain.Trigger,
+	observation *weather_domain.TemperatureObservation,
+	stationWeather trigger_domain.StationWeather,
+) error {
+	// Conditions may also reference other stations or market prices
+	data, err := fetchMarketData(ctx, e.exchangeService, []*trigger_domain.Trigger{trigger})
+	if err != nil {
+		return err
+	}
+	// The trigger monitor and order tracker may be trading the same tickers
+	unlock := e.tickers.Lock(data.tickersActedOn(trigger)...)
+	defer unlock()
+
+	snapshot, err := data.snapshot(trigger, e.now())
+	if err != nil {
+		return err
+	}
+
+	// Stop watching markets that can no longer trade
+	if expiry := snapshot.Expiry(); expiry != nil {
+		_, err := e.triggerService.ExpireTrigger(ctx, trigger, *expiry)
+		return err
+	}
+	snapshot.Weather[observation.StationID] = stationWeather
+
+	for _, stationID := range trigger.Condition.Stations() {
+		if stationID == observation.StationID {
+			continue
+		}
+		latest, err := e.weatherService.GetLatestTemperature(stationID)
+		if err != nil {
+			return fmt.Errorf("get latest temperature for %s: %w", stationID, err)
+		}
+		weather, err := e.getStationWeather(latest)
+		if err != nil {
+			return err
+		}
+		snapshot.Weather[stationID] = weather
+	}
+
+	isSatisfied, err := trigger.Condition.IsSatisfied(snapshot)
+	if err != nil {
+		return err
+	}
+
+	// Wait out the confirmation policy, if any, before firing
+	confirmed, err := e.triggerService.ConfirmTrigger(ctx, trigger, isSatisfied, observation.Timestamp)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	executedTrigger, err := e.triggerExecutor.ExecuteTrigger(ctx, trigger)
+	if err != nil {
+		return err
+	}
+	log.Printf("Executed weather trigger %s", executedTrigger.TriggerID)
+
+	return nil
+}
+
+// getStationWeather combines the observation with the highest temperature
+// stored so far for the observation's day
+func (e *WeatherTriggerEvaluator) getStationWeather(
+	observation *weather_domain.TemperatureObservation,
+) (trigger_domain.StationWeather, error) {
+	observedAt := observation.Timestamp.In(e.location)
+	startOfDay := time.Date(observedAt.Year(), observedAt.Month(), observedAt.Day(), 0, 0, 0, 0, e.location)
+
+	dailyMax := observation.Temperature
+	maxObservation, err := e.weatherService.GetMaxTemperature(observation.StationID, startOfDay, startOfDay.AddDate(0, 0, 1))
+	if err != nil {
+		return trigger_domain.StationWeather{}, fmt.Errorf("get daily max temperature: %w", err)
+	}
+	if maxObservation.Temperature.In(dailyMax.TemperatureUnit).Value > dailyMax.Value {
+		dailyMax = maxObservation.Temperature
+	}
+
+	return trigger_domain.StationWeather{
+		Temperature:         observation.Temperature,
+		DailyMaxTemperature: dailyMax,
+		ObservedAt:          observation.Timestamp,
+	}, nil
+}
@@ -0,0 +1,186 @@
+package trigger_service
+
+import (
+	"context"
+	"prediction-risk/internal/app/contract"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_mock "prediction-risk/internal/app/risk/trigger/mock"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	weather_mocks "prediction-risk/internal/app/weather/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeatherTriggerEvaluator_HandleObservation(t *testing.T) {
+	foo := contract.ContractIdentifier{Ticker: "FOO", Side: contract.SideYes}
+	est := time.FixedZone("EST", -5*60*60)
+
+	newWeatherTrigger := func(t *testing.T) *trigger_domain.Trigger {
+		condition, err := trigger_domain.NewWeatherCondition(
+			"KNYC",
+			trigger_domain.MetricDailyMaxTemperature,
+			weather_domain.Temperature{Value: 80, TemperatureUnit: weather_domain.Fahrenheit},
+			trigger_domain.Above,
+		)
+		require.NoError(t, err)
+		action, err := trigger_domain.NewTriggerAction(foo, trigger_domain.Sell, nil, nil)
+		require.NoError(t, err)
+		trigger, err := trigger_domain.NewConditionalTrigger(*condition, []trigger_domain.TriggerAction{*action})
+		require.NoError(t, err)
+		return trigger
+	}
+
+	tests := []struct {
+		name          string
+		observedC     float64
+		dailyMaxC     float64
+		expectExecute bool
+	}{
+		{name: "daily max crosses threshold", observedC: 25, dailyMaxC: 27, expectExecute: true},
+		{name: "new observation crosses threshold", observedC: 27, dailyMaxC: 25, expectExecute: true},
+		{name: "below threshold", observedC: 25, dailyMaxC: 26, expectExecute: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger := newWeatherTrigger(t)
+			observedAt := time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC)
+			observation := weather_domain.NewTemperatureObservation(
+				"KNYC",
+				weather_domain.Temperature{Value: tt.observedC, TemperatureUnit: weather_domain.Celsius},
+				observedAt,
+			)
+			maxObservation := weather_domain.NewTemperatureObservation(
+				"KNYC",
+				weather_domain.Temperature{Value: tt.dailyMaxC, TemperatureUnit: weather_domain.Celsius},
+				observedAt.Add(-2*time.Hour),
+			)
+
+			repo := new(trigger_mock.MockTriggerRepository)
+			repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{trigger}, nil)
+			exchange := new(trigger_mock.MockExchangeService)
+			exchange.On("GetMarkets", mock.Anything, []contract.Ticker{foo.Ticker}).Return([]*exchange_domain.Market{{
+				Ticker: foo.Ticker,
+				Status: exchange_domain.MarketStatus{State: exchange_domain.MarketStateOpen},
+			}}, nil)
+			weather := new(weather_mocks.MockWeatherObservationService)
+
+			startOfDay := time.Date(2025, 7, 1, 0, 0, 0, 0, est)
+			weather.On("GetMaxTemperature", "KNYC", startOfDay, startOfDay.AddDate(0, 0, 1)).
+				Return(maxObservation, nil)
+
+			if tt.expectExecute {
+				exchange.On("CreateOrder", mock.Anything, mock.Anything).Return(&exchange_domain.Order{}, nil).Once()
+				repo.On("Get", mock.Anything, trigger.TriggerID).Return(trigger, nil)
+				repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+					return t.Status == trigger_domain.StatusExecuting
+				})).Return(nil).Once()
+				repo.On("Persist", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+					return t.Status == trigger_domain.StatusTriggered
+				})).Return(nil).Once()
+				repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Twice()
+				repo.On("PersistOrders", mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			triggerService := NewTriggerService(repo)
+			evaluator := NewWeatherTriggerEvaluator(
+				triggerService,
+				NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil),
+				exchange,
+				NewTickerLocks(),
+				weather,
+				est,
+			)
+			evaluator.now = func() time.Time { return observedAt.Add(10 * time.Minute) }
+
+			err := evaluator.HandleObservation(context.Background(), observation)
+
+			require.NoError(t, err)
+			if !tt.expectExecute {
+				exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+			}
+			repo.AssertExpectations(t)
+			exchange.AssertExpectations(t)
+			weather.AssertExpectations(t)
+		})
+	}
+
+	t.Run("expires trigger when market settled", func(t *testing.T) {
+		trigger := newWeatherTrigger(t)
+		observedAt := time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC)
+		observation := weather_domain.NewTemperatureObservation(
+			"KNYC",
+			weather_domain.Temperature{Value: 35, TemperatureUnit: weather_domain.Celsius},
+			observedAt,
+		)
+
+		result := "yes"
+		repo := new(trigger_mock.MockTriggerRepository)
+		repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{trigger}, nil)
+		repo.On("Expire", mock.Anything, mock.MatchedBy(func(t *trigger_domain.Trigger) bool {
+			return t.Status == trigger_domain.StatusExpired &&
+				t.Expiry.Reason == trigger_domain.ExpiryReasonMarketSettled &&
+				*t.Expiry.SettlementResult == "yes"
+		}), trigger_domain.StatusActive).Return(nil).Once()
+		repo.On("AppendHistory", mock.Anything, mock.Anything).Return(nil).Once()
+		exchange := new(trigger_mock.MockExchangeService)
+		exchange.On("GetMarkets", mock.Anything, []contract.Ticker{foo.Ticker}).Return([]*exchange_domain.Market{{
+			Ticker: foo.Ticker,
+			Status: exchange_domain.MarketStatus{State: exchange_domain.MarketStateSettled, Result: &result},
+		}}, nil)
+		weather := new(weather_mocks.MockWeatherObservationService)
+		weather.On("GetMaxTemperature", "KNYC", mock.Anything, mock.Anything).Return(observation, nil)
+
+		triggerService := NewTriggerService(repo)
+		evaluator := NewWeatherTriggerEvaluator(
+			triggerService,
+			NewTriggerExecutor(triggerService, exchange, NewTickerLocks(), nil),
+			exchange,
+			NewTickerLocks(),
+			weather,
+			est,
+		)
+		evaluator.now = func() time.Time { return observedAt.Add(10 * time.Minute) }
+
+		require.NoError(t, evaluator.HandleObservation(context.Background(), observation))
+		exchange.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ignores stale observations", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		evaluator := NewWeatherTriggerEvaluator(NewTriggerService(repo), nil, nil, nil, nil, est)
+
+		observation := weather_domain.NewTemperatureObservation(
+			"KNYC",
+			weather_domain.Temperature{Value: 35, TemperatureUnit: weather_domain.Celsius},
+			time.Now().Add(-6*time.Hour),
+		)
+
+		assert.NoError(t, evaluator.HandleObservation(context.Background(), observation))
+		repo.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+
+	t.Run("ignores other stations", func(t *testing.T) {
+		repo := new(trigger_mock.MockTriggerRepository)
+		repo.On("GetAll", mock.Anything).Return([]*trigger_domain.Trigger{newWeatherTrigger(t)}, nil)
+		weather := new(weather_mocks.MockWeatherObservationService)
+
+		triggerService := NewTriggerService(repo)
+		evaluator := NewWeatherTriggerEvaluator(triggerService, nil, nil, nil, weather, est)
+
+		observation := weather_domain.NewTemperatureObservation(
+			"KLGA",
+			weather_domain.Temperature{Value: 35, TemperatureUnit: weather_domain.Celsius},
+			time.Now(),
+		)
+
+		assert.NoError(t, evaluator.HandleObservation(context.Background(), observation))
+		weather.AssertNotCalled(t, "GetMaxTemperature", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,77 @@
+package weather_domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Gridpoint identifies an NWS forecast grid square, e.g. OKX/33,37 for Central Park
+type Gridpoint struct {
+	OfficeID string
+	X        int
+	Y        int
+}
+
+func NewGridpoint(officeID string, x int, y int) (Gridpoint, error) {
+	if officeID == "" {
+		return Gridpoint{}, errors.New("gridpoint must have a forecast office")
+	}
+	if x < 0 || y < 0 {
+		return Gridpoint{}, fmt.Errorf("invalid gridpoint coordinates: %d,%d", x, y)
+	}
+	return Gridpoint{OfficeID: officeID, X: x, Y: y}, nil
+}
+
+func (g Gridpoint) String() string {
+	return fmt.Sprintf("%s/%d,%d", g.OfficeID, g.X, g.Y)
+}
+
+// ForecastPeriod is the temperature forecast for one period, an hour for hourly forecasts
+type ForecastPeriod struct {
+	StartTime   time.Time
+	EndTime     time.Time
+	Temperature Temperature
+}
+
+// TemperatureForecast is the latest temperature forecast issued for a gridpoint
+type TemperatureForecast struct {
+	Gridpoint   Gridpoint
+	Periods     []ForecastPeriod
+	GeneratedAt time.Time
+}
+
+// MaxTemperature returns the highest temperature forecast for periods starting
+// in [start, end), or false if the forecast has no such periods
+func (f TemperatureForecast) MaxTemperature(start time.Time, end time.Time) (Temperature, bool) {
+	return f.extremeTemperature(start, end, func(value, extreme float64) bool { return value > extreme })
+}
+
+// MinTemperature returns the lowest temperature forecast for periods starting
+// in [start, end), or false if the forecast has no such periods
+func (f TemperatureForecast) MinTemperature(start time.Time, end time.Time) (Temperature, bool) {
+	return f.extremeTemperature(start, end, func(value, extreme float64) bool { return value < extreme })
+}
+
+func (f TemperatureForecast) extremeTemperature(
+	start time.Time,
+	end time.Time,
+	exceeds func(value, extreme float64) bool,
+) (Temperature, bool) {
+	var extreme Temperature
+	found := false
+	for _, period := range f.Periods {
+		if period.StartTime.Before(start) || !period.StartTime.Before(end) {
+			continue
+		}
+		if !found {
+			extreme = period.Temperature
+			found = true
+			continue
+		}
+		if exceeds(period.Temperature.In(extreme.TemperatureUnit).Value, extreme.Value) {
+			extreme = period.Temperature
+		}
+	}
+	return extreme, found
+}
@@ -1,6 +1,7 @@
 package weather_domain
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,11 +24,45 @@ const (
 	Fahrenheit TemperatureUnit = "FAHRENHEIT"
 )
 
+func (u TemperatureUnit) IsValid() bool {
+	switch u {
+	case Celsius, Fahrenheit:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewTemperatureUnit(s string) (TemperatureUnit, error) {
+	switch s {
+	case "CELSIUS":
+		return Celsius, nil
+	case "FAHRENHEIT":
+		return Fahrenheit, nil
+	default:
+		return "", fmt.Errorf("invalid TemperatureUnit: %s", s)
+	}
+}
+
 type Temperature struct {
 	Value           float64
 	TemperatureUnit TemperatureUnit
 }
 
+// In converts the temperature to the given unit
+func (t Temperature) In(unit TemperatureUnit) Temperature {
+	if t.TemperatureUnit == unit {
+		return t
+	}
+
+	switch unit {
+	case Fahrenheit:
+		return Temperature{Value: t.Value*9/5 + 32, TemperatureUnit: Fahrenheit}
+	default:
+		return Temperature{Value: (t.Value - 32) * 5 / 9, TemperatureUnit: Celsius}
+	}
+}
+
 type TemperatureObservation struct {
 	ObservationID ObservationID
 	StationID     string
@@ -55,4 +90,6 @@ func NewTemperatureObservation(
 
 type TemperatureObservationFilter struct {
 	StationID *string
+	Start     *time.Time // inclusive
+	End       *time.Time // exclusive
 }
@@ -0,0 +1,84 @@
+package weather_domain
+
+import (
+	"fmt"
+	"math"
+	"prediction-risk/internal/app/contract"
+	"time"
+)
+
+// DailyMetric is the daily value a weather market settles on
+type DailyMetric string
+
+const (
+	DailyHigh DailyMetric = "DAILY_HIGH"
+	DailyLow  DailyMetric = "DAILY_LOW"
+)
+
+func (m DailyMetric) String() string {
+	return string(m)
+}
+
+func (m DailyMetric) IsValid() bool {
+	switch m {
+	case DailyHigh, DailyLow:
+		return true
+	default:
+		return false
+	}
+}
+
+// TemperatureRange is an inclusive range of whole degrees. A nil bound is open,
+// e.g. "80° or above" has no max.
+type TemperatureRange struct {
+	Min             *float64
+	Max             *float64
+	TemperatureUnit TemperatureUnit
+}
+
+// Contains reports whether the temperature falls in the range once rounded to a
+// whole degree, as NWS climate reports do
+func (r TemperatureRange) Contains(temperature Temperature) bool {
+	value := math.Round(temperature.In(r.TemperatureUnit).Value)
+	if r.Min != nil && value < *r.Min {
+		return false
+	}
+	if r.Max != nil && value > *r.Max {
+		return false
+	}
+	return true
+}
+
+func (r TemperatureRange) String() string {
+	switch {
+	case r.Min != nil && r.Max != nil:
+		return fmt.Sprintf("[%.0f, %.0f] %s", *r.Min, *r.Max, r.TemperatureUnit)
+	case r.Min != nil:
+		return fmt.Sprintf("[%.0f, ∞) %s", *r.Min, r.TemperatureUnit)
+	case r.Max != nil:
+		return fmt.Sprintf("(-∞, %.0f] %s", *r.Max, r.TemperatureUnit)
+	default:
+		return fmt.Sprintf("(-∞, ∞) %s", r.TemperatureUnit)
+	}
+}
+
+// WeatherMarket is a market that settles YES when a station's daily high or low
+// for a date falls in its range
+type WeatherMarket struct {
+	Ticker      contract.Ticker
+	EventTicker contract.Ticker
+	StationID   string
+	Metric      DailyMetric
+	Date        time.Time // midnight starting the station's climate day
+	Range       TemperatureRange
+}
+
+// IsOn reports whether the time falls on the market's climate day
+func (m WeatherMarket) IsOn(t time.Time) bool {
+	return !t.Before(m.Date) && t.Before(m.Date.AddDate(0, 0, 1))
+}
+
+func (m WeatherMarket) String() string {
+	return fmt.Sprintf("%s: %s %s on %s in %s",
+		m.Ticker, m.StationID, m.Metric, m.Date.Format(time.DateOnly), m.Range)
+}
@@ -8,6 +8,8 @@ type ForecastPeriod struct {
 	StartTime                  time.Time         `json:"startTime"`
 	EndTime                    time.Time         `json:"endTime"`
 	IsDaytime                  bool              `json:"isDaytime"`
+	Temperature                float64           `json:"temperature"`
+	TemperatureUnit            string            `json:"temperatureUnit"`
 	TemperatureTrend           string            `json:"temperatureTrend"`
 	ProbabilityOfPrecipitation QuantitativeValue `json:"probabilityOfPrecipitation"`
 	Dewpoint                   QuantitativeValue `json:"dewpoint"`
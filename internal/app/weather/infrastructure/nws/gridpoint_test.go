@@ -152,6 +152,8 @@ func TestGridpointGetHourlyForecast(t *testing.T) {
 								StartTime:        time.Now(),
 								EndTime:          time.Now().Add(1 * time.Hour),
 								IsDaytime:        false,
+								Temperature:      76,
+								TemperatureUnit:  "F",
 								TemperatureTrend: "steady",
 								WindDirection:    "N",
 								ShortForecast:    "Clear",
@@ -209,7 +211,9 @@ func TestGridpointGetHourlyForecast(t *testing.T) {
 			require.NoError(t, err)
 			require.NotNil(t, result)
 			assert.Equal(t, "Feature", result.Type)
-			assert.NotEmpty(t, result.Properties.Periods)
+			require.NotEmpty(t, result.Properties.Periods)
+			assert.Equal(t, 76.0, result.Properties.Periods[0].Temperature)
+			assert.Equal(t, "F", result.Properties.Periods[0].TemperatureUnit)
 		})
 	}
 }
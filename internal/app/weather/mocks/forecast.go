@@ -0,0 +1,19 @@
+package weather_mocks
+
+import (
+	weather_domain "prediction-risk/internal/app/weather/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockForecastService struct {
+	mock.Mock
+}
+
+func (m *MockForecastService) GetHourlyForecast(gridpoint weather_domain.Gridpoint) (*weather_domain.TemperatureForecast, error) {
+	args := m.Called(gridpoint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*weather_domain.TemperatureForecast), args.Error(1)
+}
@@ -0,0 +1,19 @@
+package weather_mocks
+
+import (
+	"prediction-risk/internal/app/weather/infrastructure/nws"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockForecastGetter struct {
+	mock.Mock
+}
+
+func (m *MockForecastGetter) GetHourlyForecast(officeID string, xCoordinate int, yCoordinate int) (*nws.Forecast, error) {
+	args := m.Called(officeID, xCoordinate, yCoordinate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*nws.Forecast), args.Error(1)
+}
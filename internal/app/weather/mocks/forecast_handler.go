@@ -0,0 +1,17 @@
+package weather_mocks
+
+import (
+	"context"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockForecastHandler struct {
+	mock.Mock
+}
+
+func (m *MockForecastHandler) HandleForecast(ctx context.Context, forecast *weather_domain.TemperatureForecast) error {
+	args := m.Called(ctx, forecast)
+	return args.Error(0)
+}
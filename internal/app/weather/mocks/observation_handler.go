@@ -0,0 +1,17 @@
+package weather_mocks
+
+import (
+	"context"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockObservationHandler struct {
+	mock.Mock
+}
+
+func (m *MockObservationHandler) HandleObservation(ctx context.Context, observation *weather_domain.TemperatureObservation) error {
+	args := m.Called(ctx, observation)
+	return args.Error(0)
+}
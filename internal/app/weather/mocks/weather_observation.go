@@ -31,3 +31,11 @@ func (m *MockWeatherObservationService) GetLatestTemperature(stationID string) (
 	}
 	return args.Get(0).(*weather_domain.TemperatureObservation), args.Error(1)
 }
+
+func (m *MockWeatherObservationService) GetMaxTemperature(stationID string, startTime time.Time, endTime time.Time) (*weather_domain.TemperatureObservation, error) {
+	args := m.Called(stationID, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*weather_domain.TemperatureObservation), args.Error(1)
+}
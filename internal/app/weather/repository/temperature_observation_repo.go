@@ -71,6 +71,16 @@ func (r *temperatureObservationRepo) Get(filter *weather_domain.TemperatureObser
 		conditions = append(conditions, fmt.Sprintf("station_id = $%d", len(args)))
 	}
 
+	if filter != nil && filter.Start != nil {
+		args = append(args, *filter.Start)
+		conditions = append(conditions, fmt.Sprintf("timestamp >= $%d", len(args)))
+	}
+
+	if filter != nil && filter.End != nil {
+		args = append(args, *filter.End)
+		conditions = append(conditions, fmt.Sprintf("timestamp < $%d", len(args)))
+	}
+
 	if len(conditions) > 0 {
 		query += " AND " + strings.Join(conditions, " AND ")
 	}
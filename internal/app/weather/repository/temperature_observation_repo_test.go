@@ -224,6 +224,35 @@ func TestTemperatureObservationRepo_Get(t *testing.T) {
 		assert.Empty(t, results)
 	})
 
+	t.Run("get with time range filter", func(t *testing.T) {
+		testDB.InsertTestData(t)
+		defer testDB.Cleanup(t)
+
+		stationID := "KNYC"
+		now := time.Now().UTC().Truncate(time.Second)
+		for i := 0; i < 3; i++ {
+			obs := weather_domain.NewTemperatureObservation(
+				stationID,
+				weather_domain.Temperature{Value: 20.0 + float64(i), TemperatureUnit: weather_domain.Celsius},
+				now.Add(time.Duration(-i)*time.Hour),
+			)
+			require.NoError(t, repo.Persist(obs))
+		}
+
+		start := now.Add(-90 * time.Minute)
+		end := now
+		filter := &weather_domain.TemperatureObservationFilter{
+			StationID: &stationID,
+			Start:     &start,
+			End:       &end,
+		}
+
+		results, err := repo.Get(filter)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, 21.0, results[0].Temperature.Value)
+	})
+
 	t.Run("returned observations are ordered by timestamp DESC", func(t *testing.T) {
 		testDB.InsertTestData(t)
 		defer testDB.Cleanup(t)
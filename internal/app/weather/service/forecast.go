@@ -0,0 +1,66 @@
+package weather_service
+
+import (
+	"fmt"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"prediction-risk/internal/app/weather/infrastructure/nws"
+)
+
+type ForecastService interface {
+	GetHourlyForecast(gridpoint weather_domain.Gridpoint) (*weather_domain.TemperatureForecast, error)
+}
+
+type ForecastGetter interface {
+	GetHourlyForecast(officeID string, xCoordinate int, yCoordinate int) (*nws.Forecast, error)
+}
+
+type forecastService struct {
+	forecastGetter ForecastGetter
+}
+
+func NewForecastService(nwsClient *nws.NWSClient) *forecastService {
+	return &forecastService{
+		forecastGetter: nwsClient.Gridpoint,
+	}
+}
+
+// GetHourlyForecast gets the latest hourly temperature forecast for a gridpoint
+func (s *forecastService) GetHourlyForecast(
+	gridpoint weather_domain.Gridpoint,
+) (*weather_domain.TemperatureForecast, error) {
+	forecast, err := s.forecastGetter.GetHourlyForecast(gridpoint.OfficeID, gridpoint.X, gridpoint.Y)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve hourly forecast from NWS: %w", err)
+	}
+
+	periods := make([]weather_domain.ForecastPeriod, 0, len(forecast.Properties.Periods))
+	for _, period := range forecast.Properties.Periods {
+		unit, err := forecastTemperatureUnit(period.TemperatureUnit)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, weather_domain.ForecastPeriod{
+			StartTime:   period.StartTime,
+			EndTime:     period.EndTime,
+			Temperature: weather_domain.Temperature{Value: period.Temperature, TemperatureUnit: unit},
+		})
+	}
+
+	return &weather_domain.TemperatureForecast{
+		Gridpoint:   gridpoint,
+		Periods:     periods,
+		GeneratedAt: forecast.Properties.GeneratedAt,
+	}, nil
+}
+
+// Forecast periods report their unit as a single letter
+func forecastTemperatureUnit(unit string) (weather_domain.TemperatureUnit, error) {
+	switch unit {
+	case "F":
+		return weather_domain.Fahrenheit, nil
+	case "C":
+		return weather_domain.Celsius, nil
+	default:
+		return "", fmt.Errorf("unsupported temperature unit from NWS forecast: %s", unit)
+	}
+}
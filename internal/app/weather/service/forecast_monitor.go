@@ -0,0 +1,97 @@
+package weather_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"sync"
+	"time"
+)
+
+// ForecastHandler reacts to temperature forecasts as they are polled
+type ForecastHandler interface {
+	HandleForecast(ctx context.Context, forecast *weather_domain.TemperatureForecast) error
+}
+
+type ForecastMonitor struct {
+	gridpoint       weather_domain.Gridpoint
+	forecastService ForecastService
+	handlers        []ForecastHandler
+	interval        time.Duration
+	done            chan struct{}
+	cancel          context.CancelFunc
+	running         sync.WaitGroup
+}
+
+func NewForecastMonitor(
+	gridpoint weather_domain.Gridpoint,
+	forecastService ForecastService,
+	interval time.Duration,
+	handlers ...ForecastHandler,
+) *ForecastMonitor {
+	return &ForecastMonitor{
+		gridpoint:       gridpoint,
+		forecastService: forecastService,
+		handlers:        handlers,
+		interval:        interval,
+		done:            make(chan struct{}),
+	}
+}
+
+func (m *ForecastMonitor) Start() {
+	log.Printf("Starting ForecastMonitor for gridpoint: %v", m.gridpoint)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	m.running.Add(1)
+	go func() {
+		defer m.running.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.done:
+				log.Println("ForecastMonitor stopped")
+				return
+			case <-ticker.C:
+				log.Println("Running forecast check...")
+				if err := m.checkForecast(ctx); err != nil {
+					log.Printf("Error checking forecast: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop cancels the check in progress and waits for it to return
+func (m *ForecastMonitor) Stop() {
+	log.Println("Stopping ForecastMonitor...")
+	if m.cancel != nil {
+		m.cancel()
+	}
+	close(m.done)
+	m.running.Wait()
+}
+
+func (m *ForecastMonitor) checkForecast(ctx context.Context) error {
+	forecast, err := m.forecastService.GetHourlyForecast(m.gridpoint)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve hourly forecast: %w", err)
+	}
+	log.Printf("Retrieved hourly forecast for %v generated at %v", forecast.Gridpoint, forecast.GeneratedAt)
+
+	var handlerErrors []error
+	for _, handler := range m.handlers {
+		if err := handler.HandleForecast(ctx, forecast); err != nil {
+			handlerErrors = append(handlerErrors, err)
+		}
+	}
+	if len(handlerErrors) > 0 {
+		return fmt.Errorf("handling forecast: %w", errors.Join(handlerErrors...))
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package weather_service
+
+import (
+	"errors"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"prediction-risk/internal/app/weather/infrastructure/nws"
+	weather_mocks "prediction-risk/internal/app/weather/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHourlyForecast(t *testing.T) {
+	gridpoint, err := weather_domain.NewGridpoint("OKX", 33, 37)
+	require.NoError(t, err)
+	generatedAt := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("converts forecast periods", func(t *testing.T) {
+		getter := &weather_mocks.MockForecastGetter{}
+		service := &forecastService{forecastGetter: getter}
+
+		getter.On("GetHourlyForecast", "OKX", 33, 37).Return(&nws.Forecast{
+			Properties: nws.ForecastProperties{
+				GeneratedAt: generatedAt,
+				Periods: []nws.ForecastPeriod{
+					{StartTime: generatedAt, EndTime: generatedAt.Add(time.Hour), Temperature: 79, TemperatureUnit: "F"},
+					{StartTime: generatedAt.Add(time.Hour), EndTime: generatedAt.Add(2 * time.Hour), Temperature: 27, TemperatureUnit: "C"},
+				},
+			},
+		}, nil)
+
+		forecast, err := service.GetHourlyForecast(gridpoint)
+
+		require.NoError(t, err)
+		assert.Equal(t, gridpoint, forecast.Gridpoint)
+		assert.Equal(t, generatedAt, forecast.GeneratedAt)
+		require.Len(t, forecast.Periods, 2)
+		assert.Equal(t, weather_domain.Temperature{Value: 79, TemperatureUnit: weather_domain.Fahrenheit}, forecast.Periods[0].Temperature)
+		assert.Equal(t, weather_domain.Temperature{Value: 27, TemperatureUnit: weather_domain.Celsius}, forecast.Periods[1].Temperature)
+
+		high, ok := forecast.MaxTemperature(generatedAt, generatedAt.Add(24*time.Hour))
+		require.True(t, ok)
+		assert.Equal(t, 27.0, high.Value)
+	})
+
+	t.Run("rejects unknown units", func(t *testing.T) {
+		getter := &weather_mocks.MockForecastGetter{}
+		service := &forecastService{forecastGetter: getter}
+
+		getter.On("GetHourlyForecast", "OKX", 33, 37).Return(&nws.Forecast{
+			Properties: nws.ForecastProperties{
+				Periods: []nws.ForecastPeriod{{StartTime: generatedAt, Temperature: 300, TemperatureUnit: "K"}},
+			},
+		}, nil)
+
+		forecast, err := service.GetHourlyForecast(gridpoint)
+
+		assert.ErrorContains(t, err, "unsupported temperature unit")
+		assert.Nil(t, forecast)
+	})
+
+	t.Run("wraps client errors", func(t *testing.T) {
+		getter := &weather_mocks.MockForecastGetter{}
+		service := &forecastService{forecastGetter: getter}
+
+		getter.On("GetHourlyForecast", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("503"))
+
+		forecast, err := service.GetHourlyForecast(gridpoint)
+
+		assert.ErrorContains(t, err, "failed to retrieve hourly forecast")
+		assert.Nil(t, forecast)
+	})
+}
@@ -0,0 +1,278 @@
+package weather_service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// weatherSeries is the station a Kalshi weather series settles on. NWS climate
+// days run midnight to midnight local standard time, all year.
+type weatherSeries struct {
+	StationID string
+	Location  *time.Location
+}
+
+var (
+	eastern  = time.FixedZone("EST", -5*60*60)
+	central  = time.FixedZone("CST", -6*60*60)
+	mountain = time.FixedZone("MST", -7*60*60)
+	pacific  = time.FixedZone("PST", -8*60*60)
+)
+
+// Series are listed under both their current KX tickers and their legacy ones
+var weatherSeriesByTicker = map[string]weatherSeries{
+	"KXHIGHNY":   {StationID: "KNYC", Location: eastern},
+	"HIGHNY":     {StationID: "KNYC", Location: eastern},
+	"KXHIGHCHI":  {StationID: "KMDW", Location: central},
+	"HIGHCHI":    {StationID: "KMDW", Location: central},
+	"KXHIGHMIA":  {StationID: "KMIA", Location: eastern},
+	"HIGHMIA":    {StationID: "KMIA", Location: eastern},
+	"KXHIGHAUS":  {StationID: "KAUS", Location: central},
+	"HIGHAUS":    {StationID: "KAUS", Location: central},
+	"KXHIGHDEN":  {StationID: "KDEN", Location: mountain},
+	"HIGHDEN":    {StationID: "KDEN", Location: mountain},
+	"KXHIGHPHIL": {StationID: "KPHL", Location: eastern},
+	"HIGHPHIL":   {StationID: "KPHL", Location: eastern},
+	"KXHIGHLAX":  {StationID: "KLAX", Location: pacific},
+	"HIGHLAX":    {StationID: "KLAX", Location: pacific},
+}
+
+var (
+	// Subtitles read "78° to 79°", "80° or above" or "72° or below"
+	subtitleBetween = regexp.MustCompile(`(-?\d+)°?F?\s+to\s+(-?\d+)°?`)
+	subtitleAbove   = regexp.MustCompile(`(-?\d+)°?F?\s+or\s+(above|higher|more)`)
+	subtitleBelow   = regexp.MustCompile(`(-?\d+)°?F?\s+or\s+(below|lower|less)`)
+
+	// Rules read "is between 78-79°", "is greater than 79°" or "is less than 73°"
+	rulesBetween = regexp.MustCompile(`between\s+(-?\d+)\s*-\s*(-?\d+)°`)
+	rulesAbove   = regexp.MustCompile(`(greater than|above)\s+(-?\d+)°`)
+	rulesBelow   = regexp.MustCompile(`(less than|below)\s+(-?\d+)°`)
+
+	// Tickers end in B followed by a bracket's midpoint or T followed by a threshold
+	tickerStrike = regexp.MustCompile(`^([BT])(-?\d+(?:\.\d+)?)$`)
+)
+
+// ParseWeatherMarket reads the station, date and temperature range a Kalshi
+// daily temperature market settles on
+func ParseWeatherMarket(market *exchange_domain.Market) (*weather_domain.WeatherMarket, error) {
+	segments := strings.Split(string(market.Ticker), "-")
+	if len(segments) != 3 {
+		return nil, fmt.Errorf("market %s is not a daily weather market", market.Ticker)
+	}
+
+	series, ok := weatherSeriesByTicker[segments[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown weather series: %s", segments[0])
+	}
+
+	date, err := parseTickerDate(segments[1], series.Location)
+	if err != nil {
+		return nil, fmt.Errorf("market %s: %w", market.Ticker, err)
+	}
+
+	temperatureRange, err := parseTemperatureRange(market.Info, segments[2])
+	if err != nil {
+		return nil, fmt.Errorf("market %s: %w", market.Ticker, err)
+	}
+
+	eventTicker := market.Info.EventTicker
+	if eventTicker == "" {
+		eventTicker = contract.Ticker(segments[0] + "-" + segments[1])
+	}
+
+	return &weather_domain.WeatherMarket{
+		Ticker:      market.Ticker,
+		EventTicker: eventTicker,
+		StationID:   series.StationID,
+		Metric:      parseDailyMetric(segments[0], market.Info.RulesPrimary),
+		Date:        date,
+		Range:       temperatureRange,
+	}, nil
+}
+
+// Ticker dates read like 25JUL01
+func parseTickerDate(s string, location *time.Location) (time.Time, error) {
+	if len(s) != 7 {
+		return time.Time{}, fmt.Errorf("invalid ticker date: %s", s)
+	}
+	normalized := s[:3] + strings.ToLower(s[3:5]) + s[5:]
+	date, err := time.ParseInLocation("06Jan02", normalized, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid ticker date: %s", s)
+	}
+	return date, nil
+}
+
+func parseDailyMetric(seriesTicker string, rules string) weather_domain.DailyMetric {
+	switch {
+	case strings.Contains(rules, "lowest temperature"):
+		return weather_domain.DailyLow
+	case strings.Contains(rules, "highest temperature"):
+		return weather_domain.DailyHigh
+	case strings.Contains(seriesTicker, "LOW"):
+		return weather_domain.DailyLow
+	default:
+		return weather_domain.DailyHigh
+	}
+}
+
+// parseTemperatureRange prefers the subtitle, then the rules, then the strike
+func parseTemperatureRange(info exchange_domain.MarketInfo, strikeSegment string) (weather_domain.TemperatureRange, error) {
+	fahrenheit := func(min, max *float64) weather_domain.TemperatureRange {
+		return weather_domain.TemperatureRange{Min: min, Max: max, TemperatureUnit: weather_domain.Fahrenheit}
+	}
+
+	if match := subtitleBetween.FindStringSubmatch(info.YesSubTitle); match != nil {
+		return fahrenheit(lo.ToPtr(parseDegrees(match[1])), lo.ToPtr(parseDegrees(match[2]))), nil
+	}
+	if match := subtitleAbove.FindStringSubmatch(info.YesSubTitle); match != nil {
+		return fahrenheit(lo.ToPtr(parseDegrees(match[1])), nil), nil
+	}
+	if match := subtitleBelow.FindStringSubmatch(info.YesSubTitle); match != nil {
+		return fahrenheit(nil, lo.ToPtr(parseDegrees(match[1]))), nil
+	}
+
+	if match := rulesBetween.FindStringSubmatch(info.RulesPrimary); match != nil {
+		return fahrenheit(lo.ToPtr(parseDegrees(match[1])), lo.ToPtr(parseDegrees(match[2]))), nil
+	}
+	if match := rulesAbove.FindStringSubmatch(info.RulesPrimary); match != nil {
+		return fahrenheit(lo.ToPtr(parseDegrees(match[2])+1), nil), nil
+	}
+	if match := rulesBelow.FindStringSubmatch(info.RulesPrimary); match != nil {
+		return fahrenheit(nil, lo.ToPtr(parseDegrees(match[2])-1)), nil
+	}
+
+	match := tickerStrike.FindStringSubmatch(strikeSegment)
+	if match == nil {
+		return weather_domain.TemperatureRange{}, errors.New("cannot determine temperature range")
+	}
+	strike, err := strconv.ParseFloat(match[2], 64)
+	if err != nil {
+		return weather_domain.TemperatureRange{}, fmt.Errorf("invalid strike: %s", match[2])
+	}
+	if info.StrikePrice != nil {
+		if strike, err = strconv.ParseFloat(*info.StrikePrice, 64); err != nil {
+			return weather_domain.TemperatureRange{}, fmt.Errorf("invalid strike price: %s", *info.StrikePrice)
+		}
+	}
+
+	// Brackets span the two whole degrees either side of their midpoint
+	if match[1] == "B" {
+		return fahrenheit(lo.ToPtr(math.Floor(strike)), lo.ToPtr(math.Ceil(strike))), nil
+	}
+	// Thresholds are at or above the strike, unless the rules say below
+	if strings.Contains(info.RulesPrimary, "below") || strings.Contains(info.RulesPrimary, "less than") {
+		return fahrenheit(nil, lo.ToPtr(math.Ceil(strike)-1)), nil
+	}
+	return fahrenheit(lo.ToPtr(math.Ceil(strike)), nil), nil
+}
+
+func parseDegrees(s string) float64 {
+	// The regular expressions only match integers
+	value, _ := strconv.ParseFloat(s, 64)
+	return value
+}
+
+// WeatherMarketResolver maps temperatures to the weather market that would settle YES on them
+type WeatherMarketResolver struct {
+	markets []*weather_domain.WeatherMarket
+}
+
+// NewWeatherMarketResolver parses the markets, typically every market in one or more weather events
+func NewWeatherMarketResolver(markets []*exchange_domain.Market) (*WeatherMarketResolver, error) {
+	weatherMarkets := make([]*weather_domain.WeatherMarket, 0, len(markets))
+	for _, market := range markets {
+		weatherMarket, err := ParseWeatherMarket(market)
+		if err != nil {
+			return nil, err
+		}
+		weatherMarkets = append(weatherMarkets, weatherMarket)
+	}
+	return &WeatherMarketResolver{markets: weatherMarkets}, nil
+}
+
+// Markets returns the parsed markets
+func (r *WeatherMarketResolver) Markets() []*weather_domain.WeatherMarket {
+	return r.markets
+}
+
+// Resolve finds the market on the station's metric for the day containing date whose range
+// contains the temperature
+func (r *WeatherMarketResolver) Resolve(
+	stationID string,
+	metric weather_domain.DailyMetric,
+	date time.Time,
+	temperature weather_domain.Temperature,
+) (*weather_domain.WeatherMarket, error) {
+	for _, market := range r.marketsOn(stationID, metric, date) {
+		if market.Range.Contains(temperature) {
+			return market, nil
+		}
+	}
+	return nil, core.NewErrNotFound("weather market",
+		fmt.Sprintf("%s %s %s %.1f %s", stationID, metric, date.Format(time.DateOnly), temperature.Value, temperature.TemperatureUnit))
+}
+
+// ResolveObservation finds the market that would settle YES if the observation
+// were the final value of the metric for its day
+func (r *WeatherMarketResolver) ResolveObservation(
+	observation *weather_domain.TemperatureObservation,
+	metric weather_domain.DailyMetric,
+) (*weather_domain.WeatherMarket, error) {
+	return r.Resolve(observation.StationID, metric, observation.Timestamp, observation.Temperature)
+}
+
+// ResolveForecast finds the market that would settle YES on the forecast's high or low
+// for the station's day containing date
+func (r *WeatherMarketResolver) ResolveForecast(
+	stationID string,
+	forecast *weather_domain.TemperatureForecast,
+	metric weather_domain.DailyMetric,
+	date time.Time,
+) (*weather_domain.WeatherMarket, error) {
+	markets := r.marketsOn(stationID, metric, date)
+	if len(markets) == 0 {
+		return nil, core.NewErrNotFound("weather market",
+			fmt.Sprintf("%s %s %s", stationID, metric, date.Format(time.DateOnly)))
+	}
+
+	// Every market in the event shares the climate day
+	start := markets[0].Date
+	end := start.AddDate(0, 0, 1)
+	var forecasted weather_domain.Temperature
+	var ok bool
+	switch metric {
+	case weather_domain.DailyHigh:
+		forecasted, ok = forecast.MaxTemperature(start, end)
+	case weather_domain.DailyLow:
+		forecasted, ok = forecast.MinTemperature(start, end)
+	default:
+		return nil, fmt.Errorf("invalid daily metric: %s", metric)
+	}
+	if !ok {
+		return nil, fmt.Errorf("forecast for %s has no periods on %s", forecast.Gridpoint, start.Format(time.DateOnly))
+	}
+
+	return r.Resolve(stationID, metric, start, forecasted)
+}
+
+func (r *WeatherMarketResolver) marketsOn(
+	stationID string,
+	metric weather_domain.DailyMetric,
+	date time.Time,
+) []*weather_domain.WeatherMarket {
+	return lo.Filter(r.markets, func(m *weather_domain.WeatherMarket, _ int) bool {
+		return m.StationID == stationID && m.Metric == metric && m.IsOn(date)
+	})
+}
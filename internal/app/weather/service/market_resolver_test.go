@@ -0,0 +1,240 @@
+package weather_service
+
+import (
+	"errors"
+	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWeatherMarket(ticker string, subtitle string, rules string) *exchange_domain.Market {
+	return &exchange_domain.Market{
+		Ticker: contract.Ticker(ticker),
+		Info: exchange_domain.MarketInfo{
+			EventTicker:  "KXHIGHNY-25JUL01",
+			YesSubTitle:  subtitle,
+			RulesPrimary: rules,
+		},
+	}
+}
+
+// The KXHIGHNY event for July 1, 2025
+func newHighNYEvent() []*exchange_domain.Market {
+	rules := func(outcome string) string {
+		return "If the highest temperature recorded in Central Park, New York for Jul 1, 2025 as reported by the " +
+			"National Weather Service's Climatological Report (Daily), is " + outcome + ", then the market resolves to Yes."
+	}
+	return []*exchange_domain.Market{
+		newWeatherMarket("KXHIGHNY-25JUL01-T73", "72° or below", rules("less than 73°")),
+		newWeatherMarket("KXHIGHNY-25JUL01-B73.5", "73° to 74°", rules("between 73-74°")),
+		newWeatherMarket("KXHIGHNY-25JUL01-B75.5", "75° to 76°", rules("between 75-76°")),
+		newWeatherMarket("KXHIGHNY-25JUL01-B77.5", "77° to 78°", rules("between 77-78°")),
+		newWeatherMarket("KXHIGHNY-25JUL01-B79.5", "79° to 80°", rules("between 79-80°")),
+		newWeatherMarket("KXHIGHNY-25JUL01-T80", "81° or above", rules("greater than 80°")),
+	}
+}
+
+func TestParseWeatherMarket(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	july1 := time.Date(2025, 7, 1, 0, 0, 0, 0, est)
+
+	tests := []struct {
+		name         string
+		market       *exchange_domain.Market
+		wantMin      *float64
+		wantMax      *float64
+		wantMetric   weather_domain.DailyMetric
+		errorMessage string
+	}{
+		{
+			name:       "bracket from subtitle",
+			market:     newWeatherMarket("KXHIGHNY-25JUL01-B78.5", "78° to 79°", ""),
+			wantMin:    lo.ToPtr(78.0),
+			wantMax:    lo.ToPtr(79.0),
+			wantMetric: weather_domain.DailyHigh,
+		},
+		{
+			name:       "upper threshold from subtitle",
+			market:     newWeatherMarket("KXHIGHNY-25JUL01-T80", "80° or above", ""),
+			wantMin:    lo.ToPtr(80.0),
+			wantMetric: weather_domain.DailyHigh,
+		},
+		{
+			name:       "lower threshold from rules",
+			market:     newWeatherMarket("KXHIGHNY-25JUL01-T73", "", "If the highest temperature ... is less than 73°, then the market resolves to Yes."),
+			wantMax:    lo.ToPtr(72.0),
+			wantMetric: weather_domain.DailyHigh,
+		},
+		{
+			name:       "bracket from ticker",
+			market:     newWeatherMarket("KXHIGHNY-25JUL01-B78.5", "", ""),
+			wantMin:    lo.ToPtr(78.0),
+			wantMax:    lo.ToPtr(79.0),
+			wantMetric: weather_domain.DailyHigh,
+		},
+		{
+			name:       "low temperature from rules",
+			market:     newWeatherMarket("KXHIGHNY-25JUL01-B64.5", "64° to 65°", "If the lowest temperature recorded ..."),
+			wantMin:    lo.ToPtr(64.0),
+			wantMax:    lo.ToPtr(65.0),
+			wantMetric: weather_domain.DailyLow,
+		},
+		{
+			name:         "unknown series",
+			market:       newWeatherMarket("KXHIGHXYZ-25JUL01-B78.5", "78° to 79°", ""),
+			errorMessage: "unknown weather series",
+		},
+		{
+			name:         "invalid date",
+			market:       newWeatherMarket("KXHIGHNY-25XYZ01-B78.5", "78° to 79°", ""),
+			errorMessage: "invalid ticker date",
+		},
+		{
+			name:         "not a weather market",
+			market:       newWeatherMarket("KXHIGHNY-25JUL01", "", ""),
+			errorMessage: "not a daily weather market",
+		},
+		{
+			name:         "no range",
+			market:       newWeatherMarket("KXHIGHNY-25JUL01-X1", "", ""),
+			errorMessage: "cannot determine temperature range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			market, err := ParseWeatherMarket(tt.market)
+			if tt.errorMessage != "" {
+				assert.ErrorContains(t, err, tt.errorMessage)
+				assert.Nil(t, market)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.market.Ticker, market.Ticker)
+			assert.Equal(t, contract.Ticker("KXHIGHNY-25JUL01"), market.EventTicker)
+			assert.Equal(t, "KNYC", market.StationID)
+			assert.Equal(t, tt.wantMetric, market.Metric)
+			assert.True(t, july1.Equal(market.Date))
+			assert.Equal(t, tt.wantMin, market.Range.Min)
+			assert.Equal(t, tt.wantMax, market.Range.Max)
+			assert.Equal(t, weather_domain.Fahrenheit, market.Range.TemperatureUnit)
+		})
+	}
+}
+
+func TestWeatherMarketResolver_ResolveObservation(t *testing.T) {
+	resolver, err := NewWeatherMarketResolver(newHighNYEvent())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		stationID   string
+		temperature weather_domain.Temperature
+		observedAt  time.Time
+		wantTicker  contract.Ticker
+		wantErr     bool
+	}{
+		{
+			name:        "fahrenheit in bracket",
+			stationID:   "KNYC",
+			temperature: weather_domain.Temperature{Value: 78, TemperatureUnit: weather_domain.Fahrenheit},
+			observedAt:  time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC),
+			wantTicker:  "KXHIGHNY-25JUL01-B77.5",
+		},
+		{
+			// 26.1°C is 78.98°F, which the climate report rounds to 79°F
+			name:        "celsius rounds into bracket",
+			stationID:   "KNYC",
+			temperature: weather_domain.Temperature{Value: 26.1, TemperatureUnit: weather_domain.Celsius},
+			observedAt:  time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC),
+			wantTicker:  "KXHIGHNY-25JUL01-B79.5",
+		},
+		{
+			name:        "open-ended upper threshold",
+			stationID:   "KNYC",
+			temperature: weather_domain.Temperature{Value: 95, TemperatureUnit: weather_domain.Fahrenheit},
+			observedAt:  time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC),
+			wantTicker:  "KXHIGHNY-25JUL01-T80",
+		},
+		{
+			name:        "open-ended lower threshold",
+			stationID:   "KNYC",
+			temperature: weather_domain.Temperature{Value: 60, TemperatureUnit: weather_domain.Fahrenheit},
+			observedAt:  time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC),
+			wantTicker:  "KXHIGHNY-25JUL01-T73",
+		},
+		{
+			// 04:00 UTC on July 2 is still July 1 in local standard time
+			name:        "climate day uses local standard time",
+			stationID:   "KNYC",
+			temperature: weather_domain.Temperature{Value: 74, TemperatureUnit: weather_domain.Fahrenheit},
+			observedAt:  time.Date(2025, 7, 2, 4, 0, 0, 0, time.UTC),
+			wantTicker:  "KXHIGHNY-25JUL01-B73.5",
+		},
+		{
+			name:        "other day",
+			stationID:   "KNYC",
+			temperature: weather_domain.Temperature{Value: 78, TemperatureUnit: weather_domain.Fahrenheit},
+			observedAt:  time.Date(2025, 7, 2, 18, 0, 0, 0, time.UTC),
+			wantErr:     true,
+		},
+		{
+			name:        "other station",
+			stationID:   "KLGA",
+			temperature: weather_domain.Temperature{Value: 78, TemperatureUnit: weather_domain.Fahrenheit},
+			observedAt:  time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC),
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observation := weather_domain.NewTemperatureObservation(tt.stationID, tt.temperature, tt.observedAt)
+
+			market, err := resolver.ResolveObservation(observation, weather_domain.DailyHigh)
+			if tt.wantErr {
+				var notFoundErr *core.ErrNotFound
+				assert.True(t, errors.As(err, &notFoundErr))
+				assert.Nil(t, market)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTicker, market.Ticker)
+		})
+	}
+}
+
+func TestWeatherMarketResolver_ResolveForecast(t *testing.T) {
+	resolver, err := NewWeatherMarketResolver(newHighNYEvent())
+	require.NoError(t, err)
+	est := time.FixedZone("EST", -5*60*60)
+
+	start := time.Date(2025, 7, 1, 9, 0, 0, 0, est)
+	periods := make([]weather_domain.ForecastPeriod, 0)
+	for i, temperature := range []float64{72, 75, 77, 76, 74} {
+		periodStart := start.Add(time.Duration(i) * time.Hour)
+		periods = append(periods, weather_domain.ForecastPeriod{
+			StartTime:   periodStart,
+			EndTime:     periodStart.Add(time.Hour),
+			Temperature: weather_domain.Temperature{Value: temperature, TemperatureUnit: weather_domain.Fahrenheit},
+		})
+	}
+	forecast := &weather_domain.TemperatureForecast{
+		Gridpoint: weather_domain.Gridpoint{OfficeID: "OKX", X: 33, Y: 37},
+		Periods:   periods,
+	}
+
+	market, err := resolver.ResolveForecast("KNYC", forecast, weather_domain.DailyHigh, start)
+	require.NoError(t, err)
+	assert.Equal(t, contract.Ticker("KXHIGHNY-25JUL01-B77.5"), market.Ticker)
+
+	_, err = resolver.ResolveForecast("KNYC", forecast, weather_domain.DailyLow, start)
+	assert.Error(t, err)
+}
@@ -1,27 +1,40 @@
 package weather_service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	weather_domain "prediction-risk/internal/app/weather/domain"
+	"sync"
 	"time"
 )
 
+// ObservationHandler reacts to temperature observations as they arrive
+type ObservationHandler interface {
+	HandleObservation(ctx context.Context, observation *weather_domain.TemperatureObservation) error
+}
+
 type WeatherMonitor struct {
 	stationID                 string
 	weatherObservationService WeatherObservationService
+	handlers                  []ObservationHandler
 	interval                  time.Duration
 	done                      chan struct{}
+	cancel                    context.CancelFunc
+	running                   sync.WaitGroup
 }
 
 func NewWeatherMonitor(
 	stationID string,
 	weatherObservationService WeatherObservationService,
 	interval time.Duration,
+	handlers ...ObservationHandler,
 ) *WeatherMonitor {
 	return &WeatherMonitor{
 		stationID:                 stationID,
 		weatherObservationService: weatherObservationService,
+		handlers:                  handlers,
 		interval:                  interval,
 		done:                      make(chan struct{}),
 	}
@@ -29,6 +42,8 @@ func NewWeatherMonitor(
 
 func (m *WeatherMonitor) Start() {
 	log.Printf("Starting WeatherMonitor for station: %v", m.stationID)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
 
 	// First get any missed observations from the last 24 hours
 	startTime := time.Now().UTC().Add(-24 * time.Hour)
@@ -51,13 +66,15 @@ func (m *WeatherMonitor) Start() {
 
 		// Process historical observations
 		for _, observation := range observations {
-			if _, err := m.processWeatherObservation(observation); err != nil {
+			if _, err := m.processWeatherObservation(ctx, observation); err != nil {
 				log.Printf("Error processing historical observation: %v", err)
 			}
 		}
 	}
 
+	m.running.Add(1)
 	go func() {
+		defer m.running.Done()
 		ticker := time.NewTicker(m.interval)
 		defer ticker.Stop()
 
@@ -68,7 +85,7 @@ func (m *WeatherMonitor) Start() {
 				return
 			case <-ticker.C:
 				log.Println("Running weather observation check...")
-				if err := m.checkWeatherObservation(); err != nil {
+				if err := m.checkWeatherObservation(ctx); err != nil {
 					log.Printf("Error checking weather observation: %v", err)
 				}
 			}
@@ -76,19 +93,24 @@ func (m *WeatherMonitor) Start() {
 	}()
 }
 
+// Stop cancels the check in progress and waits for it to return
 func (m *WeatherMonitor) Stop() {
 	log.Println("Stopping WeatherMonitor...")
+	if m.cancel != nil {
+		m.cancel()
+	}
 	close(m.done)
+	m.running.Wait()
 }
 
-func (m *WeatherMonitor) checkWeatherObservation() error {
+func (m *WeatherMonitor) checkWeatherObservation(ctx context.Context) error {
 	observation, err := m.weatherObservationService.RetrieveLatestObservation(m.stationID)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve latest weather observation: %w", err)
 	}
 	log.Printf("Retrieved latest weather observation: %v", observation)
 
-	_, err = m.processWeatherObservation(observation)
+	_, err = m.processWeatherObservation(ctx, observation)
 	if err != nil {
 		return fmt.Errorf("failed to process weather observation: %w", err)
 	}
@@ -97,8 +119,20 @@ func (m *WeatherMonitor) checkWeatherObservation() error {
 }
 
 func (m *WeatherMonitor) processWeatherObservation(
+	ctx context.Context,
 	observation *weather_domain.TemperatureObservation,
 ) (*weather_domain.TemperatureObservation, error) {
 	log.Printf("Processing weather observation: %v", observation)
+
+	var handlerErrors []error
+	for _, handler := range m.handlers {
+		if err := handler.HandleObservation(ctx, observation); err != nil {
+			handlerErrors = append(handlerErrors, err)
+		}
+	}
+	if len(handlerErrors) > 0 {
+		return nil, fmt.Errorf("handling observation: %w", errors.Join(handlerErrors...))
+	}
+
 	return observation, nil
 }
@@ -1,6 +1,7 @@
 package weather_service
 
 import (
+	"context"
 	"fmt"
 	weather_domain "prediction-risk/internal/app/weather/domain"
 	weather_mocks "prediction-risk/internal/app/weather/mocks"
@@ -171,7 +172,7 @@ func TestWeatherMonitor_CheckWeatherObservation(t *testing.T) {
 			interval,
 		)
 
-		err := monitor.checkWeatherObservation()
+		err := monitor.checkWeatherObservation(context.Background())
 		require.NoError(t, err)
 		mockService.AssertExpectations(t)
 	})
@@ -191,7 +192,7 @@ func TestWeatherMonitor_CheckWeatherObservation(t *testing.T) {
 			interval,
 		)
 
-		err := monitor.checkWeatherObservation()
+		err := monitor.checkWeatherObservation(context.Background())
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to retrieve latest weather observation")
 		mockService.AssertExpectations(t)
@@ -208,9 +209,52 @@ func TestWeatherMonitor_ProcessWeatherObservation(t *testing.T) {
 		)
 
 		observation := createTestObservation("KNYC")
-		processed, err := monitor.processWeatherObservation(observation)
+		processed, err := monitor.processWeatherObservation(context.Background(), observation)
 
 		require.NoError(t, err)
 		assert.Equal(t, observation, processed)
 	})
+	t.Run("passes observation to handlers", func(t *testing.T) {
+		mockService := &weather_mocks.MockWeatherObservationService{}
+		handler := &weather_mocks.MockObservationHandler{}
+		monitor := NewWeatherMonitor(
+			"KNYC",
+			mockService,
+			time.Second,
+			handler,
+		)
+
+		observation := createTestObservation("KNYC")
+		handler.On("HandleObservation", mock.Anything, observation).Return(nil).Once()
+
+		processed, err := monitor.processWeatherObservation(context.Background(), observation)
+
+		require.NoError(t, err)
+		assert.Equal(t, observation, processed)
+		handler.AssertExpectations(t)
+	})
+
+	t.Run("returns handler errors after running every handler", func(t *testing.T) {
+		mockService := &weather_mocks.MockWeatherObservationService{}
+		failing := &weather_mocks.MockObservationHandler{}
+		succeeding := &weather_mocks.MockObservationHandler{}
+		monitor := NewWeatherMonitor(
+			"KNYC",
+			mockService,
+			time.Second,
+			failing,
+			succeeding,
+		)
+
+		observation := createTestObservation("KNYC")
+		failing.On("HandleObservation", mock.Anything, observation).Return(assert.AnError).Once()
+		succeeding.On("HandleObservation", mock.Anything, observation).Return(nil).Once()
+
+		processed, err := monitor.processWeatherObservation(context.Background(), observation)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, processed)
+		failing.AssertExpectations(t)
+		succeeding.AssertExpectations(t)
+	})
 }
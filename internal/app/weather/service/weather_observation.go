@@ -14,6 +14,7 @@ type WeatherObservationService interface {
 	RetrieveLatestObservation(stationID string) (*weather_domain.TemperatureObservation, error)
 	RetrieveObservationsInRange(stationID string, startTime time.Time, endTime time.Time) ([]*weather_domain.TemperatureObservation, *weather_domain.RetrievalStats, error)
 	GetLatestTemperature(stationID string) (*weather_domain.TemperatureObservation, error)
+	GetMaxTemperature(stationID string, startTime time.Time, endTime time.Time) (*weather_domain.TemperatureObservation, error)
 }
 
 type ObservationGetter interface {
@@ -150,3 +151,33 @@ func (s *weatherObservationService) GetLatestTemperature(
 
 	return latest, nil
 }
+
+// GetMaxTemperature retrieves the stored observation with the highest temperature
+// for a station within [startTime, endTime)
+func (s *weatherObservationService) GetMaxTemperature(
+	stationID string,
+	startTime time.Time,
+	endTime time.Time,
+) (*weather_domain.TemperatureObservation, error) {
+	filter := &weather_domain.TemperatureObservationFilter{
+		StationID: &stationID,
+		Start:     &startTime,
+		End:       &endTime,
+	}
+	observations, err := s.temperatureObservationRepo.Get(filter)
+	if err != nil {
+		return nil, fmt.Errorf("fetching temperature observations: %w", err)
+	}
+
+	if len(observations) == 0 {
+		return nil, fmt.Errorf("no temperature observations found for station %s between %v and %v",
+			stationID, startTime, endTime)
+	}
+
+	// Compare in a single unit in case stored observations are mixed
+	max := lo.MaxBy(observations, func(a *weather_domain.TemperatureObservation, b *weather_domain.TemperatureObservation) bool {
+		return a.Temperature.In(weather_domain.Celsius).Value > b.Temperature.In(weather_domain.Celsius).Value
+	})
+
+	return max, nil
+}
@@ -241,3 +241,46 @@ func TestGetLatestTemperature(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestGetMaxTemperature(t *testing.T) {
+	t.Run("returns warmest observation in range", func(t *testing.T) {
+		service, _, mockRepo := newTestService()
+
+		stationID := "KNYC"
+		start := time.Date(2025, 1, 20, 5, 0, 0, 0, time.UTC)
+		end := start.Add(24 * time.Hour)
+		cool := weather_domain.NewTemperatureObservation(
+			stationID,
+			weather_domain.Temperature{Value: 20.0, TemperatureUnit: weather_domain.Celsius},
+			start.Add(time.Hour),
+		)
+		warm := weather_domain.NewTemperatureObservation(
+			stationID,
+			weather_domain.Temperature{Value: 27.0, TemperatureUnit: weather_domain.Celsius},
+			start.Add(2*time.Hour),
+		)
+
+		filter := &weather_domain.TemperatureObservationFilter{
+			StationID: &stationID,
+			Start:     &start,
+			End:       &end,
+		}
+		mockRepo.On("Get", filter).Return([]*weather_domain.TemperatureObservation{cool, warm}, nil)
+
+		observation, err := service.GetMaxTemperature(stationID, start, end)
+
+		assert.NoError(t, err)
+		assert.Equal(t, warm, observation)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("no observations in range", func(t *testing.T) {
+		service, _, mockRepo := newTestService()
+
+		mockRepo.On("Get", mock.Anything).Return([]*weather_domain.TemperatureObservation{}, nil)
+
+		observation, err := service.GetMaxTemperature("KNYC", time.Now().Add(-time.Hour), time.Now())
+		assert.Error(t, err)
+		assert.Nil(t, observation)
+	})
+}
@@ -25,6 +25,14 @@ type Config struct {
 		BaseURL   string
 		UserAgent string
 	}
+	// Default pre-trade guards for triggers without their own, zero disables a limit
+	Guards struct {
+		MaxSpread    int
+		MinLiquidity int
+		MinVolume24H int
+		MinDepth     int
+		OnFailure    string
+	}
 }
 
 func LoadConfig() (*Config, error) {
@@ -47,6 +55,12 @@ func LoadConfig() (*Config, error) {
 	viper.BindEnv("Databases.Host", "DB_HOST")
 	viper.BindEnv("NWS.BaseURL", "NWS_BASE_URL")
 	viper.BindEnv("NWS.UserAgent", "NWS_USER_AGENT")
+	viper.BindEnv("Guards.MaxSpread", "GUARD_MAX_SPREAD")
+	viper.BindEnv("Guards.MinLiquidity", "GUARD_MIN_LIQUIDITY")
+	viper.BindEnv("Guards.MinVolume24H", "GUARD_MIN_VOLUME_24H")
+	viper.BindEnv("Guards.MinDepth", "GUARD_MIN_DEPTH")
+	viper.SetDefault("Guards.OnFailure", "DEFER")
+	viper.BindEnv("Guards.OnFailure", "GUARD_ON_FAILURE")
 
 	var cfg Config
 	if err := viper.Unmarshal(&cfg); err != nil {
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"prediction-risk/internal/app/contract"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type BracketRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewBracketRoutes(service *trigger_service.TriggerService) *BracketRoutes {
+	return &BracketRoutes{service: service}
+}
+
+func (routes *BracketRoutes) Register(router chi.Router) {
+	router.Route("/api/brackets", func(r chi.Router) {
+		r.Post("/", routes.CreateBracket)
+		r.Get("/{id}", routes.GetBracket)
+		r.Delete("/{id}", routes.CancelBracket)
+	})
+}
+
+// CreateBracketRequest buys into a position with the entry, as for an entry trigger, and
+// protects whatever it fills with a stop and a take profit. The exits stay pending until the
+// entry's orders are done.
+type CreateBracketRequest struct {
+	Contract struct {
+		Ticker string `json:"ticker"`
+		Side   string `json:"side"`
+	} `json:"contract"`
+	Entry struct {
+		Direction    string `json:"direction"`
+		TriggerPrice int    `json:"trigger_price"`
+		Size         uint   `json:"size"`
+		LimitPrice   *int   `json:"limit_price"`
+		MaxCost      *int   `json:"max_cost"`
+	} `json:"entry"`
+	StopPrice            int     `json:"stop_price"`
+	StopLimitPrice       *int    `json:"stop_limit_price"`
+	TakeProfitPrice      int     `json:"take_profit_price"`
+	TakeProfitLimitPrice *int    `json:"take_profit_limit_price"`
+	PriceSource          *string `json:"price_source"`
+}
+
+type BracketResponse struct {
+	GroupID    string                    `json:"group_id"`
+	GroupType  string                    `json:"group_type"`
+	Contract   ContractIDResponse        `json:"contract"`
+	Entry      EntryTriggerResponse      `json:"entry"`
+	Stop       StopTriggerResponse       `json:"stop"`
+	TakeProfit TakeProfitTriggerResponse `json:"take_profit"`
+	CreatedAt  time.Time                 `json:"created_at"`
+	UpdatedAt  time.Time                 `json:"updated_at"`
+}
+
+func ToBracketResponse(bracket *trigger_domain.Bracket) BracketResponse {
+	return BracketResponse{
+		GroupID:   bracket.Group.GroupID.String(),
+		GroupType: bracket.Group.GroupType.String(),
+		Contract: ContractIDResponse{
+			Ticker: string(bracket.Stop.Condition.Contract.Ticker),
+			Side:   bracket.Stop.Condition.Contract.Side.String(),
+		},
+		Entry:      ToEntryTriggerResponse(bracket.Entry),
+		Stop:       ToStopTriggerResponse(bracket.Stop),
+		TakeProfit: ToTakeProfitTriggerResponse(bracket.TakeProfit),
+		CreatedAt:  bracket.Group.CreatedAt,
+		UpdatedAt:  bracket.Group.UpdatedAt,
+	}
+}
+
+func parseOptionalPrice(price *int) (*contract.ContractPrice, error) {
+	if price == nil {
+		return nil, nil
+	}
+	cp, err := contract.NewContractPrice(*price)
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func parseOptionalPriceSource(source *string) (*trigger_domain.PriceSource, error) {
+	if source == nil {
+		return nil, nil
+	}
+	ps, err := trigger_domain.NewPriceSource(*source)
+	if err != nil {
+		return nil, err
+	}
+	return &ps, nil
+}
+
+func (r *BracketRoutes) CreateBracket(w http.ResponseWriter, req *http.Request) {
+	var request CreateBracketRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	side, err := contract.NewSide(request.Contract.Side)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractIdentifier := contract.ContractIdentifier{
+		Ticker: contract.Ticker(request.Contract.Ticker),
+		Side:   side,
+	}
+
+	entryPrice, err := contract.NewContractPrice(request.Entry.TriggerPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entryLimitPrice, err := parseOptionalPrice(request.Entry.LimitPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stopPrice, err := contract.NewContractPrice(request.StopPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	takeProfitPrice, err := contract.NewContractPrice(request.TakeProfitPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stopLimitPrice, err := parseOptionalPrice(request.StopLimitPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	takeProfitLimitPrice, err := parseOptionalPrice(request.TakeProfitLimitPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bracket, err := r.service.CreateBracket(
+		req.Context(),
+		contractIdentifier,
+		trigger_domain.Direction(request.Entry.Direction),
+		entryPrice,
+		request.Entry.Size,
+		entryLimitPrice,
+		request.Entry.MaxCost,
+		stopPrice,
+		stopLimitPrice,
+		takeProfitPrice,
+		takeProfitLimitPrice,
+		priceSource,
+	)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToBracketResponse(bracket)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *BracketRoutes) GetBracket(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bracket, err := r.service.GetBracket(req.Context(), trigger_domain.TriggerGroupID(groupID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToBracketResponse(bracket)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *BracketRoutes) CancelBracket(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bracket, err := r.service.CancelBracket(req.Context(), trigger_domain.TriggerGroupID(groupID), requestActor(req))
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToBracketResponse(bracket)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
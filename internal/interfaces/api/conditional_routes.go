@@ -0,0 +1,605 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	weather_domain "prediction-risk/internal/app/weather/domain"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type ConditionalTriggerRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewConditionalTriggerRoutes(service *trigger_service.TriggerService) *ConditionalTriggerRoutes {
+	return &ConditionalTriggerRoutes{service: service}
+}
+
+func (routes *ConditionalTriggerRoutes) Register(router chi.Router) {
+	router.Route("/api/conditional-triggers", func(r chi.Router) {
+		r.Post("/", routes.CreateConditionalTrigger)
+		r.Get("/", routes.ListConditionalTriggers)
+		r.Get("/{id}", routes.GetConditionalTrigger)
+		r.Patch("/{id}", routes.UpdateConditionalTrigger)
+		r.Delete("/{id}", routes.CancelConditionalTrigger)
+	})
+}
+
+type ContractRequest struct {
+	Ticker string `json:"ticker"`
+	Side   string `json:"side"`
+}
+
+func (c ContractRequest) toContractIdentifier() (contract.ContractIdentifier, error) {
+	side, err := contract.NewSide(c.Side)
+	if err != nil {
+		return contract.ContractIdentifier{}, err
+	}
+	return contract.ContractIdentifier{
+		Ticker: contract.Ticker(c.Ticker),
+		Side:   side,
+	}, nil
+}
+
+// PriceRuleRequest compares the source price (BID, ASK, LAST or MID; ASK if omitted) to the threshold
+type PriceRuleRequest struct {
+	Threshold int    `json:"threshold"`
+	Direction string `json:"direction"`
+	Source    string `json:"source,omitempty"`
+}
+
+type WeatherRuleRequest struct {
+	StationID string  `json:"station_id"`
+	Metric    string  `json:"metric"`
+	Threshold float64 `json:"threshold"`
+	Unit      string  `json:"unit"`
+	Direction string  `json:"direction"`
+}
+
+// ForecastRuleRequest looks at the latest NWS hourly forecast for a gridpoint (e.g. OKX 33,37);
+// metric is DAILY_HIGH or DAILY_LOW for the day day_offset days from today
+type ForecastRuleRequest struct {
+	OfficeID  string  `json:"office_id"`
+	GridX     int     `json:"grid_x"`
+	GridY     int     `json:"grid_y"`
+	Metric    string  `json:"metric"`
+	DayOffset int     `json:"day_offset"`
+	Threshold float64 `json:"threshold"`
+	Unit      string  `json:"unit"`
+	Direction string  `json:"direction"`
+}
+
+// TimeRuleRequest fires at an absolute time (reference ABSOLUTE with at), or at an
+// offset from a market's close or expiration (negative offsets are before)
+type TimeRuleRequest struct {
+	Reference     string     `json:"reference"`
+	At            *time.Time `json:"at,omitempty"`
+	Ticker        string     `json:"ticker,omitempty"`
+	OffsetMinutes int        `json:"offset_minutes,omitempty"`
+}
+
+// EventRuleRequest looks at everything held across an event's markets; threshold is in cents
+type EventRuleRequest struct {
+	EventTicker string `json:"event_ticker"`
+	Metric      string `json:"metric"`
+	Threshold   int    `json:"threshold"`
+	Direction   string `json:"direction"`
+}
+
+// ConditionRequest is one node of a condition tree. Price leaves set contract and price,
+// weather, forecast, time and event leaves set their rule; composite nodes set operator (AND, OR, NOT) and children.
+type ConditionRequest struct {
+	Operator string               `json:"operator,omitempty"`
+	Children []ConditionRequest   `json:"children,omitempty"`
+	Contract *ContractRequest     `json:"contract,omitempty"`
+	Price    *PriceRuleRequest    `json:"price,omitempty"`
+	Weather  *WeatherRuleRequest  `json:"weather,omitempty"`
+	Forecast *ForecastRuleRequest `json:"forecast,omitempty"`
+	Time     *TimeRuleRequest     `json:"time,omitempty"`
+	Event    *EventRuleRequest    `json:"event,omitempty"`
+}
+
+func (c ConditionRequest) toCondition() (*trigger_domain.TriggerCondition, error) {
+	if c.Operator != "" {
+		operator, err := trigger_domain.NewLogicalOperator(c.Operator)
+		if err != nil {
+			return nil, err
+		}
+
+		children := make([]trigger_domain.TriggerCondition, 0, len(c.Children))
+		for _, childRequest := range c.Children {
+			child, err := childRequest.toCondition()
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, *child)
+		}
+		return trigger_domain.NewCompositeCondition(operator, children...)
+	}
+
+	if c.Time != nil {
+		reference, err := trigger_domain.NewTimeReference(c.Time.Reference)
+		if err != nil {
+			return nil, err
+		}
+		if reference == trigger_domain.TimeReferenceAbsolute {
+			if c.Time.At == nil {
+				return nil, errors.New("absolute time rule must have a time")
+			}
+			return trigger_domain.NewAbsoluteTimeCondition(*c.Time.At)
+		}
+		return trigger_domain.NewMarketTimeCondition(
+			contract.Ticker(c.Time.Ticker),
+			reference,
+			time.Duration(c.Time.OffsetMinutes)*time.Minute,
+		)
+	}
+
+	if c.Event != nil {
+		metric, err := trigger_domain.NewEventMetric(c.Event.Metric)
+		if err != nil {
+			return nil, err
+		}
+		return trigger_domain.NewEventCondition(
+			contract.Ticker(c.Event.EventTicker),
+			metric,
+			c.Event.Threshold,
+			trigger_domain.Direction(c.Event.Direction),
+		)
+	}
+
+	if c.Weather != nil {
+		metric, err := trigger_domain.NewWeatherMetric(c.Weather.Metric)
+		if err != nil {
+			return nil, err
+		}
+		unit, err := weather_domain.NewTemperatureUnit(c.Weather.Unit)
+		if err != nil {
+			return nil, err
+		}
+		return trigger_domain.NewWeatherCondition(
+			c.Weather.StationID,
+			metric,
+			weather_domain.Temperature{Value: c.Weather.Threshold, TemperatureUnit: unit},
+			trigger_domain.Direction(c.Weather.Direction),
+		)
+	}
+
+	if c.Forecast != nil {
+		gridpoint, err := weather_domain.NewGridpoint(c.Forecast.OfficeID, c.Forecast.GridX, c.Forecast.GridY)
+		if err != nil {
+			return nil, err
+		}
+		metric, err := trigger_domain.NewForecastMetric(c.Forecast.Metric)
+		if err != nil {
+			return nil, err
+		}
+		unit, err := weather_domain.NewTemperatureUnit(c.Forecast.Unit)
+		if err != nil {
+			return nil, err
+		}
+		return trigger_domain.NewForecastCondition(
+			gridpoint,
+			metric,
+			c.Forecast.DayOffset,
+			weather_domain.Temperature{Value: c.Forecast.Threshold, TemperatureUnit: unit},
+			trigger_domain.Direction(c.Forecast.Direction),
+		)
+	}
+
+	if c.Contract == nil || c.Price == nil {
+		return nil, errors.New("condition must have an operator, a weather, forecast, time or event rule, or a contract and price")
+	}
+
+	contractIdentifier, err := c.Contract.toContractIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	threshold, err := contract.NewContractPrice(c.Price.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Price.Source == "" {
+		return trigger_domain.NewPriceCondition(contractIdentifier, threshold, trigger_domain.Direction(c.Price.Direction))
+	}
+	source, err := trigger_domain.NewPriceSource(c.Price.Source)
+	if err != nil {
+		return nil, err
+	}
+	return trigger_domain.NewPriceConditionWithSource(contractIdentifier, threshold, trigger_domain.Direction(c.Price.Direction), source)
+}
+
+type ActionRequest struct {
+	Contract   ContractRequest `json:"contract"`
+	Side       string          `json:"side"`
+	Size       *uint           `json:"size"`
+	LimitPrice *int            `json:"limit_price"`
+	MaxCost    *int            `json:"max_cost"`
+}
+
+func (a ActionRequest) toAction() (*trigger_domain.TriggerAction, error) {
+	contractIdentifier, err := a.Contract.toContractIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	side, err := trigger_domain.NewOrderSide(a.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	limitPrice, err := parseOptionalPrice(a.LimitPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	if side == trigger_domain.Buy {
+		if a.Size == nil {
+			return nil, errors.New("size must be provided for buy actions")
+		}
+		return trigger_domain.NewBuyAction(contractIdentifier, *a.Size, limitPrice, a.MaxCost)
+	}
+	if a.MaxCost != nil {
+		return nil, errors.New("max cost only applies to buy actions")
+	}
+
+	return trigger_domain.NewTriggerAction(contractIdentifier, side, a.Size, limitPrice)
+}
+
+func toActions(requests []ActionRequest) ([]trigger_domain.TriggerAction, error) {
+	actions := make([]trigger_domain.TriggerAction, 0, len(requests))
+	for _, actionRequest := range requests {
+		action, err := actionRequest.toAction()
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, *action)
+	}
+	return actions, nil
+}
+
+type CreateConditionalTriggerRequest struct {
+	Condition ConditionRequest `json:"condition"`
+	Actions   []ActionRequest  `json:"actions"`
+}
+
+type UpdateConditionalTriggerRequest struct {
+	Condition ConditionRequest `json:"condition"`
+}
+
+type PriceRuleResponse struct {
+	Threshold int    `json:"threshold"`
+	Direction string `json:"direction"`
+	Source    string `json:"source"`
+}
+
+type WeatherRuleResponse struct {
+	StationID string  `json:"station_id"`
+	Metric    string  `json:"metric"`
+	Threshold float64 `json:"threshold"`
+	Unit      string  `json:"unit"`
+	Direction string  `json:"direction"`
+}
+
+type ForecastRuleResponse struct {
+	OfficeID  string  `json:"office_id"`
+	GridX     int     `json:"grid_x"`
+	GridY     int     `json:"grid_y"`
+	Metric    string  `json:"metric"`
+	DayOffset int     `json:"day_offset"`
+	Threshold float64 `json:"threshold"`
+	Unit      string  `json:"unit"`
+	Direction string  `json:"direction"`
+}
+
+type TimeRuleResponse struct {
+	Reference     string     `json:"reference"`
+	At            *time.Time `json:"at,omitempty"`
+	Ticker        string     `json:"ticker,omitempty"`
+	OffsetMinutes int        `json:"offset_minutes,omitempty"`
+}
+
+type EventRuleResponse struct {
+	EventTicker string `json:"event_ticker"`
+	Metric      string `json:"metric"`
+	Threshold   int    `json:"threshold"`
+	Direction   string `json:"direction"`
+}
+
+type ConditionResponse struct {
+	Operator string                `json:"operator,omitempty"`
+	Children []ConditionResponse   `json:"children,omitempty"`
+	Contract *ContractIDResponse   `json:"contract,omitempty"`
+	Price    *PriceRuleResponse    `json:"price,omitempty"`
+	Weather  *WeatherRuleResponse  `json:"weather,omitempty"`
+	Forecast *ForecastRuleResponse `json:"forecast,omitempty"`
+	Time     *TimeRuleResponse     `json:"time,omitempty"`
+	Event    *EventRuleResponse    `json:"event,omitempty"`
+}
+
+func ToConditionResponse(condition trigger_domain.TriggerCondition) ConditionResponse {
+	if condition.IsComposite() {
+		return ConditionResponse{
+			Operator: condition.Operator.String(),
+			Children: lo.Map(condition.Children, func(child trigger_domain.TriggerCondition, _ int) ConditionResponse {
+				return ToConditionResponse(child)
+			}),
+		}
+	}
+
+	if condition.Time != nil {
+		return ConditionResponse{
+			Time: &TimeRuleResponse{
+				Reference:     condition.Time.Reference.String(),
+				At:            condition.Time.At,
+				Ticker:        string(condition.Time.Ticker),
+				OffsetMinutes: int(condition.Time.Offset / time.Minute),
+			},
+		}
+	}
+
+	if condition.Event != nil {
+		return ConditionResponse{
+			Event: &EventRuleResponse{
+				EventTicker: string(condition.Event.EventTicker),
+				Metric:      condition.Event.Metric.String(),
+				Threshold:   condition.Event.Threshold,
+				Direction:   condition.Event.Direction.String(),
+			},
+		}
+	}
+
+	if condition.Weather != nil {
+		return ConditionResponse{
+			Weather: &WeatherRuleResponse{
+				StationID: condition.Weather.StationID,
+				Metric:    condition.Weather.Metric.String(),
+				Threshold: condition.Weather.Threshold.Value,
+				Unit:      string(condition.Weather.Threshold.TemperatureUnit),
+				Direction: condition.Weather.Direction.String(),
+			},
+		}
+	}
+
+	if condition.Forecast != nil {
+		return ConditionResponse{
+			Forecast: &ForecastRuleResponse{
+				OfficeID:  condition.Forecast.Gridpoint.OfficeID,
+				GridX:     condition.Forecast.Gridpoint.X,
+				GridY:     condition.Forecast.Gridpoint.Y,
+				Metric:    condition.Forecast.Metric.String(),
+				DayOffset: condition.Forecast.DayOffset,
+				Threshold: condition.Forecast.Threshold.Value,
+				Unit:      string(condition.Forecast.Threshold.TemperatureUnit),
+				Direction: condition.Forecast.Direction.String(),
+			},
+		}
+	}
+
+	response := ConditionResponse{
+		Contract: &ContractIDResponse{
+			Ticker: string(condition.Contract.Ticker),
+			Side:   condition.Contract.Side.String(),
+		},
+	}
+	if condition.Price != nil {
+		response.Price = &PriceRuleResponse{
+			Threshold: condition.Price.Threshold.Value(),
+			Direction: condition.Price.Direction.String(),
+			Source:    condition.Price.Source.String(),
+		}
+	}
+	return response
+}
+
+type ActionResponse struct {
+	Contract   ContractIDResponse `json:"contract"`
+	Side       string             `json:"side"`
+	Size       *uint              `json:"size"`
+	LimitPrice *int               `json:"limit_price"`
+	MaxCost    *int               `json:"max_cost,omitempty"`
+}
+
+func ToActionResponse(action trigger_domain.TriggerAction) ActionResponse {
+	var limitPrice *int
+	if action.LimitPrice != nil {
+		value := action.LimitPrice.Value()
+		limitPrice = &value
+	}
+
+	return ActionResponse{
+		Contract: ContractIDResponse{
+			Ticker: string(action.Contract.Ticker),
+			Side:   action.Contract.Side.String(),
+		},
+		Side:       action.Side.String(),
+		Size:       action.Size,
+		LimitPrice: limitPrice,
+		MaxCost:    action.MaxCost,
+	}
+}
+
+type ConditionalTriggerResponse struct {
+	TriggerID    string                 `json:"trigger_id"`
+	TriggerType  string                 `json:"trigger_type"`
+	Status       string                 `json:"status"`
+	Condition    ConditionResponse      `json:"condition"`
+	Actions      []ActionResponse       `json:"actions"`
+	Expiry       *ExpiryResponse        `json:"expiry,omitempty"`
+	Confirmation *ConfirmationResponse  `json:"confirmation,omitempty"`
+	Guard        *GuardResponse         `json:"guard,omitempty"`
+	GuardFailure *GuardFailureResponse  `json:"guard_failure,omitempty"`
+	Orders       []TriggerOrderResponse `json:"orders,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+func ToConditionalTriggerResponse(trigger *trigger_domain.Trigger) ConditionalTriggerResponse {
+	return ConditionalTriggerResponse{
+		TriggerID:   trigger.TriggerID.String(),
+		TriggerType: trigger.TriggerType.String(),
+		Status:      trigger.Status.String(),
+		Condition:   ToConditionResponse(trigger.Condition),
+		Actions: lo.Map(trigger.Actions, func(action trigger_domain.TriggerAction, _ int) ActionResponse {
+			return ToActionResponse(action)
+		}),
+		Expiry:       ToExpiryResponse(trigger.Expiry),
+		Confirmation: ToConfirmationResponse(trigger.Confirmation),
+		Guard:        ToGuardResponse(trigger.Guard),
+		GuardFailure: ToGuardFailureResponse(trigger.GuardFailure),
+		Orders:       ToTriggerOrderResponses(trigger.Orders),
+		CreatedAt:    trigger.CreatedAt,
+		UpdatedAt:    trigger.UpdatedAt,
+	}
+}
+
+func (r *ConditionalTriggerRoutes) CreateConditionalTrigger(w http.ResponseWriter, req *http.Request) {
+	var request CreateConditionalTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	condition, err := request.Condition.toCondition()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid condition: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	actions, err := toActions(request.Actions)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid action: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CreateConditionalTrigger(req.Context(), *condition, actions)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToConditionalTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *ConditionalTriggerRoutes) ListConditionalTriggers(w http.ResponseWriter, req *http.Request) {
+	triggers, err := r.service.GetByType(req.Context(), trigger_domain.TriggerTypeConditional)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := lo.Map(triggers, func(trigger *trigger_domain.Trigger, _ int) ConditionalTriggerResponse {
+		return ToConditionalTriggerResponse(trigger)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *ConditionalTriggerRoutes) GetConditionalTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.GetByID(req.Context(), trigger_domain.TriggerID(triggerID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if trigger == nil || trigger.TriggerType != trigger_domain.TriggerTypeConditional {
+		http.Error(w, "trigger not found", http.StatusNotFound)
+		return
+	}
+
+	response := ToConditionalTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *ConditionalTriggerRoutes) UpdateConditionalTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateConditionalTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	condition, err := request.Condition.toCondition()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid condition: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.UpdateConditionalTrigger(req.Context(), trigger_domain.TriggerID(triggerID), *condition, requestActor(req))
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) || errors.Is(err, trigger_service.ErrInvalidTriggerType) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToConditionalTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *ConditionalTriggerRoutes) CancelConditionalTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CancelTriggerOfType(req.Context(), trigger_domain.TriggerID(triggerID), trigger_domain.TriggerTypeConditional, requestActor(req))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToConditionalTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
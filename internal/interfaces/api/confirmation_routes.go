@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// ConfirmationRoutes manage the confirmation policy of any trigger type
+type ConfirmationRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewConfirmationRoutes(service *trigger_service.TriggerService) *ConfirmationRoutes {
+	return &ConfirmationRoutes{service: service}
+}
+
+func (routes *ConfirmationRoutes) Register(router chi.Router) {
+	router.Route("/api/triggers/{id}/confirmation", func(r chi.Router) {
+		r.Put("/", routes.SetConfirmation)
+		r.Delete("/", routes.ClearConfirmation)
+	})
+}
+
+// SetConfirmationRequest requires either consecutive evaluations or a minimum duration
+type SetConfirmationRequest struct {
+	Evaluations     *int `json:"evaluations"`
+	DurationSeconds *int `json:"duration_seconds"`
+}
+
+type ConfirmationResponse struct {
+	RequiredEvaluations     int        `json:"required_evaluations,omitempty"`
+	RequiredDurationSeconds int        `json:"required_duration_seconds,omitempty"`
+	SatisfiedCount          int        `json:"satisfied_count"`
+	SatisfiedSince          *time.Time `json:"satisfied_since,omitempty"`
+}
+
+type TriggerConfirmationResponse struct {
+	TriggerID    string                `json:"trigger_id"`
+	TriggerType  string                `json:"trigger_type"`
+	Status       string                `json:"status"`
+	Confirmation *ConfirmationResponse `json:"confirmation"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+func ToConfirmationResponse(confirmation *trigger_domain.Confirmation) *ConfirmationResponse {
+	if confirmation == nil {
+		return nil
+	}
+	return &ConfirmationResponse{
+		RequiredEvaluations:     confirmation.RequiredEvaluations,
+		RequiredDurationSeconds: int(confirmation.RequiredDuration / time.Second),
+		SatisfiedCount:          confirmation.SatisfiedCount,
+		SatisfiedSince:          confirmation.SatisfiedSince,
+	}
+}
+
+func ToTriggerConfirmationResponse(trigger *trigger_domain.Trigger) TriggerConfirmationResponse {
+	return TriggerConfirmationResponse{
+		TriggerID:    trigger.TriggerID.String(),
+		TriggerType:  trigger.TriggerType.String(),
+		Status:       trigger.Status.String(),
+		Confirmation: ToConfirmationResponse(trigger.Confirmation),
+		UpdatedAt:    trigger.UpdatedAt,
+	}
+}
+
+func (r *ConfirmationRoutes) SetConfirmation(w http.ResponseWriter, req *http.Request) {
+	var request SetConfirmationRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var confirmation *trigger_domain.Confirmation
+	var err error
+	switch {
+	case request.Evaluations != nil && request.DurationSeconds != nil:
+		http.Error(w, "specify evaluations or duration_seconds, not both", http.StatusBadRequest)
+		return
+	case request.Evaluations != nil:
+		confirmation, err = trigger_domain.NewEvaluationConfirmation(*request.Evaluations)
+	case request.DurationSeconds != nil:
+		confirmation, err = trigger_domain.NewDurationConfirmation(time.Duration(*request.DurationSeconds) * time.Second)
+	default:
+		http.Error(w, "evaluations or duration_seconds is required", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.updateConfirmation(w, req, confirmation)
+}
+
+func (r *ConfirmationRoutes) ClearConfirmation(w http.ResponseWriter, req *http.Request) {
+	r.updateConfirmation(w, req, nil)
+}
+
+func (r *ConfirmationRoutes) updateConfirmation(
+	w http.ResponseWriter,
+	req *http.Request,
+	confirmation *trigger_domain.Confirmation,
+) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.SetConfirmation(req.Context(), trigger_domain.TriggerID(triggerID), confirmation)
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToTriggerConfirmationResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
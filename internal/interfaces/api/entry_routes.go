@@ -0,0 +1,301 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type EntryTriggerRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewEntryTriggerRoutes(service *trigger_service.TriggerService) *EntryTriggerRoutes {
+	return &EntryTriggerRoutes{service: service}
+}
+
+func (routes *EntryTriggerRoutes) Register(router chi.Router) {
+	router.Route("/api/entry-triggers", func(r chi.Router) {
+		r.Post("/", routes.CreateEntryTrigger)
+		r.Get("/", routes.ListEntryTriggers)
+		r.Get("/{id}", routes.GetEntryTrigger)
+		r.Patch("/{id}", routes.UpdateEntryTrigger)
+		r.Delete("/{id}", routes.CancelEntryTrigger)
+	})
+}
+
+// CreateEntryTriggerRequest buys size contracts once the price crosses trigger_price
+// in the given direction (BELOW to buy a dip, ABOVE to buy a breakout)
+type CreateEntryTriggerRequest struct {
+	Contract struct {
+		Ticker string `json:"ticker"`
+		Side   string `json:"side"`
+	} `json:"contract"`
+	Direction    string  `json:"direction"`
+	TriggerPrice int     `json:"trigger_price"`
+	Size         uint    `json:"size"`
+	LimitPrice   *int    `json:"limit_price"`
+	MaxCost      *int    `json:"max_cost"`
+	PriceSource  *string `json:"price_source"`
+}
+
+type UpdateEntryTriggerRequest struct {
+	TriggerPrice *int    `json:"trigger_price"`
+	Size         *uint   `json:"size"`
+	LimitPrice   *int    `json:"limit_price"`
+	MaxCost      *int    `json:"max_cost"`
+	PriceSource  *string `json:"price_source"`
+}
+
+type EntryTriggerResponse struct {
+	TriggerID    string                 `json:"trigger_id"`
+	TriggerType  string                 `json:"trigger_type"`
+	Contract     ContractIDResponse     `json:"contract"`
+	Status       string                 `json:"status"`
+	Direction    string                 `json:"direction"`
+	TriggerPrice int                    `json:"trigger_price"`
+	Size         uint                   `json:"size"`
+	LimitPrice   *int                   `json:"limit_price"`
+	MaxCost      *int                   `json:"max_cost"`
+	PriceSource  string                 `json:"price_source"`
+	Expiry       *ExpiryResponse        `json:"expiry,omitempty"`
+	Confirmation *ConfirmationResponse  `json:"confirmation,omitempty"`
+	Guard        *GuardResponse         `json:"guard,omitempty"`
+	GuardFailure *GuardFailureResponse  `json:"guard_failure,omitempty"`
+	Orders       []TriggerOrderResponse `json:"orders,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+func ToEntryTriggerResponse(trigger *trigger_domain.Trigger) EntryTriggerResponse {
+	action := trigger.Actions[0]
+
+	var limitPrice *int
+	if action.LimitPrice != nil {
+		value := action.LimitPrice.Value()
+		limitPrice = &value
+	}
+
+	var size uint
+	if action.Size != nil {
+		size = *action.Size
+	}
+
+	return EntryTriggerResponse{
+		TriggerID:   trigger.TriggerID.String(),
+		TriggerType: trigger.TriggerType.String(),
+		Contract: ContractIDResponse{
+			Ticker: string(trigger.Condition.Contract.Ticker),
+			Side:   trigger.Condition.Contract.Side.String(),
+		},
+		Status:       trigger.Status.String(),
+		Direction:    trigger.Condition.Price.Direction.String(),
+		TriggerPrice: trigger.Condition.Price.Threshold.Value(),
+		PriceSource:  trigger.Condition.Price.Source.String(),
+		Size:         size,
+		LimitPrice:   limitPrice,
+		MaxCost:      action.MaxCost,
+		Expiry:       ToExpiryResponse(trigger.Expiry),
+		Confirmation: ToConfirmationResponse(trigger.Confirmation),
+		Guard:        ToGuardResponse(trigger.Guard),
+		GuardFailure: ToGuardFailureResponse(trigger.GuardFailure),
+		Orders:       ToTriggerOrderResponses(trigger.Orders),
+		CreatedAt:    trigger.CreatedAt,
+		UpdatedAt:    trigger.UpdatedAt,
+	}
+}
+
+func (r *EntryTriggerRoutes) CreateEntryTrigger(w http.ResponseWriter, req *http.Request) {
+	var request CreateEntryTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	side, err := contract.NewSide(request.Contract.Side)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractIdentifier := contract.ContractIdentifier{
+		Ticker: contract.Ticker(request.Contract.Ticker),
+		Side:   side,
+	}
+
+	triggerPrice, err := contract.NewContractPrice(request.TriggerPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limitPrice, err := parseOptionalPrice(request.LimitPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CreateEntryTrigger(
+		req.Context(),
+		contractIdentifier,
+		trigger_domain.Direction(request.Direction),
+		triggerPrice,
+		request.Size,
+		limitPrice,
+		request.MaxCost,
+		priceSource,
+	)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToEntryTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *EntryTriggerRoutes) ListEntryTriggers(w http.ResponseWriter, req *http.Request) {
+	triggers, err := r.service.GetByType(req.Context(), trigger_domain.TriggerTypeEntry)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := lo.Map(triggers, func(trigger *trigger_domain.Trigger, _ int) EntryTriggerResponse {
+		return ToEntryTriggerResponse(trigger)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *EntryTriggerRoutes) GetEntryTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.GetByID(req.Context(), trigger_domain.TriggerID(triggerID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if trigger == nil || trigger.TriggerType != trigger_domain.TriggerTypeEntry {
+		http.Error(w, "trigger not found", http.StatusNotFound)
+		return
+	}
+
+	response := ToEntryTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *EntryTriggerRoutes) UpdateEntryTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateEntryTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	triggerPrice, err := parseOptionalPrice(request.TriggerPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limitPrice, err := parseOptionalPrice(request.LimitPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.UpdateEntryTrigger(
+		req.Context(),
+		trigger_domain.TriggerID(triggerID),
+		triggerPrice,
+		request.Size,
+		limitPrice,
+		request.MaxCost,
+		priceSource,
+		requestActor(req),
+	)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) || errors.Is(err, trigger_service.ErrInvalidTriggerType) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToEntryTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *EntryTriggerRoutes) CancelEntryTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CancelTriggerOfType(req.Context(), trigger_domain.TriggerID(triggerID), trigger_domain.TriggerTypeEntry, requestActor(req))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToEntryTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type EventExposureTriggerRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewEventExposureTriggerRoutes(service *trigger_service.TriggerService) *EventExposureTriggerRoutes {
+	return &EventExposureTriggerRoutes{service: service}
+}
+
+func (routes *EventExposureTriggerRoutes) Register(router chi.Router) {
+	router.Route("/api/event-exposure-triggers", func(r chi.Router) {
+		r.Post("/", routes.CreateEventExposureTrigger)
+		r.Get("/", routes.ListEventExposureTriggers)
+		r.Get("/{id}", routes.GetEventExposureTrigger)
+		r.Patch("/{id}", routes.UpdateEventExposureTrigger)
+		r.Delete("/{id}", routes.CancelEventExposureTrigger)
+	})
+}
+
+// CreateEventExposureTriggerRequest flattens every position in the event once the
+// metric (EXPOSURE or PNL) crosses the threshold, given in cents and negative for losses
+type CreateEventExposureTriggerRequest struct {
+	EventTicker string `json:"event_ticker"`
+	Metric      string `json:"metric"`
+	Threshold   int    `json:"threshold"`
+	Direction   string `json:"direction"`
+}
+
+type UpdateEventExposureTriggerRequest struct {
+	Threshold *int    `json:"threshold"`
+	Direction *string `json:"direction"`
+}
+
+type EventExposureTriggerResponse struct {
+	TriggerID    string                 `json:"trigger_id"`
+	TriggerType  string                 `json:"trigger_type"`
+	EventTicker  string                 `json:"event_ticker"`
+	Status       string                 `json:"status"`
+	Metric       string                 `json:"metric"`
+	Threshold    int                    `json:"threshold"`
+	Direction    string                 `json:"direction"`
+	Expiry       *ExpiryResponse        `json:"expiry,omitempty"`
+	Confirmation *ConfirmationResponse  `json:"confirmation,omitempty"`
+	Guard        *GuardResponse         `json:"guard,omitempty"`
+	GuardFailure *GuardFailureResponse  `json:"guard_failure,omitempty"`
+	Orders       []TriggerOrderResponse `json:"orders,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+func ToEventExposureTriggerResponse(trigger *trigger_domain.Trigger) EventExposureTriggerResponse {
+	return EventExposureTriggerResponse{
+		TriggerID:    trigger.TriggerID.String(),
+		TriggerType:  trigger.TriggerType.String(),
+		EventTicker:  string(trigger.Condition.Event.EventTicker),
+		Status:       trigger.Status.String(),
+		Metric:       trigger.Condition.Event.Metric.String(),
+		Threshold:    trigger.Condition.Event.Threshold,
+		Direction:    trigger.Condition.Event.Direction.String(),
+		Expiry:       ToExpiryResponse(trigger.Expiry),
+		Confirmation: ToConfirmationResponse(trigger.Confirmation),
+		Guard:        ToGuardResponse(trigger.Guard),
+		GuardFailure: ToGuardFailureResponse(trigger.GuardFailure),
+		Orders:       ToTriggerOrderResponses(trigger.Orders),
+		CreatedAt:    trigger.CreatedAt,
+		UpdatedAt:    trigger.UpdatedAt,
+	}
+}
+
+func (r *EventExposureTriggerRoutes) CreateEventExposureTrigger(w http.ResponseWriter, req *http.Request) {
+	var request CreateEventExposureTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	metric, err := trigger_domain.NewEventMetric(request.Metric)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CreateEventExposureTrigger(
+		req.Context(),
+		contract.Ticker(request.EventTicker),
+		metric,
+		request.Threshold,
+		trigger_domain.Direction(request.Direction),
+	)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToEventExposureTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *EventExposureTriggerRoutes) ListEventExposureTriggers(w http.ResponseWriter, req *http.Request) {
+	triggers, err := r.service.GetByType(req.Context(), trigger_domain.TriggerTypeEventExposure)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := lo.Map(triggers, func(trigger *trigger_domain.Trigger, _ int) EventExposureTriggerResponse {
+		return ToEventExposureTriggerResponse(trigger)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *EventExposureTriggerRoutes) GetEventExposureTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.GetByID(req.Context(), trigger_domain.TriggerID(triggerID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if trigger == nil || trigger.TriggerType != trigger_domain.TriggerTypeEventExposure {
+		http.Error(w, "trigger not found", http.StatusNotFound)
+		return
+	}
+
+	response := ToEventExposureTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *EventExposureTriggerRoutes) UpdateEventExposureTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateEventExposureTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var direction *trigger_domain.Direction
+	if request.Direction != nil {
+		d := trigger_domain.Direction(*request.Direction)
+		direction = &d
+	}
+
+	trigger, err := r.service.UpdateEventExposureTrigger(
+		req.Context(),
+		trigger_domain.TriggerID(triggerID),
+		request.Threshold,
+		direction,
+		requestActor(req),
+	)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) || errors.Is(err, trigger_service.ErrInvalidTriggerType) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToEventExposureTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *EventExposureTriggerRoutes) CancelEventExposureTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CancelTriggerOfType(req.Context(), trigger_domain.TriggerID(triggerID), trigger_domain.TriggerTypeEventExposure, requestActor(req))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToEventExposureTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
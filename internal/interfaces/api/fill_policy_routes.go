@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// FillPolicyRoutes manage how the unfilled orders of any trigger type are followed up
+type FillPolicyRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewFillPolicyRoutes(service *trigger_service.TriggerService) *FillPolicyRoutes {
+	return &FillPolicyRoutes{service: service}
+}
+
+func (routes *FillPolicyRoutes) Register(router chi.Router) {
+	router.Route("/api/triggers/{id}/fill-policy", func(r chi.Router) {
+		r.Put("/", routes.SetFillPolicy)
+		r.Delete("/", routes.ClearFillPolicy)
+	})
+}
+
+// SetFillPolicyRequest follows up orders still unfilled timeout_seconds after they were
+// placed. action is REPRICE (cancel and place the remainder at the current bid or ask) or
+// MARKET (cancel and send the remainder as a market order).
+type SetFillPolicyRequest struct {
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	Action         string `json:"action"`
+}
+
+type FillPolicyResponse struct {
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	Action         string `json:"action"`
+}
+
+type TriggerFillPolicyResponse struct {
+	TriggerID   string                 `json:"trigger_id"`
+	TriggerType string                 `json:"trigger_type"`
+	Status      string                 `json:"status"`
+	FillPolicy  *FillPolicyResponse    `json:"fill_policy"`
+	Orders      []TriggerOrderResponse `json:"orders,omitempty"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+func ToFillPolicyResponse(policy *trigger_domain.FillPolicy) *FillPolicyResponse {
+	if policy == nil {
+		return nil
+	}
+	return &FillPolicyResponse{
+		TimeoutSeconds: int(policy.Timeout / time.Second),
+		Action:         policy.Action.String(),
+	}
+}
+
+func ToTriggerFillPolicyResponse(trigger *trigger_domain.Trigger) TriggerFillPolicyResponse {
+	return TriggerFillPolicyResponse{
+		TriggerID:   trigger.TriggerID.String(),
+		TriggerType: trigger.TriggerType.String(),
+		Status:      trigger.Status.String(),
+		FillPolicy:  ToFillPolicyResponse(trigger.FillPolicy),
+		Orders:      ToTriggerOrderResponses(trigger.Orders),
+		UpdatedAt:   trigger.UpdatedAt,
+	}
+}
+
+func (r *FillPolicyRoutes) SetFillPolicy(w http.ResponseWriter, req *http.Request) {
+	var request SetFillPolicyRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	action, err := trigger_domain.NewFillAction(request.Action)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.updateTrigger(w, req, func(triggerID trigger_domain.TriggerID) (*trigger_domain.Trigger, error) {
+		return r.service.SetFillPolicy(req.Context(), triggerID, &trigger_domain.FillPolicy{
+			Timeout: time.Duration(request.TimeoutSeconds) * time.Second,
+			Action:  action,
+		})
+	})
+}
+
+func (r *FillPolicyRoutes) ClearFillPolicy(w http.ResponseWriter, req *http.Request) {
+	r.updateTrigger(w, req, func(triggerID trigger_domain.TriggerID) (*trigger_domain.Trigger, error) {
+		return r.service.SetFillPolicy(req.Context(), triggerID, nil)
+	})
+}
+
+func (r *FillPolicyRoutes) updateTrigger(
+	w http.ResponseWriter,
+	req *http.Request,
+	update func(trigger_domain.TriggerID) (*trigger_domain.Trigger, error),
+) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := update(trigger_domain.TriggerID(triggerID))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToTriggerFillPolicyResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
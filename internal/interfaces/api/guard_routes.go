@@ -0,0 +1,174 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// GuardRoutes manage the pre-trade guards of any trigger type, and resume
+// triggers a failed guard flagged for review
+type GuardRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewGuardRoutes(service *trigger_service.TriggerService) *GuardRoutes {
+	return &GuardRoutes{service: service}
+}
+
+func (routes *GuardRoutes) Register(router chi.Router) {
+	router.Route("/api/triggers/{id}", func(r chi.Router) {
+		r.Put("/guard", routes.SetGuard)
+		r.Delete("/guard", routes.ClearGuard)
+		r.Post("/resume", routes.ResumeTrigger)
+	})
+}
+
+// SetGuardRequest sets the trigger's own limits; omitted limits are not checked.
+// on_failure is DEFER (retry next evaluation, the default) or FLAG (hold for review).
+type SetGuardRequest struct {
+	MaxSpread    *int    `json:"max_spread"`
+	MinLiquidity *int    `json:"min_liquidity"`
+	MinVolume24H *int    `json:"min_volume_24h"`
+	MinDepth     *int    `json:"min_depth"`
+	OnFailure    *string `json:"on_failure"`
+}
+
+type GuardResponse struct {
+	MaxSpread    *int   `json:"max_spread,omitempty"`
+	MinLiquidity *int   `json:"min_liquidity,omitempty"`
+	MinVolume24H *int   `json:"min_volume_24h,omitempty"`
+	MinDepth     *int   `json:"min_depth,omitempty"`
+	OnFailure    string `json:"on_failure"`
+}
+
+// GuardFailureResponse explains why a trigger's orders were last held back
+type GuardFailureResponse struct {
+	Reason   string    `json:"reason"`
+	Action   string    `json:"action"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+type TriggerGuardResponse struct {
+	TriggerID    string                `json:"trigger_id"`
+	TriggerType  string                `json:"trigger_type"`
+	Status       string                `json:"status"`
+	Guard        *GuardResponse        `json:"guard"`
+	GuardFailure *GuardFailureResponse `json:"guard_failure,omitempty"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+func ToGuardResponse(guard *trigger_domain.ExecutionGuard) *GuardResponse {
+	if guard == nil {
+		return nil
+	}
+	return &GuardResponse{
+		MaxSpread:    guard.MaxSpread,
+		MinLiquidity: guard.MinLiquidity,
+		MinVolume24H: guard.MinVolume24H,
+		MinDepth:     guard.MinDepth,
+		OnFailure:    guard.OnFailure.String(),
+	}
+}
+
+func ToGuardFailureResponse(failure *trigger_domain.GuardFailure) *GuardFailureResponse {
+	if failure == nil {
+		return nil
+	}
+	return &GuardFailureResponse{
+		Reason:   failure.Reason,
+		Action:   failure.Action.String(),
+		FailedAt: failure.FailedAt,
+	}
+}
+
+func ToTriggerGuardResponse(trigger *trigger_domain.Trigger) TriggerGuardResponse {
+	return TriggerGuardResponse{
+		TriggerID:    trigger.TriggerID.String(),
+		TriggerType:  trigger.TriggerType.String(),
+		Status:       trigger.Status.String(),
+		Guard:        ToGuardResponse(trigger.Guard),
+		GuardFailure: ToGuardFailureResponse(trigger.GuardFailure),
+		UpdatedAt:    trigger.UpdatedAt,
+	}
+}
+
+func (r *GuardRoutes) SetGuard(w http.ResponseWriter, req *http.Request) {
+	var request SetGuardRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	onFailure := trigger_domain.GuardFailureDefer
+	if request.OnFailure != nil {
+		action, err := trigger_domain.NewGuardFailureAction(*request.OnFailure)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		onFailure = action
+	}
+
+	r.updateTrigger(w, req, func(triggerID trigger_domain.TriggerID) (*trigger_domain.Trigger, error) {
+		return r.service.SetGuard(req.Context(), triggerID, &trigger_domain.ExecutionGuard{
+			MaxSpread:    request.MaxSpread,
+			MinLiquidity: request.MinLiquidity,
+			MinVolume24H: request.MinVolume24H,
+			MinDepth:     request.MinDepth,
+			OnFailure:    onFailure,
+		})
+	})
+}
+
+func (r *GuardRoutes) ClearGuard(w http.ResponseWriter, req *http.Request) {
+	r.updateTrigger(w, req, func(triggerID trigger_domain.TriggerID) (*trigger_domain.Trigger, error) {
+		return r.service.SetGuard(req.Context(), triggerID, nil)
+	})
+}
+
+func (r *GuardRoutes) ResumeTrigger(w http.ResponseWriter, req *http.Request) {
+	r.updateTrigger(w, req, func(triggerID trigger_domain.TriggerID) (*trigger_domain.Trigger, error) {
+		return r.service.ResumeTrigger(req.Context(), triggerID, requestActor(req))
+	})
+}
+
+func (r *GuardRoutes) updateTrigger(
+	w http.ResponseWriter,
+	req *http.Request,
+	update func(trigger_domain.TriggerID) (*trigger_domain.Trigger, error),
+) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := update(trigger_domain.TriggerID(triggerID))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToTriggerGuardResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,275 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type HedgeTriggerRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewHedgeTriggerRoutes(service *trigger_service.TriggerService) *HedgeTriggerRoutes {
+	return &HedgeTriggerRoutes{service: service}
+}
+
+func (routes *HedgeTriggerRoutes) Register(router chi.Router) {
+	router.Route("/api/hedge-triggers", func(r chi.Router) {
+		r.Post("/", routes.CreateHedgeTrigger)
+		r.Get("/", routes.ListHedgeTriggers)
+		r.Get("/{id}", routes.GetHedgeTrigger)
+		r.Patch("/{id}", routes.UpdateHedgeTrigger)
+		r.Delete("/{id}", routes.CancelHedgeTrigger)
+	})
+}
+
+// CreateHedgeTriggerRequest watches the contract's price and places the actions,
+// at least one of them on another contract, once it crosses trigger_price
+type CreateHedgeTriggerRequest struct {
+	Contract     ContractRequest `json:"contract"`
+	Direction    string          `json:"direction"`
+	TriggerPrice int             `json:"trigger_price"`
+	PriceSource  *string         `json:"price_source"`
+	Actions      []ActionRequest `json:"actions"`
+}
+
+// UpdateHedgeTriggerRequest replaces the actions only if any are given
+type UpdateHedgeTriggerRequest struct {
+	TriggerPrice *int            `json:"trigger_price"`
+	PriceSource  *string         `json:"price_source"`
+	Actions      []ActionRequest `json:"actions"`
+}
+
+type HedgeTriggerResponse struct {
+	TriggerID    string                 `json:"trigger_id"`
+	TriggerType  string                 `json:"trigger_type"`
+	Contract     ContractIDResponse     `json:"contract"`
+	Status       string                 `json:"status"`
+	Direction    string                 `json:"direction"`
+	TriggerPrice int                    `json:"trigger_price"`
+	PriceSource  string                 `json:"price_source"`
+	Actions      []ActionResponse       `json:"actions"`
+	Expiry       *ExpiryResponse        `json:"expiry,omitempty"`
+	Confirmation *ConfirmationResponse  `json:"confirmation,omitempty"`
+	Guard        *GuardResponse         `json:"guard,omitempty"`
+	GuardFailure *GuardFailureResponse  `json:"guard_failure,omitempty"`
+	Orders       []TriggerOrderResponse `json:"orders,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+func ToHedgeTriggerResponse(trigger *trigger_domain.Trigger) HedgeTriggerResponse {
+	return HedgeTriggerResponse{
+		TriggerID:   trigger.TriggerID.String(),
+		TriggerType: trigger.TriggerType.String(),
+		Contract: ContractIDResponse{
+			Ticker: string(trigger.Condition.Contract.Ticker),
+			Side:   trigger.Condition.Contract.Side.String(),
+		},
+		Status:       trigger.Status.String(),
+		Direction:    trigger.Condition.Price.Direction.String(),
+		TriggerPrice: trigger.Condition.Price.Threshold.Value(),
+		PriceSource:  trigger.Condition.Price.Source.String(),
+		Actions: lo.Map(trigger.Actions, func(action trigger_domain.TriggerAction, _ int) ActionResponse {
+			return ToActionResponse(action)
+		}),
+		Expiry:       ToExpiryResponse(trigger.Expiry),
+		Confirmation: ToConfirmationResponse(trigger.Confirmation),
+		Guard:        ToGuardResponse(trigger.Guard),
+		GuardFailure: ToGuardFailureResponse(trigger.GuardFailure),
+		Orders:       ToTriggerOrderResponses(trigger.Orders),
+		CreatedAt:    trigger.CreatedAt,
+		UpdatedAt:    trigger.UpdatedAt,
+	}
+}
+
+func (r *HedgeTriggerRoutes) CreateHedgeTrigger(w http.ResponseWriter, req *http.Request) {
+	var request CreateHedgeTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractIdentifier, err := request.Contract.toContractIdentifier()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	triggerPrice, err := contract.NewContractPrice(request.TriggerPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actions, err := toActions(request.Actions)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid action: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CreateHedgeTrigger(
+		req.Context(),
+		contractIdentifier,
+		trigger_domain.Direction(request.Direction),
+		triggerPrice,
+		actions,
+		priceSource,
+	)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToHedgeTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *HedgeTriggerRoutes) ListHedgeTriggers(w http.ResponseWriter, req *http.Request) {
+	triggers, err := r.service.GetByType(req.Context(), trigger_domain.TriggerTypeHedge)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := lo.Map(triggers, func(trigger *trigger_domain.Trigger, _ int) HedgeTriggerResponse {
+		return ToHedgeTriggerResponse(trigger)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *HedgeTriggerRoutes) GetHedgeTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.GetByID(req.Context(), trigger_domain.TriggerID(triggerID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if trigger == nil || trigger.TriggerType != trigger_domain.TriggerTypeHedge {
+		http.Error(w, "trigger not found", http.StatusNotFound)
+		return
+	}
+
+	response := ToHedgeTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *HedgeTriggerRoutes) UpdateHedgeTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateHedgeTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	triggerPrice, err := parseOptionalPrice(request.TriggerPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var actions []trigger_domain.TriggerAction
+	if len(request.Actions) > 0 {
+		actions, err = toActions(request.Actions)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid action: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	trigger, err := r.service.UpdateHedgeTrigger(
+		req.Context(),
+		trigger_domain.TriggerID(triggerID),
+		triggerPrice,
+		actions,
+		priceSource,
+		requestActor(req),
+	)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) || errors.Is(err, trigger_service.ErrInvalidTriggerType) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToHedgeTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *HedgeTriggerRoutes) CancelHedgeTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CancelTriggerOfType(req.Context(), trigger_domain.TriggerID(triggerID), trigger_domain.TriggerTypeHedge, requestActor(req))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToHedgeTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
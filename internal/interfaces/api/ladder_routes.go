@@ -0,0 +1,225 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"prediction-risk/internal/app/contract"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type StopLadderRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewStopLadderRoutes(service *trigger_service.TriggerService) *StopLadderRoutes {
+	return &StopLadderRoutes{service: service}
+}
+
+func (routes *StopLadderRoutes) Register(router chi.Router) {
+	router.Route("/api/stop-ladders", func(r chi.Router) {
+		r.Post("/", routes.CreateStopLadder)
+		r.Get("/{id}", routes.GetStopLadder)
+		r.Patch("/{id}", routes.UpdateStopLadder)
+		r.Delete("/{id}", routes.CancelStopLadder)
+	})
+}
+
+// LadderTierRequest is one step of a ladder; share is the percent of the position
+// sold at this tier, and the shares of all tiers must add up to 100
+type LadderTierRequest struct {
+	TriggerPrice int  `json:"trigger_price"`
+	LimitPrice   *int `json:"limit_price"`
+	Share        int  `json:"share"`
+}
+
+func toLadderTiers(requests []LadderTierRequest) ([]trigger_domain.LadderTier, error) {
+	tiers := make([]trigger_domain.LadderTier, 0, len(requests))
+	for _, tierRequest := range requests {
+		triggerPrice, err := contract.NewContractPrice(tierRequest.TriggerPrice)
+		if err != nil {
+			return nil, err
+		}
+		limitPrice, err := parseOptionalPrice(tierRequest.LimitPrice)
+		if err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, trigger_domain.LadderTier{
+			TriggerPrice: triggerPrice,
+			LimitPrice:   limitPrice,
+			Share:        tierRequest.Share,
+		})
+	}
+	return tiers, nil
+}
+
+type CreateStopLadderRequest struct {
+	Contract    ContractRequest     `json:"contract"`
+	Tiers       []LadderTierRequest `json:"tiers"`
+	PriceSource *string             `json:"price_source"`
+}
+
+// UpdateStopLadderRequest replaces the tiers that have not fired yet, highest first,
+// with shares of the position still held
+type UpdateStopLadderRequest struct {
+	Tiers       []LadderTierRequest `json:"tiers"`
+	PriceSource *string             `json:"price_source"`
+}
+
+type StopLadderResponse struct {
+	GroupID   string                `json:"group_id"`
+	GroupType string                `json:"group_type"`
+	Contract  ContractIDResponse    `json:"contract"`
+	Tiers     []StopTriggerResponse `json:"tiers"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+func ToStopLadderResponse(ladder *trigger_domain.StopLadder) StopLadderResponse {
+	return StopLadderResponse{
+		GroupID:   ladder.Group.GroupID.String(),
+		GroupType: ladder.Group.GroupType.String(),
+		Contract: ContractIDResponse{
+			Ticker: string(ladder.Tiers[0].Condition.Contract.Ticker),
+			Side:   ladder.Tiers[0].Condition.Contract.Side.String(),
+		},
+		Tiers: lo.Map(ladder.Tiers, func(trigger *trigger_domain.Trigger, _ int) StopTriggerResponse {
+			return ToStopTriggerResponse(trigger)
+		}),
+		CreatedAt: ladder.Group.CreatedAt,
+		UpdatedAt: ladder.Group.UpdatedAt,
+	}
+}
+
+func (r *StopLadderRoutes) CreateStopLadder(w http.ResponseWriter, req *http.Request) {
+	var request CreateStopLadderRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractIdentifier, err := request.Contract.toContractIdentifier()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tiers, err := toLadderTiers(request.Tiers)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid tier: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ladder, err := r.service.CreateStopLadder(req.Context(), contractIdentifier, tiers, priceSource)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToStopLadderResponse(ladder)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *StopLadderRoutes) GetStopLadder(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ladder, err := r.service.GetStopLadder(req.Context(), trigger_domain.TriggerGroupID(groupID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToStopLadderResponse(ladder)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *StopLadderRoutes) UpdateStopLadder(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateStopLadderRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tiers, err := toLadderTiers(request.Tiers)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid tier: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ladder, err := r.service.UpdateStopLadder(req.Context(), trigger_domain.TriggerGroupID(groupID), tiers, priceSource, requestActor(req))
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToStopLadderResponse(ladder)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *StopLadderRoutes) CancelStopLadder(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ladder, err := r.service.CancelStopLadder(req.Context(), trigger_domain.TriggerGroupID(groupID), requestActor(req))
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToStopLadderResponse(ladder)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/samber/lo"
+)
+
+// OrderRoutes list the exchange orders sent by trigger executions
+type OrderRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewOrderRoutes(service *trigger_service.TriggerService) *OrderRoutes {
+	return &OrderRoutes{service: service}
+}
+
+func (routes *OrderRoutes) Register(router chi.Router) {
+	router.Route("/api/orders", func(r chi.Router) {
+		r.Get("/", routes.ListOrders)
+	})
+}
+
+// TriggerOrderResponse is an order a trigger sent. action_index is the position of the
+// action it carried out among those resolved when the trigger executed, and attempt counts
+// the follow-ups that replaced an unfilled remainder of that action's order.
+type TriggerOrderResponse struct {
+	OrderID         string             `json:"order_id"`
+	TriggerID       string             `json:"trigger_id"`
+	ActionIndex     int                `json:"action_index"`
+	Attempt         int                `json:"attempt"`
+	Exchange        string             `json:"exchange"`
+	ExchangeOrderID string             `json:"exchange_order_id"`
+	Reference       string             `json:"reference"`
+	Contract        ContractIDResponse `json:"contract"`
+	Action          string             `json:"action"`
+	OrderType       string             `json:"order_type"`
+	Quantity        uint               `json:"quantity"`
+	LimitPrice      *int               `json:"limit_price"`
+	FilledQuantity  uint               `json:"filled_quantity"`
+	Status          string             `json:"status"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	FollowUp        *FollowUpResponse  `json:"follow_up,omitempty"`
+}
+
+// FollowUpResponse is the replacement of an order's remainder that has yet to be placed
+type FollowUpResponse struct {
+	Attempt   int    `json:"attempt"`
+	Remaining uint   `json:"remaining"`
+	Action    string `json:"action"`
+}
+
+func ToTriggerOrderResponse(triggerOrder trigger_domain.TriggerOrder) TriggerOrderResponse {
+	order := triggerOrder.Order
+	var limitPrice *int
+	if order.LimitPrice != nil {
+		value := order.LimitPrice.Value()
+		limitPrice = &value
+	}
+
+	response := TriggerOrderResponse{
+		OrderID:         order.OrderID.String(),
+		TriggerID:       triggerOrder.TriggerID.String(),
+		ActionIndex:     triggerOrder.ActionIndex,
+		Attempt:         triggerOrder.Attempt,
+		Exchange:        string(order.Exchange),
+		ExchangeOrderID: order.ExchangeOrderID,
+		Reference:       order.Reference,
+		Contract: ContractIDResponse{
+			Ticker: order.Ticker,
+			Side:   order.Side.String(),
+		},
+		Action:         string(order.Action),
+		OrderType:      string(order.OrderType),
+		Quantity:       order.Quantity,
+		LimitPrice:     limitPrice,
+		FilledQuantity: order.FilledQuantity,
+		Status:         order.Status,
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+	}
+	if followUp := triggerOrder.FollowUp; followUp != nil {
+		response.FollowUp = &FollowUpResponse{
+			Attempt:   followUp.Attempt,
+			Remaining: followUp.Remaining,
+			Action:    followUp.Action.String(),
+		}
+	}
+	return response
+}
+
+// ToTriggerOrderResponses maps the orders shown on a trigger, nil when it has sent none
+func ToTriggerOrderResponses(orders []trigger_domain.TriggerOrder) []TriggerOrderResponse {
+	if len(orders) == 0 {
+		return nil
+	}
+	return lo.Map(orders, func(order trigger_domain.TriggerOrder, _ int) TriggerOrderResponse {
+		return ToTriggerOrderResponse(order)
+	})
+}
+
+func (r *OrderRoutes) ListOrders(w http.ResponseWriter, req *http.Request) {
+	orders, err := r.service.GetOrders(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := lo.Map(orders, func(order trigger_domain.TriggerOrder, _ int) TriggerOrderResponse {
+		return ToTriggerOrderResponse(order)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
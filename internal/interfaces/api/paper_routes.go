@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	exchange_service "prediction-risk/internal/app/exchange/service"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// PaperTradingRoutes report the simulated ledger kept in dry run mode
+type PaperTradingRoutes struct {
+	service *exchange_service.PaperExchangeService
+}
+
+func NewPaperTradingRoutes(service *exchange_service.PaperExchangeService) *PaperTradingRoutes {
+	return &PaperTradingRoutes{service: service}
+}
+
+func (routes *PaperTradingRoutes) Register(router chi.Router) {
+	router.Route("/api/paper", func(r chi.Router) {
+		r.Get("/positions", routes.GetPositions)
+		r.Get("/pnl", routes.GetPNL)
+	})
+}
+
+type PaperPositionResponse struct {
+	Ticker    string `json:"ticker"`
+	Side      string `json:"side"`
+	Quantity  uint   `json:"quantity"`
+	CostBasis int    `json:"cost_basis"`
+}
+
+// PaperPNLResponse is in cents; unrealized P&L marks open positions to the current bid
+type PaperPNLResponse struct {
+	Realized   int       `json:"realized"`
+	Unrealized int       `json:"unrealized"`
+	Total      int       `json:"total"`
+	AsOf       time.Time `json:"as_of"`
+}
+
+func ToPaperPositionResponse(position *exchange_domain.Position) PaperPositionResponse {
+	return PaperPositionResponse{
+		Ticker:    string(position.ContractID.Ticker),
+		Side:      position.ContractID.Side.String(),
+		Quantity:  position.Quantity,
+		CostBasis: position.CostBasis,
+	}
+}
+
+func (r *PaperTradingRoutes) GetPositions(w http.ResponseWriter, req *http.Request) {
+	positions, err := r.service.GetPositions(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]PaperPositionResponse, 0, len(positions))
+	for _, position := range positions {
+		response = append(response, ToPaperPositionResponse(position))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *PaperTradingRoutes) GetPNL(w http.ResponseWriter, req *http.Request) {
+	pnl, err := r.service.GetPNL(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := PaperPNLResponse{
+		Realized:   pnl.Realized,
+		Unrealized: pnl.Unrealized,
+		Total:      pnl.Total(),
+		AsOf:       time.Now(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type ProtectionPolicyRoutes struct {
+	service *trigger_service.ProtectionPolicyService
+}
+
+func NewProtectionPolicyRoutes(service *trigger_service.ProtectionPolicyService) *ProtectionPolicyRoutes {
+	return &ProtectionPolicyRoutes{service: service}
+}
+
+func (routes *ProtectionPolicyRoutes) Register(router chi.Router) {
+	router.Route("/api/protection-policies", func(r chi.Router) {
+		r.Post("/", routes.CreatePolicy)
+		r.Get("/", routes.GetPolicies)
+		r.Get("/{id}", routes.GetPolicy)
+		r.Patch("/{id}", routes.UpdatePolicy)
+		r.Delete("/{id}", routes.DeletePolicy)
+	})
+}
+
+// CreateProtectionPolicyRequest protects every position in a series or event ticker;
+// side may be omitted to cover both YES and NO positions
+type CreateProtectionPolicyRequest struct {
+	Ticker     string  `json:"ticker"`
+	Side       *string `json:"side"`
+	OffsetType string  `json:"offset_type"`
+	Offset     int     `json:"offset"`
+}
+
+type UpdateProtectionPolicyRequest struct {
+	OffsetType *string `json:"offset_type"`
+	Offset     *int    `json:"offset"`
+	Enabled    *bool   `json:"enabled"`
+}
+
+type ProtectionPolicyResponse struct {
+	PolicyID   string    `json:"policy_id"`
+	Ticker     string    `json:"ticker"`
+	Side       *string   `json:"side"`
+	OffsetType string    `json:"offset_type"`
+	Offset     int       `json:"offset"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func ToProtectionPolicyResponse(policy *trigger_domain.ProtectionPolicy) ProtectionPolicyResponse {
+	var side *string
+	if policy.Side != nil {
+		s := policy.Side.String()
+		side = &s
+	}
+	return ProtectionPolicyResponse{
+		PolicyID:   policy.PolicyID.String(),
+		Ticker:     string(policy.Ticker),
+		Side:       side,
+		OffsetType: string(policy.OffsetType),
+		Offset:     policy.Offset,
+		Enabled:    policy.Enabled,
+		CreatedAt:  policy.CreatedAt,
+		UpdatedAt:  policy.UpdatedAt,
+	}
+}
+
+func (r *ProtectionPolicyRoutes) CreatePolicy(w http.ResponseWriter, req *http.Request) {
+	var request CreateProtectionPolicyRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var side contract.Side
+	if request.Side != nil {
+		s, err := contract.NewSide(*request.Side)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		side = s
+	}
+
+	offsetType, err := trigger_domain.NewTrailType(request.OffsetType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	policy, err := r.service.CreatePolicy(req.Context(), contract.Ticker(request.Ticker), side, offsetType, request.Offset)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidProtectionPolicy) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToProtectionPolicyResponse(policy)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *ProtectionPolicyRoutes) GetPolicies(w http.ResponseWriter, req *http.Request) {
+	policies, err := r.service.GetPolicies(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := lo.Map(policies, func(policy *trigger_domain.ProtectionPolicy, _ int) ProtectionPolicyResponse {
+		return ToProtectionPolicyResponse(policy)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *ProtectionPolicyRoutes) GetPolicy(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	policyID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	policy, err := r.service.GetPolicy(req.Context(), trigger_domain.ProtectionPolicyID(policyID))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToProtectionPolicyResponse(policy)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *ProtectionPolicyRoutes) UpdatePolicy(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	policyID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateProtectionPolicyRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var offsetType *trigger_domain.TrailType
+	if request.OffsetType != nil {
+		t, err := trigger_domain.NewTrailType(*request.OffsetType)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offsetType = &t
+	}
+
+	policy, err := r.service.UpdatePolicy(
+		req.Context(),
+		trigger_domain.ProtectionPolicyID(policyID),
+		offsetType,
+		request.Offset,
+		request.Enabled,
+	)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidProtectionPolicy) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToProtectionPolicyResponse(policy)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *ProtectionPolicyRoutes) DeletePolicy(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	policyID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := r.service.DeletePolicy(req.Context(), trigger_domain.ProtectionPolicyID(policyID)); err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"prediction-risk/internal/app/contract"
 	"prediction-risk/internal/app/core"
+	exchange_domain "prediction-risk/internal/app/exchange/domain"
+	exchange_service "prediction-risk/internal/app/exchange/service"
 	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
 	trigger_service "prediction-risk/internal/app/risk/trigger/service"
 	"time"
@@ -16,11 +18,15 @@ import (
 )
 
 type StopTriggerRoutes struct {
-	service *trigger_service.TriggerService
+	service         *trigger_service.TriggerService
+	exchangeService exchange_service.ExchangeService
 }
 
-func NewStopTriggerRoutes(service *trigger_service.TriggerService) *StopTriggerRoutes {
-	return &StopTriggerRoutes{service: service}
+func NewStopTriggerRoutes(
+	service *trigger_service.TriggerService,
+	exchangeService exchange_service.ExchangeService,
+) *StopTriggerRoutes {
+	return &StopTriggerRoutes{service: service, exchangeService: exchangeService}
 }
 
 func (routes *StopTriggerRoutes) Register(router chi.Router) {
@@ -30,6 +36,7 @@ func (routes *StopTriggerRoutes) Register(router chi.Router) {
 		r.Get("/{id}", routes.GetStopTrigger)
 		r.Patch("/{id}", routes.UpdateStopTrigger)
 		r.Delete("/{id}", routes.CancelStopTrigger)
+		r.Get("/{id}/history", routes.GetStopTriggerHistory)
 	})
 }
 
@@ -38,13 +45,23 @@ type CreateStopTriggerRequest struct {
 		Ticker string `json:"ticker"`
 		Side   string `json:"side"`
 	} `json:"contract"`
-	TriggerPrice int  `json:"trigger_price"`
-	LimitPrice   *int `json:"limit_price"`
+	TriggerPrice int                     `json:"trigger_price"`
+	LimitPrice   *int                    `json:"limit_price"`
+	PriceSource  *string                 `json:"price_source"`
+	CostBasis    *CostBasisOffsetRequest `json:"cost_basis"`
+}
+
+// CostBasisOffsetRequest places the stop below the position's average entry instead of
+// at trigger_price, e.g. {"offset_type": "PERCENT", "offset": 15} for 15% below entry
+type CostBasisOffsetRequest struct {
+	OffsetType string `json:"offset_type"`
+	Offset     int    `json:"offset"`
 }
 
 type UpdateStopTriggerRequest struct {
-	TriggerPrice *int `json:"trigger_price"`
-	LimitPrice   *int `json:"limit_price"`
+	TriggerPrice *int    `json:"trigger_price"`
+	LimitPrice   *int    `json:"limit_price"`
+	PriceSource  *string `json:"price_source"`
 }
 
 type ContractIDResponse struct {
@@ -52,15 +69,88 @@ type ContractIDResponse struct {
 	Side   string `json:"side"`
 }
 
+// TriggerChangeResponse is one entry in a trigger's audit trail
+type TriggerChangeResponse struct {
+	Field     string    `json:"field"`
+	OldValue  *string   `json:"old_value"`
+	NewValue  *string   `json:"new_value"`
+	Actor     string    `json:"actor"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func ToTriggerChangeResponse(change trigger_domain.TriggerChange) TriggerChangeResponse {
+	return TriggerChangeResponse{
+		Field:     change.Field.String(),
+		OldValue:  change.OldValue,
+		NewValue:  change.NewValue,
+		Actor:     change.Actor.String(),
+		ChangedAt: change.ChangedAt,
+	}
+}
+
+// requestActor identifies who made a change through the API, from the X-Actor header
+func requestActor(req *http.Request) trigger_domain.Actor {
+	if actor := req.Header.Get("X-Actor"); actor != "" {
+		return trigger_domain.Actor(actor)
+	}
+	return trigger_domain.Actor("api")
+}
+
+// CostBasisResponse shows the entry a cost basis stop is resolved from
+type CostBasisResponse struct {
+	OffsetType   string `json:"offset_type"`
+	Offset       int    `json:"offset"`
+	AverageEntry int    `json:"average_entry"`
+}
+
+func ToCostBasisResponse(costBasis *trigger_domain.CostBasisStop) *CostBasisResponse {
+	if costBasis == nil {
+		return nil
+	}
+	return &CostBasisResponse{
+		OffsetType:   costBasis.OffsetType.String(),
+		Offset:       costBasis.Offset,
+		AverageEntry: costBasis.AverageEntry.Value(),
+	}
+}
+
+// ExpiryResponse explains why a trigger was expired
+type ExpiryResponse struct {
+	Reason           string    `json:"reason"`
+	Ticker           string    `json:"ticker"`
+	SettlementResult *string   `json:"settlement_result,omitempty"`
+	ExpiredAt        time.Time `json:"expired_at"`
+}
+
+func ToExpiryResponse(expiry *trigger_domain.TriggerExpiry) *ExpiryResponse {
+	if expiry == nil {
+		return nil
+	}
+	return &ExpiryResponse{
+		Reason:           expiry.Reason.String(),
+		Ticker:           string(expiry.Ticker),
+		SettlementResult: expiry.SettlementResult,
+		ExpiredAt:        expiry.ExpiredAt,
+	}
+}
+
 type StopTriggerResponse struct {
-	TriggerID    string             `json:"trigger_id"`
-	TriggerType  string             `json:"trigger_type"`
-	Contract     ContractIDResponse `json:"contract"`
-	Status       string             `json:"status"`
-	TriggerPrice int                `json:"trigger_price"`
-	LimitPrice   *int               `json:"limit_price"`
-	CreatedAt    time.Time          `json:"created_at"`
-	UpdatedAt    time.Time          `json:"updated_at"`
+	TriggerID    string                 `json:"trigger_id"`
+	TriggerType  string                 `json:"trigger_type"`
+	Contract     ContractIDResponse     `json:"contract"`
+	Status       string                 `json:"status"`
+	TriggerPrice int                    `json:"trigger_price"`
+	LimitPrice   *int                   `json:"limit_price"`
+	SizePercent  *int                   `json:"size_percent,omitempty"`
+	PriceSource  string                 `json:"price_source"`
+	CostBasis    *CostBasisResponse     `json:"cost_basis,omitempty"`
+	Expiry       *ExpiryResponse        `json:"expiry,omitempty"`
+	Confirmation *ConfirmationResponse  `json:"confirmation,omitempty"`
+	Guard        *GuardResponse         `json:"guard,omitempty"`
+	GuardFailure *GuardFailureResponse  `json:"guard_failure,omitempty"`
+	Orders       []TriggerOrderResponse `json:"orders,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
 }
 
 // In api/mappers.go
@@ -80,7 +170,15 @@ func ToStopTriggerResponse(trigger *trigger_domain.Trigger) StopTriggerResponse
 		},
 		Status:       trigger.Status.String(),
 		TriggerPrice: trigger.Condition.Price.Threshold.Value(),
+		PriceSource:  trigger.Condition.Price.Source.String(),
 		LimitPrice:   limitPrice,
+		SizePercent:  trigger.Actions[0].SizePercent,
+		CostBasis:    ToCostBasisResponse(trigger.CostBasis),
+		Expiry:       ToExpiryResponse(trigger.Expiry),
+		Confirmation: ToConfirmationResponse(trigger.Confirmation),
+		Guard:        ToGuardResponse(trigger.Guard),
+		GuardFailure: ToGuardFailureResponse(trigger.GuardFailure),
+		Orders:       ToTriggerOrderResponses(trigger.Orders),
 		CreatedAt:    trigger.CreatedAt,
 		UpdatedAt:    trigger.UpdatedAt,
 	}
@@ -104,6 +202,11 @@ func (r *StopTriggerRoutes) CreateStopTrigger(w http.ResponseWriter, req *http.R
 		Side:   side,
 	}
 
+	if request.CostBasis != nil {
+		r.createCostBasisStopTrigger(w, req, contractIdentifier, *request.CostBasis, request.PriceSource)
+		return
+	}
+
 	triggerPrice, err := contract.NewContractPrice(request.TriggerPrice)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -118,7 +221,13 @@ func (r *StopTriggerRoutes) CreateStopTrigger(w http.ResponseWriter, req *http.R
 		limitPrice = &cp
 	}
 
-	trigger, err := r.service.CreateStopTrigger(contractIdentifier, triggerPrice, limitPrice)
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CreateStopTrigger(req.Context(), contractIdentifier, triggerPrice, limitPrice, priceSource)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -129,8 +238,56 @@ func (r *StopTriggerRoutes) CreateStopTrigger(w http.ResponseWriter, req *http.R
 	json.NewEncoder(w).Encode(response)
 }
 
+// createCostBasisStopTrigger resolves the stop from the current position in the contract
+func (r *StopTriggerRoutes) createCostBasisStopTrigger(
+	w http.ResponseWriter,
+	req *http.Request,
+	contractIdentifier contract.ContractIdentifier,
+	request CostBasisOffsetRequest,
+	priceSourceRequest *string,
+) {
+	offsetType, err := trigger_domain.NewTrailType(request.OffsetType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	priceSource, err := parseOptionalPriceSource(priceSourceRequest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	positions, err := r.exchangeService.GetPositions(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	position, ok := lo.Find(positions, func(p *exchange_domain.Position) bool {
+		return p.ContractID == contractIdentifier
+	})
+	if !ok {
+		http.Error(w, "no position in contract", http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CreateCostBasisStopTrigger(req.Context(), *position, offsetType, request.Offset, priceSource)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToStopTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
 func (r *StopTriggerRoutes) ListStopTriggers(w http.ResponseWriter, req *http.Request) {
-	triggers, err := r.service.Get()
+	triggers, err := r.service.GetByType(req.Context(), trigger_domain.TriggerTypeStop)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -153,7 +310,7 @@ func (r *StopTriggerRoutes) GetStopTrigger(w http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	trigger, err := r.service.GetByID(trigger_domain.TriggerID(triggerID))
+	trigger, err := r.service.GetByID(req.Context(), trigger_domain.TriggerID(triggerID))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -203,8 +360,18 @@ func (r *StopTriggerRoutes) UpdateStopTrigger(w http.ResponseWriter, req *http.R
 		limitPrice = &cp
 	}
 
-	trigger, err := r.service.UpdateStopTrigger(trigger_domain.TriggerID(triggerID), triggerPrice, limitPrice)
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.UpdateStopTrigger(req.Context(), trigger_domain.TriggerID(triggerID), triggerPrice, limitPrice, priceSource, requestActor(req))
 	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -223,13 +390,17 @@ func (r *StopTriggerRoutes) CancelStopTrigger(w http.ResponseWriter, req *http.R
 		return
 	}
 
-	trigger, err := r.service.CancelTrigger(trigger_domain.TriggerID(triggerID))
+	trigger, err := r.service.CancelTriggerOfType(req.Context(), trigger_domain.TriggerID(triggerID), trigger_domain.TriggerTypeStop, requestActor(req))
 	if err != nil {
 		var notFoundErr *core.ErrNotFound // Note the pointer type
 		if errors.As(err, &notFoundErr) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -238,3 +409,31 @@ func (r *StopTriggerRoutes) CancelStopTrigger(w http.ResponseWriter, req *http.R
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+func (r *StopTriggerRoutes) GetStopTriggerHistory(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	history, err := r.service.GetHistory(req.Context(), trigger_domain.TriggerID(triggerID))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := lo.Map(history, func(change trigger_domain.TriggerChange, _ int) TriggerChangeResponse {
+		return ToTriggerChangeResponse(change)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,275 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type TakeProfitTriggerRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewTakeProfitTriggerRoutes(service *trigger_service.TriggerService) *TakeProfitTriggerRoutes {
+	return &TakeProfitTriggerRoutes{service: service}
+}
+
+func (routes *TakeProfitTriggerRoutes) Register(router chi.Router) {
+	router.Route("/api/take-profit-triggers", func(r chi.Router) {
+		r.Post("/", routes.CreateTakeProfitTrigger)
+		r.Get("/", routes.ListTakeProfitTriggers)
+		r.Get("/{id}", routes.GetTakeProfitTrigger)
+		r.Patch("/{id}", routes.UpdateTakeProfitTrigger)
+		r.Delete("/{id}", routes.CancelTakeProfitTrigger)
+	})
+}
+
+type CreateTakeProfitTriggerRequest struct {
+	Contract struct {
+		Ticker string `json:"ticker"`
+		Side   string `json:"side"`
+	} `json:"contract"`
+	TriggerPrice int     `json:"trigger_price"`
+	LimitPrice   *int    `json:"limit_price"`
+	PriceSource  *string `json:"price_source"`
+}
+
+type UpdateTakeProfitTriggerRequest struct {
+	TriggerPrice *int    `json:"trigger_price"`
+	LimitPrice   *int    `json:"limit_price"`
+	PriceSource  *string `json:"price_source"`
+}
+
+type TakeProfitTriggerResponse struct {
+	TriggerID    string                 `json:"trigger_id"`
+	TriggerType  string                 `json:"trigger_type"`
+	Contract     ContractIDResponse     `json:"contract"`
+	Status       string                 `json:"status"`
+	TriggerPrice int                    `json:"trigger_price"`
+	LimitPrice   *int                   `json:"limit_price"`
+	PriceSource  string                 `json:"price_source"`
+	Expiry       *ExpiryResponse        `json:"expiry,omitempty"`
+	Confirmation *ConfirmationResponse  `json:"confirmation,omitempty"`
+	Guard        *GuardResponse         `json:"guard,omitempty"`
+	GuardFailure *GuardFailureResponse  `json:"guard_failure,omitempty"`
+	Orders       []TriggerOrderResponse `json:"orders,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+func ToTakeProfitTriggerResponse(trigger *trigger_domain.Trigger) TakeProfitTriggerResponse {
+	var limitPrice *int
+	if trigger.Actions[0].LimitPrice != nil {
+		value := trigger.Actions[0].LimitPrice.Value()
+		limitPrice = &value
+	}
+
+	return TakeProfitTriggerResponse{
+		TriggerID:   trigger.TriggerID.String(),
+		TriggerType: trigger.TriggerType.String(),
+		Contract: ContractIDResponse{
+			Ticker: string(trigger.Condition.Contract.Ticker),
+			Side:   trigger.Condition.Contract.Side.String(),
+		},
+		Status:       trigger.Status.String(),
+		TriggerPrice: trigger.Condition.Price.Threshold.Value(),
+		PriceSource:  trigger.Condition.Price.Source.String(),
+		LimitPrice:   limitPrice,
+		Expiry:       ToExpiryResponse(trigger.Expiry),
+		Confirmation: ToConfirmationResponse(trigger.Confirmation),
+		Guard:        ToGuardResponse(trigger.Guard),
+		GuardFailure: ToGuardFailureResponse(trigger.GuardFailure),
+		Orders:       ToTriggerOrderResponses(trigger.Orders),
+		CreatedAt:    trigger.CreatedAt,
+		UpdatedAt:    trigger.UpdatedAt,
+	}
+}
+
+func (r *TakeProfitTriggerRoutes) CreateTakeProfitTrigger(w http.ResponseWriter, req *http.Request) {
+	var request CreateTakeProfitTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	side, err := contract.NewSide(request.Contract.Side)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractIdentifier := contract.ContractIdentifier{
+		Ticker: contract.Ticker(request.Contract.Ticker),
+		Side:   side,
+	}
+
+	triggerPrice, err := contract.NewContractPrice(request.TriggerPrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var limitPrice *contract.ContractPrice
+	if request.LimitPrice != nil {
+		cp, err := contract.NewContractPrice(*request.LimitPrice)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		limitPrice = &cp
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CreateTakeProfitTrigger(req.Context(), contractIdentifier, triggerPrice, limitPrice, priceSource)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToTakeProfitTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *TakeProfitTriggerRoutes) ListTakeProfitTriggers(w http.ResponseWriter, req *http.Request) {
+	triggers, err := r.service.GetByType(req.Context(), trigger_domain.TriggerTypeTakeProfit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := lo.Map(triggers, func(trigger *trigger_domain.Trigger, _ int) TakeProfitTriggerResponse {
+		return ToTakeProfitTriggerResponse(trigger)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *TakeProfitTriggerRoutes) GetTakeProfitTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.GetByID(req.Context(), trigger_domain.TriggerID(triggerID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if trigger == nil || trigger.TriggerType != trigger_domain.TriggerTypeTakeProfit {
+		http.Error(w, "trigger not found", http.StatusNotFound)
+		return
+	}
+
+	response := ToTakeProfitTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *TakeProfitTriggerRoutes) UpdateTakeProfitTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateTakeProfitTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var triggerPrice *contract.ContractPrice
+	if request.TriggerPrice != nil {
+		tp, err := contract.NewContractPrice(*request.TriggerPrice)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		triggerPrice = &tp
+	}
+
+	var limitPrice *contract.ContractPrice
+	if request.LimitPrice != nil {
+		cp, err := contract.NewContractPrice(*request.LimitPrice)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		limitPrice = &cp
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.UpdateTakeProfitTrigger(req.Context(), trigger_domain.TriggerID(triggerID), triggerPrice, limitPrice, priceSource, requestActor(req))
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) || errors.Is(err, trigger_service.ErrInvalidTriggerType) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToTakeProfitTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *TakeProfitTriggerRoutes) CancelTakeProfitTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CancelTriggerOfType(req.Context(), trigger_domain.TriggerID(triggerID), trigger_domain.TriggerTypeTakeProfit, requestActor(req))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToTakeProfitTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"prediction-risk/internal/app/contract"
+	"prediction-risk/internal/app/core"
+	trigger_domain "prediction-risk/internal/app/risk/trigger/domain"
+	trigger_service "prediction-risk/internal/app/risk/trigger/service"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type TrailingStopTriggerRoutes struct {
+	service *trigger_service.TriggerService
+}
+
+func NewTrailingStopTriggerRoutes(service *trigger_service.TriggerService) *TrailingStopTriggerRoutes {
+	return &TrailingStopTriggerRoutes{service: service}
+}
+
+func (routes *TrailingStopTriggerRoutes) Register(router chi.Router) {
+	router.Route("/api/trailing-stop-triggers", func(r chi.Router) {
+		r.Post("/", routes.CreateTrailingStopTrigger)
+		r.Get("/", routes.ListTrailingStopTriggers)
+		r.Get("/{id}", routes.GetTrailingStopTrigger)
+		r.Patch("/{id}", routes.UpdateTrailingStopTrigger)
+		r.Delete("/{id}", routes.CancelTrailingStopTrigger)
+	})
+}
+
+type CreateTrailingStopTriggerRequest struct {
+	Contract struct {
+		Ticker string `json:"ticker"`
+		Side   string `json:"side"`
+	} `json:"contract"`
+	TrailType      string  `json:"trail_type"`
+	TrailAmount    int     `json:"trail_amount"`
+	ReferencePrice int     `json:"reference_price"`
+	PriceSource    *string `json:"price_source"`
+}
+
+type UpdateTrailingStopTriggerRequest struct {
+	TrailType   *string `json:"trail_type"`
+	TrailAmount *int    `json:"trail_amount"`
+	PriceSource *string `json:"price_source"`
+}
+
+type TrailingStopTriggerResponse struct {
+	TriggerID     string                 `json:"trigger_id"`
+	TriggerType   string                 `json:"trigger_type"`
+	Contract      ContractIDResponse     `json:"contract"`
+	Status        string                 `json:"status"`
+	TrailType     string                 `json:"trail_type"`
+	TrailAmount   int                    `json:"trail_amount"`
+	HighWaterMark int                    `json:"high_water_mark"`
+	StopPrice     int                    `json:"stop_price"`
+	PriceSource   string                 `json:"price_source"`
+	Expiry        *ExpiryResponse        `json:"expiry,omitempty"`
+	Confirmation  *ConfirmationResponse  `json:"confirmation,omitempty"`
+	Guard         *GuardResponse         `json:"guard,omitempty"`
+	GuardFailure  *GuardFailureResponse  `json:"guard_failure,omitempty"`
+	Orders        []TriggerOrderResponse `json:"orders,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+func ToTrailingStopTriggerResponse(trigger *trigger_domain.Trigger) TrailingStopTriggerResponse {
+	response := TrailingStopTriggerResponse{
+		TriggerID:   trigger.TriggerID.String(),
+		TriggerType: trigger.TriggerType.String(),
+		Contract: ContractIDResponse{
+			Ticker: string(trigger.Condition.Contract.Ticker),
+			Side:   trigger.Condition.Contract.Side.String(),
+		},
+		Status:       trigger.Status.String(),
+		StopPrice:    trigger.Condition.Price.Threshold.Value(),
+		PriceSource:  trigger.Condition.Price.Source.String(),
+		Expiry:       ToExpiryResponse(trigger.Expiry),
+		Confirmation: ToConfirmationResponse(trigger.Confirmation),
+		Guard:        ToGuardResponse(trigger.Guard),
+		GuardFailure: ToGuardFailureResponse(trigger.GuardFailure),
+		Orders:       ToTriggerOrderResponses(trigger.Orders),
+		CreatedAt:    trigger.CreatedAt,
+		UpdatedAt:    trigger.UpdatedAt,
+	}
+
+	if trigger.TrailingStop != nil {
+		response.TrailType = trigger.TrailingStop.TrailType.String()
+		response.TrailAmount = trigger.TrailingStop.TrailAmount
+		response.HighWaterMark = trigger.TrailingStop.HighWaterMark.Value()
+	}
+
+	return response
+}
+
+func (r *TrailingStopTriggerRoutes) CreateTrailingStopTrigger(w http.ResponseWriter, req *http.Request) {
+	var request CreateTrailingStopTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	side, err := contract.NewSide(request.Contract.Side)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contractIdentifier := contract.ContractIdentifier{
+		Ticker: contract.Ticker(request.Contract.Ticker),
+		Side:   side,
+	}
+
+	trailType, err := trigger_domain.NewTrailType(request.TrailType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	referencePrice, err := contract.NewContractPrice(request.ReferencePrice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CreateTrailingStopTrigger(req.Context(), contractIdentifier, trailType, request.TrailAmount, referencePrice, priceSource)
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToTrailingStopTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *TrailingStopTriggerRoutes) ListTrailingStopTriggers(w http.ResponseWriter, req *http.Request) {
+	triggers, err := r.service.GetByType(req.Context(), trigger_domain.TriggerTypeTrailingStop)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := lo.Map(triggers, func(trigger *trigger_domain.Trigger, _ int) TrailingStopTriggerResponse {
+		return ToTrailingStopTriggerResponse(trigger)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *TrailingStopTriggerRoutes) GetTrailingStopTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.GetByID(req.Context(), trigger_domain.TriggerID(triggerID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if trigger == nil || trigger.TriggerType != trigger_domain.TriggerTypeTrailingStop {
+		http.Error(w, "trigger not found", http.StatusNotFound)
+		return
+	}
+
+	response := ToTrailingStopTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *TrailingStopTriggerRoutes) UpdateTrailingStopTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateTrailingStopTriggerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var trailType *trigger_domain.TrailType
+	if request.TrailType != nil {
+		tt, err := trigger_domain.NewTrailType(*request.TrailType)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		trailType = &tt
+	}
+
+	priceSource, err := parseOptionalPriceSource(request.PriceSource)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.UpdateTrailingStopTrigger(req.Context(), trigger_domain.TriggerID(triggerID), trailType, request.TrailAmount, priceSource, requestActor(req))
+	if err != nil {
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) || errors.Is(err, trigger_service.ErrInvalidTriggerType) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToTrailingStopTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (r *TrailingStopTriggerRoutes) CancelTrailingStopTrigger(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+
+	triggerID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.service.CancelTriggerOfType(req.Context(), trigger_domain.TriggerID(triggerID), trigger_domain.TriggerTypeTrailingStop, requestActor(req))
+	if err != nil {
+		var notFoundErr *core.ErrNotFound
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, trigger_service.ErrInvalidTrigger) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ToTrailingStopTriggerResponse(trigger)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}